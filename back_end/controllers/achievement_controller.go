@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"type_writer_api/services/achievements"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type AchievementsController struct {
+	AchievementsService achievements_service.AchievementsServiceInterface
+}
+
+func (a *AchievementsController) GetUserAchievements(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		userId int
+		err    error
+	)
+
+	userId, err = strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad user id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad user id in request")
+	}
+
+	achievements, err := a.AchievementsService.GetUserAchievements(reqCtx, userId)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "user not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "user not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error getting user achievements", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error getting user achievements")
+	}
+
+	return ctx.JSON(http.StatusOK, achievements)
+}
+
+func NewAchievementsController(achievementsService *achievements_service.AchievementsService) *AchievementsController {
+	return &AchievementsController{
+		AchievementsService: achievementsService,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"type_writer_api/engines"
 	"type_writer_api/services/activites"
 	"type_writer_api/structures"
 
@@ -63,7 +64,16 @@ func (t *ActivitiesController) CreateActivity(ctx echo.Context) error {
 	}
 
 	createdActivity, err := t.ActivitiesService.CreateActivity(reqCtx, req)
-	if err != nil {
+	if err != nil && err == activities_service.ErrInvalidActivityRules {
+		slog.ErrorContext(reqCtx, "invalid activity rules", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid activity rules")
+	} else if err != nil && err == activities_service.ErrUnknownActivityKind {
+		slog.ErrorContext(reqCtx, "unknown activity kind", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "unknown activity kind")
+	} else if err != nil && err == activities_service.ErrInvalidScoringFormula {
+		slog.ErrorContext(reqCtx, "invalid scoring formula", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid scoring formula")
+	} else if err != nil {
 		slog.ErrorContext(reqCtx, "error creating new activity", "error", err)
 		return ctx.JSON(http.StatusInternalServerError, "error creating new activity")
 	}
@@ -92,7 +102,16 @@ func (t *ActivitiesController) UpdateActivity(ctx echo.Context) error {
 	}
 
 	updatedActivity, err := t.ActivitiesService.UpdateActivity(reqCtx, req, activityId)
-	if err != nil {
+	if err != nil && err == activities_service.ErrInvalidActivityRules {
+		slog.ErrorContext(reqCtx, "invalid activity rules", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid activity rules")
+	} else if err != nil && err == activities_service.ErrUnknownActivityKind {
+		slog.ErrorContext(reqCtx, "unknown activity kind", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "unknown activity kind")
+	} else if err != nil && err == activities_service.ErrInvalidScoringFormula {
+		slog.ErrorContext(reqCtx, "invalid scoring formula", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid scoring formula")
+	} else if err != nil {
 		slog.ErrorContext(reqCtx, "error updating activity", "error", err)
 		return ctx.JSON(http.StatusInternalServerError, "error updating activity")
 	}
@@ -125,6 +144,69 @@ func (t *ActivitiesController) DeleteActivity(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, activity)
 }
 
+func (t *ActivitiesController) GetActivityKinds(ctx echo.Context) error {
+	kinds := t.ActivitiesService.GetActivityKinds(ctx.Request().Context())
+
+	return ctx.JSON(http.StatusOK, struct{ Kinds []string `json:"kinds"`}{Kinds: kinds})
+}
+
+func (t *ActivitiesController) EvaluateActivity(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		req        structures.ActivityInput
+		activityId int
+		err        error
+	)
+
+	activityId, err = strconv.Atoi(ctx.Param("activity_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad activity id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad activity id in request")
+	}
+
+	err = ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	result, err := t.ActivitiesService.EvaluateActivity(reqCtx, activityId, req)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "activity not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "activity not found")
+	} else if err != nil && (err == engines.ErrInvalidKeystrokes || err == engines.ErrTextTypeNotAllowed) {
+		slog.ErrorContext(reqCtx, "invalid activity input", "error", err)
+		return ctx.JSON(http.StatusBadRequest, err.Error())
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error evaluating activity", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error evaluating activity")
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (t *ActivitiesController) TestScoringFormula(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	req := structures.ScoringFormulaTestReq{}
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	samples, err := t.ActivitiesService.TestScoringFormula(reqCtx, req)
+	if err != nil && err == activities_service.ErrInvalidScoringFormula {
+		slog.ErrorContext(reqCtx, "invalid scoring formula", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid scoring formula")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error testing scoring formula", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error testing scoring formula")
+	}
+
+	return ctx.JSON(http.StatusOK, struct{ Samples []*structures.ScoringFormulaSample `json:"samples"`}{Samples: samples})
+}
+
 func NewActivitiesController(activitiesService *activities_service.ActivitiesService) *ActivitiesController {
 	return &ActivitiesController{
 		ActivitiesService: activitiesService,
@@ -34,6 +34,7 @@ func (a *AuthController) Login(ctx echo.Context) error {
 
 	// Custom claims for authorization middleware
 	claims := &structures.JwtCustomClaims{
+		UserId: loginUserInfo.Id,
 		UserType: loginUserInfo.UserType,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject: loginUserInfo.Username,
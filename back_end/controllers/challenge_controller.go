@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	local_middleware "type_writer_api/middleware"
+	"type_writer_api/services/challenges"
+	"type_writer_api/structures"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ChallengesController struct {
+	ChallengesService challenges_service.ChallengesServiceInterface
+}
+
+func (c *ChallengesController) GetTodayChallenge(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+
+	challenge, err := c.ChallengesService.GetTodayChallenge(reqCtx, ctx.QueryParam("tz"))
+	if err != nil && err == challenges_service.ErrInvalidTimeZone {
+		slog.ErrorContext(reqCtx, "invalid time zone", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid time zone")
+	} else if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "challenge not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "challenge not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching challenge", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching challenge")
+	}
+
+	return ctx.JSON(http.StatusOK, challenge)
+}
+
+func (c *ChallengesController) GetChallenge(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+
+	challenge, err := c.ChallengesService.GetChallenge(reqCtx, ctx.Param("date"))
+	if err != nil && err == challenges_service.ErrInvalidChallengeDate {
+		slog.ErrorContext(reqCtx, "bad challenge date in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad challenge date in request")
+	} else if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "challenge not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "challenge not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching challenge", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching challenge")
+	}
+
+	return ctx.JSON(http.StatusOK, challenge)
+}
+
+func (c *ChallengesController) GetChallengeLeaderboard(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+
+	entries, err := c.ChallengesService.GetChallengeLeaderboard(reqCtx, ctx.Param("date"))
+	if err != nil && err == challenges_service.ErrInvalidChallengeDate {
+		slog.ErrorContext(reqCtx, "bad challenge date in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad challenge date in request")
+	} else if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "challenge not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "challenge not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching challenge leaderboard", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching challenge leaderboard")
+	}
+
+	return ctx.JSON(http.StatusOK, struct {
+		Entries []*structures.ChallengeLeaderboardEntry `json:"entries"`
+	}{Entries: entries})
+}
+
+func (c *ChallengesController) CreateChallengeAttempt(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	req := structures.ChallengeAttemptReq{}
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+
+	attempt, err := c.ChallengesService.CreateChallengeAttempt(reqCtx, ctx.Param("date"), req, claims.UserId)
+	if err != nil && err == challenges_service.ErrInvalidChallengeDate {
+		slog.ErrorContext(reqCtx, "bad challenge date in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad challenge date in request")
+	} else if err != nil && err == challenges_service.ErrScoreNotInChallenge {
+		slog.ErrorContext(reqCtx, "score was not made on the challenge", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "score was not made on the challenge")
+	} else if err != nil && err == challenges_service.ErrScoreNotOwned {
+		slog.ErrorContext(reqCtx, "score belongs to another user", "error", err)
+		return ctx.JSON(http.StatusForbidden, "score belongs to another user")
+	} else if err != nil && err == challenges_service.ErrAlreadyAttempted {
+		slog.ErrorContext(reqCtx, "challenge already attempted", "error", err)
+		return ctx.JSON(http.StatusConflict, "challenge already attempted")
+	} else if err != nil && err == challenges_service.ErrNotFirstScore {
+		slog.ErrorContext(reqCtx, "score is not the first on the challenge", "error", err)
+		return ctx.JSON(http.StatusConflict, "only the first score on the challenge can be entered")
+	} else if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "challenge or score not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "challenge or score not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error creating challenge attempt", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error creating challenge attempt")
+	}
+
+	return ctx.JSON(http.StatusCreated, attempt)
+}
+
+func (c *ChallengesController) GetChallengeQueue(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+
+	entries, err := c.ChallengesService.GetChallengeQueue(reqCtx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching challenge queue", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching challenge queue")
+	}
+
+	return ctx.JSON(http.StatusOK, struct {
+		Entries []*structures.ChallengeQueueEntry `json:"entries"`
+	}{Entries: entries})
+}
+
+func (c *ChallengesController) CreateChallengeQueueEntry(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	req := structures.ChallengeQueueEntryReq{}
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	createdEntry, err := c.ChallengesService.CreateChallengeQueueEntry(reqCtx, req)
+	if err != nil && err == challenges_service.ErrInvalidQueueEntry {
+		slog.ErrorContext(reqCtx, "invalid challenge queue entry", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid challenge queue entry")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error creating challenge queue entry", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error creating challenge queue entry")
+	}
+
+	return ctx.JSON(http.StatusCreated, createdEntry)
+}
+
+func (c *ChallengesController) DeleteChallengeQueueEntry(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		entryId int
+		err     error
+	)
+
+	entryId, err = strconv.Atoi(ctx.Param("entry_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad queue entry id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad queue entry id in request")
+	}
+
+	deleted, err := c.ChallengesService.DeleteChallengeQueueEntry(reqCtx, entryId)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error deleting challenge queue entry", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error deleting challenge queue entry")
+	}
+	if !deleted {
+		return ctx.JSON(http.StatusNotFound, "challenge queue entry not found")
+	}
+
+	return ctx.JSON(http.StatusOK, deleted)
+}
+
+func NewChallengesController(challengesService *challenges_service.ChallengesService) *ChallengesController {
+	return &ChallengesController{
+		ChallengesService: challengesService,
+	}
+}
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	local_middleware "type_writer_api/middleware"
+	"type_writer_api/services/courses"
+	"type_writer_api/structures"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type CoursesController struct {
+	CoursesService courses_service.CoursesServiceInterface
+}
+
+func (c *CoursesController) GetCourses(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+
+	courses, err := c.CoursesService.GetCourses(reqCtx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching courses", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching courses")
+	}
+
+	return ctx.JSON(http.StatusOK, struct{ Courses []*structures.Course `json:"courses"`}{Courses: courses})
+}
+
+func (c *CoursesController) GetCourse(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		courseId int
+		err      error
+	)
+
+	courseId, err = strconv.Atoi(ctx.Param("course_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad course id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad course id in request")
+	}
+
+	course, err := c.CoursesService.GetCourseById(reqCtx, courseId)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "course not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "course not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching course", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching course")
+	}
+
+	return ctx.JSON(http.StatusOK, course)
+}
+
+func (c *CoursesController) CreateCourse(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	req := structures.CourseReq{}
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	createdCourse, err := c.CoursesService.CreateCourse(reqCtx, req)
+	if err != nil && err == courses_service.ErrInvalidLesson {
+		slog.ErrorContext(reqCtx, "invalid course lesson", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid course lesson")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error creating new course", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error creating new course")
+	}
+
+	return ctx.JSON(http.StatusCreated, createdCourse)
+}
+
+func (c *CoursesController) UpdateCourse(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		req      structures.CourseReq
+		courseId int
+		err      error
+	)
+
+	courseId, err = strconv.Atoi(ctx.Param("course_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad course id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad course id in request")
+	}
+
+	err = ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	updatedCourse, err := c.CoursesService.UpdateCourse(reqCtx, req, courseId)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "course not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "course not found")
+	} else if err != nil && err == courses_service.ErrInvalidLesson {
+		slog.ErrorContext(reqCtx, "invalid course lesson", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid course lesson")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error updating course", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error updating course")
+	}
+
+	return ctx.JSON(http.StatusOK, updatedCourse)
+}
+
+func (c *CoursesController) DeleteCourse(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		courseId int
+		err      error
+	)
+
+	courseId, err = strconv.Atoi(ctx.Param("course_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad course id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad course id in request")
+	}
+
+	course, err := c.CoursesService.DeleteCourse(reqCtx, courseId)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "course not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "course not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error deleting course", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error deleting course")
+	}
+
+	return ctx.JSON(http.StatusOK, course)
+}
+
+func (c *CoursesController) GetCourseProgress(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		courseId int
+		err      error
+	)
+
+	courseId, err = strconv.Atoi(ctx.Param("course_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad course id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad course id in request")
+	}
+
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+
+	progress, err := c.CoursesService.GetCourseProgress(reqCtx, courseId, claims.UserId)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "course not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "course not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching course progress", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching course progress")
+	}
+
+	return ctx.JSON(http.StatusOK, progress)
+}
+
+func NewCoursesController(coursesService *courses_service.CoursesService) *CoursesController {
+	return &CoursesController{
+		CoursesService: coursesService,
+	}
+}
@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	local_middleware "type_writer_api/middleware"
+	"type_writer_api/services/goals"
+	"type_writer_api/structures"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type GoalsController struct {
+	GoalsService goals_service.GoalsServiceInterface
+}
+
+func (g *GoalsController) GetUserStreak(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		userId int
+		err    error
+	)
+
+	userId, err = strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad user id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad user id in request")
+	}
+
+	streak, err := g.GoalsService.GetUserStreak(reqCtx, userId)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "user not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "user not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error getting user streak", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error getting user streak")
+	}
+
+	return ctx.JSON(http.StatusOK, streak)
+}
+
+func (g *GoalsController) GetUserGoals(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		userId int
+		err    error
+	)
+
+	userId, err = strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad user id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad user id in request")
+	}
+
+	viewerId, viewerType := local_middleware.ContextViewer(ctx)
+
+	goals, err := g.GoalsService.GetUserGoals(reqCtx, userId, viewerId, viewerType)
+	if err != nil && err == goals_service.ErrGoalsForbidden {
+		slog.ErrorContext(reqCtx, "goals belong to another user", "error", err)
+		return ctx.JSON(http.StatusForbidden, "goals belong to another user")
+	} else if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "user not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "user not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error getting user goals", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error getting user goals")
+	}
+
+	return ctx.JSON(http.StatusOK, goals)
+}
+
+func (g *GoalsController) CreateUserGoal(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		req    structures.UserGoalReq
+		userId int
+		err    error
+	)
+
+	userId, err = strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad user id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad user id in request")
+	}
+
+	err = ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	viewerId, viewerType := local_middleware.ContextViewer(ctx)
+
+	goal, err := g.GoalsService.CreateUserGoal(reqCtx, req, userId, viewerId, viewerType)
+	if err != nil && err == goals_service.ErrGoalsForbidden {
+		slog.ErrorContext(reqCtx, "goals belong to another user", "error", err)
+		return ctx.JSON(http.StatusForbidden, "goals belong to another user")
+	} else if err != nil && err == goals_service.ErrInvalidGoal {
+		slog.ErrorContext(reqCtx, "invalid goal", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid goal, unknown metric, bad target or past target date")
+	} else if err != nil && err == goals_service.ErrTooManyGoals {
+		slog.ErrorContext(reqCtx, "too many goals", "error", err)
+		return ctx.JSON(http.StatusConflict, "too many goals")
+	} else if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "user not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "user not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error creating user goal", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error creating user goal")
+	}
+
+	return ctx.JSON(http.StatusCreated, goal)
+}
+
+func (g *GoalsController) DeleteUserGoal(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		userId int
+		goalId int
+		err    error
+	)
+
+	userId, err = strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad user id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad user id in request")
+	}
+	goalId, err = strconv.Atoi(ctx.Param("goal_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad goal id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad goal id in request")
+	}
+
+	viewerId, viewerType := local_middleware.ContextViewer(ctx)
+
+	deleted, err := g.GoalsService.DeleteUserGoal(reqCtx, userId, goalId, viewerId, viewerType)
+	if err != nil && err == goals_service.ErrGoalsForbidden {
+		slog.ErrorContext(reqCtx, "goals belong to another user", "error", err)
+		return ctx.JSON(http.StatusForbidden, "goals belong to another user")
+	} else if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "goal not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "goal not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error deleting user goal", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error deleting user goal")
+	}
+
+	return ctx.JSON(http.StatusOK, deleted)
+}
+
+func NewGoalsController(goalsService *goals_service.GoalsService) *GoalsController {
+	return &GoalsController{
+		GoalsService: goalsService,
+	}
+}
@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"type_writer_api/services/keyboard_layouts"
+	"type_writer_api/structures"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type KeyboardLayoutsController struct {
+	KeyboardLayoutsService keyboard_layouts_service.KeyboardLayoutsServiceInterface
+}
+
+func (k *KeyboardLayoutsController) GetKeyboardLayouts(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+
+	layouts, err := k.KeyboardLayoutsService.GetKeyboardLayouts(reqCtx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching keyboard layouts", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching keyboard layouts")
+	}
+
+	return ctx.JSON(http.StatusOK, struct {
+		KeyboardLayouts []*structures.KeyboardLayout `json:"keyboard_layouts"`
+	}{KeyboardLayouts: layouts})
+}
+
+func (k *KeyboardLayoutsController) GetKeyboardLayout(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		layoutId int
+		name     string
+		err      error
+	)
+
+	layoutId, err = strconv.Atoi(ctx.Param("layout_id"))
+	if err != nil {
+		name = ctx.Param("layout_id")
+	}
+
+	layout, err := k.KeyboardLayoutsService.GetKeyboardLayoutByIdOrName(reqCtx, &layoutId, &name)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "keyboard layout not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "keyboard layout not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching keyboard layout", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching keyboard layout")
+	}
+
+	return ctx.JSON(http.StatusOK, layout)
+}
+
+func (k *KeyboardLayoutsController) CreateKeyboardLayout(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	req := structures.KeyboardLayoutReq{}
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	createdLayout, err := k.KeyboardLayoutsService.CreateKeyboardLayout(reqCtx, req)
+	if err != nil && err == keyboard_layouts_service.ErrInvalidLayout {
+		slog.ErrorContext(reqCtx, "invalid keyboard layout", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid keyboard layout")
+	} else if err != nil && err == keyboard_layouts_service.ErrLayoutNameTaken {
+		slog.ErrorContext(reqCtx, "keyboard layout name already in use", "error", err)
+		return ctx.JSON(http.StatusConflict, "keyboard layout name already in use")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error creating new keyboard layout", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error creating new keyboard layout")
+	}
+
+	return ctx.JSON(http.StatusCreated, createdLayout)
+}
+
+func (k *KeyboardLayoutsController) UpdateKeyboardLayout(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		req      structures.KeyboardLayoutReq
+		layoutId int
+		err      error
+	)
+
+	layoutId, err = strconv.Atoi(ctx.Param("layout_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad keyboard layout id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad keyboard layout id in request")
+	}
+
+	err = ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	updatedLayout, err := k.KeyboardLayoutsService.UpdateKeyboardLayout(reqCtx, req, layoutId)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "keyboard layout not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "keyboard layout not found")
+	} else if err != nil && err == keyboard_layouts_service.ErrInvalidLayout {
+		slog.ErrorContext(reqCtx, "invalid keyboard layout", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid keyboard layout")
+	} else if err != nil && err == keyboard_layouts_service.ErrLayoutNameTaken {
+		slog.ErrorContext(reqCtx, "keyboard layout name already in use", "error", err)
+		return ctx.JSON(http.StatusConflict, "keyboard layout name already in use")
+	} else if err != nil && err == keyboard_layouts_service.ErrLayoutInUse {
+		slog.ErrorContext(reqCtx, "keyboard layout in use", "error", err)
+		return ctx.JSON(http.StatusConflict, "keyboard layout is in use, users have to pick another one first")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error updating keyboard layout", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error updating keyboard layout")
+	}
+
+	return ctx.JSON(http.StatusOK, updatedLayout)
+}
+
+func (k *KeyboardLayoutsController) DeleteKeyboardLayout(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		layoutId int
+		err      error
+	)
+
+	layoutId, err = strconv.Atoi(ctx.Param("layout_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad keyboard layout id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad keyboard layout id in request")
+	}
+
+	layout, err := k.KeyboardLayoutsService.DeleteKeyboardLayout(reqCtx, layoutId)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "keyboard layout not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "keyboard layout not found")
+	} else if err != nil && err == keyboard_layouts_service.ErrLayoutInUse {
+		slog.ErrorContext(reqCtx, "keyboard layout in use", "error", err)
+		return ctx.JSON(http.StatusConflict, "keyboard layout is in use, users have to pick another one first")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error deleting keyboard layout", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error deleting keyboard layout")
+	}
+
+	return ctx.JSON(http.StatusOK, layout)
+}
+
+func (k *KeyboardLayoutsController) GetTextKeyProfile(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		textId int
+		err    error
+	)
+
+	textId, err = strconv.Atoi(ctx.Param("text_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad text id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad text id in request")
+	}
+
+	profile, err := k.KeyboardLayoutsService.GetTextKeyProfile(reqCtx, textId, ctx.QueryParam("layout"))
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "text or keyboard layout not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "text or keyboard layout not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error building text key profile", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error building text key profile")
+	}
+
+	return ctx.JSON(http.StatusOK, profile)
+}
+
+func NewKeyboardLayoutsController(keyboardLayoutsService *keyboard_layouts_service.KeyboardLayoutsService) *KeyboardLayoutsController {
+	return &KeyboardLayoutsController{
+		KeyboardLayoutsService: keyboardLayoutsService,
+	}
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	local_middleware "type_writer_api/middleware"
+	"type_writer_api/services/leaderboards"
+	"type_writer_api/structures"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type LeaderboardsController struct {
+	LeaderboardsService leaderboards_service.LeaderboardsServiceInterface
+}
+
+func bindLeaderboardQuery(ctx echo.Context) (structures.LeaderboardQuery, error) {
+	query := structures.LeaderboardQuery{
+		Window: ctx.QueryParam("window"),
+		Metric: ctx.QueryParam("metric"),
+	}
+	err := echo.QueryParamsBinder(ctx).
+		Int("activity_id", &query.ActivityId).
+		Int("text_id", &query.TextId).
+		Int("limit", &query.Limit).
+		Int("offset", &query.Offset).
+		BindError()
+	return query, err
+}
+
+func leaderboardErrorResponse(ctx echo.Context, err error, action string) error {
+	reqCtx := ctx.Request().Context()
+	if err == leaderboards_service.ErrInvalidMetric {
+		slog.ErrorContext(reqCtx, "invalid leaderboard metric", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid leaderboard metric")
+	} else if err == leaderboards_service.ErrInvalidWindow {
+		slog.ErrorContext(reqCtx, "invalid leaderboard window", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid leaderboard window")
+	} else if err == leaderboards_service.ErrInvalidPage {
+		slog.ErrorContext(reqCtx, "invalid leaderboard page", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid leaderboard page")
+	} else if err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "user not ranked on leaderboard", "error", err)
+		return ctx.JSON(http.StatusNotFound, "user not ranked on leaderboard")
+	}
+	slog.ErrorContext(reqCtx, "error "+action, "error", err)
+	return ctx.JSON(http.StatusInternalServerError, "error "+action)
+}
+
+func (l *LeaderboardsController) GetLeaderboard(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+
+	query, err := bindLeaderboardQuery(ctx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad leaderboard query in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad leaderboard query in request")
+	}
+
+	leaderboard, err := l.LeaderboardsService.GetLeaderboard(reqCtx, query)
+	if err != nil {
+		return leaderboardErrorResponse(ctx, err, "fetching leaderboard")
+	}
+
+	return ctx.JSON(http.StatusOK, leaderboard)
+}
+
+func (l *LeaderboardsController) GetMyRank(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+
+	query, err := bindLeaderboardQuery(ctx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad leaderboard query in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad leaderboard query in request")
+	}
+
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+
+	entry, err := l.LeaderboardsService.GetUserRank(reqCtx, query, claims.UserId)
+	if err != nil {
+		return leaderboardErrorResponse(ctx, err, "fetching leaderboard rank")
+	}
+
+	return ctx.JSON(http.StatusOK, entry)
+}
+
+func (l *LeaderboardsController) GetAroundMe(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var radius int
+
+	query, err := bindLeaderboardQuery(ctx)
+	if err == nil {
+		err = echo.QueryParamsBinder(ctx).Int("radius", &radius).BindError()
+	}
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad leaderboard query in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad leaderboard query in request")
+	}
+
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+
+	leaderboard, err := l.LeaderboardsService.GetAroundUser(reqCtx, query, claims.UserId, radius)
+	if err != nil {
+		return leaderboardErrorResponse(ctx, err, "fetching leaderboard around user")
+	}
+
+	return ctx.JSON(http.StatusOK, leaderboard)
+}
+
+func NewLeaderboardsController(leaderboardsService *leaderboards_service.LeaderboardsService) *LeaderboardsController {
+	return &LeaderboardsController{
+		LeaderboardsService: leaderboardsService,
+	}
+}
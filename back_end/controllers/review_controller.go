@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	local_middleware "type_writer_api/middleware"
+	"type_writer_api/services/reviews"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ReviewsController struct {
+	ReviewsService reviews_service.ReviewsServiceInterface
+}
+
+func (r *ReviewsController) CreateReviewDrill(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		userId int
+		err    error
+	)
+
+	userId, err = strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad user id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad user id in request")
+	}
+
+	viewerId, viewerType := local_middleware.ContextViewer(ctx)
+
+	drill, err := r.ReviewsService.CreateReviewDrill(reqCtx, userId, viewerId, viewerType)
+	if err != nil && err == reviews_service.ErrReviewDeckForbidden {
+		slog.ErrorContext(reqCtx, "review deck belongs to another user", "error", err)
+		return ctx.JSON(http.StatusForbidden, "review deck belongs to another user")
+	} else if err != nil && err == reviews_service.ErrNoDueWords {
+		slog.ErrorContext(reqCtx, "no words due for review", "error", err)
+		return ctx.JSON(http.StatusNotFound, "no words due for review")
+	} else if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "user not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "user not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error creating review drill", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error creating review drill")
+	}
+
+	return ctx.JSON(http.StatusCreated, drill)
+}
+
+func NewReviewsController(reviewsService *reviews_service.ReviewsService) *ReviewsController {
+	return &ReviewsController{
+		ReviewsService: reviewsService,
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"type_writer_api/engines"
+	"type_writer_api/helpers"
+	local_middleware "type_writer_api/middleware"
 	"type_writer_api/services/scores"
 	"type_writer_api/structures"
 
@@ -52,6 +55,35 @@ func (t *ScoresController) GetScore(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, score)
 }
 
+func (t *ScoresController) GetScoreKeystrokes(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		scoreId int
+		err     error
+	)
+
+	scoreId, err = strconv.Atoi(ctx.Param("score_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad score id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad score id in request")
+	}
+
+	viewerId, viewerType := local_middleware.ContextViewer(ctx)
+	keystrokeLog, err := t.ScoresService.GetScoreKeystrokes(reqCtx, scoreId, viewerId, viewerType)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "keystroke log not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "keystroke log not found")
+	} else if err != nil && err == scores_service.ErrKeystrokesForbidden {
+		slog.ErrorContext(reqCtx, "keystroke log belongs to another user", "error", err)
+		return ctx.JSON(http.StatusForbidden, "keystroke log belongs to another user")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching keystroke log", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching keystroke log")
+	}
+
+	return ctx.JSON(http.StatusOK, keystrokeLog)
+}
+
 func (t *ScoresController) CreateScore(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 	req := structures.ScoreReq{}
@@ -62,8 +94,30 @@ func (t *ScoresController) CreateScore(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
 	}
 
-	createdScore, err := t.ScoresService.CreateScore(reqCtx, req)
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
 	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+	// only admins may file a score for someone else
+	if claims.UserType != structures.USER_TYPE_ADMIN {
+		req.UserId = claims.UserId
+	}
+
+	createdScore, err := t.ScoresService.CreateScore(reqCtx, req)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "activity or text not found", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "activity or text not found")
+	} else if err != nil && (err == helpers.ErrInvalidKeystrokeLog || err == helpers.ErrKeystrokeLogTooLarge || err == engines.ErrInvalidKeystrokes || err == engines.ErrTextTypeNotAllowed) {
+		slog.ErrorContext(reqCtx, "invalid keystroke log", "error", err)
+		return ctx.JSON(http.StatusBadRequest, err.Error())
+	} else if err != nil && (err == helpers.ErrInvalidScoreResult || err == helpers.ErrUnsupportedResultVersion) {
+		slog.ErrorContext(reqCtx, "invalid score result", "error", err)
+		return ctx.JSON(http.StatusBadRequest, err.Error())
+	} else if err != nil && err == scores_service.ErrResultMismatch {
+		slog.ErrorContext(reqCtx, "result does not match the keystrokes", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "result does not match the keystrokes")
+	} else if err != nil {
 		slog.ErrorContext(reqCtx, "error creating new score", "error", err)
 		return ctx.JSON(http.StatusInternalServerError, "error creating new score")
 	}
@@ -92,7 +146,13 @@ func (t *ScoresController) UpdateScore(ctx echo.Context) error {
 	}
 
 	updatedScore, err := t.ScoresService.UpdateScore(reqCtx, req, scoreId)
-	if err != nil {
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "score not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "score not found")
+	} else if err != nil && (err == helpers.ErrInvalidScoreResult || err == helpers.ErrUnsupportedResultVersion) {
+		slog.ErrorContext(reqCtx, "invalid score result", "error", err)
+		return ctx.JSON(http.StatusBadRequest, err.Error())
+	} else if err != nil {
 		slog.ErrorContext(reqCtx, "error updating score", "error", err)
 		return ctx.JSON(http.StatusInternalServerError, "error updating score")
 	}
@@ -125,6 +185,54 @@ func (t *ScoresController) DeleteScore(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, score)
 }
 
+func (t *ScoresController) GetFlaggedScores(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+
+	scores, err := t.ScoresService.GetFlaggedScores(reqCtx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching flagged scores", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching flagged scores")
+	}
+
+	return ctx.JSON(http.StatusOK, struct{ Scores []*structures.Score `json:"scores"`}{Scores: scores})
+}
+
+func (t *ScoresController) ApproveScore(ctx echo.Context) error {
+	return t.reviewScore(ctx, true)
+}
+
+func (t *ScoresController) RejectScore(ctx echo.Context) error {
+	return t.reviewScore(ctx, false)
+}
+
+func (t *ScoresController) reviewScore(ctx echo.Context, approved bool) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		scoreId int
+		err     error
+	)
+
+	scoreId, err = strconv.Atoi(ctx.Param("score_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad score id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad score id in request")
+	}
+
+	score, err := t.ScoresService.ReviewScore(reqCtx, scoreId, approved)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "score not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "score not found")
+	} else if err != nil && err == scores_service.ErrScoreNotFlagged {
+		slog.ErrorContext(reqCtx, "score is not flagged for review", "error", err)
+		return ctx.JSON(http.StatusConflict, "score is not flagged for review")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error reviewing score", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error reviewing score")
+	}
+
+	return ctx.JSON(http.StatusOK, score)
+}
+
 func NewScoresController(scoresService *scores_service.ScoresService) *ScoresController {
 	return &ScoresController{
 		ScoresService: scoresService,
@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"type_writer_api/engines"
+	local_middleware "type_writer_api/middleware"
+	"type_writer_api/services/sessions"
+	"type_writer_api/structures"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type SessionsController struct {
+	SessionsService sessions_service.SessionsServiceInterface
+}
+
+// sessionError answers the errors shared by every request made on a running
+// session
+func sessionError(ctx echo.Context, err error, action string) error {
+	reqCtx := ctx.Request().Context()
+
+	if err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "session not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "session not found")
+	} else if err == sessions_service.ErrInvalidNonce {
+		slog.ErrorContext(reqCtx, "invalid session nonce", "error", err)
+		return ctx.JSON(http.StatusForbidden, "invalid session nonce")
+	} else if err == sessions_service.ErrSessionExpired {
+		slog.ErrorContext(reqCtx, "session expired", "error", err)
+		return ctx.JSON(http.StatusGone, "session expired")
+	} else if err == sessions_service.ErrSessionNotActive || err == sessions_service.ErrSessionConflict {
+		slog.ErrorContext(reqCtx, err.Error(), "error", err)
+		return ctx.JSON(http.StatusConflict, err.Error())
+	} else if err == engines.ErrInvalidKeystrokes || err == engines.ErrTextTypeNotAllowed {
+		slog.ErrorContext(reqCtx, "invalid session input", "error", err)
+		return ctx.JSON(http.StatusBadRequest, err.Error())
+	}
+	slog.ErrorContext(reqCtx, "error "+action, "error", err)
+	return ctx.JSON(http.StatusInternalServerError, "error "+action)
+}
+
+func (s *SessionsController) StartSession(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	req := structures.TypingSessionReq{}
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+
+	session, err := s.SessionsService.StartSession(reqCtx, req, claims.UserId, claims.UserType)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "activity or text not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "activity or text not found")
+	} else if err != nil && err == engines.ErrTextTypeNotAllowed {
+		slog.ErrorContext(reqCtx, "text type not allowed by the activity", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "text type not allowed by the activity")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error starting session", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error starting session")
+	}
+
+	return ctx.JSON(http.StatusCreated, session)
+}
+
+func (s *SessionsController) GetSession(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		sessionId int
+		err       error
+	)
+
+	sessionId, err = strconv.Atoi(ctx.Param("session_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad session id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad session id in request")
+	}
+
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+
+	session, err := s.SessionsService.GetSession(reqCtx, sessionId, claims.UserId)
+	if err != nil {
+		return sessionError(ctx, err, "fetching session")
+	}
+
+	return ctx.JSON(http.StatusOK, session)
+}
+
+func (s *SessionsController) AddSessionEvents(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		req       structures.SessionEventsReq
+		sessionId int
+		err       error
+	)
+
+	sessionId, err = strconv.Atoi(ctx.Param("session_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad session id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad session id in request")
+	}
+
+	err = ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+
+	session, err := s.SessionsService.AddSessionEvents(reqCtx, sessionId, req, claims.UserId)
+	if err != nil {
+		return sessionError(ctx, err, "adding session events")
+	}
+
+	return ctx.JSON(http.StatusOK, session)
+}
+
+func (s *SessionsController) FinishSession(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		req       structures.SessionFinishReq
+		sessionId int
+		err       error
+	)
+
+	sessionId, err = strconv.Atoi(ctx.Param("session_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad session id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad session id in request")
+	}
+
+	err = ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+
+	score, err := s.SessionsService.FinishSession(reqCtx, sessionId, req, claims.UserId)
+	if err != nil {
+		return sessionError(ctx, err, "finishing session")
+	}
+
+	return ctx.JSON(http.StatusCreated, score)
+}
+
+func NewSessionsController(sessionsService *sessions_service.SessionsService) *SessionsController {
+	return &SessionsController{
+		SessionsService: sessionsService,
+	}
+}
@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	local_middleware "type_writer_api/middleware"
+	"type_writer_api/services/stats"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type StatsController struct {
+	StatsService stats_service.StatsServiceInterface
+}
+
+func (s *StatsController) GetUserStats(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		userId int
+		err    error
+	)
+
+	userId, err = strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad user id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad user id in request")
+	}
+
+	stats, err := s.StatsService.GetUserStats(reqCtx, userId, ctx.QueryParam("bucket"))
+	if err != nil && err == stats_service.ErrInvalidBucket {
+		slog.ErrorContext(reqCtx, "invalid stats bucket", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid stats bucket")
+	} else if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "user not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "user not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching user stats", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching user stats")
+	}
+
+	return ctx.JSON(http.StatusOK, stats)
+}
+
+func (s *StatsController) GetUserKeymapStats(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		userId int
+		err    error
+	)
+
+	userId, err = strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad user id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad user id in request")
+	}
+
+	viewerId, viewerType := local_middleware.ContextViewer(ctx)
+
+	stats, err := s.StatsService.GetUserKeymapStats(reqCtx, userId, viewerId, viewerType, ctx.QueryParam("window"), ctx.QueryParam("layout"))
+	if err != nil && err == stats_service.ErrInvalidWindow {
+		slog.ErrorContext(reqCtx, "invalid stats window", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid stats window")
+	} else if err != nil && err == stats_service.ErrInvalidKeyboardLayout {
+		slog.ErrorContext(reqCtx, "unknown keyboard layout", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "unknown keyboard layout")
+	} else if err != nil && err == stats_service.ErrKeystrokeStatsForbidden {
+		slog.ErrorContext(reqCtx, "keystroke stats belong to another user", "error", err)
+		return ctx.JSON(http.StatusForbidden, "keystroke stats belong to another user")
+	} else if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "user not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "user not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching user keymap stats", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching user keymap stats")
+	}
+
+	return ctx.JSON(http.StatusOK, stats)
+}
+
+func (s *StatsController) GetUserNgramStats(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		userId int
+		err    error
+	)
+
+	userId, err = strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad user id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad user id in request")
+	}
+
+	viewerId, viewerType := local_middleware.ContextViewer(ctx)
+
+	stats, err := s.StatsService.GetUserNgramStats(reqCtx, userId, viewerId, viewerType, ctx.QueryParam("window"))
+	if err != nil && err == stats_service.ErrInvalidWindow {
+		slog.ErrorContext(reqCtx, "invalid stats window", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid stats window")
+	} else if err != nil && err == stats_service.ErrKeystrokeStatsForbidden {
+		slog.ErrorContext(reqCtx, "keystroke stats belong to another user", "error", err)
+		return ctx.JSON(http.StatusForbidden, "keystroke stats belong to another user")
+	} else if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "user not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "user not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching user ngram stats", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching user ngram stats")
+	}
+
+	return ctx.JSON(http.StatusOK, stats)
+}
+
+func (s *StatsController) CreateNgramDrill(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		userId int
+		err    error
+	)
+
+	userId, err = strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad user id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad user id in request")
+	}
+
+	viewerId, viewerType := local_middleware.ContextViewer(ctx)
+
+	drill, err := s.StatsService.CreateNgramDrill(reqCtx, userId, viewerId, viewerType)
+	if err != nil && err == stats_service.ErrKeystrokeStatsForbidden {
+		slog.ErrorContext(reqCtx, "keystroke stats belong to another user", "error", err)
+		return ctx.JSON(http.StatusForbidden, "keystroke stats belong to another user")
+	} else if err != nil && err == stats_service.ErrNoWeakNgrams {
+		slog.ErrorContext(reqCtx, "not enough keystrokes to find weak sequences", "error", err)
+		return ctx.JSON(http.StatusConflict, "not enough keystrokes to find weak sequences")
+	} else if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "user not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "user not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error creating ngram drill", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error creating ngram drill")
+	}
+
+	return ctx.JSON(http.StatusCreated, drill)
+}
+
+func (s *StatsController) GetUserRecommendations(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		userId int
+		err    error
+	)
+
+	userId, err = strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad user id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad user id in request")
+	}
+
+	viewerId, viewerType := local_middleware.ContextViewer(ctx)
+
+	recommendations, err := s.StatsService.GetUserRecommendations(reqCtx, userId, viewerId, viewerType)
+	if err != nil && err == stats_service.ErrKeystrokeStatsForbidden {
+		slog.ErrorContext(reqCtx, "keystroke stats belong to another user", "error", err)
+		return ctx.JSON(http.StatusForbidden, "keystroke stats belong to another user")
+	} else if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "user not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "user not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching user recommendations", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching user recommendations")
+	}
+
+	return ctx.JSON(http.StatusOK, recommendations)
+}
+
+func NewStatsController(statsService *stats_service.StatsService) *StatsController {
+	return &StatsController{
+		StatsService: statsService,
+	}
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"type_writer_api/helpers"
+	"type_writer_api/services/tags"
+	"type_writer_api/structures"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type TagsController struct {
+	TagsService tags_service.TagsServiceInterface
+}
+
+func (t *TagsController) GetTags(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+
+	tags, err := t.TagsService.GetTags(reqCtx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching tags", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching tags")
+	}
+
+	return ctx.JSON(http.StatusOK, struct{ Tags []*structures.Tag `json:"tags"` }{Tags: tags})
+}
+
+func (t *TagsController) GetTag(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		tagId int
+		name  string
+		err   error
+	)
+
+	tagId, err = strconv.Atoi(ctx.Param("tag_id"))
+	if err != nil {
+		name = ctx.Param("tag_id")
+	}
+
+	tag, err := t.TagsService.GetTagByIdOrName(reqCtx, &tagId, &name)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "tag not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "tag not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching tag", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching tag")
+	}
+
+	return ctx.JSON(http.StatusOK, tag)
+}
+
+func (t *TagsController) CreateTag(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	req := structures.TagReq{}
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	createdTag, err := t.TagsService.CreateTag(reqCtx, req)
+	if err != nil && err == tags_service.ErrInvalidTagName {
+		slog.ErrorContext(reqCtx, "invalid tag name", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid tag name")
+	} else if err != nil && err == helpers.ErrTagTooLong {
+		slog.ErrorContext(reqCtx, "tag name too long", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "tag names cannot be longer than 60 characters")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error creating new tag", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error creating new tag")
+	}
+
+	return ctx.JSON(http.StatusCreated, createdTag)
+}
+
+func (t *TagsController) UpdateTag(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		req   structures.TagReq
+		tagId int
+		err   error
+	)
+
+	tagId, err = strconv.Atoi(ctx.Param("tag_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad tag id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad tag id in request")
+	}
+
+	err = ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	updatedTag, err := t.TagsService.RenameTag(reqCtx, req, tagId)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "tag not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "tag not found")
+	} else if err != nil && err == tags_service.ErrInvalidTagName {
+		slog.ErrorContext(reqCtx, "invalid tag name", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid tag name")
+	} else if err != nil && err == helpers.ErrTagTooLong {
+		slog.ErrorContext(reqCtx, "tag name too long", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "tag names cannot be longer than 60 characters")
+	} else if err != nil && err == tags_service.ErrTagNameTaken {
+		slog.ErrorContext(reqCtx, "tag name already in use", "error", err)
+		return ctx.JSON(http.StatusConflict, "tag name already in use, merge the tags instead")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error updating tag", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error updating tag")
+	}
+
+	return ctx.JSON(http.StatusOK, updatedTag)
+}
+
+func (t *TagsController) DeleteTag(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		tagId int
+		err   error
+	)
+
+	tagId, err = strconv.Atoi(ctx.Param("tag_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad tag id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad tag id in request")
+	}
+
+	tag, err := t.TagsService.DeleteTag(reqCtx, tagId)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "tag not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "tag not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error deleting tag", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error deleting tag")
+	}
+
+	return ctx.JSON(http.StatusOK, tag)
+}
+
+func (t *TagsController) MergeTag(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		req   structures.TagMergeReq
+		tagId int
+		err   error
+	)
+
+	tagId, err = strconv.Atoi(ctx.Param("tag_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad tag id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad tag id in request")
+	}
+
+	err = ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	mergedTag, err := t.TagsService.MergeTags(reqCtx, tagId, req.TargetId)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "tag not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "tag not found")
+	} else if err != nil && err == tags_service.ErrSelfMerge {
+		slog.ErrorContext(reqCtx, "bad merge target", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "cannot merge a tag into itself")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error merging tags", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error merging tags")
+	}
+
+	return ctx.JSON(http.StatusOK, mergedTag)
+}
+
+func NewTagsController(tagsService *tags_service.TagsService) *TagsController {
+	return &TagsController{
+		TagsService: tagsService,
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"type_writer_api/helpers"
+	local_middleware "type_writer_api/middleware"
 	"type_writer_api/services/texts"
 	"type_writer_api/structures"
 
@@ -18,8 +20,24 @@ type TextsController struct {
 func (t *TextsController) GetTexts(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 
-	texts, err := t.TextsService.GetTexts(reqCtx)
-	if err != nil {
+	viewerId, _ := local_middleware.ContextViewer(ctx)
+
+	texts, err := t.TextsService.GetTexts(reqCtx, structures.TextFilter{
+		Language: ctx.QueryParam("language"),
+		License:  ctx.QueryParam("license"),
+		Sort:     ctx.QueryParam("sort"),
+		ViewerId: viewerId,
+	})
+	if err != nil && err == texts_service.ErrInvalidLanguage {
+		slog.ErrorContext(reqCtx, "invalid text language", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid text language")
+	} else if err != nil && err == texts_service.ErrInvalidLicense {
+		slog.ErrorContext(reqCtx, "invalid text license", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid text license")
+	} else if err != nil && err == texts_service.ErrInvalidSort {
+		slog.ErrorContext(reqCtx, "invalid text sort", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid text sort")
+	} else if err != nil {
 		slog.ErrorContext(reqCtx, "error fetching texts", "error", err)
 		return ctx.JSON(http.StatusInternalServerError, "error fetching texts")
 	}
@@ -40,7 +58,9 @@ func (t *TextsController) GetText(ctx echo.Context) error {
 		title = ctx.Param("text_id")
 	}
 
-	text, err := t.TextsService.GetTextByIdOrTitle(reqCtx, &textId, &title)
+	viewerId, viewerType := local_middleware.ContextViewer(ctx)
+
+	text, err := t.TextsService.GetTextByIdOrTitle(reqCtx, &textId, &title, viewerId, viewerType)
 	if err != nil && err == gorm.ErrRecordNotFound {
 		slog.ErrorContext(reqCtx, "text not found", "error", err)
 		return ctx.JSON(http.StatusNotFound, "text not found")
@@ -52,6 +72,22 @@ func (t *TextsController) GetText(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, text)
 }
 
+func (t *TextsController) GetSharedText(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	viewerId, viewerType := local_middleware.ContextViewer(ctx)
+
+	text, err := t.TextsService.GetTextByShareSlug(reqCtx, ctx.Param("share_slug"), viewerId, viewerType)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "text not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "text not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching shared text", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching shared text")
+	}
+
+	return ctx.JSON(http.StatusOK, text)
+}
+
 func (t *TextsController) CreateText(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 	req := structures.TextReq{}
@@ -62,8 +98,38 @@ func (t *TextsController) CreateText(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
 	}
 
-	createdText, err := t.TextsService.CreateText(reqCtx, req)
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
 	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+
+	createdText, err := t.TextsService.CreateText(reqCtx, req, claims.UserId, claims.UserType)
+	if err != nil && err == texts_service.ErrInvalidTextStatus {
+		slog.ErrorContext(reqCtx, "invalid text status", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid text status")
+	} else if err != nil && err == texts_service.ErrInvalidLanguage {
+		slog.ErrorContext(reqCtx, "invalid text language", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid text language")
+	} else if err != nil && err == texts_service.ErrInvalidVisibility {
+		slog.ErrorContext(reqCtx, "invalid text visibility", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid text visibility")
+	} else if err != nil && err == texts_service.ErrInvalidLicense {
+		slog.ErrorContext(reqCtx, "invalid text license", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid text license")
+	} else if err != nil && err == texts_service.ErrInvalidSourceUrl {
+		slog.ErrorContext(reqCtx, "invalid text source url", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid text source url")
+	} else if err != nil && err == texts_service.ErrUntypeableText {
+		slog.ErrorContext(reqCtx, "untypeable text body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "text contains untypeable characters, check it with /texts/lint")
+	} else if err != nil && err == helpers.ErrTagTooLong {
+		slog.ErrorContext(reqCtx, "tag name too long", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "tag names cannot be longer than 60 characters")
+	} else if err != nil && err == texts_service.ErrDuplicateText {
+		slog.ErrorContext(reqCtx, "duplicate text", "error", err)
+		return ctx.JSON(http.StatusConflict, "text is a near duplicate of an existing text")
+	} else if err != nil {
 		slog.ErrorContext(reqCtx, "error creating new text", "error", err)
 		return ctx.JSON(http.StatusInternalServerError, "error creating new text")
 	}
@@ -91,8 +157,44 @@ func (t *TextsController) UpdateText(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
 	}
 
-	updatedText, err := t.TextsService.UpdateText(reqCtx, req, textId)
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
 	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+
+	updatedText, err := t.TextsService.UpdateText(reqCtx, req, textId, claims.UserId, claims.UserType)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "text not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "text not found")
+	} else if err != nil && err == texts_service.ErrTextNotOwned {
+		slog.ErrorContext(reqCtx, "text not owned by caller", "error", err)
+		return ctx.JSON(http.StatusForbidden, "text belongs to another user")
+	} else if err != nil && err == texts_service.ErrInvalidTextStatus {
+		slog.ErrorContext(reqCtx, "invalid text status", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid text status")
+	} else if err != nil && err == texts_service.ErrInvalidLanguage {
+		slog.ErrorContext(reqCtx, "invalid text language", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid text language")
+	} else if err != nil && err == texts_service.ErrInvalidVisibility {
+		slog.ErrorContext(reqCtx, "invalid text visibility", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid text visibility")
+	} else if err != nil && err == texts_service.ErrInvalidLicense {
+		slog.ErrorContext(reqCtx, "invalid text license", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid text license")
+	} else if err != nil && err == texts_service.ErrInvalidSourceUrl {
+		slog.ErrorContext(reqCtx, "invalid text source url", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid text source url")
+	} else if err != nil && err == texts_service.ErrUntypeableText {
+		slog.ErrorContext(reqCtx, "untypeable text body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "text contains untypeable characters, check it with /texts/lint")
+	} else if err != nil && err == helpers.ErrTagTooLong {
+		slog.ErrorContext(reqCtx, "tag name too long", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "tag names cannot be longer than 60 characters")
+	} else if err != nil && err == texts_service.ErrDuplicateText {
+		slog.ErrorContext(reqCtx, "duplicate text", "error", err)
+		return ctx.JSON(http.StatusConflict, "text is a near duplicate of an existing text")
+	} else if err != nil {
 		slog.ErrorContext(reqCtx, "error updating text", "error", err)
 		return ctx.JSON(http.StatusInternalServerError, "error updating text")
 	}
@@ -113,10 +215,19 @@ func (t *TextsController) DeleteText(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, "bad text id in request")
 	}
 
-	text, err := t.TextsService.DeleteText(reqCtx, textId)
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+
+	text, err := t.TextsService.DeleteText(reqCtx, textId, claims.UserId, claims.UserType)
 	if err != nil && err == gorm.ErrRecordNotFound {
 		slog.ErrorContext(reqCtx, "text not found", "error", err)
 		return ctx.JSON(http.StatusNotFound, "text not found")
+	} else if err != nil && err == texts_service.ErrTextNotOwned {
+		slog.ErrorContext(reqCtx, "text not owned by caller", "error", err)
+		return ctx.JSON(http.StatusForbidden, "text belongs to another user")
 	} else if err != nil {
 		slog.ErrorContext(reqCtx, "error deleting text", "error", err)
 		return ctx.JSON(http.StatusInternalServerError, "error deleting text")
@@ -125,6 +236,276 @@ func (t *TextsController) DeleteText(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, text)
 }
 
+func (t *TextsController) GetModerationTexts(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+
+	texts, err := t.TextsService.GetModerationTexts(reqCtx, ctx.QueryParam("status"))
+	if err != nil && err == texts_service.ErrInvalidTextStatus {
+		slog.ErrorContext(reqCtx, "invalid text status", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "invalid text status")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching moderation texts", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching moderation texts")
+	}
+
+	return ctx.JSON(http.StatusOK, struct{ Texts []*structures.Text `json:"texts"`}{Texts: texts})
+}
+
+func (t *TextsController) ApproveText(ctx echo.Context) error {
+	return t.reviewText(ctx, true)
+}
+
+func (t *TextsController) RejectText(ctx echo.Context) error {
+	return t.reviewText(ctx, false)
+}
+
+func (t *TextsController) reviewText(ctx echo.Context, approved bool) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		req    structures.TextReviewReq
+		textId int
+		err    error
+	)
+
+	textId, err = strconv.Atoi(ctx.Param("text_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad text id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad text id in request")
+	}
+
+	err = ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	reviewedText, err := t.TextsService.ReviewText(reqCtx, req, textId, approved)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "text not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "text not found")
+	} else if err != nil && err == texts_service.ErrTextNotPending {
+		slog.ErrorContext(reqCtx, "text not pending review", "error", err)
+		return ctx.JSON(http.StatusConflict, "text is not pending review")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error reviewing text", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error reviewing text")
+	}
+
+	return ctx.JSON(http.StatusOK, reviewedText)
+}
+
+func (t *TextsController) GetSimilarTexts(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		textId int
+		err    error
+	)
+
+	textId, err = strconv.Atoi(ctx.Param("text_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad text id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad text id in request")
+	}
+
+	viewerId, viewerType := local_middleware.ContextViewer(ctx)
+
+	similar, err := t.TextsService.GetSimilarTexts(reqCtx, textId, viewerId, viewerType)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "text not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "text not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching similar texts", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching similar texts")
+	}
+
+	return ctx.JSON(http.StatusOK, struct {
+		SimilarTexts []*structures.SimilarText `json:"similar_texts"`
+	}{SimilarTexts: similar})
+}
+
+func (t *TextsController) GetLicenses(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+
+	licenses, err := t.TextsService.GetLicenses(reqCtx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching licenses", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching licenses")
+	}
+
+	return ctx.JSON(http.StatusOK, struct{ Licenses []*structures.License `json:"licenses"`}{Licenses: licenses})
+}
+
+func (t *TextsController) RateText(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		req    structures.TextRatingReq
+		textId int
+		err    error
+	)
+
+	textId, err = strconv.Atoi(ctx.Param("text_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad text id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad text id in request")
+	}
+
+	err = ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+
+	rating, err := t.TextsService.RateText(reqCtx, req, textId, claims.UserId, claims.UserType)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "text not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "text not found")
+	} else if err != nil && err == texts_service.ErrInvalidRating {
+		slog.ErrorContext(reqCtx, "invalid text rating", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "rating must be between 1 and 5")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error rating text", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error rating text")
+	}
+
+	return ctx.JSON(http.StatusOK, rating)
+}
+
+func (t *TextsController) DeleteTextRating(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		textId int
+		err    error
+	)
+
+	textId, err = strconv.Atoi(ctx.Param("text_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad text id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad text id in request")
+	}
+
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+
+	deleted, err := t.TextsService.DeleteTextRating(reqCtx, textId, claims.UserId)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error deleting text rating", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error deleting text rating")
+	}
+
+	return ctx.JSON(http.StatusOK, deleted)
+}
+
+func (t *TextsController) FavoriteText(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		textId int
+		err    error
+	)
+
+	textId, err = strconv.Atoi(ctx.Param("text_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad text id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad text id in request")
+	}
+
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+
+	favorited, err := t.TextsService.FavoriteText(reqCtx, textId, claims.UserId, claims.UserType)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		slog.ErrorContext(reqCtx, "text not found", "error", err)
+		return ctx.JSON(http.StatusNotFound, "text not found")
+	} else if err != nil {
+		slog.ErrorContext(reqCtx, "error favoriting text", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error favoriting text")
+	}
+
+	return ctx.JSON(http.StatusOK, favorited)
+}
+
+func (t *TextsController) UnfavoriteText(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		textId int
+		err    error
+	)
+
+	textId, err = strconv.Atoi(ctx.Param("text_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad text id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad text id in request")
+	}
+
+	claims, err := local_middleware.ContextClaimsGetter(ctx)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error reading request claims", "error", err)
+		return ctx.JSON(http.StatusUnauthorized, "error reading request claims")
+	}
+
+	unfavorited, err := t.TextsService.UnfavoriteText(reqCtx, textId, claims.UserId)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error unfavoriting text", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error unfavoriting text")
+	}
+
+	return ctx.JSON(http.StatusOK, unfavorited)
+}
+
+func (t *TextsController) GetUserFavorites(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var (
+		userId int
+		err    error
+	)
+
+	userId, err = strconv.Atoi(ctx.Param("user_id"))
+	if err != nil {
+		slog.ErrorContext(reqCtx, "bad user id in request", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "bad user id in request")
+	}
+
+	viewerId, viewerType := local_middleware.ContextViewer(ctx)
+
+	texts, err := t.TextsService.GetFavoriteTexts(reqCtx, userId, viewerId, viewerType)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error fetching favorite texts", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error fetching favorite texts")
+	}
+
+	return ctx.JSON(http.StatusOK, struct{ Texts []*structures.Text `json:"texts"`}{Texts: texts})
+}
+
+func (t *TextsController) LintText(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	req := structures.TextLintReq{}
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error binding request body", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "error binding request body, incomplete or bad request")
+	}
+
+	result, err := t.TextsService.LintText(reqCtx, req)
+	if err != nil {
+		slog.ErrorContext(reqCtx, "error linting text", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, "error linting text")
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
 func NewTextsController(textsService *texts_service.TextsService) *TextsController {
 	return &TextsController{
 		TextsService: textsService,
@@ -65,7 +65,13 @@ func (u *UsersController) CreateUser(ctx echo.Context) error {
 	}
 
 	createdUser, err := u.UsersService.CreateUser(reqCtx, req)
-	if err != nil {
+	if err != nil && err == users_service.ErrInvalidKeyboardLayout {
+		slog.ErrorContext(reqCtx, "unknown keyboard layout", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "unknown keyboard layout")
+	} else if err != nil && err == users_service.ErrInvalidTimeZone {
+		slog.ErrorContext(reqCtx, "unknown time zone", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "unknown time zone")
+	} else if err != nil {
 		slog.ErrorContext(reqCtx, "error creating new user", "error", err)
 		return ctx.JSON(http.StatusInternalServerError, "error creating new user")
 	}
@@ -94,7 +100,13 @@ func (u *UsersController) UpdateUser(ctx echo.Context) error {
 	}
 
 	updatedUser, err := u.UsersService.UpdateUser(reqCtx, req, userId)
-	if err != nil {
+	if err != nil && err == users_service.ErrInvalidKeyboardLayout {
+		slog.ErrorContext(reqCtx, "unknown keyboard layout", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "unknown keyboard layout")
+	} else if err != nil && err == users_service.ErrInvalidTimeZone {
+		slog.ErrorContext(reqCtx, "unknown time zone", "error", err)
+		return ctx.JSON(http.StatusBadRequest, "unknown time zone")
+	} else if err != nil {
 		slog.ErrorContext(reqCtx, "error updating user", "error", err)
 		return ctx.JSON(http.StatusInternalServerError, "error updating user")
 	}
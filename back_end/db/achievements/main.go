@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"type_writer_api/engines"
+	"type_writer_api/providers/achievements"
+	"type_writer_api/providers/texts"
+	"type_writer_api/providers/users"
+	"type_writer_api/services/achievements"
+	"type_writer_api/structures"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Awards the badges of the config to every ranked score stored so far, badges
+// already earned are kept
+func main() {
+	DB_USER := os.Getenv("DB_USER")
+	DB_PASS := os.Getenv("DB_PASS")
+	DB_NAME := os.Getenv("DB_NAME")
+	DB_PORT := os.Getenv("DB_PORT")
+
+	if DB_USER == "" || DB_PASS == "" || DB_NAME == "" || DB_PORT == "" {
+		log.Fatalf("Missing one or more environment variables: %v - %v - %v - %v", DB_USER, DB_PASS, DB_NAME, DB_PORT)
+	}
+
+	badges, err := os.ReadFile(structures.BADGES_CONFIG_PATH)
+	if err != nil {
+		log.Fatal("Error reading badges config ", err)
+	}
+	achievementRules, err := engines.ParseAchievementRules(badges)
+	if err != nil {
+		log.Fatal("Error parsing badges config ", err)
+	}
+
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN: fmt.Sprintf("host=type_writer-db-1 user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC", DB_USER, DB_PASS, DB_NAME, DB_PORT),
+	}), &gorm.Config{})
+	if err != nil {
+		log.Fatal("Error starting db connection for achievements ", err)
+	}
+
+	achievementsService := achievements_service.NewAchievementsService(
+		achievements_provider.NewAchievementsProvider(db),
+		users_provider.NewUsersProvider(db),
+		texts_provider.NewTextsProvider(db),
+		achievementRules,
+	)
+	awarded, err := achievementsService.BackfillAchievements(context.Background())
+	if err != nil {
+		log.Fatal("Error backfilling achievements ", err)
+	}
+	log.Printf("Achievements backfilled, %d awarded", awarded)
+}
@@ -0,0 +1,126 @@
+package engines
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+	"type_writer_api/structures"
+
+	"github.com/casbin/govaluate"
+)
+
+var ErrInvalidBadge = errors.New("invalid badge definition")
+
+// AchievementRules are the compiled badge definitions scores are checked
+// against, in the order they were defined
+type AchievementRules struct {
+	badges     []*structures.Badge
+	conditions []*govaluate.EvaluableExpression
+}
+
+func isAchievementMetric(name string) bool {
+	return isScoringMetric(name) || slices.Contains(structures.ACHIEVEMENT_METRICS, name)
+}
+
+// ParseBadgeCondition compiles the condition of a badge, it takes the same
+// expressions scoring formulas do but has to come out true or false
+func ParseBadgeCondition(condition string) (*govaluate.EvaluableExpression, error) {
+	if len(condition) > structures.MAX_BADGE_CONDITION_LENGTH {
+		return nil, ErrInvalidBadge
+	}
+
+	expression, err := govaluate.NewEvaluableExpressionWithFunctions(condition, formulaFunctions)
+	if err != nil {
+		return nil, ErrInvalidBadge
+	}
+
+	for _, token := range expression.Tokens() {
+		if !formulaTokens[token.Kind] {
+			return nil, ErrInvalidBadge
+		}
+	}
+	sample := map[string]any{}
+	for _, variable := range expression.Vars() {
+		if !isAchievementMetric(variable) {
+			return nil, ErrInvalidBadge
+		}
+		sample[variable] = 1.0
+	}
+	if value, err := expression.Evaluate(sample); err != nil {
+		return nil, ErrInvalidBadge
+	} else if _, ok := value.(bool); !ok {
+		return nil, ErrInvalidBadge
+	}
+
+	return expression, nil
+}
+
+// ParseAchievementRules reads the badge definitions out of the config, every
+// badge needs a unique id, a name and a valid condition
+func ParseAchievementRules(config []byte) (*AchievementRules, error) {
+	badges := []*structures.Badge{}
+	decoder := json.NewDecoder(bytes.NewReader(config))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&badges); err != nil {
+		return nil, err
+	}
+	return NewAchievementRules(badges)
+}
+
+func NewAchievementRules(badges []*structures.Badge) (*AchievementRules, error) {
+	rules := &AchievementRules{}
+	seen := map[string]bool{}
+	for _, badge := range badges {
+		if badge == nil || badge.Id == "" || badge.Name == "" || seen[badge.Id] {
+			return nil, ErrInvalidBadge
+		}
+		seen[badge.Id] = true
+
+		condition, err := ParseBadgeCondition(badge.Condition)
+		if err != nil {
+			return nil, err
+		}
+		rules.badges = append(rules.badges, badge)
+		rules.conditions = append(rules.conditions, condition)
+	}
+	return rules, nil
+}
+
+func (r *AchievementRules) Badges() []*structures.Badge {
+	return r.badges
+}
+
+// AchievementMetrics are the values badge conditions are evaluated over for
+// a score, texts of unknown difficulty have a difficulty of 0
+func AchievementMetrics(score *structures.Score, standing structures.ScoreStanding) map[string]float64 {
+	metrics := ResultMetrics(score.Result, score.Duration)
+	metrics["total_scores"] = float64(standing.TotalScores)
+	metrics["streak_days"] = float64(standing.StreakDays)
+	metrics["difficulty"] = structures.TEXT_DIFFICULTY_LEVELS[standing.Difficulty]
+	return metrics
+}
+
+// Award lists the badges a score earns, badges the user already has are left
+// out so awarding the same score twice gives nothing new
+func (r *AchievementRules) Award(score *structures.Score, standing structures.ScoreStanding, earned map[string]bool, awardedAt time.Time) []*structures.UserAchievement {
+	parameters := map[string]any{}
+	for metric, value := range AchievementMetrics(score, standing) {
+		parameters[metric] = value
+	}
+
+	awarded := []*structures.UserAchievement{}
+	for idx, badge := range r.badges {
+		if earned[badge.Id] {
+			continue
+		}
+		value, err := r.conditions[idx].Evaluate(parameters)
+		if met, ok := value.(bool); err != nil || !ok || !met {
+			continue
+		}
+		scoreId := score.Id
+		awarded = append(awarded, &structures.UserAchievement{UserId: score.UserId, BadgeId: badge.Id, ScoreId: &scoreId, AwardedAt: awardedAt})
+	}
+	return awarded
+}
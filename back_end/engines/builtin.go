@@ -0,0 +1,142 @@
+package engines
+
+import (
+	"type_writer_api/structures"
+)
+
+// TimedTestEngine types for a fixed time, the fastest accurate typist wins
+type TimedTestEngine struct{}
+
+func (e *TimedTestEngine) Kind() string {
+	return structures.ACTIVITY_KIND_TIMED_TEST
+}
+
+func (e *TimedTestEngine) ValidateRules(rules structures.ActivityRules) error {
+	if rules.TimeLimit <= 0 || rules.WordTarget > 0 {
+		return ErrInvalidRules
+	}
+	return nil
+}
+
+func (e *TimedTestEngine) ComputeResult(rules structures.ActivityRules, input structures.ActivityInput) (*structures.ActivityResult, error) {
+	p, err := play(rules, input)
+	if err != nil {
+		return nil, err
+	}
+	// the clock keeps running until the limit unless the text ran out first
+	if !p.finished() {
+		p.duration = rules.TimeLimit * 1000
+	}
+	return p.result(rules), nil
+}
+
+func (e *TimedTestEngine) RankResults(results []*structures.ActivityResult) {
+	rankBy(results, func(a, b *structures.ActivityResult) bool {
+		if a.Wpm != b.Wpm {
+			return a.Wpm > b.Wpm
+		}
+		return a.Accuracy > b.Accuracy
+	})
+}
+
+// WordRaceEngine types a number of words, the quickest to get there wins
+type WordRaceEngine struct{}
+
+func (e *WordRaceEngine) Kind() string {
+	return structures.ACTIVITY_KIND_WORD_RACE
+}
+
+func (e *WordRaceEngine) ValidateRules(rules structures.ActivityRules) error {
+	if rules.WordTarget <= 0 || rules.TimeLimit > 0 {
+		return ErrInvalidRules
+	}
+	return nil
+}
+
+func (e *WordRaceEngine) ComputeResult(rules structures.ActivityRules, input structures.ActivityInput) (*structures.ActivityResult, error) {
+	p, err := play(rules, input)
+	if err != nil {
+		return nil, err
+	}
+	result := p.result(rules)
+	// texts shorter than the target are raced in full
+	target := min(rules.WordTarget, len(p.wordEnds))
+	result.Completed = result.Words >= target
+	result.Passed = result.Passed && result.Completed
+	return result, nil
+}
+
+func (e *WordRaceEngine) RankResults(results []*structures.ActivityResult) {
+	rankBy(results, func(a, b *structures.ActivityResult) bool {
+		if a.Completed != b.Completed {
+			return a.Completed
+		}
+		if a.Completed && a.Duration != b.Duration {
+			return a.Duration < b.Duration
+		}
+		if a.Words != b.Words {
+			return a.Words > b.Words
+		}
+		return a.Accuracy > b.Accuracy
+	})
+}
+
+// SuddenDeathEngine ends the test on the first error, the furthest typist
+// wins
+type SuddenDeathEngine struct{}
+
+func (e *SuddenDeathEngine) Kind() string {
+	return structures.ACTIVITY_KIND_SUDDEN_DEATH
+}
+
+func (e *SuddenDeathEngine) ValidateRules(rules structures.ActivityRules) error {
+	if !rules.StopOnError {
+		return ErrInvalidRules
+	}
+	return nil
+}
+
+func (e *SuddenDeathEngine) ComputeResult(rules structures.ActivityRules, input structures.ActivityInput) (*structures.ActivityResult, error) {
+	p, err := play(rules, input)
+	if err != nil {
+		return nil, err
+	}
+	result := p.result(rules)
+	result.Passed = result.Passed && result.Errors == 0
+	return result, nil
+}
+
+func (e *SuddenDeathEngine) RankResults(results []*structures.ActivityResult) {
+	rankBy(results, func(a, b *structures.ActivityResult) bool {
+		if a.Letters != b.Letters {
+			return a.Letters > b.Letters
+		}
+		return a.Wpm > b.Wpm
+	})
+}
+
+// ZenEngine has no clock and no target, the text is typed at leisure and
+// results are not compared
+type ZenEngine struct{}
+
+func (e *ZenEngine) Kind() string {
+	return structures.ACTIVITY_KIND_ZEN
+}
+
+func (e *ZenEngine) ValidateRules(rules structures.ActivityRules) error {
+	if rules.TimeLimit > 0 || rules.WordTarget > 0 || rules.StopOnError {
+		return ErrInvalidRules
+	}
+	return nil
+}
+
+func (e *ZenEngine) ComputeResult(rules structures.ActivityRules, input structures.ActivityInput) (*structures.ActivityResult, error) {
+	p, err := play(rules, input)
+	if err != nil {
+		return nil, err
+	}
+	return p.result(rules), nil
+}
+
+// RankResults keeps zen results in the order they were given
+func (e *ZenEngine) RankResults(results []*structures.ActivityResult) {}
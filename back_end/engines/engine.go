@@ -0,0 +1,65 @@
+package engines
+
+import (
+	"errors"
+	"sort"
+	"type_writer_api/structures"
+)
+
+var (
+	ErrInvalidRules       = errors.New("rules do not fit the activity kind")
+	ErrInvalidKeystrokes  = errors.New("invalid keystrokes")
+	ErrTextTypeNotAllowed = errors.New("text type not allowed by the activity")
+)
+
+// ActivityEngine runs one kind of activity, it decides which rule sets make
+// sense for the kind, turns a typing input into a result and orders results
+// from best to worst
+type ActivityEngine interface {
+	Kind() string
+	ValidateRules(rules structures.ActivityRules) error
+	ComputeResult(rules structures.ActivityRules, input structures.ActivityInput) (*structures.ActivityResult, error)
+	RankResults(results []*structures.ActivityResult)
+}
+
+var registry = map[string]ActivityEngine{}
+
+func init() {
+	Register(&TimedTestEngine{})
+	Register(&WordRaceEngine{})
+	Register(&SuddenDeathEngine{})
+	Register(&ZenEngine{})
+}
+
+// Register adds an engine to the registry, replacing the one registered for
+// the same kind
+func Register(engine ActivityEngine) {
+	registry[engine.Kind()] = engine
+}
+
+// ByKind returns the engine registered for an activity kind
+func ByKind(kind string) (ActivityEngine, bool) {
+	engine, ok := registry[kind]
+	return engine, ok
+}
+
+// Kinds lists every registered activity kind in alphabetical order
+func Kinds() []string {
+	kinds := make([]string, 0, len(registry))
+	for kind := range registry {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// rankBy sorts results best first, results that passed always rank above
+// the ones that did not
+func rankBy(results []*structures.ActivityResult, better func(a, b *structures.ActivityResult) bool) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Passed != results[j].Passed {
+			return results[i].Passed
+		}
+		return better(results[i], results[j])
+	})
+}
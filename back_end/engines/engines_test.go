@@ -0,0 +1,499 @@
+package engines
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"type_writer_api/helpers"
+	"type_writer_api/structures"
+)
+
+// typeKeys presses every key in order with a fixed interval between them
+func typeKeys(keys []string, interval int) []*structures.Keystroke {
+	keystrokes := make([]*structures.Keystroke, 0, len(keys))
+	for idx, key := range keys {
+		keystrokes = append(keystrokes, &structures.Keystroke{Key: key, Offset: (idx + 1) * interval})
+	}
+	return keystrokes
+}
+
+func typeText(text string, interval int) []*structures.Keystroke {
+	var keys []string
+	for _, char := range text {
+		keys = append(keys, string(char))
+	}
+	return typeKeys(keys, interval)
+}
+
+func TestComputeResult(t *testing.T) {
+	const text = "the quick brown fox"
+
+	data := []struct {
+		testName       string
+		kind           string
+		rules          structures.ActivityRules
+		input          structures.ActivityInput
+		expectedResult structures.ActivityResult
+		expectedErr    error
+	}{
+		{
+			testName:       "timed test finished before the clock",
+			kind:           structures.ACTIVITY_KIND_TIMED_TEST,
+			rules:          structures.ActivityRules{TimeLimit: 60},
+			input:          structures.ActivityInput{TextBody: text, Keystrokes: typeText(text, 100)},
+			expectedResult: structures.ActivityResult{Wpm: 120, RawWpm: 120, Cpm: 600, Accuracy: 100, Consistency: 100, Words: 4, Letters: 19, Duration: 1900, Completed: true, Passed: true},
+		},
+		{
+			testName:       "timed test cut by the clock",
+			kind:           structures.ACTIVITY_KIND_TIMED_TEST,
+			rules:          structures.ActivityRules{TimeLimit: 1},
+			input:          structures.ActivityInput{TextBody: text, Keystrokes: typeText(text, 100)},
+			expectedResult: structures.ActivityResult{Wpm: 120, RawWpm: 120, Cpm: 600, Accuracy: 100, Consistency: 100, Words: 2, Letters: 10, Duration: 1000, Passed: true},
+		},
+		{
+			testName:       "timed test that stopped typing runs out the clock",
+			kind:           structures.ACTIVITY_KIND_TIMED_TEST,
+			rules:          structures.ActivityRules{TimeLimit: 2},
+			input:          structures.ActivityInput{TextBody: text, Keystrokes: typeText("the", 100)},
+			expectedResult: structures.ActivityResult{Wpm: 18, RawWpm: 18, Cpm: 90, Accuracy: 100, Consistency: 100, Words: 1, Letters: 3, Duration: 2000, Passed: true},
+		},
+		{
+			testName:       "word race stops at the target",
+			kind:           structures.ACTIVITY_KIND_WORD_RACE,
+			rules:          structures.ActivityRules{WordTarget: 2},
+			input:          structures.ActivityInput{TextBody: text, Keystrokes: typeText(text, 100)},
+			expectedResult: structures.ActivityResult{Wpm: 120, RawWpm: 120, Cpm: 600, Accuracy: 100, Consistency: 100, Words: 2, Letters: 9, Duration: 900, Completed: true, Passed: true},
+		},
+		{
+			testName:       "word race short of the target",
+			kind:           structures.ACTIVITY_KIND_WORD_RACE,
+			rules:          structures.ActivityRules{WordTarget: 3},
+			input:          structures.ActivityInput{TextBody: text, Keystrokes: typeText("the qu", 100)},
+			expectedResult: structures.ActivityResult{Wpm: 120, RawWpm: 120, Cpm: 600, Accuracy: 100, Consistency: 100, Words: 1, Letters: 6, Duration: 600},
+		},
+		{
+			testName:       "sudden death ends on the first error",
+			kind:           structures.ACTIVITY_KIND_SUDDEN_DEATH,
+			rules:          structures.ActivityRules{StopOnError: true},
+			input:          structures.ActivityInput{TextBody: text, Keystrokes: typeText("thx quick", 100)},
+			expectedResult: structures.ActivityResult{Wpm: 80, RawWpm: 120, Cpm: 400, Accuracy: 66.67, Errors: 1, Consistency: 100, Letters: 2, Duration: 300},
+		},
+		{
+			testName:       "corrections count against accuracy",
+			kind:           structures.ACTIVITY_KIND_ZEN,
+			rules:          structures.ActivityRules{AccuracyThreshold: 80, MustCorrectErrors: true},
+			input:          structures.ActivityInput{TextBody: "the", Keystrokes: typeKeys([]string{"t", "h", "a", structures.KEY_BACKSPACE, "e"}, 100)},
+			expectedResult: structures.ActivityResult{Wpm: 72, RawWpm: 96, Cpm: 360, Accuracy: 75, Errors: 1, Corrected: 1, Consistency: 64.64, Words: 1, Letters: 3, Duration: 500, Completed: true},
+		},
+		{
+			testName:       "a wrong last letter still ends the text",
+			kind:           structures.ACTIVITY_KIND_ZEN,
+			input:          structures.ActivityInput{TextBody: "the", Keystrokes: typeText("thx", 100)},
+			expectedResult: structures.ActivityResult{Wpm: 80, RawWpm: 120, Cpm: 400, Accuracy: 66.67, Errors: 1, Consistency: 100, Letters: 2, Duration: 300, Completed: true, Passed: true},
+		},
+		{
+			testName:       "uncorrected errors leave the text unfinished",
+			kind:           structures.ACTIVITY_KIND_ZEN,
+			rules:          structures.ActivityRules{MustCorrectErrors: true},
+			input:          structures.ActivityInput{TextBody: "the", Keystrokes: typeKeys([]string{"t", "h", "x", "Shift"}, 100)},
+			expectedResult: structures.ActivityResult{Wpm: 60, RawWpm: 90, Cpm: 300, Accuracy: 66.67, Errors: 1, Consistency: 100, Letters: 2, Duration: 400, Passed: true},
+		},
+		{
+			testName:    "keystrokes out of order",
+			kind:        structures.ACTIVITY_KIND_ZEN,
+			input:       structures.ActivityInput{TextBody: "the", Keystrokes: []*structures.Keystroke{{Key: "t", Offset: 200}, {Key: "h", Offset: 100}}},
+			expectedErr: ErrInvalidKeystrokes,
+		},
+		{
+			testName:    "text type not allowed",
+			kind:        structures.ACTIVITY_KIND_ZEN,
+			rules:       structures.ActivityRules{AllowedTextTypes: []string{structures.TEXT_TYPE_DRILL}},
+			input:       structures.ActivityInput{TextType: structures.TEXT_TYPE_FULL_TEXT, TextBody: "the"},
+			expectedErr: ErrTextTypeNotAllowed,
+		},
+	}
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			engine, ok := ByKind(testCase.kind)
+			if !ok {
+				t.Fatalf("no engine registered for %v", testCase.kind)
+			}
+
+			result, err := engine.ComputeResult(testCase.rules, testCase.input)
+
+			if testCase.expectedErr != nil {
+				if err != testCase.expectedErr {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err := helpers.CompareReflectedStructFields(*result, testCase.expectedResult); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestRankResults(t *testing.T) {
+	data := []struct {
+		testName    string
+		kind        string
+		results     []*structures.ActivityResult
+		expectedWpm []float64
+	}{
+		{
+			testName: "timed test ranks by wpm then accuracy",
+			kind:     structures.ACTIVITY_KIND_TIMED_TEST,
+			results: []*structures.ActivityResult{
+				{Wpm: 80, Accuracy: 90, Passed: true},
+				{Wpm: 120, Accuracy: 80, Passed: false},
+				{Wpm: 80, Accuracy: 99, Passed: true},
+				{Wpm: 100, Accuracy: 95, Passed: true},
+			},
+			expectedWpm: []float64{100, 80, 80, 120},
+		},
+		{
+			testName: "word race ranks finished runs by time",
+			kind:     structures.ACTIVITY_KIND_WORD_RACE,
+			results: []*structures.ActivityResult{
+				{Wpm: 1, Words: 8, Passed: true},
+				{Wpm: 2, Words: 10, Duration: 9000, Completed: true, Passed: true},
+				{Wpm: 3, Words: 10, Duration: 7000, Completed: true, Passed: true},
+			},
+			expectedWpm: []float64{3, 2, 1},
+		},
+		{
+			testName: "sudden death ranks by how far the run got",
+			kind:     structures.ACTIVITY_KIND_SUDDEN_DEATH,
+			results: []*structures.ActivityResult{
+				{Wpm: 1, Letters: 40},
+				{Wpm: 2, Letters: 80},
+				{Wpm: 3, Letters: 80},
+			},
+			expectedWpm: []float64{3, 2, 1},
+		},
+		{
+			testName: "zen keeps the given order",
+			kind:     structures.ACTIVITY_KIND_ZEN,
+			results: []*structures.ActivityResult{
+				{Wpm: 1},
+				{Wpm: 3, Passed: true},
+				{Wpm: 2},
+			},
+			expectedWpm: []float64{1, 3, 2},
+		},
+	}
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			engine, _ := ByKind(testCase.kind)
+			engine.RankResults(testCase.results)
+
+			for idx, result := range testCase.results {
+				if result.Wpm != testCase.expectedWpm[idx] {
+					t.Fatalf("unexpected rank %v: got wpm %v, expected %v", idx, result.Wpm, testCase.expectedWpm[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestKinds(t *testing.T) {
+	kinds := Kinds()
+	expected := []string{
+		structures.ACTIVITY_KIND_SUDDEN_DEATH,
+		structures.ACTIVITY_KIND_TIMED_TEST,
+		structures.ACTIVITY_KIND_WORD_RACE,
+		structures.ACTIVITY_KIND_ZEN,
+	}
+	if len(kinds) != len(expected) {
+		t.Fatalf("unexpected kinds %v", kinds)
+	}
+	for idx, kind := range kinds {
+		if kind != expected[idx] {
+			t.Fatalf("unexpected kinds %v", kinds)
+		}
+	}
+}
+
+func TestParseFormula(t *testing.T) {
+	data := []struct {
+		testName    string
+		formula     string
+		expectedErr error
+	}{
+		{testName: "single metric", formula: "wpm"},
+		{testName: "wpm times accuracy squared", formula: "wpm * (accuracy / 100) ** 2"},
+		{testName: "functions", formula: "max(0, round(wpm - abs(errors - corrected)))"},
+		{testName: "ternary", formula: "accuracy >= 95 ? wpm : wpm / 2"},
+		{testName: "empty", formula: "", expectedErr: ErrInvalidFormula},
+		{testName: "syntax error", formula: "wpm *", expectedErr: ErrInvalidFormula},
+		{testName: "unknown metric", formula: "speed * 2", expectedErr: ErrInvalidFormula},
+		{testName: "unknown function", formula: "sqrt(wpm)", expectedErr: ErrInvalidFormula},
+		{testName: "strings", formula: "wpm + 'fast'", expectedErr: ErrInvalidFormula},
+		{testName: "patterns", formula: "'wpm' =~ 'w.*'", expectedErr: ErrInvalidFormula},
+		{testName: "not a number", formula: "wpm > 60", expectedErr: ErrInvalidFormula},
+		{testName: "division by zero errors", formula: "wpm / errors", expectedErr: ErrInvalidFormula},
+		{testName: "ternary without else", formula: "wpm < 60 ? 100", expectedErr: ErrInvalidFormula},
+		{testName: "overflows", formula: "wpm ** 400", expectedErr: ErrInvalidFormula},
+		{testName: "guarded division", formula: "errors > 0 ? wpm / errors : wpm"},
+		{testName: "too long", formula: "wpm" + strings.Repeat(" + wpm", structures.MAX_SCORING_FORMULA_LENGTH/6), expectedErr: ErrInvalidFormula},
+	}
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			_, err := ParseFormula(testCase.formula)
+			if err != testCase.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestFinalScore(t *testing.T) {
+	metrics := map[string]float64{"wpm": 60, "accuracy": 90, "errors": 0}
+
+	score, err := FinalScore("wpm * accuracy / 100", metrics)
+	if err != nil || score != 54 {
+		t.Fatalf("unexpected final score %v, error %v", score, err)
+	}
+	score, err = FinalScore("wpm / errors", metrics)
+	if err != ErrInvalidFormula || score != 60 {
+		t.Fatalf("expected the default formula to score %v, got %v with error %v", 60, score, err)
+	}
+}
+
+func TestResultMetrics(t *testing.T) {
+	metrics := ResultMetrics(structures.ScoreResult{Version: 1, Wpm: 80, Accuracy: 97.5, Errors: 2, Extra: map[string]any{"mode": "words"}}, 60)
+
+	if len(metrics) != len(structures.SCORING_METRICS) {
+		t.Fatalf("expected every scoring metric, got %v", metrics)
+	}
+	expected := map[string]float64{"wpm": 80, "accuracy": 97.5, "errors": 2, "letters": 0, "duration": 60}
+	for metric, value := range expected {
+		if metrics[metric] != value {
+			t.Fatalf("unexpected metrics %v", metrics)
+		}
+	}
+}
+
+func TestKeystrokeEvents(t *testing.T) {
+	input := structures.ActivityInput{
+		TextBody:   "hi",
+		Keystrokes: typeKeys([]string{"Shift", "h", "u", structures.KEY_BACKSPACE, "i", "!"}, 100),
+	}
+
+	events, err := KeystrokeEvents(structures.ActivityRules{MustCorrectErrors: true}, input)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// the shift press changes nothing and the replay stops once the text is done
+	// and corrected
+	expected := []structures.KeystrokeEvent{
+		{Key: "h", Offset: 200, Expected: "h", Correct: true},
+		{Key: "u", Offset: 300, Expected: "i"},
+		{Key: structures.KEY_BACKSPACE, Offset: 400, Backspace: true},
+		{Key: "i", Offset: 500, Expected: "i", Correct: true},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("unexpected events length: expected %v, got %v", len(expected), len(events))
+	}
+	for idx, event := range events {
+		if *event != expected[idx] {
+			t.Fatalf("event %v: expected %+v, got %+v", idx, expected[idx], *event)
+		}
+	}
+
+	_, err = KeystrokeEvents(structures.ActivityRules{AllowedTextTypes: []string{structures.TEXT_TYPE_DRILL}}, input)
+	if err != ErrTextTypeNotAllowed {
+		t.Fatalf("expected error: %v but got %v instead", ErrTextTypeNotAllowed, err)
+	}
+}
+
+func TestCheckPlausibility(t *testing.T) {
+	uneven := []*structures.Keystroke{}
+	offset := 0
+	for idx, char := range strings.Repeat("uneven rhythm ", 5) {
+		offset += 5
+		if idx%2 == 0 {
+			offset += 295
+		}
+		uneven = append(uneven, &structures.Keystroke{Key: string(char), Offset: offset})
+	}
+
+	pasted := typeText(strings.Repeat("typed ", 20), 200)
+	for idx, char := range "pasted text" {
+		pasted = append(pasted, &structures.Keystroke{Key: string(char), Offset: 24200 + idx*2})
+	}
+
+	data := []struct {
+		testName   string
+		keystrokes []*structures.Keystroke
+		expected   []string
+	}{
+		{
+			testName:   "steady typist",
+			keystrokes: typeText(strings.Repeat("the quick brown fox ", 20), 120),
+			expected:   []string{},
+		},
+		{
+			testName:   "faster than anyone for a whole window",
+			keystrokes: typeText(strings.Repeat("the quick brown fox ", 20), 40),
+			expected:   []string{structures.SCORE_FLAG_SUSTAINED_WPM},
+		},
+		{
+			testName:   "half the keys pressed together",
+			keystrokes: uneven,
+			expected:   []string{structures.SCORE_FLAG_KEY_INTERVALS},
+		},
+		{
+			testName:   "text pasted at the end",
+			keystrokes: pasted,
+			expected:   []string{structures.SCORE_FLAG_PASTE_BURST},
+		},
+		{
+			testName:   "too short to tell",
+			keystrokes: typeText("hi", 1),
+			expected:   []string{},
+		},
+	}
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			reasons := CheckPlausibility(testCase.keystrokes)
+			if strings.Join(reasons, ",") != strings.Join(testCase.expected, ",") {
+				t.Fatalf("expected reasons %v, got %v", testCase.expected, reasons)
+			}
+		})
+	}
+}
+
+func TestResultMatches(t *testing.T) {
+	computed := &structures.ActivityResult{Wpm: 80, Accuracy: 95.5, Errors: 2}
+
+	data := []struct {
+		testName string
+		claimed  structures.ScoreResult
+		expected bool
+	}{
+		{testName: "same result", claimed: structures.ScoreResult{Wpm: 80, Accuracy: 95.5, Errors: 2}, expected: true},
+		{testName: "within the tolerances", claimed: structures.ScoreResult{Wpm: 83, Accuracy: 96, Errors: 2}, expected: true},
+		{testName: "wpm too high", claimed: structures.ScoreResult{Wpm: 300, Accuracy: 95.5, Errors: 2}, expected: false},
+		{testName: "accuracy too high", claimed: structures.ScoreResult{Wpm: 80, Accuracy: 100, Errors: 2}, expected: false},
+		{testName: "errors left out", claimed: structures.ScoreResult{Wpm: 80, Accuracy: 95.5}, expected: false},
+	}
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			if matches := ResultMatches(testCase.claimed, computed); matches != testCase.expected {
+				t.Fatalf("expected %v, got %v", testCase.expected, matches)
+			}
+		})
+	}
+}
+
+func TestScoreResult(t *testing.T) {
+	result := ScoreResult(&structures.ActivityResult{Wpm: 80, RawWpm: 85, Cpm: 400, Accuracy: 95.5, Errors: 2, Corrected: 1, Consistency: 88, Words: 12, Letters: 60, Duration: 9000, Completed: true})
+
+	expected := structures.ScoreResult{Version: structures.SCORE_RESULT_VERSION, Wpm: 80, RawWpm: 85, Cpm: 400, Accuracy: 95.5, Errors: 2, Corrected: 1, Consistency: 88, Words: 12, Letters: 60}
+	if err := helpers.CompareReflectedStructFields(result, expected); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseBadgeCondition(t *testing.T) {
+	data := []struct {
+		testName    string
+		condition   string
+		expectedErr error
+	}{
+		{testName: "scoring metric", condition: "wpm >= 60"},
+		{testName: "achievement metrics", condition: "total_scores >= 100 || streak_days >= 7"},
+		{testName: "mixed", condition: "accuracy == 100 && difficulty == 3"},
+		{testName: "empty", condition: "", expectedErr: ErrInvalidBadge},
+		{testName: "not a condition", condition: "wpm * 2", expectedErr: ErrInvalidBadge},
+		{testName: "unknown metric", condition: "level > 3", expectedErr: ErrInvalidBadge},
+		{testName: "strings", condition: "difficulty == 'hard'", expectedErr: ErrInvalidBadge},
+		{testName: "too long", condition: "wpm > 1" + strings.Repeat(" && wpm > 1", structures.MAX_BADGE_CONDITION_LENGTH/10), expectedErr: ErrInvalidBadge},
+	}
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			_, err := ParseBadgeCondition(testCase.condition)
+			if err != testCase.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestParseAchievementRules(t *testing.T) {
+	data := []struct {
+		testName    string
+		config      string
+		expectedLen int
+		expectErr   bool
+	}{
+		{testName: "badges", config: `[{"id": "a", "name": "A", "condition": "wpm > 1"}, {"id": "b", "name": "B", "condition": "streak_days > 1"}]`, expectedLen: 2},
+		{testName: "no badges", config: `[]`},
+		{testName: "duplicate id", config: `[{"id": "a", "name": "A", "condition": "wpm > 1"}, {"id": "a", "name": "B", "condition": "wpm > 2"}]`, expectErr: true},
+		{testName: "missing name", config: `[{"id": "a", "condition": "wpm > 1"}]`, expectErr: true},
+		{testName: "unknown field", config: `[{"id": "a", "name": "A", "condition": "wpm > 1", "points": 10}]`, expectErr: true},
+		{testName: "invalid condition", config: `[{"id": "a", "name": "A", "condition": "wpm"}]`, expectErr: true},
+	}
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			rules, err := ParseAchievementRules([]byte(testCase.config))
+			if (err != nil) != testCase.expectErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err == nil && len(rules.Badges()) != testCase.expectedLen {
+				t.Fatalf("expected %d badges, got %v", testCase.expectedLen, rules.Badges())
+			}
+		})
+	}
+}
+
+func TestAward(t *testing.T) {
+	rules, err := NewAchievementRules([]*structures.Badge{
+		{Id: "first_60_wpm", Name: "Sixty", Condition: "wpm >= 60"},
+		{Id: "100_tests", Name: "Centurion", Condition: "total_scores >= 100"},
+		{Id: "flawless_hard_text", Name: "Flawless", Condition: "accuracy == 100 && difficulty == 3"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	awardedAt := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	score := &structures.Score{Id: 7, UserId: 2, Result: structures.ScoreResult{Wpm: 64, Accuracy: 100}}
+
+	data := []struct {
+		testName string
+		standing structures.ScoreStanding
+		earned   map[string]bool
+		expected []string
+	}{
+		{testName: "hard text", standing: structures.ScoreStanding{TotalScores: 100, Difficulty: "hard"}, expected: []string{"first_60_wpm", "100_tests", "flawless_hard_text"}},
+		{testName: "normal text", standing: structures.ScoreStanding{TotalScores: 99, Difficulty: "normal"}, expected: []string{"first_60_wpm"}},
+		{testName: "already earned", standing: structures.ScoreStanding{TotalScores: 100}, earned: map[string]bool{"first_60_wpm": true}, expected: []string{"100_tests"}},
+	}
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			awarded := rules.Award(score, testCase.standing, testCase.earned, awardedAt)
+			if len(awarded) != len(testCase.expected) {
+				t.Fatalf("expected badges %v, got %v", testCase.expected, awarded)
+			}
+			for idx, achievement := range awarded {
+				if achievement.BadgeId != testCase.expected[idx] || achievement.UserId != 2 || *achievement.ScoreId != 7 || !achievement.AwardedAt.Equal(awardedAt) {
+					t.Fatalf("unexpected achievement %+v", achievement)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package engines
+
+import (
+	"errors"
+	"math"
+	"type_writer_api/structures"
+
+	"github.com/casbin/govaluate"
+)
+
+var ErrInvalidFormula = errors.New("invalid scoring formula")
+
+// formulaTokens are the only parts of the expression language a scoring
+// formula may use, strings, patterns and accessors are left out so formulas
+// stay plain arithmetic over the result metrics
+var formulaTokens = map[govaluate.TokenKind]bool{
+	govaluate.PREFIX:       true,
+	govaluate.NUMERIC:      true,
+	govaluate.BOOLEAN:      true,
+	govaluate.VARIABLE:     true,
+	govaluate.FUNCTION:     true,
+	govaluate.SEPARATOR:    true,
+	govaluate.COMPARATOR:   true,
+	govaluate.LOGICALOP:    true,
+	govaluate.MODIFIER:     true,
+	govaluate.CLAUSE:       true,
+	govaluate.CLAUSE_CLOSE: true,
+	govaluate.TERNARY:      true,
+}
+
+var formulaFunctions = map[string]govaluate.ExpressionFunction{
+	"min":   numericFunction(2, func(args []float64) float64 { return math.Min(args[0], args[1]) }),
+	"max":   numericFunction(2, func(args []float64) float64 { return math.Max(args[0], args[1]) }),
+	"abs":   numericFunction(1, func(args []float64) float64 { return math.Abs(args[0]) }),
+	"round": numericFunction(1, func(args []float64) float64 { return math.Round(args[0]) }),
+}
+
+func numericFunction(arity int, fn func(args []float64) float64) govaluate.ExpressionFunction {
+	return func(args ...any) (any, error) {
+		if len(args) != arity {
+			return nil, ErrInvalidFormula
+		}
+		values := make([]float64, arity)
+		for idx, arg := range args {
+			value, ok := arg.(float64)
+			if !ok {
+				return nil, ErrInvalidFormula
+			}
+			values[idx] = value
+		}
+		return fn(values), nil
+	}
+}
+
+// formulaSamples are the metrics a formula is tried on before it is accepted,
+// a result with nothing typed, a typical one and the far ends of what the
+// engines report
+var formulaSamples = []map[string]float64{
+	{},
+	{"wpm": 1, "raw_wpm": 1, "cpm": 1, "accuracy": 1, "errors": 1, "corrected": 1, "consistency": 1, "words": 1, "letters": 1, "duration": 1},
+	{"wpm": 60, "raw_wpm": 65, "cpm": 300, "accuracy": 95, "errors": 5, "corrected": 3, "consistency": 80, "words": 50, "letters": 250, "duration": 60},
+	{"wpm": 250, "raw_wpm": 300, "cpm": 1500, "accuracy": 100, "consistency": 100, "words": structures.MAX_ACTIVITY_WORD_TARGET, "letters": 5000, "duration": structures.MAX_ACTIVITY_TIME_LIMIT},
+	{"wpm": 5, "raw_wpm": 40, "cpm": 25, "accuracy": 10, "errors": 500, "words": 2, "letters": 10, "duration": 1},
+}
+
+// ParseFormula compiles a scoring formula, it fails for formulas that read
+// anything other than the result metrics or that do not come out as a finite
+// number for every one of the sample results
+func ParseFormula(formula string) (*govaluate.EvaluableExpression, error) {
+	if len(formula) > structures.MAX_SCORING_FORMULA_LENGTH {
+		return nil, ErrInvalidFormula
+	}
+
+	expression, err := govaluate.NewEvaluableExpressionWithFunctions(formula, formulaFunctions)
+	if err != nil {
+		return nil, ErrInvalidFormula
+	}
+
+	for _, token := range expression.Tokens() {
+		if !formulaTokens[token.Kind] {
+			return nil, ErrInvalidFormula
+		}
+	}
+	for _, variable := range expression.Vars() {
+		if !isScoringMetric(variable) {
+			return nil, ErrInvalidFormula
+		}
+	}
+	// a formula like "wpm > 60" parses fine but scores nothing, one like
+	// "wpm / errors" only breaks once a result has no errors
+	for _, sample := range formulaSamples {
+		if _, err := evaluate(expression, sample); err != nil {
+			return nil, err
+		}
+	}
+
+	return expression, nil
+}
+
+// EvaluateFormula scores a set of result metrics, metrics that are missing
+// count as zero and formulas that do not come out as a finite number fail
+func EvaluateFormula(formula string, metrics map[string]float64) (float64, error) {
+	if formula == "" {
+		formula = structures.DEFAULT_SCORING_FORMULA
+	}
+	expression, err := ParseFormula(formula)
+	if err != nil {
+		return 0, err
+	}
+
+	score, err := evaluate(expression, metrics)
+	if err != nil {
+		return 0, err
+	}
+	return round(score), nil
+}
+
+// FinalScore scores a set of result metrics with the formula of an activity,
+// when that formula cannot score them the default formula does and the
+// error is handed back so it can be logged
+func FinalScore(formula string, metrics map[string]float64) (float64, error) {
+	score, err := EvaluateFormula(formula, metrics)
+	if err == nil {
+		return score, nil
+	}
+	score, _ = EvaluateFormula(structures.DEFAULT_SCORING_FORMULA, metrics)
+	return score, err
+}
+
+func evaluate(expression *govaluate.EvaluableExpression, metrics map[string]float64) (float64, error) {
+	parameters := map[string]any{}
+	for _, metric := range structures.SCORING_METRICS {
+		parameters[metric] = metrics[metric]
+	}
+
+	value, err := expression.Evaluate(parameters)
+	if err != nil {
+		return 0, ErrInvalidFormula
+	}
+	score, ok := value.(float64)
+	if !ok || math.IsNaN(score) || math.IsInf(score, 0) {
+		return 0, ErrInvalidFormula
+	}
+	return score, nil
+}
+
+// ResultMetrics reads the scoring metrics out of a score result, the
+// duration is the one of the score in seconds
+func ResultMetrics(result structures.ScoreResult, duration int) map[string]float64 {
+	return map[string]float64{
+		"wpm":         result.Wpm,
+		"raw_wpm":     result.RawWpm,
+		"cpm":         result.Cpm,
+		"accuracy":    result.Accuracy,
+		"errors":      float64(result.Errors),
+		"corrected":   float64(result.Corrected),
+		"consistency": result.Consistency,
+		"words":       float64(result.Words),
+		"letters":     float64(result.Letters),
+		"duration":    float64(duration),
+	}
+}
+
+// ScoreResult turns an engine result into the result stored with a score,
+// the duration lives on the score itself in seconds
+func ScoreResult(activityResult *structures.ActivityResult) structures.ScoreResult {
+	return structures.ScoreResult{
+		Version:     structures.SCORE_RESULT_VERSION,
+		Wpm:         activityResult.Wpm,
+		RawWpm:      activityResult.RawWpm,
+		Cpm:         activityResult.Cpm,
+		Accuracy:    activityResult.Accuracy,
+		Errors:      activityResult.Errors,
+		Corrected:   activityResult.Corrected,
+		Consistency: activityResult.Consistency,
+		Words:       activityResult.Words,
+		Letters:     activityResult.Letters,
+	}
+}
+
+func isScoringMetric(name string) bool {
+	for _, metric := range structures.SCORING_METRICS {
+		if metric == name {
+			return true
+		}
+	}
+	return false
+}
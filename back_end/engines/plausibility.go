@@ -0,0 +1,90 @@
+package engines
+
+import (
+	"math"
+	"type_writer_api/structures"
+	"unicode/utf8"
+)
+
+// CheckPlausibility looks for keystrokes no person could have typed and
+// returns why, an empty list means nothing stood out
+func CheckPlausibility(keystrokes []*structures.Keystroke) []string {
+	reasons := []string{}
+	characters := []int{}
+	for _, keystroke := range keystrokes {
+		if keystroke.Key == structures.KEY_ENTER || utf8.RuneCountInString(keystroke.Key) == 1 {
+			characters = append(characters, keystroke.Offset)
+		}
+	}
+
+	if sustainedWpmTooHigh(characters) {
+		reasons = append(reasons, structures.SCORE_FLAG_SUSTAINED_WPM)
+	}
+	if tooManyFastIntervals(keystrokes) {
+		reasons = append(reasons, structures.SCORE_FLAG_KEY_INTERVALS)
+	}
+	if hasPasteBurst(characters) {
+		reasons = append(reasons, structures.SCORE_FLAG_PASTE_BURST)
+	}
+	return reasons
+}
+
+// sustainedWpmTooHigh slides a window over the character offsets, tests
+// shorter than the window are left to the other checks
+func sustainedWpmTooHigh(offsets []int) bool {
+	if len(offsets) == 0 || offsets[len(offsets)-1]-offsets[0] < structures.SUSTAINED_WPM_WINDOW {
+		return false
+	}
+
+	// a word is five characters
+	limit := structures.MAX_SUSTAINED_WPM * 5 * structures.SUSTAINED_WPM_WINDOW / 60000
+	end := 0
+	for start := range offsets {
+		for end < len(offsets) && offsets[end]-offsets[start] < structures.SUSTAINED_WPM_WINDOW {
+			end++
+		}
+		if end-start > limit {
+			return true
+		}
+		if end == len(offsets) {
+			break
+		}
+	}
+	return false
+}
+
+func tooManyFastIntervals(keystrokes []*structures.Keystroke) bool {
+	intervals := len(keystrokes) - 1
+	if intervals < structures.MIN_KEY_INTERVAL_SAMPLES {
+		return false
+	}
+
+	fast := 0
+	for idx := 1; idx < len(keystrokes); idx++ {
+		if keystrokes[idx].Offset-keystrokes[idx-1].Offset < structures.MIN_KEY_INTERVAL {
+			fast++
+		}
+	}
+	return float64(fast) > float64(intervals)*structures.MAX_FAST_KEY_SHARE
+}
+
+func hasPasteBurst(offsets []int) bool {
+	for end := structures.PASTE_BURST_KEYS - 1; end < len(offsets); end++ {
+		if offsets[end]-offsets[end-structures.PASTE_BURST_KEYS+1] <= structures.PASTE_BURST_SPAN {
+			return true
+		}
+	}
+	return false
+}
+
+// ResultMatches tells whether the result a client claimed agrees with the
+// one recomputed from its keystrokes
+func ResultMatches(claimed structures.ScoreResult, computed *structures.ActivityResult) bool {
+	if math.Abs(claimed.Wpm-computed.Wpm) > math.Max(1, computed.Wpm*structures.RESULT_WPM_TOLERANCE) {
+		return false
+	}
+	if math.Abs(claimed.Accuracy-computed.Accuracy) > structures.RESULT_ACCURACY_TOLERANCE {
+		return false
+	}
+	return claimed.Errors == computed.Errors
+}
@@ -0,0 +1,212 @@
+package engines
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"type_writer_api/structures"
+	"unicode/utf8"
+)
+
+// playback replays keystrokes against the text the way the typing screen
+// does, every key fills the next position and backspace clears the last one
+type playback struct {
+	target     []rune
+	wordEnds   []int
+	typed      []rune
+	wrong      map[int]bool
+	keystrokes int
+	correct    int
+	errors     int
+	corrected  int
+	duration   int
+	presses    []int
+	events     []*structures.KeystrokeEvent
+}
+
+func newPlayback(text string) *playback {
+	p := &playback{target: []rune(text), wrong: map[int]bool{}}
+	inWord := false
+	for idx, char := range p.target {
+		isSpace := strings.ContainsRune(" \t\n", char)
+		if inWord && isSpace {
+			p.wordEnds = append(p.wordEnds, idx)
+		}
+		inWord = !isSpace
+	}
+	if inWord {
+		p.wordEnds = append(p.wordEnds, len(p.target))
+	}
+	return p
+}
+
+// press applies a keystroke and reports whether it was a typing error, keys
+// that do not produce a single character are ignored
+func (p *playback) press(keystroke *structures.Keystroke) bool {
+	p.duration = keystroke.Offset
+
+	if keystroke.Key == structures.KEY_BACKSPACE {
+		if len(p.typed) == 0 {
+			return false
+		}
+		last := len(p.typed) - 1
+		p.events = append(p.events, &structures.KeystrokeEvent{Key: keystroke.Key, Offset: keystroke.Offset, Backspace: true})
+		if p.wrong[last] {
+			delete(p.wrong, last)
+			p.corrected++
+		}
+		p.typed = p.typed[:last]
+		return false
+	}
+
+	key := keystroke.Key
+	if key == structures.KEY_ENTER {
+		key = "\n"
+	}
+	if utf8.RuneCountInString(key) != 1 || p.finished() {
+		return false
+	}
+
+	char, _ := utf8.DecodeRuneInString(key)
+	position := len(p.typed)
+	p.typed = append(p.typed, char)
+	p.keystrokes++
+	p.presses = append(p.presses, keystroke.Offset)
+	p.events = append(p.events, &structures.KeystrokeEvent{
+		Key:      keystroke.Key,
+		Offset:   keystroke.Offset,
+		Expected: string(p.target[position]),
+		Correct:  char == p.target[position],
+	})
+	if char != p.target[position] {
+		p.wrong[position] = true
+		p.errors++
+		return true
+	}
+	p.correct++
+	return false
+}
+
+func (p *playback) finished() bool {
+	return len(p.typed) >= len(p.target)
+}
+
+// words counts the words of the text typed in full without a wrong letter
+func (p *playback) words() int {
+	count, start := 0, 0
+	for _, end := range p.wordEnds {
+		if end > len(p.typed) {
+			break
+		}
+		clean := true
+		for position := start; position < end; position++ {
+			if p.wrong[position] {
+				clean = false
+				break
+			}
+		}
+		if clean {
+			count++
+		}
+		start = end
+	}
+	return count
+}
+
+func (p *playback) result(rules structures.ActivityRules) *structures.ActivityResult {
+	letters := len(p.typed) - len(p.wrong)
+	result := &structures.ActivityResult{
+		Errors:      p.errors,
+		Corrected:   p.corrected,
+		Consistency: p.consistency(),
+		Words:       p.words(),
+		Letters:     letters,
+		Duration:    p.duration,
+		Completed:   p.finished() && (!rules.MustCorrectErrors || len(p.wrong) == 0),
+	}
+	if p.keystrokes > 0 {
+		result.Accuracy = round(float64(p.correct) * 100 / float64(p.keystrokes))
+	}
+	// a word is five characters, durations are in milliseconds
+	if p.duration > 0 {
+		result.Cpm = round(float64(letters) * 60000 / float64(p.duration))
+		result.Wpm = round(float64(letters) * 12000 / float64(p.duration))
+		result.RawWpm = round(float64(p.keystrokes) * 12000 / float64(p.duration))
+	}
+	result.Passed = result.Accuracy >= rules.AccuracyThreshold
+	return result
+}
+
+// consistency is how even the pace was, 100 less the coefficient of
+// variation of the time between two typed characters
+func (p *playback) consistency() float64 {
+	if len(p.presses) < 2 {
+		return 0
+	}
+
+	intervals := make([]float64, 0, len(p.presses)-1)
+	mean := 0.0
+	for idx := 1; idx < len(p.presses); idx++ {
+		interval := float64(p.presses[idx] - p.presses[idx-1])
+		intervals = append(intervals, interval)
+		mean += interval
+	}
+	mean /= float64(len(intervals))
+	if mean == 0 {
+		return 0
+	}
+
+	variance := 0.0
+	for _, interval := range intervals {
+		variance += (interval - mean) * (interval - mean)
+	}
+	deviation := math.Sqrt(variance / float64(len(intervals)))
+	return round(max(0, 100-deviation*100/mean))
+}
+
+// round keeps two decimals so results compare the same way they are shown
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// play runs the input through the shared limits of every rule set, it stops
+// once the text is done (and corrected when the rules ask for it), the time limit or the word target is reached or on
+// the first error when the rules ask for it
+func play(rules structures.ActivityRules, input structures.ActivityInput) (*playback, error) {
+	if len(rules.AllowedTextTypes) != 0 && !slices.Contains(rules.AllowedTextTypes, input.TextType) {
+		return nil, ErrTextTypeNotAllowed
+	}
+
+	p := newPlayback(input.TextBody)
+	timeLimit := rules.TimeLimit * 1000
+	lastOffset := 0
+	for _, keystroke := range input.Keystrokes {
+		if keystroke == nil || keystroke.Offset < lastOffset {
+			return nil, ErrInvalidKeystrokes
+		}
+		lastOffset = keystroke.Offset
+
+		if timeLimit > 0 && keystroke.Offset > timeLimit {
+			p.duration = timeLimit
+			break
+		}
+		if p.press(keystroke) && rules.StopOnError {
+			break
+		}
+		if (p.finished() && (len(p.wrong) == 0 || !rules.MustCorrectErrors)) || (rules.WordTarget > 0 && p.words() >= rules.WordTarget) {
+			break
+		}
+	}
+	return p, nil
+}
+
+// KeystrokeEvents replays the input like any engine does and returns how each
+// keystroke landed on the text, keystrokes the replay stopped before or that
+// changed nothing are left out
+func KeystrokeEvents(rules structures.ActivityRules, input structures.ActivityInput) ([]*structures.KeystrokeEvent, error) {
+	p, err := play(rules, input)
+	if err != nil {
+		return nil, err
+	}
+	return p.events, nil
+}
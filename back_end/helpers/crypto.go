@@ -1,9 +1,15 @@
 package helpers
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
+// SHARE_SLUG_BYTES of randomness make share slugs impossible to guess
+const SHARE_SLUG_BYTES = 16
+
 func HashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
@@ -15,3 +21,12 @@ func HashPassword(password string) (string, error) {
 func TestPasswordHash(hashed, plain string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(plain))
 }
+
+// RandomSlug returns an url safe string made from size random bytes
+func RandomSlug(size int) (string, error) {
+	buffer := make([]byte, size)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
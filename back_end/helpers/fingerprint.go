@@ -0,0 +1,107 @@
+package helpers
+
+import (
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+const (
+	FINGERPRINT_SIZE        = 64
+	FINGERPRINT_SHINGLE_LEN = 3
+
+	// texts this similar to an existing one are refused as duplicates while
+	// anything above the warn threshold is only reported back
+	DUPLICATE_REJECT_SIMILARITY = 0.9
+	DUPLICATE_WARN_SIMILARITY   = 0.5
+)
+
+// fingerprintSeeds are the per slot seeds of the minhash family, derived with
+// splitmix64 from a fixed start so signatures stay comparable across restarts
+var fingerprintSeeds = func() []uint64 {
+	seeds := make([]uint64, FINGERPRINT_SIZE)
+	state := uint64(0x5eed)
+	for idx := range seeds {
+		state += 0x9e3779b97f4a7c15
+		seeds[idx] = mix64(state)
+	}
+	return seeds
+}()
+
+func mix64(x uint64) uint64 {
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// fingerprintWords lowercases the text and splits it into words, dropping
+// punctuation so formatting differences do not change the fingerprint
+func fingerprintWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Shingles returns the set of overlapping word n-grams of the text, texts too
+// short for a single shingle become one shingle with all their words
+func Shingles(text string) map[string]bool {
+	words := fingerprintWords(text)
+	shingles := map[string]bool{}
+
+	if len(words) == 0 {
+		return shingles
+	}
+	if len(words) < FINGERPRINT_SHINGLE_LEN {
+		shingles[strings.Join(words, " ")] = true
+		return shingles
+	}
+	for idx := 0; idx+FINGERPRINT_SHINGLE_LEN <= len(words); idx++ {
+		shingles[strings.Join(words[idx:idx+FINGERPRINT_SHINGLE_LEN], " ")] = true
+	}
+	return shingles
+}
+
+// TextFingerprint computes the minhash signature of the text shingles, empty
+// texts have no fingerprint
+func TextFingerprint(text string) []uint32 {
+	shingles := Shingles(text)
+	if len(shingles) == 0 {
+		return nil
+	}
+
+	signature := make([]uint32, FINGERPRINT_SIZE)
+	for idx := range signature {
+		signature[idx] = ^uint32(0)
+	}
+
+	for shingle := range shingles {
+		hasher := fnv.New64a()
+		hasher.Write([]byte(shingle))
+		shingleHash := hasher.Sum64()
+
+		for idx, seed := range fingerprintSeeds {
+			value := uint32(mix64(shingleHash ^ seed))
+			if value < signature[idx] {
+				signature[idx] = value
+			}
+		}
+	}
+
+	return signature
+}
+
+// FingerprintSimilarity estimates the jaccard similarity of the shingles of two
+// texts as the share of matching signature slots
+func FingerprintSimilarity(a, b []uint32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	matching := 0
+	for idx := range a {
+		if a[idx] == b[idx] {
+			matching++
+		}
+	}
+	return float64(matching) / float64(len(a))
+}
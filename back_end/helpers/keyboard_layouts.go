@@ -0,0 +1,258 @@
+package helpers
+
+import (
+	"strings"
+	"type_writer_api/structures"
+	"unicode"
+)
+
+// builtinLayoutRows holds the plain and shifted output of the number, top,
+// home and bottom rows of every builtin layout, left to right
+var builtinLayoutRows = []struct {
+	name        string
+	description string
+	rows        [4][2]string
+}{
+	{
+		structures.DEFAULT_KEYBOARD_LAYOUT,
+		"US QWERTY",
+		[4][2]string{
+			{"`1234567890-=", "~!@#$%^&*()_+"},
+			{"qwertyuiop[]\\", "QWERTYUIOP{}|"},
+			{"asdfghjkl;'", "ASDFGHJKL:\""},
+			{"zxcvbnm,./", "ZXCVBNM<>?"},
+		},
+	},
+	{
+		"dvorak",
+		"US Dvorak simplified keyboard",
+		[4][2]string{
+			{"`1234567890[]", "~!@#$%^&*(){}"},
+			{"',.pyfgcrl/=\\", "\"<>PYFGCRL?+|"},
+			{"aoeuidhtns-", "AOEUIDHTNS_"},
+			{";qjkxbmwvz", ":QJKXBMWVZ"},
+		},
+	},
+	{
+		"colemak",
+		"Colemak",
+		[4][2]string{
+			{"`1234567890-=", "~!@#$%^&*()_+"},
+			{"qwfpgjluy;[]\\", "QWFPGJLUY:{}|"},
+			{"arstdhneio'", "ARSTDHNEIO\""},
+			{"zxcvbkm,./", "ZXCVBKM<>?"},
+		},
+	},
+	{
+		"azerty",
+		"French AZERTY",
+		[4][2]string{
+			{"²&é\"'(-è_çà)=", "³1234567890°+"},
+			{"azertyuiop^$", "AZERTYUIOP¨£"},
+			{"qsdfghjklmù*", "QSDFGHJKLM%µ"},
+			{"wxcvbn,;:!", "WXCVBN?./§"},
+		},
+	},
+	{
+		"qwertz",
+		"German QWERTZ",
+		[4][2]string{
+			{"^1234567890ß´", "°!\"§$%&/()=?`"},
+			{"qwertzuiopü+", "QWERTZUIOPÜ*"},
+			{"asdfghjklöä#", "ASDFGHJKLÖÄ'"},
+			{"yxcvbnm,.-", "YXCVBNM;:_"},
+		},
+	},
+}
+
+var builtinRowNames = [4]string{
+	structures.KEY_ROW_NUMBER,
+	structures.KEY_ROW_TOP,
+	structures.KEY_ROW_HOME,
+	structures.KEY_ROW_BOTTOM,
+}
+
+// columnFinger follows the standard touch typing assignment, the index fingers
+// cover two columns each and the right pinky everything past the ring finger
+func columnFinger(row string, column int) string {
+	if row == structures.KEY_ROW_NUMBER {
+		if column == 0 {
+			return structures.FINGER_LEFT_PINKY
+		}
+		column--
+	}
+
+	switch column {
+	case 0:
+		return structures.FINGER_LEFT_PINKY
+	case 1:
+		return structures.FINGER_LEFT_RING
+	case 2:
+		return structures.FINGER_LEFT_MIDDLE
+	case 3, 4:
+		return structures.FINGER_LEFT_INDEX
+	case 5, 6:
+		return structures.FINGER_RIGHT_INDEX
+	case 7:
+		return structures.FINGER_RIGHT_MIDDLE
+	case 8:
+		return structures.FINGER_RIGHT_RING
+	}
+	return structures.FINGER_RIGHT_PINKY
+}
+
+func buildLayout(name, description string, rows [4][2]string) *structures.KeyboardLayout {
+	layout := &structures.KeyboardLayout{
+		Name:        name,
+		Description: description,
+		Builtin:     true,
+		Keys:        []*structures.KeyPosition{},
+	}
+
+	for rowIdx, row := range rows {
+		plain, shifted := []rune(row[0]), []rune(row[1])
+		for column, char := range plain {
+			key := &structures.KeyPosition{
+				Char:   string(char),
+				Row:    builtinRowNames[rowIdx],
+				Column: column,
+				Finger: columnFinger(builtinRowNames[rowIdx], column),
+			}
+			if column < len(shifted) {
+				key.ShiftChar = string(shifted[column])
+			}
+			layout.Keys = append(layout.Keys, key)
+		}
+	}
+
+	layout.Keys = append(layout.Keys,
+		&structures.KeyPosition{Char: "\t", Row: structures.KEY_ROW_TOP, Column: -1, Finger: structures.FINGER_LEFT_PINKY},
+		&structures.KeyPosition{Char: "\n", Row: structures.KEY_ROW_HOME, Column: len([]rune(rows[2][0])), Finger: structures.FINGER_RIGHT_PINKY},
+		&structures.KeyPosition{Char: " ", Row: structures.KEY_ROW_SPACE, Column: 0, Finger: structures.FINGER_THUMB},
+	)
+
+	return layout
+}
+
+// BuiltinKeyboardLayouts returns a fresh copy of the layouts shipped with the
+// api, callers are free to modify the result
+func BuiltinKeyboardLayouts() []*structures.KeyboardLayout {
+	layouts := []*structures.KeyboardLayout{}
+	for _, builtin := range builtinLayoutRows {
+		layouts = append(layouts, buildLayout(builtin.name, builtin.description, builtin.rows))
+	}
+	return layouts
+}
+
+// BuiltinKeyboardLayout looks a builtin layout up by its case insensitive name
+func BuiltinKeyboardLayout(name string) (*structures.KeyboardLayout, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, builtin := range builtinLayoutRows {
+		if builtin.name == name {
+			return buildLayout(builtin.name, builtin.description, builtin.rows), true
+		}
+	}
+	return nil, false
+}
+
+// FingerHand returns the hand a finger belongs to, thumbs belong to neither
+func FingerHand(finger string) string {
+	switch {
+	case strings.HasPrefix(finger, structures.HAND_LEFT+"_"):
+		return structures.HAND_LEFT
+	case strings.HasPrefix(finger, structures.HAND_RIGHT+"_"):
+		return structures.HAND_RIGHT
+	}
+	return ""
+}
+
+// KeyStroke is the physical key and modifier needed to produce a character
+type KeyStroke struct {
+	Key     *structures.KeyPosition
+	Shifted bool
+}
+
+// KeyIndex maps every character a layout can produce to the keystroke that
+// produces it, uppercase letters fall back to their shifted lowercase key when
+// the layout does not list them
+func KeyIndex(layout *structures.KeyboardLayout) map[rune]KeyStroke {
+	index := map[rune]KeyStroke{}
+
+	for _, key := range layout.Keys {
+		for _, char := range key.Char {
+			index[char] = KeyStroke{Key: key}
+		}
+		for _, char := range key.ShiftChar {
+			if _, ok := index[char]; !ok {
+				index[char] = KeyStroke{Key: key, Shifted: true}
+			}
+		}
+	}
+	for _, key := range layout.Keys {
+		for _, char := range key.Char {
+			upper := unicode.ToUpper(char)
+			if _, ok := index[upper]; !ok && upper != char {
+				index[upper] = KeyStroke{Key: key, Shifted: true}
+			}
+		}
+	}
+
+	return index
+}
+
+// BuildKeyProfile runs the text through the layout, counting the load on every
+// finger, row and hand along with the transitions between consecutive keys
+func BuildKeyProfile(layout *structures.KeyboardLayout, text string) structures.KeyProfile {
+	profile := structures.KeyProfile{
+		Layout:        layout.Name,
+		UnmappedChars: []string{},
+		Fingers:       map[string]int{},
+		Rows:          map[string]int{},
+		Hands:         map[string]int{},
+	}
+	index := KeyIndex(layout)
+	seenUnmapped := map[rune]bool{}
+
+	var previous *structures.KeyPosition
+	for _, char := range text {
+		stroke, ok := index[char]
+		if !ok {
+			profile.Unmapped++
+			if !seenUnmapped[char] {
+				seenUnmapped[char] = true
+				profile.UnmappedChars = append(profile.UnmappedChars, string(char))
+			}
+			previous = nil
+			continue
+		}
+
+		profile.Keystrokes++
+		if stroke.Shifted {
+			profile.ShiftedKeystrokes++
+		}
+		profile.Fingers[stroke.Key.Finger]++
+		profile.Rows[stroke.Key.Row]++
+
+		hand := FingerHand(stroke.Key.Finger)
+		if hand != "" {
+			profile.Hands[hand]++
+		}
+
+		if previous != nil && previous != stroke.Key {
+			if previous.Finger == stroke.Key.Finger && stroke.Key.Finger != structures.FINGER_THUMB {
+				profile.SameFingerBigrams++
+			}
+			previousHand := FingerHand(previous.Finger)
+			if hand != "" && previousHand != "" && hand != previousHand {
+				profile.HandAlternations++
+			}
+		}
+		previous = stroke.Key
+	}
+
+	if profile.Keystrokes > 0 {
+		profile.HomeRowRatio = float64(profile.Rows[structures.KEY_ROW_HOME]) / float64(profile.Keystrokes)
+	}
+
+	return profile
+}
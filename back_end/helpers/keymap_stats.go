@@ -0,0 +1,110 @@
+package helpers
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"type_writer_api/structures"
+)
+
+// fingerOrder is the left to right order fingers are listed in
+var fingerOrder = []string{
+	structures.FINGER_LEFT_PINKY,
+	structures.FINGER_LEFT_RING,
+	structures.FINGER_LEFT_MIDDLE,
+	structures.FINGER_LEFT_INDEX,
+	structures.FINGER_THUMB,
+	structures.FINGER_RIGHT_INDEX,
+	structures.FINGER_RIGHT_MIDDLE,
+	structures.FINGER_RIGHT_RING,
+	structures.FINGER_RIGHT_PINKY,
+}
+
+// BuildKeymapStats goes over keystroke logs and counts, for every key of the
+// layout, how often it was expected and how often it was missed. Backspaces
+// and keystrokes typed past the end of the text are not counted
+func BuildKeymapStats(layout *structures.KeyboardLayout, logs [][]*structures.KeystrokeEvent) structures.KeymapStats {
+	stats := structures.KeymapStats{
+		Layout:     layout.Name,
+		Scores:     len(logs),
+		Keys:       []*structures.KeyStats{},
+		Fingers:    []*structures.FingerStats{},
+		Confusions: []*structures.ConfusionPair{},
+	}
+	index := KeyIndex(layout)
+	keys := map[string]*structures.KeyStats{}
+	fingers := map[string]*structures.FingerStats{}
+	confusions := map[[2]string]int{}
+
+	for _, events := range logs {
+		for _, event := range events {
+			if event.Backspace || event.Expected == "" {
+				continue
+			}
+
+			// characters sharing a physical key are counted on that key
+			keyStats := &structures.KeyStats{Key: event.Expected}
+			if stroke, ok := index[[]rune(event.Expected)[0]]; ok {
+				keyStats = &structures.KeyStats{Key: stroke.Key.Char, Row: stroke.Key.Row, Finger: stroke.Key.Finger}
+			}
+			if counted, ok := keys[keyStats.Key]; ok {
+				keyStats = counted
+			} else {
+				keys[keyStats.Key] = keyStats
+			}
+
+			keyStats.Attempts++
+			if !event.Correct {
+				keyStats.Errors++
+				confusions[[2]string{event.Expected, event.Key}]++
+			}
+
+			if keyStats.Finger == "" {
+				continue
+			}
+			fingerStats, ok := fingers[keyStats.Finger]
+			if !ok {
+				fingerStats = &structures.FingerStats{Finger: keyStats.Finger}
+				fingers[keyStats.Finger] = fingerStats
+			}
+			fingerStats.Attempts++
+			if !event.Correct {
+				fingerStats.Errors++
+			}
+		}
+	}
+
+	for _, keyStats := range keys {
+		keyStats.Accuracy = roundPercent(keyStats.Attempts-keyStats.Errors, keyStats.Attempts)
+		stats.Keys = append(stats.Keys, keyStats)
+	}
+	slices.SortFunc(stats.Keys, func(a, b *structures.KeyStats) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+
+	for _, finger := range fingerOrder {
+		if fingerStats, ok := fingers[finger]; ok {
+			fingerStats.ErrorRate = roundPercent(fingerStats.Errors, fingerStats.Attempts)
+			stats.Fingers = append(stats.Fingers, fingerStats)
+		}
+	}
+
+	for pair, count := range confusions {
+		stats.Confusions = append(stats.Confusions, &structures.ConfusionPair{Expected: pair[0], Typed: pair[1], Count: count})
+	}
+	slices.SortFunc(stats.Confusions, func(a, b *structures.ConfusionPair) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Expected, b.Expected), cmp.Compare(a.Typed, b.Typed))
+	})
+	if len(stats.Confusions) > structures.MAX_KEYMAP_CONFUSIONS {
+		stats.Confusions = stats.Confusions[:structures.MAX_KEYMAP_CONFUSIONS]
+	}
+
+	return stats
+}
+
+func roundPercent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
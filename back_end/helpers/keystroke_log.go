@@ -0,0 +1,110 @@
+package helpers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"type_writer_api/structures"
+)
+
+var (
+	ErrKeystrokeLogTooLarge = errors.New("keystroke log too large")
+	ErrInvalidKeystrokeLog  = errors.New("invalid keystroke log")
+)
+
+const (
+	keystrokeFlagCorrect = 1 << iota
+	keystrokeFlagBackspace
+)
+
+// EncodeKeystrokeLog packs the events into gzipped [key, offset, expected,
+// flags] tuples, refusing logs over the event or size limits
+func EncodeKeystrokeLog(events []*structures.KeystrokeEvent) ([]byte, error) {
+	if len(events) > structures.MAX_KEYSTROKE_LOG_EVENTS {
+		return nil, ErrKeystrokeLogTooLarge
+	}
+
+	tuples := make([][4]any, 0, len(events))
+	for _, event := range events {
+		if event == nil || event.Key == "" || event.Offset < 0 {
+			return nil, ErrInvalidKeystrokeLog
+		}
+		flags := 0
+		if event.Correct {
+			flags |= keystrokeFlagCorrect
+		}
+		if event.Backspace {
+			flags |= keystrokeFlagBackspace
+		}
+		tuples = append(tuples, [4]any{event.Key, event.Offset, event.Expected, flags})
+	}
+
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if err := json.NewEncoder(writer).Encode(tuples); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	if buffer.Len() > structures.MAX_KEYSTROKE_LOG_BYTES {
+		return nil, ErrKeystrokeLogTooLarge
+	}
+	return buffer.Bytes(), nil
+}
+
+// DecodeKeystrokeLog unpacks a log written by EncodeKeystrokeLog
+func DecodeKeystrokeLog(data []byte) ([]*structures.KeystrokeEvent, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidKeystrokeLog
+	}
+	defer reader.Close()
+
+	// a log inflating far past what the limits allow was not written by us
+	decoded, err := io.ReadAll(io.LimitReader(reader, 64*structures.MAX_KEYSTROKE_LOG_BYTES))
+	if err != nil {
+		return nil, ErrInvalidKeystrokeLog
+	}
+
+	var tuples [][]json.RawMessage
+	if err := json.Unmarshal(decoded, &tuples); err != nil {
+		return nil, ErrInvalidKeystrokeLog
+	}
+
+	events := make([]*structures.KeystrokeEvent, 0, len(tuples))
+	for _, tuple := range tuples {
+		var (
+			event structures.KeystrokeEvent
+			flags int
+		)
+		if len(tuple) != 4 {
+			return nil, ErrInvalidKeystrokeLog
+		}
+		for idx, field := range []any{&event.Key, &event.Offset, &event.Expected, &flags} {
+			if err := json.Unmarshal(tuple[idx], field); err != nil {
+				return nil, ErrInvalidKeystrokeLog
+			}
+		}
+		event.Correct = flags&keystrokeFlagCorrect != 0
+		event.Backspace = flags&keystrokeFlagBackspace != 0
+		events = append(events, &event)
+	}
+	return events, nil
+}
+
+// NewKeystrokeLog encodes the events into a log ready to be stored with a
+// score
+func NewKeystrokeLog(events []*structures.KeystrokeEvent) (*structures.ScoreKeystrokeLog, error) {
+	data, err := EncodeKeystrokeLog(events)
+	if err != nil {
+		return nil, err
+	}
+	return &structures.ScoreKeystrokeLog{
+		Encoding:   structures.KEYSTROKE_LOG_ENCODING,
+		EventCount: len(events),
+		Data:       data,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package helpers
+
+import "strings"
+
+const DEFAULT_LANGUAGE = "en"
+
+func isAlpha(s string) bool {
+	for _, char := range s {
+		if (char < 'a' || char > 'z') && (char < 'A' || char > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for _, char := range s {
+		if !isAlpha(string(char)) && (char < '0' || char > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeLanguage canonicalizes a BCP 47 style language tag so "EN_us" and
+// "en-US" are stored the same way, it reports false for malformed tags
+func NormalizeLanguage(language string) (string, bool) {
+	subtags := strings.FieldsFunc(strings.TrimSpace(language), func(r rune) bool { return r == '-' || r == '_' })
+	if len(subtags) == 0 {
+		return "", false
+	}
+
+	primary := subtags[0]
+	if len(primary) < 2 || len(primary) > 3 || !isAlpha(primary) {
+		return "", false
+	}
+	normalized := []string{strings.ToLower(primary)}
+
+	for _, subtag := range subtags[1:] {
+		if len(subtag) < 2 || len(subtag) > 8 || !isAlphanumeric(subtag) {
+			return "", false
+		}
+		switch {
+		case len(subtag) == 2 && isAlpha(subtag):
+			// region, "US"
+			normalized = append(normalized, strings.ToUpper(subtag))
+		case len(subtag) == 4 && isAlpha(subtag):
+			// script, "Latn"
+			normalized = append(normalized, strings.ToUpper(subtag[:1])+strings.ToLower(subtag[1:]))
+		default:
+			normalized = append(normalized, strings.ToLower(subtag))
+		}
+	}
+
+	return strings.Join(normalized, "-"), true
+}
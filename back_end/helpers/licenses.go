@@ -0,0 +1,92 @@
+package helpers
+
+import (
+	"net/url"
+	"strings"
+	"type_writer_api/structures"
+)
+
+// licenses are the ones texts can be published under, keyed by the id stored
+// on the text
+var licenses = []*structures.License{
+	{Id: "public-domain", Name: "Public Domain", AttributionRequired: false},
+	{Id: "cc0-1.0", Name: "CC0 1.0", Url: "https://creativecommons.org/publicdomain/zero/1.0/", AttributionRequired: false},
+	{Id: "cc-by-4.0", Name: "CC BY 4.0", Url: "https://creativecommons.org/licenses/by/4.0/", AttributionRequired: true},
+	{Id: "cc-by-sa-4.0", Name: "CC BY-SA 4.0", Url: "https://creativecommons.org/licenses/by-sa/4.0/", AttributionRequired: true},
+	{Id: "cc-by-nc-4.0", Name: "CC BY-NC 4.0", Url: "https://creativecommons.org/licenses/by-nc/4.0/", AttributionRequired: true},
+	{Id: "cc-by-nc-sa-4.0", Name: "CC BY-NC-SA 4.0", Url: "https://creativecommons.org/licenses/by-nc-sa/4.0/", AttributionRequired: true},
+	{Id: "cc-by-nd-4.0", Name: "CC BY-ND 4.0", Url: "https://creativecommons.org/licenses/by-nd/4.0/", AttributionRequired: true},
+	{Id: "gutenberg", Name: "Project Gutenberg License", Url: "https://www.gutenberg.org/policy/license.html", AttributionRequired: true},
+	{Id: "fair-use", Name: "Fair use excerpt", AttributionRequired: true},
+	{Id: "all-rights-reserved", Name: "All rights reserved, used with permission", AttributionRequired: true},
+}
+
+// Licenses lists every license a text can be published under
+func Licenses() []*structures.License {
+	result := make([]*structures.License, 0, len(licenses))
+	for _, license := range licenses {
+		copied := *license
+		result = append(result, &copied)
+	}
+	return result
+}
+
+// NormalizeLicense canonicalizes a license id so "CC BY 4.0" and "cc-by-4.0"
+// match, it reports false for licenses that are not known
+func NormalizeLicense(license string) (string, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(license))
+	normalized = strings.Join(strings.FieldsFunc(normalized, func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-'
+	}), "-")
+
+	for _, known := range licenses {
+		if known.Id == normalized {
+			return normalized, true
+		}
+	}
+	return "", false
+}
+
+// LicenseById returns the license with the given canonical id
+func LicenseById(id string) (*structures.License, bool) {
+	for _, license := range licenses {
+		if license.Id == id {
+			copied := *license
+			return &copied, true
+		}
+	}
+	return nil, false
+}
+
+// IsValidSourceUrl reports whether the url can be linked to as the source of
+// a text, only absolute http and https urls are accepted
+func IsValidSourceUrl(sourceUrl string) bool {
+	parsed, err := url.Parse(sourceUrl)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// AttributionLine credits the origin of a text in a single line like
+// "Pride and Prejudice by Jane Austen (https://...), Project Gutenberg License"
+func AttributionLine(text *structures.Text) string {
+	line := text.Source
+	if text.Author != "" {
+		if line != "" {
+			line += " by " + text.Author
+		} else {
+			line = text.Author
+		}
+	}
+	if line == "" {
+		line = text.Title
+	}
+	if text.SourceUrl != "" {
+		line += " (" + text.SourceUrl + ")"
+	}
+	if license, ok := LicenseById(text.License); ok {
+		line += ", " + license.Name
+	}
+	return line
+}
@@ -0,0 +1,139 @@
+package helpers
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"type_writer_api/structures"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NgramLatencies samples how long every bigram and trigram took to type,
+// only runs of correctly typed letters count so an error, a backspace or any
+// other character starts a new run. Sequences are lowercased
+func NgramLatencies(logs [][]*structures.KeystrokeEvent) map[string][]int {
+	latencies := map[string][]int{}
+	for _, events := range logs {
+		run := []*structures.KeystrokeEvent{}
+		for _, event := range events {
+			char, size := utf8.DecodeRuneInString(event.Expected)
+			if event.Backspace || !event.Correct || size != len(event.Expected) || !unicode.IsLetter(char) {
+				run = run[:0]
+				continue
+			}
+			run = append(run, event)
+			if len(run) > 3 {
+				run = run[1:]
+			}
+
+			for n := 2; n <= 3 && n <= len(run); n++ {
+				first := run[len(run)-n]
+				latency := event.Offset - first.Offset
+				if latency < 0 || latency > (n-1)*structures.MAX_NGRAM_LATENCY {
+					continue
+				}
+				var ngram strings.Builder
+				for _, typed := range run[len(run)-n:] {
+					ngram.WriteString(strings.ToLower(typed.Expected))
+				}
+				latencies[ngram.String()] = append(latencies[ngram.String()], latency)
+			}
+		}
+	}
+	return latencies
+}
+
+// MedianLatency is the middle sample, or the mean of the two middle ones
+func MedianLatency[T int | float64](samples []T) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return float64(sorted[middle])
+	}
+	return float64(sorted[middle-1]+sorted[middle]) / 2
+}
+
+// RankNgrams lists the sequences of the given length a user is slowest on
+// compared to the baseline, sequences missing from the baseline are compared
+// to the median of all the user's sequences of that length
+func RankNgrams(latencies map[string][]int, baselines map[string]float64, size int) []*structures.NgramLatency {
+	ranked := []*structures.NgramLatency{}
+	medians := []float64{}
+	for ngram, samples := range latencies {
+		if utf8.RuneCountInString(ngram) != size || len(samples) < structures.MIN_NGRAM_SAMPLES {
+			continue
+		}
+		median := MedianLatency(samples)
+		ranked = append(ranked, &structures.NgramLatency{Ngram: ngram, Samples: len(samples), MedianLatency: median})
+		medians = append(medians, median)
+	}
+
+	own := MedianLatency(medians)
+	for _, latency := range ranked {
+		latency.BaselineLatency = own
+		if baseline, ok := baselines[latency.Ngram]; ok && baseline > 0 {
+			latency.BaselineLatency = baseline
+		}
+		if latency.BaselineLatency > 0 {
+			latency.Ratio = math.Round(latency.MedianLatency/latency.BaselineLatency*100) / 100
+		}
+	}
+
+	slices.SortFunc(ranked, func(a, b *structures.NgramLatency) int {
+		return cmp.Or(cmp.Compare(b.Ratio, a.Ratio), cmp.Compare(b.MedianLatency, a.MedianLatency), cmp.Compare(a.Ngram, b.Ngram))
+	})
+	if len(ranked) > structures.MAX_WEAK_NGRAMS {
+		ranked = ranked[:structures.MAX_WEAK_NGRAMS]
+	}
+	return ranked
+}
+
+// BuildNgramDrill writes a drill out of the words of the given texts that
+// hold the most of the sequences, words are repeated until the drill is long
+// enough. The sequences are drilled on their own when no word holds them
+func BuildNgramDrill(ngrams []string, texts []string) string {
+	counts := map[string]int{}
+	for _, text := range texts {
+		for _, word := range strings.FieldsFunc(strings.ToLower(text), func(char rune) bool { return !unicode.IsLetter(char) }) {
+			if _, seen := counts[word]; seen {
+				continue
+			}
+			count := 0
+			for _, ngram := range ngrams {
+				count += strings.Count(word, ngram)
+			}
+			counts[word] = count
+		}
+	}
+
+	words := []string{}
+	for word, count := range counts {
+		if count > 0 {
+			words = append(words, word)
+		}
+	}
+	slices.SortFunc(words, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+	if len(words) > structures.NGRAM_DRILL_CANDIDATE_WORDS {
+		words = words[:structures.NGRAM_DRILL_CANDIDATE_WORDS]
+	}
+	if len(words) == 0 {
+		words = ngrams
+	}
+	if len(words) == 0 {
+		return ""
+	}
+
+	drill := make([]string, 0, structures.NGRAM_DRILL_WORDS)
+	for idx := 0; idx < structures.NGRAM_DRILL_WORDS; idx++ {
+		drill = append(drill, words[idx%len(words)])
+	}
+	return strings.Join(drill, " ")
+}
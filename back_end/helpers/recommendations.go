@@ -0,0 +1,146 @@
+package helpers
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"type_writer_api/structures"
+	"unicode"
+)
+
+// RecommendationProfile is what texts are matched against when recommending
+// them to a user
+type RecommendationProfile struct {
+	Wpm        float64
+	WeakKeys   []string
+	WeakNgrams []string
+	LastPlayed map[int]time.Time
+	Now        time.Time
+}
+
+// ParseRecommendationWeights reads weight overrides written as
+// "weak_keys=4,rating=0", weights left out keep their default
+func ParseRecommendationWeights(config string) (structures.RecommendationFactors, error) {
+	weights := structures.DEFAULT_RECOMMENDATION_WEIGHTS
+	fields := map[string]*float64{
+		structures.RECOMMENDATION_WEIGHT_WEAK_KEYS:   &weights.WeakKeys,
+		structures.RECOMMENDATION_WEIGHT_WEAK_NGRAMS: &weights.WeakNgrams,
+		structures.RECOMMENDATION_WEIGHT_DIFFICULTY:  &weights.Difficulty,
+		structures.RECOMMENDATION_WEIGHT_NOVELTY:     &weights.Novelty,
+		structures.RECOMMENDATION_WEIGHT_RATING:      &weights.Rating,
+	}
+
+	for _, entry := range strings.Split(config, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return weights, fmt.Errorf("bad recommendation weight %q", entry)
+		}
+		field, ok := fields[strings.TrimSpace(name)]
+		if !ok {
+			return weights, fmt.Errorf("unknown recommendation weight %q", name)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+			return weights, fmt.Errorf("bad value for recommendation weight %q", name)
+		}
+		*field = weight
+	}
+
+	return weights, nil
+}
+
+// weakDensity is the share of letters of a text that are weak keys and the
+// weak sequences found per letter
+func weakDensity(body string, weakKeys map[rune]bool, weakNgrams []string) (float64, float64) {
+	body = strings.ToLower(body)
+	letters, weak := 0, 0
+	for _, char := range body {
+		if !unicode.IsLetter(char) {
+			continue
+		}
+		letters++
+		if weakKeys[char] {
+			weak++
+		}
+	}
+	if letters == 0 {
+		return 0, 0
+	}
+	sequences := 0
+	for _, ngram := range weakNgrams {
+		sequences += strings.Count(body, ngram)
+	}
+	return float64(weak) / float64(letters), float64(sequences) / float64(letters)
+}
+
+func roundFactor(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
+
+// RankRecommendations scores every text against the profile and lists the
+// best ones first. Weak keys and sequences are scored relative to the text
+// holding the most of them, so the same inputs always rank the same way
+func RankRecommendations(texts []*structures.Text, profile RecommendationProfile, weights structures.RecommendationFactors) []*structures.Recommendation {
+	weakKeys := map[rune]bool{}
+	for _, key := range profile.WeakKeys {
+		for _, char := range strings.ToLower(key) {
+			weakKeys[char] = true
+		}
+	}
+
+	recommendations := make([]*structures.Recommendation, 0, len(texts))
+	var maxKeys, maxNgrams float64
+	for _, text := range texts {
+		keys, ngrams := weakDensity(text.TextBody, weakKeys, profile.WeakNgrams)
+		maxKeys = max(maxKeys, keys)
+		maxNgrams = max(maxNgrams, ngrams)
+		recommendations = append(recommendations, &structures.Recommendation{
+			Text:    text,
+			Factors: structures.RecommendationFactors{WeakKeys: keys, WeakNgrams: ngrams},
+		})
+	}
+
+	for _, recommendation := range recommendations {
+		factors := &recommendation.Factors
+		if maxKeys > 0 {
+			factors.WeakKeys = roundFactor(factors.WeakKeys / maxKeys)
+		}
+		if maxNgrams > 0 {
+			factors.WeakNgrams = roundFactor(factors.WeakNgrams / maxNgrams)
+		}
+
+		if pace, ok := structures.RECOMMENDATION_DIFFICULTY_WPM[recommendation.Text.Difficulty]; ok {
+			factors.Difficulty = roundFactor(max(0, 1-math.Abs(profile.Wpm-pace)/structures.RECOMMENDATION_WPM_SPREAD))
+		}
+
+		factors.Novelty = 1
+		if lastPlayed, ok := profile.LastPlayed[recommendation.Text.Id]; ok {
+			days := profile.Now.Sub(lastPlayed).Hours() / 24
+			factors.Novelty = roundFactor(min(1, max(0, days/structures.RECOMMENDATION_NOVELTY_DAYS)))
+		}
+
+		factors.Rating = structures.RECOMMENDATION_UNRATED
+		if recommendation.Text.RatingCount > 0 {
+			factors.Rating = roundFactor((recommendation.Text.AverageRating - structures.MIN_TEXT_RATING) / (structures.MAX_TEXT_RATING - structures.MIN_TEXT_RATING))
+		}
+
+		recommendation.Score = roundFactor(weights.WeakKeys*factors.WeakKeys + weights.WeakNgrams*factors.WeakNgrams +
+			weights.Difficulty*factors.Difficulty + weights.Novelty*factors.Novelty + weights.Rating*factors.Rating)
+	}
+
+	slices.SortFunc(recommendations, func(a, b *structures.Recommendation) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Text.Id, b.Text.Id))
+	})
+	if len(recommendations) > structures.MAX_RECOMMENDATIONS {
+		recommendations = recommendations[:structures.MAX_RECOMMENDATIONS]
+	}
+	return recommendations
+}
@@ -0,0 +1,128 @@
+package helpers
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"type_writer_api/structures"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TypedWords goes over the keystroke log of a text and tells, for every word
+// typed, if it was ever missed. Backspacing over a mistake does not make up
+// for it, words are lowercased and the ones too short to review are left out
+// along with words typing stopped in the middle of
+func TypedWords(text string, events []*structures.KeystrokeEvent) map[string]bool {
+	textWords := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(char rune) bool { return !unicode.IsLetter(char) }) {
+		textWords[word] = true
+	}
+
+	words := map[string]bool{}
+	word := []rune{}
+	missed := false
+
+	flush := func() {
+		length := len(word)
+		if length >= structures.MIN_REVIEW_WORD_LENGTH && length <= structures.MAX_REVIEW_WORD_LENGTH && textWords[string(word)] {
+			words[string(word)] = words[string(word)] || missed
+		}
+		word = word[:0]
+		missed = false
+	}
+
+	for _, event := range events {
+		if event.Backspace {
+			if len(word) > 0 {
+				word = word[:len(word)-1]
+			}
+			continue
+		}
+		char, size := utf8.DecodeRuneInString(event.Expected)
+		if size == 0 || size != len(event.Expected) || !unicode.IsLetter(char) {
+			flush()
+			continue
+		}
+		word = append(word, unicode.ToLower(char))
+		if !event.Correct {
+			missed = true
+		}
+	}
+	flush()
+
+	return words
+}
+
+// ScheduleReview moves a word along its SM-2 schedule after a review graded
+// from 0 to 5, failed reviews start the word over from a one day interval
+func ScheduleReview(word *structures.ReviewWord, quality int, now time.Time) {
+	if word.EaseFactor == 0 {
+		word.EaseFactor = structures.REVIEW_DEFAULT_EASE
+	}
+
+	if quality < structures.REVIEW_PASSING_QUALITY {
+		word.Repetitions = 0
+		word.IntervalDays = 1
+	} else {
+		switch word.Repetitions {
+		case 0:
+			word.IntervalDays = 1
+		case 1:
+			word.IntervalDays = 6
+		default:
+			word.IntervalDays = int(math.Round(float64(word.IntervalDays) * word.EaseFactor))
+		}
+		word.Repetitions++
+	}
+
+	lapse := float64(5 - quality)
+	word.EaseFactor = max(structures.REVIEW_MIN_EASE, math.Round((word.EaseFactor+0.1-lapse*(0.08+lapse*0.02))*100)/100)
+	word.Mastered = word.Repetitions >= structures.REVIEW_MASTERED_REPETITIONS
+	word.DueAt = now.AddDate(0, 0, word.IntervalDays)
+	word.LastReviewedAt = &now
+}
+
+// GradeReviewWords grades the typed words against the deck entries a user
+// has for them and returns the entries that changed. Missed words go into the
+// deck or start over, words typed clean only move along when they were due
+// so typing them early does not count
+func GradeReviewWords(userId int, typed map[string]bool, deck []*structures.ReviewWord, now time.Time) []*structures.ReviewWord {
+	entries := map[string]*structures.ReviewWord{}
+	for _, reviewWord := range deck {
+		entries[reviewWord.Word] = reviewWord
+	}
+
+	reviewed := []*structures.ReviewWord{}
+	for _, word := range slices.Sorted(maps.Keys(typed)) {
+		reviewWord, ok := entries[word]
+		switch {
+		case typed[word]:
+			if !ok {
+				reviewWord = &structures.ReviewWord{UserId: userId, Word: word, EaseFactor: structures.REVIEW_DEFAULT_EASE}
+			}
+			reviewWord.Misses++
+			ScheduleReview(reviewWord, structures.REVIEW_QUALITY_MISSED, now)
+		case ok && !reviewWord.Mastered && !reviewWord.DueAt.After(now):
+			ScheduleReview(reviewWord, structures.REVIEW_QUALITY_CLEAN, now)
+		default:
+			continue
+		}
+		reviewed = append(reviewed, reviewWord)
+	}
+	return reviewed
+}
+
+// BuildWordDrill repeats the words in turn until the drill is long enough
+func BuildWordDrill(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	drill := make([]string, 0, structures.REVIEW_DRILL_WORDS)
+	for idx := 0; idx < structures.REVIEW_DRILL_WORDS; idx++ {
+		drill = append(drill, words[idx%len(words)])
+	}
+	return strings.Join(drill, " ")
+}
@@ -0,0 +1,140 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"type_writer_api/structures"
+)
+
+var (
+	ErrInvalidScoreResult       = errors.New("invalid score result")
+	ErrUnsupportedResultVersion = errors.New("unsupported score result version")
+)
+
+// ParseScoreResult reads a result the way a client sent it into the typed
+// schema, legacy field names are renamed and fields outside the schema end
+// up in Extra. The parsed result is validated before it is returned
+func ParseScoreResult(raw map[string]any) (structures.ScoreResult, error) {
+	fields := map[string]any{}
+	for name, value := range raw {
+		if renamed, ok := structures.SCORE_RESULT_ALIASES[name]; ok {
+			// the current name wins when a client sends both
+			if _, sent := raw[renamed]; !sent {
+				fields[renamed] = value
+			}
+			continue
+		}
+		fields[name] = value
+	}
+
+	result := structures.ScoreResult{Version: structures.SCORE_RESULT_VERSION}
+	for name, value := range fields {
+		var err error
+		switch name {
+		case "version":
+			result.Version, err = resultInt(value)
+		case "wpm":
+			result.Wpm, err = resultFloat(value)
+		case "raw_wpm":
+			result.RawWpm, err = resultFloat(value)
+		case "cpm":
+			result.Cpm, err = resultFloat(value)
+		case "accuracy":
+			result.Accuracy, err = resultFloat(value)
+		case "errors":
+			result.Errors, err = resultInt(value)
+		case "corrected":
+			result.Corrected, err = resultInt(value)
+		case "consistency":
+			result.Consistency, err = resultFloat(value)
+		case "words":
+			result.Words, err = resultInt(value)
+		case "letters":
+			result.Letters, err = resultInt(value)
+		case "extra":
+			extra, ok := value.(map[string]any)
+			if !ok {
+				return structures.ScoreResult{}, ErrInvalidScoreResult
+			}
+			for extraName, extraValue := range extra {
+				result.Extra = withExtra(result.Extra, extraName, extraValue)
+			}
+		default:
+			result.Extra = withExtra(result.Extra, name, value)
+		}
+		if err != nil {
+			return structures.ScoreResult{}, err
+		}
+	}
+
+	if err := ValidateScoreResult(result); err != nil {
+		return structures.ScoreResult{}, err
+	}
+	return result, nil
+}
+
+// ValidateScoreResult checks a result is of the current version and that
+// every metric is within its range
+func ValidateScoreResult(result structures.ScoreResult) error {
+	if result.Version != structures.SCORE_RESULT_VERSION {
+		return ErrUnsupportedResultVersion
+	}
+	if len(result.Extra) > structures.MAX_SCORE_RESULT_EXTRA_FIELDS {
+		return ErrInvalidScoreResult
+	}
+	for _, value := range []float64{result.Wpm, result.RawWpm, result.Cpm, result.Accuracy, result.Consistency} {
+		if value < 0 {
+			return ErrInvalidScoreResult
+		}
+	}
+	for _, value := range []int{result.Errors, result.Corrected, result.Words, result.Letters} {
+		if value < 0 {
+			return ErrInvalidScoreResult
+		}
+	}
+	if result.Accuracy > 100 || result.Consistency > 100 {
+		return ErrInvalidScoreResult
+	}
+	return nil
+}
+
+func withExtra(extra map[string]any, name string, value any) map[string]any {
+	if extra == nil {
+		extra = map[string]any{}
+	}
+	extra[name] = value
+	return extra
+}
+
+// resultFloat reads a metric that may come from a bound request, where json
+// numbers are float64, or from code that built the result with ints
+func resultFloat(value any) (float64, error) {
+	var number float64
+	switch typed := value.(type) {
+	case float64:
+		number = typed
+	case int:
+		number = float64(typed)
+	case json.Number:
+		parsed, err := typed.Float64()
+		if err != nil {
+			return 0, ErrInvalidScoreResult
+		}
+		number = parsed
+	default:
+		return 0, ErrInvalidScoreResult
+	}
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, ErrInvalidScoreResult
+	}
+	return number, nil
+}
+
+func resultInt(value any) (int, error) {
+	number, err := resultFloat(value)
+	if err != nil || number != math.Trunc(number) || math.Abs(number) > math.MaxInt32 {
+		return 0, ErrInvalidScoreResult
+	}
+	return int(number), nil
+}
@@ -0,0 +1,151 @@
+package helpers
+
+import (
+	"math"
+	"slices"
+	"time"
+	"type_writer_api/structures"
+)
+
+// UserLocation loads the time zone of a user, names that cannot be loaded
+// count days in UTC
+func UserLocation(timeZone string) *time.Location {
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil || timeZone == "" || timeZone == "Local" {
+		return time.UTC
+	}
+	return loc
+}
+
+// LocalDay is the calendar day the time falls on in the time zone
+func LocalDay(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(time.DateOnly)
+}
+
+// previousDay steps back one calendar day, days have no time zone of their own
+func previousDay(day string) string {
+	date, err := time.Parse(time.DateOnly, day)
+	if err != nil {
+		return ""
+	}
+	return date.AddDate(0, 0, -1).Format(time.DateOnly)
+}
+
+// StreakDays counts the days in a row ending on the until day that have any
+// practice, days are formatted as dates
+func StreakDays(days map[string]bool, until string) int {
+	streak := 0
+	for day := until; days[day]; day = previousDay(day) {
+		streak++
+	}
+	return streak
+}
+
+// LongestStreak finds the most days in a row with any practice, the days can
+// come in any order
+func LongestStreak(days []string) int {
+	sorted := slices.Clone(days)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	longest, streak := 0, 0
+	for idx, day := range sorted {
+		if idx > 0 && previousDay(day) == sorted[idx-1] {
+			streak++
+		} else {
+			streak = 1
+		}
+		longest = max(longest, streak)
+	}
+	return longest
+}
+
+// UserStreak works out the streaks of a user out of the local days they
+// practiced on, now decides which day is today
+func UserStreak(userId int, timeZone string, days []string, now time.Time) *structures.UserStreak {
+	practiced := map[string]bool{}
+	lastPracticed := ""
+	for _, day := range days {
+		practiced[day] = true
+		lastPracticed = max(lastPracticed, day)
+	}
+
+	today := LocalDay(now, UserLocation(timeZone))
+	current := StreakDays(practiced, today)
+	if current == 0 {
+		current = StreakDays(practiced, previousDay(today))
+	}
+	return &structures.UserStreak{
+		UserId:         userId,
+		TimeZone:       timeZone,
+		Current:        current,
+		Longest:        LongestStreak(days),
+		PracticedToday: practiced[today],
+		LastPracticed:  lastPracticed,
+	}
+}
+
+func roundGoal(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// ProjectGoal fits a least squares line through the samples of a goal metric
+// over time and works out the local day it reaches the target on. Samples
+// can come in any order, now is the earliest a projection can land on
+func ProjectGoal(goal *structures.UserGoal, samples []*structures.GoalSample, timeZone string, now time.Time) *structures.GoalProgress {
+	progress := &structures.GoalProgress{UserGoal: *goal, Samples: len(samples)}
+	if len(samples) < structures.MIN_GOAL_SAMPLES {
+		return progress
+	}
+
+	// days are counted from the latest sample so the fitted line at 0 is
+	// where the user stands now
+	latest := samples[0].CreatedAt
+	for _, sample := range samples {
+		if sample.CreatedAt.After(latest) {
+			latest = sample.CreatedAt
+		}
+	}
+	var meanX, meanY float64
+	for _, sample := range samples {
+		meanX += sample.CreatedAt.Sub(latest).Hours() / 24
+		meanY += sample.Value
+	}
+	meanX /= float64(len(samples))
+	meanY /= float64(len(samples))
+	var covariance, variance float64
+	for _, sample := range samples {
+		x := sample.CreatedAt.Sub(latest).Hours()/24 - meanX
+		covariance += x * (sample.Value - meanY)
+		variance += x * x
+	}
+	slope := 0.0
+	if variance > 0 {
+		slope = covariance / variance
+	}
+	current := meanY - slope*meanX
+
+	progress.Current = roundGoal(current)
+	progress.Trend = roundGoal(slope)
+	loc := UserLocation(timeZone)
+	if current >= goal.Target {
+		progress.Achieved = true
+		progress.ProjectedDate = LocalDay(latest, loc)
+		progress.OnTrack = progress.ProjectedDate <= goal.TargetDate
+		return progress
+	}
+	if slope <= 0 {
+		return progress
+	}
+	days := (goal.Target - current) / slope
+	if days > structures.MAX_GOAL_PROJECTION_DAYS {
+		return progress
+	}
+	projected := latest.Add(time.Duration(days * 24 * float64(time.Hour)))
+	if projected.Before(now) {
+		projected = now
+	}
+	progress.ProjectedDate = LocalDay(projected, loc)
+	progress.OnTrack = progress.ProjectedDate <= goal.TargetDate
+	return progress
+}
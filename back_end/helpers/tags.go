@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"type_writer_api/structures"
+)
+
+var ErrTagTooLong = errors.New("tag name too long")
+
+// NormalizeTag trims the tag, collapses inner whitespace and lowercases it so
+// "Test", " test " and "TEST" all resolve to the same stored tag
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// NormalizeTags normalizes every tag, dropping empty and duplicated entries
+// while keeping the original order
+func NormalizeTags(tags []string) []string {
+	result := []string{}
+	seen := map[string]bool{}
+
+	for _, tag := range tags {
+		normalized := NormalizeTag(tag)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		result = append(result, normalized)
+	}
+
+	return result
+}
+
+// ValidateTags rejects normalized tags that would not fit in the tags table
+func ValidateTags(tags []string) error {
+	for _, tag := range tags {
+		if utf8.RuneCountInString(tag) > structures.MAX_TAG_NAME_LENGTH {
+			return ErrTagTooLong
+		}
+	}
+
+	return nil
+}
@@ -26,10 +26,11 @@ func CompareReflectedStructFields(firstI, secondI any) error {
 		}
 
 		if firstFieldValue.Kind() == reflect.Struct {
-			err := CompareReflectedStructFields(firstFieldValue, secondFieldValue)
+			err := CompareReflectedStructFields(firstFieldValue.Interface(), secondFieldValue.Interface())
 			if err != nil {
 				return fmt.Errorf("inner struct %v, %v", firstFieldValue.Type().Name(), err.Error())
 			}
+			continue
 		}
 
 		if firstFieldValue.Kind() == reflect.Slice || firstFieldValue.Kind() == reflect.Map {
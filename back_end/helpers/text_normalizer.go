@@ -0,0 +1,204 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"type_writer_api/structures"
+	"unicode"
+)
+
+const (
+	NORMALIZE_ACTION_REPLACE = "replace"
+	NORMALIZE_ACTION_REMOVE  = "remove"
+	NORMALIZE_ACTION_FLAG    = "flag"
+	NORMALIZE_ACTION_KEEP    = "keep"
+)
+
+// NormalizationRule matches a family of characters and decides what happens to
+// them, Replace gives the typeable equivalent used by the replace action
+type NormalizationRule struct {
+	Name    string
+	Action  string
+	Match   func(r rune) bool
+	Replace func(r rune) string
+}
+
+// TextNormalizer runs every character of a text through its rules in order,
+// the first matching rule wins
+type TextNormalizer struct {
+	Rules []NormalizationRule
+}
+
+func runeSet(runes ...rune) map[rune]bool {
+	set := map[rune]bool{}
+	for _, r := range runes {
+		set[r] = true
+	}
+	return set
+}
+
+func matchSet(set map[rune]bool) func(rune) bool {
+	return func(r rune) bool { return set[r] }
+}
+
+func replaceWith(replacement string) func(rune) string {
+	return func(rune) string { return replacement }
+}
+
+var (
+	singleQuotes = runeSet('‘', '’', '‚', '‛', '′', '‹', '›')
+	doubleQuotes = runeSet('“', '”', '„', '‟', '″', '«', '»')
+	dashes       = runeSet('‐', '‑', '‒', '–', '—', '―', '−')
+	spaces       = runeSet('\u00a0', '\u1680', '\u2000', '\u2001', '\u2002', '\u2003', '\u2004', '\u2005', '\u2006', '\u2007', '\u2008', '\u2009', '\u200a', '\u202f', '\u205f', '\u3000')
+	zeroWidth    = runeSet('\u200b', '\u200c', '\u200d', '\u2060', '\ufeff', '\u00ad')
+	ligatures    = map[rune]string{'ﬀ': "ff", 'ﬁ': "fi", 'ﬂ': "fl", 'ﬃ': "ffi", 'ﬄ': "ffl", 'ﬅ': "st", 'ﬆ': "st", 'Ĳ': "IJ", 'ĳ': "ij"}
+)
+
+// DefaultNormalizationRules maps the usual copy and paste leftovers to what a
+// keyboard produces and flags symbols nobody can be expected to type
+func DefaultNormalizationRules() []NormalizationRule {
+	return []NormalizationRule{
+		{Name: "single_quotes", Action: NORMALIZE_ACTION_REPLACE, Match: matchSet(singleQuotes), Replace: replaceWith("'")},
+		{Name: "double_quotes", Action: NORMALIZE_ACTION_REPLACE, Match: matchSet(doubleQuotes), Replace: replaceWith("\"")},
+		{Name: "dashes", Action: NORMALIZE_ACTION_REPLACE, Match: matchSet(dashes), Replace: replaceWith("-")},
+		{Name: "ellipsis", Action: NORMALIZE_ACTION_REPLACE, Match: matchSet(runeSet('…')), Replace: replaceWith("...")},
+		{Name: "spaces", Action: NORMALIZE_ACTION_REPLACE, Match: matchSet(spaces), Replace: replaceWith(" ")},
+		{
+			Name:    "ligatures",
+			Action:  NORMALIZE_ACTION_REPLACE,
+			Match:   func(r rune) bool { _, ok := ligatures[r]; return ok },
+			Replace: func(r rune) string { return ligatures[r] },
+		},
+		{Name: "zero_width", Action: NORMALIZE_ACTION_REMOVE, Match: matchSet(zeroWidth)},
+		{
+			Name:   "control_characters",
+			Action: NORMALIZE_ACTION_REMOVE,
+			Match:  func(r rune) bool { return unicode.IsControl(r) && r != '\n' && r != '\t' },
+		},
+		{
+			Name:   "untypeable_symbols",
+			Action: NORMALIZE_ACTION_FLAG,
+			Match: func(r rune) bool {
+				return unicode.Is(unicode.So, r) || unicode.Is(unicode.Co, r) || unicode.Is(unicode.Cs, r) ||
+					(!unicode.IsPrint(r) && !unicode.IsSpace(r))
+			},
+		},
+	}
+}
+
+// NewTextNormalizer builds the default pipeline with the action of some rules
+// overridden, keyed by rule name
+func NewTextNormalizer(overrides map[string]string) (*TextNormalizer, error) {
+	rules := DefaultNormalizationRules()
+
+	for name, action := range overrides {
+		switch action {
+		case NORMALIZE_ACTION_REPLACE, NORMALIZE_ACTION_REMOVE, NORMALIZE_ACTION_FLAG, NORMALIZE_ACTION_KEEP:
+		default:
+			return nil, fmt.Errorf("unknown normalization action %q for rule %q", action, name)
+		}
+
+		found := false
+		for idx := range rules {
+			if rules[idx].Name != name {
+				continue
+			}
+			if action == NORMALIZE_ACTION_REPLACE && rules[idx].Replace == nil {
+				return nil, fmt.Errorf("rule %q has no replacement", name)
+			}
+			rules[idx].Action = action
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown normalization rule %q", name)
+		}
+	}
+
+	return &TextNormalizer{Rules: rules}, nil
+}
+
+// ParseNormalizationConfig reads rule overrides written as
+// "dashes=flag,ellipsis=keep", empty entries are ignored
+func ParseNormalizationConfig(config string) (map[string]string, error) {
+	overrides := map[string]string{}
+
+	for _, entry := range strings.Split(config, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, action, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("bad normalization override %q", entry)
+		}
+		overrides[strings.TrimSpace(name)] = strings.TrimSpace(action)
+	}
+
+	return overrides, nil
+}
+
+// Normalize applies the rules to the text, returning the normalized text along
+// with every character a rule acted on or flagged, flagged characters are left
+// untouched in the result
+func (n *TextNormalizer) Normalize(text string) (string, []*structures.TextLintIssue) {
+	var builder strings.Builder
+	issues := []*structures.TextLintIssue{}
+	line, column := 1, 0
+	runes := []rune(text)
+
+	for position, char := range runes {
+		// windows line endings are normalized quietly, nobody needs to hear about them
+		if char == '\r' && position+1 < len(runes) && runes[position+1] == '\n' {
+			continue
+		}
+		column++
+
+		var matched *NormalizationRule
+		for idx := range n.Rules {
+			if n.Rules[idx].Action != NORMALIZE_ACTION_KEEP && n.Rules[idx].Match(char) {
+				matched = &n.Rules[idx]
+				break
+			}
+		}
+
+		if matched == nil {
+			builder.WriteRune(char)
+		} else {
+			issue := &structures.TextLintIssue{
+				Position:  position,
+				Line:      line,
+				Column:    column,
+				Char:      string(char),
+				CodePoint: fmt.Sprintf("U+%04X", char),
+				Rule:      matched.Name,
+				Action:    matched.Action,
+			}
+			switch matched.Action {
+			case NORMALIZE_ACTION_REPLACE:
+				issue.Replacement = matched.Replace(char)
+				builder.WriteString(issue.Replacement)
+			case NORMALIZE_ACTION_FLAG:
+				builder.WriteRune(char)
+			}
+			issues = append(issues, issue)
+		}
+
+		if char == '\n' {
+			line++
+			column = 0
+		}
+	}
+
+	return builder.String(), issues
+}
+
+// HasFlaggedIssues reports whether any of the issues still needs a human to
+// rewrite the text
+func HasFlaggedIssues(issues []*structures.TextLintIssue) bool {
+	for _, issue := range issues {
+		if issue.Action == NORMALIZE_ACTION_FLAG {
+			return true
+		}
+	}
+	return false
+}
@@ -1,22 +1,47 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 	"type_writer_api/controllers"
+	"type_writer_api/engines"
 	"type_writer_api/helpers"
 	local_middleware "type_writer_api/middleware"
+	"type_writer_api/providers/achievements"
 	"type_writer_api/providers/activities"
+	"type_writer_api/providers/challenges"
+	"type_writer_api/providers/courses"
+	"type_writer_api/providers/goals"
+	"type_writer_api/providers/keyboard_layouts"
+	"type_writer_api/providers/leaderboards"
+	"type_writer_api/providers/reviews"
 	"type_writer_api/providers/scores"
+	"type_writer_api/providers/sessions"
+	"type_writer_api/providers/stats"
+	"type_writer_api/providers/tags"
 	"type_writer_api/providers/texts"
 	"type_writer_api/providers/users"
+	"type_writer_api/services/achievements"
 	"type_writer_api/services/activites"
+	"type_writer_api/services/challenges"
+	"type_writer_api/services/courses"
+	"type_writer_api/services/goals"
+	"type_writer_api/services/keyboard_layouts"
+	"type_writer_api/services/leaderboards"
+	"type_writer_api/services/reviews"
 	"type_writer_api/services/scores"
+	"type_writer_api/services/sessions"
+	"type_writer_api/services/stats"
+	"type_writer_api/services/tags"
 	"type_writer_api/services/texts"
 	"type_writer_api/services/users"
 	"type_writer_api/structures"
 
 	"log/slog"
+	_ "time/tzdata"
 
 	"github.com/casbin/casbin/v3"
 	"github.com/golang-jwt/jwt/v5"
@@ -37,6 +62,9 @@ func main() {
 	API_PORT := os.Getenv("API_PORT")
 	ENV := os.Getenv("ENV")
 	JWT_SIGNING_KEY := os.Getenv("JWT_SIGNING_KEY")
+	TEXT_NORMALIZATION := os.Getenv("TEXT_NORMALIZATION")
+	KEYSTROKE_LOG_RETENTION_DAYS := os.Getenv("KEYSTROKE_LOG_RETENTION_DAYS")
+	RECOMMENDATION_WEIGHTS := os.Getenv("RECOMMENDATION_WEIGHTS")
 
 	// Create a slog logger, which:
 	//   - Logs to stdout.
@@ -50,7 +78,7 @@ func main() {
 	e.Use(middleware.CORS())
 
 	db, err := gorm.Open(postgres.New(postgres.Config{
-		DSN: fmt.Sprintf("host=type_writer-db-1 user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=America/New_York", DB_USER, DB_PASS, DB_NAME, DB_PORT),
+		DSN: fmt.Sprintf("host=type_writer-db-1 user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC", DB_USER, DB_PASS, DB_NAME, DB_PORT),
 	}), &gorm.Config{})
 	if err != nil {
 		e.Logger.Fatal("Error initializing DB")
@@ -64,6 +92,42 @@ func main() {
 		e.Logger.Fatal("Error loading jwt signing key from env")
 	}
 
+	// Text normalization rule overrides, e.g. "dashes=flag,ellipsis=keep"
+	normalizationOverrides, err := helpers.ParseNormalizationConfig(TEXT_NORMALIZATION)
+	if err != nil {
+		e.Logger.Fatal("Error parsing text normalization config\t", err)
+	}
+	textNormalizer, err := helpers.NewTextNormalizer(normalizationOverrides)
+	if err != nil {
+		e.Logger.Fatal("Error building text normalizer\t", err)
+	}
+
+	// Days keystroke logs are kept for, 0 keeps them forever
+	keystrokeLogRetentionDays := structures.DEFAULT_KEYSTROKE_LOG_RETENTION_DAYS
+	if KEYSTROKE_LOG_RETENTION_DAYS != "" {
+		keystrokeLogRetentionDays, err = strconv.Atoi(KEYSTROKE_LOG_RETENTION_DAYS)
+		if err != nil || keystrokeLogRetentionDays < 0 {
+			e.Logger.Fatal("Error parsing keystroke log retention\t", KEYSTROKE_LOG_RETENTION_DAYS)
+		}
+	}
+	keystrokeLogRetention := time.Duration(keystrokeLogRetentionDays) * 24 * time.Hour
+
+	// Recommendation weight overrides, e.g. "weak_keys=4,rating=0"
+	recommendationWeights, err := helpers.ParseRecommendationWeights(RECOMMENDATION_WEIGHTS)
+	if err != nil {
+		e.Logger.Fatal("Error parsing recommendation weights\t", err)
+	}
+
+	// Badge definitions achievements are awarded by
+	badges, err := os.ReadFile(structures.BADGES_CONFIG_PATH)
+	if err != nil {
+		e.Logger.Fatal("Error reading badges config\t", err)
+	}
+	achievementRules, err := engines.ParseAchievementRules(badges)
+	if err != nil {
+		e.Logger.Fatal("Error parsing badges config\t", err)
+	}
+
 	if ENV == "INTEGRATION" {
 		e.Logger.Debug("Loading test fixtures")
 		err := helpers.LoadFixturesIntoDB(db, "testing/fixtures", true)
@@ -78,18 +142,79 @@ func main() {
 	textsProvider := texts_provider.NewTextsProvider(db)
 	activitiesProvider := activities_provider.NewActivitiesProvider(db)
 	scoresProvider := scores_provider.NewScoresProvider(db)
+	tagsProvider := tags_provider.NewTagsProvider(db)
+	coursesProvider := courses_provider.NewCoursesProvider(db)
+	keyboardLayoutsProvider := keyboard_layouts_provider.NewKeyboardLayoutsProvider(db)
+	challengesProvider := challenges_provider.NewChallengesProvider(db)
+	sessionsProvider := sessions_provider.NewSessionsProvider(db)
+	leaderboardsProvider := leaderboards_provider.NewLeaderboardsProvider(db)
+	statsProvider := stats_provider.NewStatsProvider(db)
+	reviewsProvider := reviews_provider.NewReviewsProvider(db)
+	achievementsProvider := achievements_provider.NewAchievementsProvider(db)
+	goalsProvider := goals_provider.NewGoalsProvider(db)
 
 	// Services
-	usersService := users_service.NewUsersService(usersProvider)
-	textsService := texts_service.NewTextsService(textsProvider)
+	usersService := users_service.NewUsersService(usersProvider, keyboardLayoutsProvider)
+	textsService := texts_service.NewTextsService(textsProvider, textNormalizer)
 	activitiesService := activities_service.NewActivitiesService(activitiesProvider)
-	scoresService := scores_service.NewScoresService(scoresProvider)
+	scoresService := scores_service.NewScoresService(scoresProvider, textsProvider, activitiesProvider, reviewsProvider, achievementsProvider, achievementRules, keystrokeLogRetention)
+	tagsService := tags_service.NewTagsService(tagsProvider)
+	coursesService := courses_service.NewCoursesService(coursesProvider, scoresProvider)
+	keyboardLayoutsService := keyboard_layouts_service.NewKeyboardLayoutsService(keyboardLayoutsProvider, textsProvider)
+	challengesService := challenges_service.NewChallengesService(challengesProvider, activitiesProvider, textsProvider, scoresProvider, usersProvider)
+	sessionsService := sessions_service.NewSessionsService(sessionsProvider, activitiesProvider, textsProvider, reviewsProvider, achievementsProvider, achievementRules)
+	leaderboardsService := leaderboards_service.NewLeaderboardsService(leaderboardsProvider)
+	reviewsService := reviews_service.NewReviewsService(reviewsProvider, usersProvider, textsProvider)
+	statsService := stats_service.NewStatsService(statsProvider, usersProvider, keyboardLayoutsProvider, textsProvider, recommendationWeights)
+	achievementsService := achievements_service.NewAchievementsService(achievementsProvider, usersProvider, textsProvider, achievementRules)
+	goalsService := goals_service.NewGoalsService(goalsProvider, usersProvider)
+
+	// Texts stored before fingerprinting existed get one so duplicate checks cover them
+	backfilled, err := textsService.BackfillFingerprints(context.Background())
+	if err != nil {
+		e.Logger.Fatal("Error backfilling text fingerprints\t", err)
+	}
+	e.Logger.Debug("Backfilled text fingerprints: ", backfilled)
+
+	// Keystroke logs past their retention are purged on start and then daily
+	purged, err := scoresService.PurgeKeystrokeLogs(context.Background())
+	if err != nil {
+		e.Logger.Fatal("Error purging keystroke logs\t", err)
+	}
+	e.Logger.Debug("Purged keystroke logs: ", purged)
+	go func() {
+		for range time.Tick(24 * time.Hour) {
+			scoresService.PurgeKeystrokeLogs(context.Background())
+		}
+	}()
+
+	// Sequence baselines are rebuilt from everyone's latest keystrokes on start and then daily
+	baselines, err := statsService.RefreshNgramBaselines(context.Background())
+	if err != nil {
+		e.Logger.Fatal("Error refreshing ngram baselines\t", err)
+	}
+	e.Logger.Debug("Refreshed ngram baselines: ", baselines)
+	go func() {
+		for range time.Tick(24 * time.Hour) {
+			statsService.RefreshNgramBaselines(context.Background())
+		}
+	}()
 
 	// Controllers
 	userController := controllers.NewUsersController(usersService)
 	textController := controllers.NewTextsController(textsService)
 	activityController := controllers.NewActivitiesController(activitiesService)
 	scoreController := controllers.NewScoresController(scoresService)
+	tagController := controllers.NewTagsController(tagsService)
+	courseController := controllers.NewCoursesController(coursesService)
+	keyboardLayoutController := controllers.NewKeyboardLayoutsController(keyboardLayoutsService)
+	challengeController := controllers.NewChallengesController(challengesService)
+	sessionController := controllers.NewSessionsController(sessionsService)
+	leaderboardController := controllers.NewLeaderboardsController(leaderboardsService)
+	statsController := controllers.NewStatsController(statsService)
+	reviewController := controllers.NewReviewsController(reviewsService)
+	achievementController := controllers.NewAchievementsController(achievementsService)
+	goalController := controllers.NewGoalsController(goalsService)
 	authController := controllers.NewAuthController(keyString, usersService)
 
 	// Secure route group setup
@@ -100,17 +225,25 @@ func main() {
 		e.Logger.Fatal("Error loading authorization enfocer", err)
 	}
 
-	s.Use(
-		echojwt.WithConfig(echojwt.Config{
-			NewClaimsFunc: 
-				func(ctx echo.Context) jwt.Claims {
-					return new(structures.JwtCustomClaims)
-				},
-			SigningKey: keyString,
-		}),
-	)
+	jwtConfig := echojwt.Config{
+		NewClaimsFunc: 
+			func(ctx echo.Context) jwt.Claims {
+				return new(structures.JwtCustomClaims)
+			},
+		SigningKey: keyString,
+	}
+	s.Use(echojwt.WithConfig(jwtConfig))
 	s.Use(local_middleware.CasbinMiddleware(ce))
 
+	// Public routes that tailor their response to the caller read the jwt when
+	// one is sent and go on anonymously otherwise
+	optionalJwtConfig := jwtConfig
+	optionalJwtConfig.ContinueOnIgnoredError = true
+	optionalJwtConfig.ErrorHandler = func(ctx echo.Context, err error) error {
+		return nil
+	}
+	optionalJwt := echojwt.WithConfig(optionalJwtConfig)
+
 	// API routes
 	//
 	// Authentication routes
@@ -120,34 +253,116 @@ func main() {
 	e.GET("/users", userController.GetUsers)
 	e.GET("/users/:user_id", userController.GetUser)
 	e.POST("/users", userController.CreateUser)
+	e.GET("/users/:user_id/favorites", textController.GetUserFavorites, optionalJwt)
+	e.GET("/users/:user_id/stats", statsController.GetUserStats)
+	e.GET("/users/:user_id/achievements", achievementController.GetUserAchievements)
+	e.GET("/users/:user_id/streak", goalController.GetUserStreak)
 	// Secure routes
+	s.GET("/users/:user_id/keymap-stats", statsController.GetUserKeymapStats)
+	s.GET("/users/:user_id/ngram-stats", statsController.GetUserNgramStats)
+	s.POST("/users/:user_id/ngram-drills", statsController.CreateNgramDrill)
+	s.GET("/users/:user_id/recommendations", statsController.GetUserRecommendations)
+	s.POST("/users/:user_id/review-drills", reviewController.CreateReviewDrill)
+	s.GET("/users/:user_id/goals", goalController.GetUserGoals)
+	s.POST("/users/:user_id/goals", goalController.CreateUserGoal)
+	s.DELETE("/users/:user_id/goals/:goal_id", goalController.DeleteUserGoal)
 	s.PUT("/users/:user_id", userController.UpdateUser)
 	s.DELETE("/users/:user_id", userController.DeleteUser)
 
 	// Text routes
-	e.GET("/texts", textController.GetTexts)
-	e.GET("/texts/:text_id", textController.GetText)
+	e.GET("/texts", textController.GetTexts, optionalJwt)
+	e.GET("/texts/:text_id", textController.GetText, optionalJwt)
+	e.GET("/texts/shared/:share_slug", textController.GetSharedText, optionalJwt)
+	e.GET("/texts/:text_id/key_profile", keyboardLayoutController.GetTextKeyProfile)
+	e.GET("/texts/:text_id/similar", textController.GetSimilarTexts, optionalJwt)
+	e.GET("/licenses", textController.GetLicenses)
 	// Secure routes
 	s.POST("/texts", textController.CreateText)
+	s.POST("/texts/lint", textController.LintText)
 	s.PUT("/texts/:text_id", textController.UpdateText)
 	s.DELETE("/texts/:text_id", textController.DeleteText)
+	s.PUT("/texts/:text_id/rating", textController.RateText)
+	s.DELETE("/texts/:text_id/rating", textController.DeleteTextRating)
+	s.POST("/texts/:text_id/favorite", textController.FavoriteText)
+	s.DELETE("/texts/:text_id/favorite", textController.UnfavoriteText)
+
+	// Moderation routes
+	// Secure routes
+	s.GET("/moderation/texts", textController.GetModerationTexts)
+	s.POST("/moderation/texts/:text_id/approve", textController.ApproveText)
+	s.POST("/moderation/texts/:text_id/reject", textController.RejectText)
+	s.GET("/moderation/scores", scoreController.GetFlaggedScores)
+	s.POST("/moderation/scores/:score_id/approve", scoreController.ApproveScore)
+	s.POST("/moderation/scores/:score_id/reject", scoreController.RejectScore)
+
+	// Tag routes
+	e.GET("/tags", tagController.GetTags)
+	e.GET("/tags/:tag_id", tagController.GetTag)
+	// Secure routes
+	s.POST("/tags", tagController.CreateTag)
+	s.PUT("/tags/:tag_id", tagController.UpdateTag)
+	s.DELETE("/tags/:tag_id", tagController.DeleteTag)
+	s.POST("/tags/:tag_id/merge", tagController.MergeTag)
+
+	// Keyboard layout routes
+	e.GET("/keyboard_layouts", keyboardLayoutController.GetKeyboardLayouts)
+	e.GET("/keyboard_layouts/:layout_id", keyboardLayoutController.GetKeyboardLayout)
+	// Secure routes
+	s.POST("/keyboard_layouts", keyboardLayoutController.CreateKeyboardLayout)
+	s.PUT("/keyboard_layouts/:layout_id", keyboardLayoutController.UpdateKeyboardLayout)
+	s.DELETE("/keyboard_layouts/:layout_id", keyboardLayoutController.DeleteKeyboardLayout)
 
 	// Activity routes
 	e.GET("/activities", activityController.GetActivities)
 	e.GET("/activities/:activity_id", activityController.GetActivity)
+	e.GET("/activity_kinds", activityController.GetActivityKinds)
 	// Secure routes
 	s.POST("/activities", activityController.CreateActivity)
 	s.PUT("/activities/:activity_id", activityController.UpdateActivity)
 	s.DELETE("/activities/:activity_id", activityController.DeleteActivity)
+	s.POST("/activities/:activity_id/evaluate", activityController.EvaluateActivity)
+	s.POST("/scoring_formulas/test", activityController.TestScoringFormula)
 
 	// Score routes
 	e.GET("/scores", scoreController.GetScores)
 	e.GET("/scores/:score_id", scoreController.GetScore)
 	// Secure routes
+	s.GET("/scores/:score_id/keystrokes", scoreController.GetScoreKeystrokes)
 	s.POST("/scores", scoreController.CreateScore)
 	s.PUT("/scores/:score_id", scoreController.UpdateScore)
 	s.DELETE("/scores/:score_id", scoreController.DeleteScore)
 
+	// Typing session routes, scores of regular users come out of finished sessions
+	s.POST("/sessions", sessionController.StartSession)
+	s.GET("/sessions/:session_id", sessionController.GetSession)
+	s.POST("/sessions/:session_id/events", sessionController.AddSessionEvents)
+	s.POST("/sessions/:session_id/finish", sessionController.FinishSession)
+
+	// Course routes
+	e.GET("/courses", courseController.GetCourses)
+	e.GET("/courses/:course_id", courseController.GetCourse)
+	// Secure routes
+	s.GET("/courses/:course_id/progress", courseController.GetCourseProgress)
+	s.POST("/courses", courseController.CreateCourse)
+	s.PUT("/courses/:course_id", courseController.UpdateCourse)
+	s.DELETE("/courses/:course_id", courseController.DeleteCourse)
+
+	// Daily challenge routes
+	e.GET("/challenges/today", challengeController.GetTodayChallenge)
+	e.GET("/challenges/:date", challengeController.GetChallenge)
+	e.GET("/challenges/:date/leaderboard", challengeController.GetChallengeLeaderboard)
+	// Secure routes
+	s.POST("/challenges/:date/attempts", challengeController.CreateChallengeAttempt)
+	s.GET("/challenge_queue", challengeController.GetChallengeQueue)
+	s.POST("/challenge_queue", challengeController.CreateChallengeQueueEntry)
+	s.DELETE("/challenge_queue/:entry_id", challengeController.DeleteChallengeQueueEntry)
+
+	// Leaderboard routes
+	e.GET("/leaderboards", leaderboardController.GetLeaderboard)
+	// Secure routes
+	s.GET("/leaderboards/me", leaderboardController.GetMyRank)
+	s.GET("/leaderboards/around_me", leaderboardController.GetAroundMe)
+
 	// Server start
 	e.Logger.Fatal(e.Start(fmt.Sprintf(":%s", API_PORT)))
 }
@@ -9,12 +9,31 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func contextUserGetter(ctx echo.Context) (string, error) {
+// ContextClaimsGetter returns the claims of the jwt validated for the request,
+// only available on routes behind the jwt middleware
+func ContextClaimsGetter(ctx echo.Context) (*structures.JwtCustomClaims, error) {
 	token, err := echo.ContextGet[*jwt.Token](ctx, "user")
+	if err != nil {
+		return nil, err
+	}
+	return token.Claims.(*structures.JwtCustomClaims), nil
+}
+
+// ContextViewer returns the id and type of the user behind the request, zero
+// values for anonymous requests on routes where the jwt is optional
+func ContextViewer(ctx echo.Context) (int, string) {
+	claims, err := ContextClaimsGetter(ctx)
+	if err != nil {
+		return 0, ""
+	}
+	return claims.UserId, claims.UserType
+}
+
+func contextUserGetter(ctx echo.Context) (string, error) {
+	claims, err := ContextClaimsGetter(ctx)
 	if err != nil {
 		return "", err
 	}	
-	claims := token.Claims.(*structures.JwtCustomClaims)
 	return claims.UserType, nil
 }
 
@@ -34,5 +53,3 @@ func CasbinMiddleware(enforcer *casbin.Enforcer) echo.MiddlewareFunc {
 		}
 	}
 }
-
-
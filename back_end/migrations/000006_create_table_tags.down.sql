@@ -0,0 +1,18 @@
+ALTER TABLE texts ADD COLUMN IF NOT EXISTS tags jsonb not null DEFAULT '[]';
+
+UPDATE texts SET tags = linked.tags
+FROM (
+    SELECT text_tags.text_id, jsonb_agg(tags.name ORDER BY tags.name) AS tags
+    FROM text_tags
+    JOIN tags ON tags.id = text_tags.tag_id
+    GROUP BY text_tags.text_id
+) AS linked
+WHERE texts.id = linked.text_id;
+
+ALTER TABLE texts ALTER COLUMN tags DROP DEFAULT;
+
+DROP TABLE IF EXISTS text_tags;
+
+DROP TRIGGER IF EXISTS update_tags_changetimestamp ON tags;
+
+DROP TABLE IF EXISTS tags;
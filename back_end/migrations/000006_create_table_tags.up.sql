@@ -0,0 +1,38 @@
+CREATE TABLE tags(
+    id serial primary key,
+    name varchar(60) not null unique,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE TRIGGER update_tags_changetimestamp BEFORE UPDATE
+    ON tags FOR EACH ROW EXECUTE PROCEDURE
+    update_updated_at_column();
+
+CREATE TABLE text_tags(
+    text_id integer not null REFERENCES texts ON DELETE CASCADE,
+    tag_id integer not null REFERENCES tags ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    primary key (text_id, tag_id)
+);
+
+CREATE INDEX text_tags_tag_id_idx ON text_tags (tag_id);
+
+-- move the existing jsonb tags into the normalized tables, applying the same
+-- normalization rules as the api: trimmed, inner whitespace collapsed, lowercase.
+-- The old column had no length limit, tags too long for the name column are
+-- dropped rather than failing the migration
+INSERT INTO tags (name)
+SELECT DISTINCT lower(regexp_replace(trim(tag.value), '\s+', ' ', 'g'))
+FROM texts, jsonb_array_elements_text(texts.tags) AS tag(value)
+WHERE trim(tag.value) <> ''
+AND char_length(regexp_replace(trim(tag.value), '\s+', ' ', 'g')) <= 60
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO text_tags (text_id, tag_id)
+SELECT DISTINCT texts.id, tags.id
+FROM texts, jsonb_array_elements_text(texts.tags) AS tag(value)
+JOIN tags ON tags.name = lower(regexp_replace(trim(tag.value), '\s+', ' ', 'g'))
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE texts DROP COLUMN tags;
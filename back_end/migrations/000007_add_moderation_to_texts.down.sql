@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS texts_status_idx;
+
+ALTER TABLE texts
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS reviewer_notes,
+    DROP COLUMN IF EXISTS submitter_id,
+    DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS text_status;
@@ -0,0 +1,10 @@
+CREATE TYPE text_status AS ENUM ('draft', 'pending', 'approved', 'rejected');
+
+-- texts that already exist were curated before moderation, so they stay public
+ALTER TABLE texts
+    ADD COLUMN status text_status not null DEFAULT 'approved',
+    ADD COLUMN submitter_id integer REFERENCES users ON DELETE SET NULL,
+    ADD COLUMN reviewer_notes text not null DEFAULT '',
+    ADD COLUMN reviewed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX texts_status_idx ON texts (status);
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS update_course_lessons_changetimestamp ON course_lessons;
+
+DROP TABLE IF EXISTS course_lessons;
+
+DROP TRIGGER IF EXISTS update_courses_changetimestamp ON courses;
+
+DROP TABLE IF EXISTS courses;
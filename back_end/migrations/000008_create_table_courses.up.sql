@@ -0,0 +1,28 @@
+CREATE TABLE courses(
+    id serial primary key,
+    title varchar(60) not null,
+    description text not null DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE TRIGGER update_courses_changetimestamp BEFORE UPDATE
+    ON courses FOR EACH ROW EXECUTE PROCEDURE
+    update_updated_at_column();
+
+CREATE TABLE course_lessons(
+    id serial primary key,
+    course_id integer not null REFERENCES courses ON DELETE CASCADE,
+    position integer not null,
+    text_id integer not null REFERENCES texts,
+    activity_id integer not null REFERENCES activities,
+    min_wpm numeric not null DEFAULT 0,
+    min_accuracy numeric not null DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    unique (course_id, position)
+);
+
+CREATE TRIGGER update_course_lessons_changetimestamp BEFORE UPDATE
+    ON course_lessons FOR EACH ROW EXECUTE PROCEDURE
+    update_updated_at_column();
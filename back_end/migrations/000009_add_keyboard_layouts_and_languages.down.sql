@@ -0,0 +1,9 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS keyboard_layout;
+
+DROP INDEX IF EXISTS texts_language_idx;
+
+ALTER TABLE texts
+    DROP COLUMN IF EXISTS language;
+
+DROP TABLE IF EXISTS keyboard_layouts;
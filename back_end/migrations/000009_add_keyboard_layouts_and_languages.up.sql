@@ -0,0 +1,21 @@
+CREATE TABLE keyboard_layouts(
+    id serial primary key,
+    name varchar(60) not null unique,
+    description text not null DEFAULT '',
+    keys jsonb not null DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE TRIGGER update_keyboard_layouts_changetimestamp BEFORE UPDATE
+    ON keyboard_layouts FOR EACH ROW EXECUTE PROCEDURE
+    update_updated_at_column();
+
+-- everything stored so far was written and typed as english on qwerty
+ALTER TABLE texts
+    ADD COLUMN language varchar(35) not null DEFAULT 'en';
+
+CREATE INDEX texts_language_idx ON texts (language);
+
+ALTER TABLE users
+    ADD COLUMN keyboard_layout varchar(60) not null DEFAULT 'qwerty';
@@ -0,0 +1,2 @@
+ALTER TABLE texts
+    DROP COLUMN IF EXISTS fingerprint;
@@ -0,0 +1,4 @@
+-- minhash signature of the text body shingles, existing texts are fingerprinted
+-- by the api on startup
+ALTER TABLE texts
+    ADD COLUMN fingerprint jsonb;
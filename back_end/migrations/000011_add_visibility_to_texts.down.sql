@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS texts_visibility_idx;
+DROP INDEX IF EXISTS texts_owner_id_idx;
+
+ALTER TABLE texts
+    DROP COLUMN IF EXISTS share_slug,
+    DROP COLUMN IF EXISTS visibility,
+    DROP COLUMN IF EXISTS owner_id;
+
+DROP TYPE IF EXISTS text_visibility;
@@ -0,0 +1,12 @@
+CREATE TYPE text_visibility AS ENUM ('public', 'unlisted', 'private');
+
+-- texts that already exist belong to whoever submitted them and stay public
+ALTER TABLE texts
+    ADD COLUMN owner_id integer REFERENCES users ON DELETE SET NULL,
+    ADD COLUMN visibility text_visibility not null DEFAULT 'public',
+    ADD COLUMN share_slug varchar(32) UNIQUE;
+
+UPDATE texts SET owner_id = submitter_id;
+
+CREATE INDEX texts_owner_id_idx ON texts (owner_id);
+CREATE INDEX texts_visibility_idx ON texts (visibility);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS texts_license_idx;
+
+ALTER TABLE texts
+    DROP COLUMN IF EXISTS attribution_required,
+    DROP COLUMN IF EXISTS license,
+    DROP COLUMN IF EXISTS source_url,
+    DROP COLUMN IF EXISTS source,
+    DROP COLUMN IF EXISTS author;
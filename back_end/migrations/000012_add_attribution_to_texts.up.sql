@@ -0,0 +1,9 @@
+-- texts that already exist were written for the app, so they carry no license
+ALTER TABLE texts
+    ADD COLUMN author varchar(255) not null DEFAULT '',
+    ADD COLUMN source varchar(255) not null DEFAULT '',
+    ADD COLUMN source_url varchar(2048) not null DEFAULT '',
+    ADD COLUMN license varchar(60) not null DEFAULT '',
+    ADD COLUMN attribution_required boolean not null DEFAULT false;
+
+CREATE INDEX texts_license_idx ON texts (license);
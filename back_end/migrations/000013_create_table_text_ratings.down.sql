@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS scores_text_id_idx;
+DROP TABLE IF EXISTS text_favorites;
+DROP TABLE IF EXISTS text_ratings;
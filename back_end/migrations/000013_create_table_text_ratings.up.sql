@@ -0,0 +1,24 @@
+CREATE TABLE text_ratings(
+    user_id integer not null REFERENCES users ON DELETE CASCADE,
+    text_id integer not null REFERENCES texts ON DELETE CASCADE,
+    rating smallint not null CHECK (rating BETWEEN 1 AND 5),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    primary key (user_id, text_id)
+);
+
+CREATE TRIGGER update_text_ratings_changetimestamp BEFORE UPDATE
+    ON text_ratings FOR EACH ROW EXECUTE PROCEDURE
+    update_updated_at_column();
+
+CREATE INDEX text_ratings_text_id_idx ON text_ratings (text_id);
+
+CREATE TABLE text_favorites(
+    user_id integer not null REFERENCES users ON DELETE CASCADE,
+    text_id integer not null REFERENCES texts ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    primary key (user_id, text_id)
+);
+
+CREATE INDEX text_favorites_text_id_idx ON text_favorites (text_id);
+CREATE INDEX scores_text_id_idx ON scores (text_id);
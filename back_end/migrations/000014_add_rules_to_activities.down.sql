@@ -0,0 +1,3 @@
+ALTER TABLE activities
+    DROP CONSTRAINT IF EXISTS activities_rules_schema_version_check,
+    DROP COLUMN IF EXISTS rules;
@@ -0,0 +1,6 @@
+-- activities that already exist keep behaving like a plain run through the
+-- whole text until an admin gives them rules
+ALTER TABLE activities
+    ADD COLUMN rules jsonb not null DEFAULT '{"schema_version": 1}'::jsonb,
+    ADD CONSTRAINT activities_rules_schema_version_check
+        CHECK (jsonb_typeof(rules -> 'schema_version') = 'number');
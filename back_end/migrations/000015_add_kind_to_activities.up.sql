@@ -0,0 +1,4 @@
+-- kinds are registered in code so new ones need no migration, activities that
+-- already exist have no limits in their rules and play as zen runs
+ALTER TABLE activities
+    ADD COLUMN kind varchar(30) not null DEFAULT 'zen';
@@ -0,0 +1,5 @@
+ALTER TABLE scores
+    DROP COLUMN IF EXISTS final_score;
+
+ALTER TABLE activities
+    DROP COLUMN IF EXISTS scoring_formula;
@@ -0,0 +1,9 @@
+-- activities without a formula score by wpm, so that is what existing scores get
+ALTER TABLE activities
+    ADD COLUMN scoring_formula varchar(500) not null DEFAULT '';
+
+ALTER TABLE scores
+    ADD COLUMN final_score double precision not null DEFAULT 0;
+
+UPDATE scores SET final_score = (result ->> 'wpm')::double precision
+    WHERE jsonb_typeof(result -> 'wpm') = 'number';
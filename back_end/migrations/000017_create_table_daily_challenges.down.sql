@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS challenge_attempts;
+DROP TABLE IF EXISTS daily_challenges;
+DROP TABLE IF EXISTS challenge_queue;
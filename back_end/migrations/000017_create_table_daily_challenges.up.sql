@@ -0,0 +1,38 @@
+-- challenges are picked from the front of the queue when their day first
+-- comes up, the queue is empty once every curated pair has been used
+CREATE TABLE challenge_queue(
+    id serial primary key,
+    activity_id integer not null REFERENCES activities ON DELETE CASCADE,
+    text_id integer not null REFERENCES texts ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE TRIGGER update_challenge_queue_changetimestamp BEFORE UPDATE
+    ON challenge_queue FOR EACH ROW EXECUTE PROCEDURE
+    update_updated_at_column();
+
+-- the date is a calendar day with no time zone, it is open for as long as it
+-- is that day somewhere
+CREATE TABLE daily_challenges(
+    id serial primary key,
+    challenge_date varchar(10) not null UNIQUE CHECK (challenge_date ~ '^\d{4}-\d{2}-\d{2}$'),
+    activity_id integer not null REFERENCES activities,
+    text_id integer not null REFERENCES texts,
+    curated boolean not null DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE TRIGGER update_daily_challenges_changetimestamp BEFORE UPDATE
+    ON daily_challenges FOR EACH ROW EXECUTE PROCEDURE
+    update_updated_at_column();
+
+-- only the first attempt of each user is ranked
+CREATE TABLE challenge_attempts(
+    challenge_id integer not null REFERENCES daily_challenges ON DELETE CASCADE,
+    user_id integer not null REFERENCES users ON DELETE CASCADE,
+    score_id integer not null UNIQUE REFERENCES scores ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    primary key (challenge_id, user_id)
+);
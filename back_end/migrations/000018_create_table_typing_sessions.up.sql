@@ -0,0 +1,23 @@
+-- a session is a typing run timed by the server, its score is only created
+-- once the session is finished before expiring
+CREATE TABLE typing_sessions(
+    id serial primary key,
+    user_id integer not null REFERENCES users ON DELETE CASCADE,
+    activity_id integer not null REFERENCES activities ON DELETE CASCADE,
+    text_id integer not null REFERENCES texts ON DELETE CASCADE,
+    nonce varchar(64) not null UNIQUE,
+    status varchar(20) not null DEFAULT 'active' CHECK (status IN ('active', 'finished', 'expired')),
+    keystrokes jsonb not null DEFAULT '[]'::jsonb CHECK (jsonb_typeof(keystrokes) = 'array'),
+    score_id integer UNIQUE REFERENCES scores ON DELETE SET NULL,
+    started_at TIMESTAMP WITH TIME ZONE not null DEFAULT now(),
+    expires_at TIMESTAMP WITH TIME ZONE not null,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE INDEX typing_sessions_user_id_idx ON typing_sessions (user_id);
+
+CREATE TRIGGER update_typing_sessions_changetimestamp BEFORE UPDATE
+    ON typing_sessions FOR EACH ROW EXECUTE PROCEDURE
+    update_updated_at_column();
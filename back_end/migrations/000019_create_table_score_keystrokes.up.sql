@@ -0,0 +1,11 @@
+-- keystroke logs are kept apart from scores so listing scores never loads
+-- them, old logs are purged according to the retention setting
+CREATE TABLE score_keystrokes(
+    score_id integer primary key REFERENCES scores ON DELETE CASCADE,
+    encoding varchar(30) not null,
+    event_count integer not null CHECK (event_count >= 0),
+    data bytea not null,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE INDEX score_keystrokes_created_at_idx ON score_keystrokes (created_at);
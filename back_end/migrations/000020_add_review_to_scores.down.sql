@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS scores_review_status_idx;
+
+ALTER TABLE scores
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS flag_reasons,
+    DROP COLUMN IF EXISTS review_status;
+
+DROP TYPE IF EXISTS score_review_status;
@@ -0,0 +1,9 @@
+CREATE TYPE score_review_status AS ENUM ('clean', 'flagged', 'approved', 'rejected');
+
+-- scores that already exist predate the plausibility checks, so they stay ranked
+ALTER TABLE scores
+    ADD COLUMN review_status score_review_status not null DEFAULT 'clean',
+    ADD COLUMN flag_reasons jsonb not null DEFAULT '[]',
+    ADD COLUMN reviewed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX scores_review_status_idx ON scores (review_status);
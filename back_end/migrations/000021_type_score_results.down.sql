@@ -0,0 +1,5 @@
+ALTER TABLE scores DROP CONSTRAINT IF EXISTS scores_result_version_check;
+
+-- extra fields go back next to the metrics, the schema version is dropped
+UPDATE scores SET result = (result - 'version' - 'extra')
+    || CASE WHEN jsonb_typeof(result -> 'extra') = 'object' THEN result -> 'extra' ELSE '{}'::jsonb END;
@@ -0,0 +1,31 @@
+-- results used to be free form, they are rewritten into version 1 of the
+-- result schema. lpm is what the first clients called cpm, fields outside the
+-- schema move into extra and metrics that are not numbers become 0
+CREATE FUNCTION score_result_number(result jsonb, field text) RETURNS numeric AS $$
+    SELECT CASE WHEN jsonb_typeof(result -> field) = 'number' THEN GREATEST((result ->> field)::numeric, 0) ELSE 0 END
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE FUNCTION score_result_extra(result jsonb) RETURNS jsonb AS $$
+    SELECT CASE WHEN jsonb_typeof(result -> 'extra') = 'object' THEN result -> 'extra' ELSE '{}'::jsonb END
+        || (result - ARRAY['version', 'wpm', 'raw_wpm', 'cpm', 'lpm', 'accuracy', 'errors', 'corrected', 'consistency', 'words', 'letters', 'extra'])
+$$ LANGUAGE sql IMMUTABLE;
+
+UPDATE scores SET result = CASE WHEN jsonb_typeof(result) = 'object' THEN result ELSE '{}'::jsonb END;
+
+UPDATE scores SET result = jsonb_build_object(
+    'version', 1,
+    'wpm', score_result_number(result, 'wpm'),
+    'raw_wpm', score_result_number(result, 'raw_wpm'),
+    'cpm', score_result_number(result, CASE WHEN result ? 'cpm' THEN 'cpm' ELSE 'lpm' END),
+    'accuracy', LEAST(score_result_number(result, 'accuracy'), 100),
+    'errors', round(score_result_number(result, 'errors')),
+    'corrected', round(score_result_number(result, 'corrected')),
+    'consistency', LEAST(score_result_number(result, 'consistency'), 100),
+    'words', round(score_result_number(result, 'words')),
+    'letters', round(score_result_number(result, 'letters'))
+) || jsonb_strip_nulls(jsonb_build_object('extra', NULLIF(score_result_extra(result), '{}'::jsonb)));
+
+DROP FUNCTION score_result_extra(jsonb);
+DROP FUNCTION score_result_number(jsonb, text);
+
+ALTER TABLE scores ADD CONSTRAINT scores_result_version_check CHECK ((result ->> 'version')::integer = 1);
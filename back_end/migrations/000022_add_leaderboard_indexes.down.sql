@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS scores_leaderboard_best_idx;
+DROP INDEX IF EXISTS scores_leaderboard_filter_idx;
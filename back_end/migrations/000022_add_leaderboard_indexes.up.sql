@@ -0,0 +1,7 @@
+-- leaderboards rank the best ranked score of every user, narrowed down by
+-- activity, text and how recent the scores are
+CREATE INDEX scores_leaderboard_filter_idx ON scores (activity_id, text_id, created_at)
+    WHERE review_status IN ('clean', 'approved');
+
+CREATE INDEX scores_leaderboard_best_idx ON scores (user_id, (COALESCE((result ->> 'wpm')::numeric, 0)) DESC, created_at)
+    WHERE review_status IN ('clean', 'approved');
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS scores_user_id_created_at_idx;
+DROP TRIGGER IF EXISTS update_scores_user_stats ON scores;
+DROP FUNCTION IF EXISTS update_user_stats();
+DROP FUNCTION IF EXISTS apply_user_stats(scores, integer);
+DROP TABLE IF EXISTS user_daily_stats;
+DROP TABLE IF EXISTS user_stats;
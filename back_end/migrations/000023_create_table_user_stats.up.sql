@@ -0,0 +1,88 @@
+-- running totals of every user's scores, overall and per UTC day, kept up to
+-- date by a trigger so stats never have to scan the whole score history.
+-- Only ranked scores are counted, flagged ones join in once approved
+CREATE TABLE user_stats(
+    user_id integer primary key REFERENCES users ON DELETE CASCADE,
+    scores integer not null DEFAULT 0,
+    duration bigint not null DEFAULT 0,
+    wpm_sum double precision not null DEFAULT 0,
+    accuracy_sum double precision not null DEFAULT 0
+);
+
+CREATE TABLE user_daily_stats(
+    user_id integer not null REFERENCES users ON DELETE CASCADE,
+    day date not null,
+    scores integer not null DEFAULT 0,
+    duration bigint not null DEFAULT 0,
+    wpm_sum double precision not null DEFAULT 0,
+    accuracy_sum double precision not null DEFAULT 0,
+    primary key (user_id, day)
+);
+
+CREATE OR REPLACE FUNCTION apply_user_stats(score scores, sign integer)
+RETURNS void AS $$
+DECLARE
+    score_wpm double precision := COALESCE((score.result ->> 'wpm')::double precision, 0);
+    score_accuracy double precision := COALESCE((score.result ->> 'accuracy')::double precision, 0);
+    score_day date := (score.created_at AT TIME ZONE 'UTC')::date;
+BEGIN
+    IF score.user_id IS NULL OR score.review_status NOT IN ('clean', 'approved') THEN
+        RETURN;
+    END IF;
+
+    INSERT INTO user_stats AS stats (user_id, scores, duration, wpm_sum, accuracy_sum)
+    VALUES (score.user_id, sign, sign * COALESCE(score.duration, 0), sign * score_wpm, sign * score_accuracy)
+    ON CONFLICT (user_id) DO UPDATE SET
+        scores = stats.scores + EXCLUDED.scores,
+        duration = stats.duration + EXCLUDED.duration,
+        wpm_sum = stats.wpm_sum + EXCLUDED.wpm_sum,
+        accuracy_sum = stats.accuracy_sum + EXCLUDED.accuracy_sum;
+
+    INSERT INTO user_daily_stats AS stats (user_id, day, scores, duration, wpm_sum, accuracy_sum)
+    VALUES (score.user_id, score_day, sign, sign * COALESCE(score.duration, 0), sign * score_wpm, sign * score_accuracy)
+    ON CONFLICT (user_id, day) DO UPDATE SET
+        scores = stats.scores + EXCLUDED.scores,
+        duration = stats.duration + EXCLUDED.duration,
+        wpm_sum = stats.wpm_sum + EXCLUDED.wpm_sum,
+        accuracy_sum = stats.accuracy_sum + EXCLUDED.accuracy_sum;
+
+    DELETE FROM user_stats WHERE user_id = score.user_id AND scores <= 0;
+    DELETE FROM user_daily_stats WHERE user_id = score.user_id AND day = score_day AND scores <= 0;
+END;
+$$ language 'plpgsql';
+
+CREATE OR REPLACE FUNCTION update_user_stats()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM apply_user_stats(OLD, -1);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM apply_user_stats(NEW, 1);
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_scores_user_stats AFTER INSERT OR UPDATE OR DELETE
+    ON scores FOR EACH ROW EXECUTE PROCEDURE
+    update_user_stats();
+
+INSERT INTO user_stats (user_id, scores, duration, wpm_sum, accuracy_sum)
+SELECT user_id, count(*), COALESCE(sum(duration), 0),
+    COALESCE(sum((result ->> 'wpm')::double precision), 0),
+    COALESCE(sum((result ->> 'accuracy')::double precision), 0)
+FROM scores
+WHERE user_id IS NOT NULL AND review_status IN ('clean', 'approved')
+GROUP BY user_id;
+
+INSERT INTO user_daily_stats (user_id, day, scores, duration, wpm_sum, accuracy_sum)
+SELECT user_id, (created_at AT TIME ZONE 'UTC')::date, count(*), COALESCE(sum(duration), 0),
+    COALESCE(sum((result ->> 'wpm')::double precision), 0),
+    COALESCE(sum((result ->> 'accuracy')::double precision), 0)
+FROM scores
+WHERE user_id IS NOT NULL AND review_status IN ('clean', 'approved')
+GROUP BY user_id, (created_at AT TIME ZONE 'UTC')::date;
+
+-- personal bests and rolling averages read a user's scores newest first
+CREATE INDEX scores_user_id_created_at_idx ON scores (user_id, created_at);
@@ -0,0 +1,8 @@
+-- median latency of every letter sequence over the latest keystroke logs of
+-- all users, weak sequences of a user are measured against it
+CREATE TABLE ngram_baselines(
+    ngram varchar(3) primary key,
+    samples integer not null,
+    median_latency double precision not null,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
@@ -0,0 +1,23 @@
+-- words every user keeps mistyping, scheduled for review SM-2 style. Words
+-- only make it into the deck once missed often enough and leave it when
+-- mastered, a word missed again afterwards comes back
+CREATE TABLE review_words(
+    user_id integer not null REFERENCES users ON DELETE CASCADE,
+    word varchar(60) not null,
+    misses integer not null DEFAULT 0,
+    repetitions integer not null DEFAULT 0,
+    ease_factor double precision not null DEFAULT 2.5,
+    interval_days integer not null DEFAULT 0,
+    due_at TIMESTAMP WITH TIME ZONE not null DEFAULT now(),
+    mastered boolean not null DEFAULT false,
+    last_reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    primary key (user_id, word)
+);
+
+CREATE INDEX review_words_user_id_due_at_idx ON review_words (user_id, due_at) WHERE NOT mastered;
+
+CREATE TRIGGER update_review_words_changetimestamp BEFORE UPDATE
+    ON review_words FOR EACH ROW EXECUTE PROCEDURE
+    update_updated_at_column();
@@ -0,0 +1,9 @@
+-- badges every user earned, badges themselves are defined in the config so
+-- only their id is kept. Each badge is awarded once
+CREATE TABLE user_achievements(
+    user_id integer not null REFERENCES users ON DELETE CASCADE,
+    badge_id varchar(60) not null,
+    score_id integer REFERENCES scores ON DELETE SET NULL,
+    awarded_at TIMESTAMP WITH TIME ZONE not null DEFAULT now(),
+    primary key (user_id, badge_id)
+);
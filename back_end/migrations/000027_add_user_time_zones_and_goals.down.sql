@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS update_user_goals_changetimestamp ON user_goals;
+
+DROP TABLE IF EXISTS user_goals;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS time_zone;
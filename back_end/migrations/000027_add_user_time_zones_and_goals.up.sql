@@ -0,0 +1,23 @@
+-- days of practice are counted in the time zone of every user rather than in
+-- the one of the database connection
+ALTER TABLE users
+    ADD COLUMN time_zone varchar(64) not null DEFAULT 'UTC';
+
+-- targets users set themselves on one of the result metrics, to be reached
+-- by the end of the target date in their time zone. The date is a calendar
+-- day with no time zone like challenge dates
+CREATE TABLE user_goals(
+    id serial primary key,
+    user_id integer not null REFERENCES users ON DELETE CASCADE,
+    metric varchar(30) not null,
+    target double precision not null,
+    target_date varchar(10) not null CHECK (target_date ~ '^\d{4}-\d{2}-\d{2}$'),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE INDEX user_goals_user_id_idx ON user_goals (user_id);
+
+CREATE TRIGGER update_user_goals_changetimestamp BEFORE UPDATE
+    ON user_goals FOR EACH ROW EXECUTE PROCEDURE
+    update_updated_at_column();
@@ -0,0 +1,6 @@
+ALTER TABLE course_lessons
+    DROP CONSTRAINT IF EXISTS course_lessons_text_id_fkey;
+
+ALTER TABLE course_lessons
+    ADD CONSTRAINT course_lessons_text_id_fkey
+    FOREIGN KEY (text_id) REFERENCES texts;
@@ -0,0 +1,8 @@
+-- lessons go away with the text they point to instead of blocking its
+-- deletion, the same way tags, ratings and challenges already do
+ALTER TABLE course_lessons
+    DROP CONSTRAINT IF EXISTS course_lessons_text_id_fkey;
+
+ALTER TABLE course_lessons
+    ADD CONSTRAINT course_lessons_text_id_fkey
+    FOREIGN KEY (text_id) REFERENCES texts ON DELETE CASCADE;
@@ -0,0 +1,96 @@
+package achievements_provider
+
+import (
+	"context"
+	"time"
+	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AchievementsProviderInterface interface {
+	GetUserAchievements(ctx context.Context, userId int) ([]*structures.UserAchievement, error)
+	AwardAchievements(ctx context.Context, achievements []*structures.UserAchievement) (int64, error)
+	CountRankedScores(ctx context.Context, userId int, until time.Time) (int, error)
+	GetScoreDays(ctx context.Context, userId int, until time.Time, limit int) ([]string, error)
+	GetRankedScores(ctx context.Context, afterId int, limit int) ([]*structures.Score, error)
+}
+
+type AchievementsProvider struct {
+	Db *gorm.DB
+}
+
+func (a *AchievementsProvider) GetUserAchievements(ctx context.Context, userId int) ([]*structures.UserAchievement, error) {
+	achievements := []*structures.UserAchievement{}
+	err := a.Db.WithContext(ctx).Table(structures.USER_ACHIEVEMENT_TABLE_NAME).
+		Where("user_id = ?", userId).
+		Order("awarded_at, badge_id").
+		Find(&achievements).Error
+	if err != nil {
+		return nil, err
+	}
+	return achievements, nil
+}
+
+// AwardAchievements stores the achievements, badges a user already has keep
+// the score they were first earned with. It returns how many were new
+func (a *AchievementsProvider) AwardAchievements(ctx context.Context, achievements []*structures.UserAchievement) (int64, error) {
+	result := a.Db.WithContext(ctx).Table(structures.USER_ACHIEVEMENT_TABLE_NAME).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&achievements)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// CountRankedScores counts the scores of a user up to the given time, scores
+// still flagged or rejected on review do not count
+func (a *AchievementsProvider) CountRankedScores(ctx context.Context, userId int, until time.Time) (int, error) {
+	var count int64
+	err := a.Db.WithContext(ctx).Table(structures.SCORE_TABLE_NAME).
+		Where("user_id = ? AND review_status IN ? AND created_at <= ?", userId, structures.RANKED_SCORE_REVIEWS, until).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// GetScoreDays lists the latest days a user has ranked scores on up to the
+// given time, days are counted in the time zone of the user and come latest
+// first
+func (a *AchievementsProvider) GetScoreDays(ctx context.Context, userId int, until time.Time, limit int) ([]string, error) {
+	days := []string{}
+	err := a.Db.WithContext(ctx).Table(structures.SCORE_TABLE_NAME).
+		Select("DISTINCT to_char(scores.created_at AT TIME ZONE users.time_zone, 'YYYY-MM-DD') AS day").
+		Joins("JOIN users ON users.id = scores.user_id").
+		Where("scores.user_id = ? AND scores.review_status IN ? AND scores.created_at <= ?", userId, structures.RANKED_SCORE_REVIEWS, until).
+		Order("day DESC").Limit(limit).
+		Pluck("day", &days).Error
+	if err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// GetRankedScores pages through the ranked scores of every user in the order
+// they were stored
+func (a *AchievementsProvider) GetRankedScores(ctx context.Context, afterId int, limit int) ([]*structures.Score, error) {
+	scores := []*structures.Score{}
+	err := a.Db.WithContext(ctx).Table(structures.SCORE_TABLE_NAME).
+		Where("id > ? AND user_id IS NOT NULL AND review_status IN ?", afterId, structures.RANKED_SCORE_REVIEWS).
+		Order("id").Limit(limit).
+		Find(&scores).Error
+	if err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
+
+func NewAchievementsProvider(db *gorm.DB) *AchievementsProvider {
+	return &AchievementsProvider{
+		Db: db,
+	}
+}
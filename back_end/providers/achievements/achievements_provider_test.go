@@ -0,0 +1,117 @@
+package achievements_provider
+
+import (
+	"context"
+	"testing"
+	"time"
+	"type_writer_api/structures"
+	"type_writer_api/testing/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetUserAchievementsSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	achievementsProvider := NewAchievementsProvider(mockGorm)
+
+	mockDB.ExpectQuery(`SELECT \* FROM "user_achievements" WHERE user_id = \$1 ORDER BY awarded_at, badge_id`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "badge_id", "score_id", "awarded_at"}).
+			AddRow(2, "first_60_wpm", 5, time.Now()).
+			AddRow(2, "7_day_streak", nil, time.Now()))
+
+	result, err := achievementsProvider.GetUserAchievements(context.Background(), 2)
+
+	if err != nil {
+		t.Fatalf("error in fetching user achievements %v", err)
+	}
+
+	if len(result) != 2 || result[0].BadgeId != "first_60_wpm" || result[0].ScoreId == nil || *result[0].ScoreId != 5 || result[1].ScoreId != nil {
+		t.Fatalf("unexpected user achievements: %v", result)
+	}
+}
+
+func TestAwardAchievementsSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	achievementsProvider := NewAchievementsProvider(mockGorm)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`INSERT INTO "user_achievements" \("user_id","badge_id","score_id","awarded_at"\) VALUES \(\$1,\$2,\$3,\$4\),\(\$5,\$6,\$7,\$8\) ON CONFLICT DO NOTHING`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectCommit()
+
+	scoreId := 5
+	result, err := achievementsProvider.AwardAchievements(context.Background(), []*structures.UserAchievement{
+		{UserId: 2, BadgeId: "first_60_wpm", ScoreId: &scoreId, AwardedAt: time.Now()},
+		{UserId: 2, BadgeId: "flawless_hard_text", ScoreId: &scoreId, AwardedAt: time.Now()},
+	})
+
+	if err != nil {
+		t.Fatalf("error in awarding achievements %v", err)
+	}
+
+	if result != 1 {
+		t.Fatalf("unexpected result: expected %v, got %v", 1, result)
+	}
+}
+
+func TestCountRankedScoresSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	achievementsProvider := NewAchievementsProvider(mockGorm)
+
+	until := time.Now()
+	mockDB.ExpectQuery(`SELECT count\(\*\) FROM "scores" WHERE user_id = \$1 AND review_status IN \(\$2,\$3\) AND created_at <= \$4`).
+		WithArgs(2, structures.SCORE_REVIEW_CLEAN, structures.SCORE_REVIEW_APPROVED, until).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	result, err := achievementsProvider.CountRankedScores(context.Background(), 2, until)
+
+	if err != nil {
+		t.Fatalf("error in counting ranked scores %v", err)
+	}
+
+	if result != 42 {
+		t.Fatalf("unexpected result: expected %v, got %v", 42, result)
+	}
+}
+
+func TestGetScoreDaysSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	achievementsProvider := NewAchievementsProvider(mockGorm)
+
+	until := time.Now()
+	mockDB.ExpectQuery(`SELECT DISTINCT to_char\(scores.created_at AT TIME ZONE users.time_zone, 'YYYY-MM-DD'\) AS day FROM "scores" JOIN users ON users.id = scores.user_id WHERE scores.user_id = \$1 AND scores.review_status IN \(\$2,\$3\) AND scores.created_at <= \$4 ORDER BY day DESC LIMIT \$5`).
+		WithArgs(2, structures.SCORE_REVIEW_CLEAN, structures.SCORE_REVIEW_APPROVED, until, structures.MAX_STREAK_DAYS).
+		WillReturnRows(sqlmock.NewRows([]string{"day"}).AddRow("2026-10-19").AddRow("2026-10-18"))
+
+	result, err := achievementsProvider.GetScoreDays(context.Background(), 2, until, structures.MAX_STREAK_DAYS)
+
+	if err != nil {
+		t.Fatalf("error in fetching score days %v", err)
+	}
+
+	if len(result) != 2 || result[0] != "2026-10-19" || result[1] != "2026-10-18" {
+		t.Fatalf("unexpected score days: %v", result)
+	}
+}
+
+func TestGetRankedScoresSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	achievementsProvider := NewAchievementsProvider(mockGorm)
+
+	mockDB.ExpectQuery(`SELECT \* FROM "scores" WHERE id > \$1 AND user_id IS NOT NULL AND review_status IN \(\$2,\$3\) ORDER BY id LIMIT \$4`).
+		WithArgs(10, structures.SCORE_REVIEW_CLEAN, structures.SCORE_REVIEW_APPROVED, 500).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "text_id", "review_status"}).
+			AddRow(11, 2, 3, structures.SCORE_REVIEW_CLEAN).
+			AddRow(14, 1, 3, structures.SCORE_REVIEW_APPROVED))
+
+	result, err := achievementsProvider.GetRankedScores(context.Background(), 10, 500)
+
+	if err != nil {
+		t.Fatalf("error in fetching ranked scores %v", err)
+	}
+
+	if len(result) != 2 || result[0].Id != 11 || result[1].UserId != 1 {
+		t.Fatalf("unexpected ranked scores: %v", result)
+	}
+}
@@ -199,3 +199,55 @@ func TestDeleteActivitySuccess(t *testing.T) {
 		t.Fatalf("unexpected result: expected %v, got %v", true, result)
 	}
 }
+
+func TestGetActivityRulesSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	activitiesProvider := NewActivitiesProvider(mockGorm)
+
+	resultRows := sqlmock.NewRows([]string{
+		"id",
+		"name",
+		"description",
+		"rules",
+	}).AddRow(
+		1,
+		"sudden death",
+		"one mistake and it is over",
+		`{"schema_version": 1, "word_target": 50, "stop_on_error": true, "allowed_text_types": ["drill"]}`,
+	)
+
+	mockDB.ExpectQuery(`SELECT \* FROM "activities" WHERE id = .+ OR name = .+`).WillReturnRows(resultRows)
+
+	inputId := 1
+	result, err := activitiesProvider.GetActivityByIdOrName(context.Background(), &inputId, nil)
+	if err != nil {
+		t.Fatalf("error in fetching activity %v", err)
+	}
+
+	expectedRules := structures.ActivityRules{SchemaVersion: 1, WordTarget: 50, StopOnError: true, AllowedTextTypes: []string{"drill"}}
+	if err := helpers.CompareReflectedStructFields(result.Rules, expectedRules); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateActivityRulesSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	activitiesProvider := NewActivitiesProvider(mockGorm)
+
+	activity := structures.Activity{
+		Id:    1,
+		Name:  "zen",
+		Rules: structures.ActivityRules{SchemaVersion: 1, BlindMode: true},
+	}
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`UPDATE "activities" SET "name"=.+,"rules"=.+,"updated_at"=.+ WHERE "id" = .+`).
+		WithArgs("zen", `{"schema_version":1,"stop_on_error":false,"must_correct_errors":false,"blind_mode":true,"accuracy_threshold":0}`, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectCommit()
+
+	_, err := activitiesProvider.UpdateActivity(context.Background(), activity)
+	if err != nil {
+		t.Fatalf("error in updating activity %v", err)
+	}
+}
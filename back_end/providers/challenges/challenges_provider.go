@@ -0,0 +1,136 @@
+package challenges_provider
+
+import (
+	"context"
+	"time"
+	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChallengesProviderInterface interface {
+	GetChallengeByDate(ctx context.Context, date string) (*structures.DailyChallenge, error)
+	ScheduleChallenge(ctx context.Context, challengeInfo structures.DailyChallenge, queueEntryId int) (bool, error)
+	GetChallengeQueue(ctx context.Context) ([]*structures.ChallengeQueueEntry, error)
+	CreateChallengeQueueEntry(ctx context.Context, entryInfo structures.ChallengeQueueEntry) (*structures.ChallengeQueueEntry, error)
+	DeleteChallengeQueueEntry(ctx context.Context, entryId int) (bool, error)
+	CreateChallengeAttempt(ctx context.Context, attemptInfo structures.ChallengeAttempt) (bool, error)
+	GetChallengeLeaderboard(ctx context.Context, challengeId int) ([]*structures.ChallengeLeaderboardEntry, error)
+	GetFirstChallengeScore(ctx context.Context, userId, activityId, textId int, startsAt, endsAt time.Time) (*structures.Score, error)
+}
+
+type ChallengesProvider struct {
+	Db *gorm.DB
+}
+
+func (c *ChallengesProvider) GetChallengeByDate(ctx context.Context, date string) (*structures.DailyChallenge, error) {
+	var challenge *structures.DailyChallenge
+	err := c.Db.WithContext(ctx).Table(structures.DAILY_CHALLENGE_TABLE_NAME).
+		First(&challenge, "challenge_date = ?", date).Error
+	if err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// ScheduleChallenge stores the challenge of a day unless another request got
+// there first, the queue entry it came from is only used up when it was stored
+func (c *ChallengesProvider) ScheduleChallenge(ctx context.Context, challengeInfo structures.DailyChallenge, queueEntryId int) (bool, error) {
+	scheduled := false
+	err := c.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Table(structures.DAILY_CHALLENGE_TABLE_NAME).
+			Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "challenge_date"}}, DoNothing: true}).
+			Create(&challengeInfo)
+		if result.Error != nil {
+			return result.Error
+		}
+		scheduled = result.RowsAffected != 0
+		if !scheduled || queueEntryId == 0 {
+			return nil
+		}
+		return tx.Table(structures.CHALLENGE_QUEUE_TABLE_NAME).Delete(&structures.ChallengeQueueEntry{Id: queueEntryId}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return scheduled, nil
+}
+
+func (c *ChallengesProvider) GetChallengeQueue(ctx context.Context) ([]*structures.ChallengeQueueEntry, error) {
+	var entries []*structures.ChallengeQueueEntry
+	err := c.Db.WithContext(ctx).Table(structures.CHALLENGE_QUEUE_TABLE_NAME).Order("id").Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (c *ChallengesProvider) CreateChallengeQueueEntry(ctx context.Context, entryInfo structures.ChallengeQueueEntry) (*structures.ChallengeQueueEntry, error) {
+	err := c.Db.WithContext(ctx).Table(structures.CHALLENGE_QUEUE_TABLE_NAME).Create(&entryInfo).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entryInfo, nil
+}
+
+func (c *ChallengesProvider) DeleteChallengeQueueEntry(ctx context.Context, entryId int) (bool, error) {
+	result := c.Db.WithContext(ctx).Table(structures.CHALLENGE_QUEUE_TABLE_NAME).Delete(&structures.ChallengeQueueEntry{Id: entryId})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected != 0, nil
+}
+
+// CreateChallengeAttempt records the ranked attempt of a user, it reports
+// false when the user already has one or the score was already entered
+func (c *ChallengesProvider) CreateChallengeAttempt(ctx context.Context, attemptInfo structures.ChallengeAttempt) (bool, error) {
+	result := c.Db.WithContext(ctx).Table(structures.CHALLENGE_ATTEMPT_TABLE_NAME).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&attemptInfo)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected != 0, nil
+}
+
+// GetChallengeLeaderboard lists the ranked attempts of a challenge best
+// first, on equal final scores the earlier attempt goes first. Scores flagged
+// or rejected in review are left out
+func (c *ChallengesProvider) GetChallengeLeaderboard(ctx context.Context, challengeId int) ([]*structures.ChallengeLeaderboardEntry, error) {
+	entries := []*structures.ChallengeLeaderboardEntry{}
+	err := c.Db.WithContext(ctx).Table(structures.CHALLENGE_ATTEMPT_TABLE_NAME).
+		Select("challenge_attempts.user_id, users.username, scores.id AS score_id, scores.final_score, scores.created_at").
+		Joins("JOIN scores ON scores.id = challenge_attempts.score_id").
+		Joins("JOIN users ON users.id = challenge_attempts.user_id").
+		Where("challenge_attempts.challenge_id = ?", challengeId).
+		Where("scores.review_status IN ?", structures.RANKED_SCORE_REVIEWS).
+		Order("scores.final_score DESC, scores.created_at, challenge_attempts.user_id").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetFirstChallengeScore finds the earliest score of a user on the challenge
+// pair within the bounds of the challenge day
+func (c *ChallengesProvider) GetFirstChallengeScore(ctx context.Context, userId, activityId, textId int, startsAt, endsAt time.Time) (*structures.Score, error) {
+	var score *structures.Score
+	err := c.Db.WithContext(ctx).Table(structures.SCORE_TABLE_NAME).
+		Select("id, user_id, activity_id, text_id, created_at").
+		Where("user_id = ? AND activity_id = ? AND text_id = ?", userId, activityId, textId).
+		Where("created_at >= ? AND created_at < ?", startsAt, endsAt).
+		Order("created_at, id").
+		Take(&score).Error
+	if err != nil {
+		return nil, err
+	}
+	return score, nil
+}
+
+func NewChallengesProvider(db *gorm.DB) *ChallengesProvider {
+	return &ChallengesProvider{
+		Db: db,
+	}
+}
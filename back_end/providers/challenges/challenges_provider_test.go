@@ -0,0 +1,190 @@
+package challenges_provider
+
+import (
+	"context"
+	"testing"
+	"time"
+	"type_writer_api/helpers"
+	"type_writer_api/structures"
+	"type_writer_api/testing/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetChallengeByDateSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	challengesProvider := NewChallengesProvider(mockGorm)
+
+	expectedRow := structures.DailyChallenge{Id: 1, ChallengeDate: "2026-10-19", ActivityId: 2, TextId: 3, Curated: true}
+
+	mockDB.ExpectQuery(`SELECT \* FROM "daily_challenges" WHERE challenge_date = .+ ORDER BY "daily_challenges"\."id" LIMIT .+`).
+		WithArgs("2026-10-19", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "challenge_date", "activity_id", "text_id", "curated"}).
+			AddRow(expectedRow.Id, expectedRow.ChallengeDate, expectedRow.ActivityId, expectedRow.TextId, expectedRow.Curated))
+
+	result, err := challengesProvider.GetChallengeByDate(context.Background(), "2026-10-19")
+
+	if err != nil {
+		t.Fatalf("error in fetching challenge %v", err)
+	}
+
+	if err := helpers.CompareReflectedStructFields(*result, expectedRow); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScheduleChallengeSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	challengesProvider := NewChallengesProvider(mockGorm)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`INSERT INTO "daily_challenges" \("challenge_date","activity_id","text_id","curated","created_at","updated_at"\) VALUES .+ ON CONFLICT \("challenge_date"\) DO NOTHING RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mockDB.ExpectExec(`DELETE FROM "challenge_queue" WHERE "challenge_queue"\."id" = .+`).WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectCommit()
+
+	result, err := challengesProvider.ScheduleChallenge(context.Background(), structures.DailyChallenge{ChallengeDate: "2026-10-19", ActivityId: 2, TextId: 3, Curated: true}, 4)
+
+	if err != nil {
+		t.Fatalf("error in scheduling challenge %v", err)
+	}
+
+	if result != true {
+		t.Fatalf("unexpected result: expected %v, got %v", true, result)
+	}
+}
+
+func TestScheduleChallengeAlreadyScheduled(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	challengesProvider := NewChallengesProvider(mockGorm)
+
+	// the queue entry is kept for another day when the insert did nothing
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`INSERT INTO "daily_challenges" .+ ON CONFLICT \("challenge_date"\) DO NOTHING RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mockDB.ExpectCommit()
+
+	result, err := challengesProvider.ScheduleChallenge(context.Background(), structures.DailyChallenge{ChallengeDate: "2026-10-19", ActivityId: 2, TextId: 3, Curated: true}, 4)
+
+	if err != nil {
+		t.Fatalf("error in scheduling challenge %v", err)
+	}
+
+	if result != false {
+		t.Fatalf("unexpected result: expected %v, got %v", false, result)
+	}
+
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetChallengeQueueSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	challengesProvider := NewChallengesProvider(mockGorm)
+
+	expectedRows := []structures.ChallengeQueueEntry{
+		{Id: 1, ActivityId: 1, TextId: 2, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{Id: 2, ActivityId: 2, TextId: 3, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+
+	resultRows := sqlmock.NewRows([]string{"id", "activity_id", "text_id", "created_at", "updated_at"})
+	for _, expectedRow := range expectedRows {
+		resultRows.AddRow(expectedRow.Id, expectedRow.ActivityId, expectedRow.TextId, expectedRow.CreatedAt, expectedRow.UpdatedAt)
+	}
+
+	mockDB.ExpectQuery(`SELECT \* FROM "challenge_queue" ORDER BY id`).WillReturnRows(resultRows)
+
+	result, err := challengesProvider.GetChallengeQueue(context.Background())
+
+	if err != nil {
+		t.Fatalf("error in fetching challenge queue %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("unexpected result length: expected %v, got %v", 2, len(result))
+	}
+
+	for indx, resultRow := range result {
+		err := helpers.CompareReflectedStructFields(*resultRow, expectedRows[indx])
+		if err != nil {
+			t.Fatalf("row %v failed: %v\n", indx, err.Error())
+		}
+	}
+}
+
+func TestCreateChallengeAttemptSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	challengesProvider := NewChallengesProvider(mockGorm)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`INSERT INTO "challenge_attempts" \("challenge_id","user_id","score_id","created_at"\) VALUES .+ ON CONFLICT DO NOTHING`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDB.ExpectCommit()
+
+	result, err := challengesProvider.CreateChallengeAttempt(context.Background(), structures.ChallengeAttempt{ChallengeId: 1, UserId: 1, ScoreId: 1})
+
+	if err != nil {
+		t.Fatalf("error in creating challenge attempt %v", err)
+	}
+
+	if result != false {
+		t.Fatalf("unexpected result: expected %v, got %v", false, result)
+	}
+}
+
+func TestGetChallengeLeaderboardSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	challengesProvider := NewChallengesProvider(mockGorm)
+
+	expectedRows := []structures.ChallengeLeaderboardEntry{
+		{UserId: 2, Username: "second", ScoreId: 5, FinalScore: 80, CreatedAt: time.Now()},
+		{UserId: 1, Username: "first", ScoreId: 4, FinalScore: 70, CreatedAt: time.Now()},
+	}
+
+	resultRows := sqlmock.NewRows([]string{"user_id", "username", "score_id", "final_score", "created_at"})
+	for _, expectedRow := range expectedRows {
+		resultRows.AddRow(expectedRow.UserId, expectedRow.Username, expectedRow.ScoreId, expectedRow.FinalScore, expectedRow.CreatedAt)
+	}
+
+	mockDB.ExpectQuery(`SELECT challenge_attempts\.user_id, users\.username, scores\.id AS score_id, scores\.final_score, scores\.created_at FROM "challenge_attempts" JOIN scores .+ JOIN users .+ WHERE challenge_attempts\.challenge_id = .+ AND scores\.review_status IN .+ ORDER BY scores\.final_score DESC`).
+		WithArgs(1, structures.SCORE_REVIEW_CLEAN, structures.SCORE_REVIEW_APPROVED).
+		WillReturnRows(resultRows)
+
+	result, err := challengesProvider.GetChallengeLeaderboard(context.Background(), 1)
+
+	if err != nil {
+		t.Fatalf("error in fetching challenge leaderboard %v", err)
+	}
+
+	for indx, resultRow := range result {
+		err := helpers.CompareReflectedStructFields(*resultRow, expectedRows[indx])
+		if err != nil {
+			t.Fatalf("row %v failed: %v\n", indx, err.Error())
+		}
+	}
+}
+
+func TestGetFirstChallengeScoreSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	challengesProvider := NewChallengesProvider(mockGorm)
+
+	startsAt := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	endsAt := startsAt.AddDate(0, 0, 1)
+	expectedRow := structures.Score{Id: 4, UserId: 1, ActivityId: 2, TextId: 3, CreatedAt: startsAt.Add(time.Hour)}
+
+	mockDB.ExpectQuery(`SELECT id, user_id, activity_id, text_id, created_at FROM "scores" WHERE \(user_id = .+ AND activity_id = .+ AND text_id = .+\) AND \(created_at >= .+ AND created_at < .+\) ORDER BY created_at, id LIMIT .+`).
+		WithArgs(1, 2, 3, startsAt, endsAt, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "activity_id", "text_id", "created_at"}).
+			AddRow(expectedRow.Id, expectedRow.UserId, expectedRow.ActivityId, expectedRow.TextId, expectedRow.CreatedAt))
+
+	result, err := challengesProvider.GetFirstChallengeScore(context.Background(), 1, 2, 3, startsAt, endsAt)
+
+	if err != nil {
+		t.Fatalf("error in fetching first challenge score %v", err)
+	}
+
+	if err := helpers.CompareReflectedStructFields(*result, expectedRow); err != nil {
+		t.Fatal(err)
+	}
+}
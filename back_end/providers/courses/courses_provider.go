@@ -0,0 +1,117 @@
+package courses_provider
+
+import (
+	"context"
+	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+)
+
+type CoursesProviderInterface interface {
+	GetCourses(ctx context.Context) ([]*structures.Course, error)
+	GetCourseById(ctx context.Context, courseId int) (*structures.Course, error)
+	CreateCourse(ctx context.Context, courseInfo structures.Course) (*structures.Course, error)
+	UpdateCourse(ctx context.Context, updatedCourseInfo structures.Course) (*structures.Course, error)
+	DeleteCourse(ctx context.Context, courseId int) (bool, error)
+}
+
+type CoursesProvider struct {
+	Db *gorm.DB
+}
+
+// replaceCourseLessons swaps the lessons of the course for the given ones,
+// keeping the order given by each lesson position
+func replaceCourseLessons(tx *gorm.DB, courseId int, lessons []*structures.CourseLesson) error {
+	err := tx.Exec("DELETE FROM course_lessons WHERE course_id = ?", courseId).Error
+	if err != nil {
+		return err
+	}
+	if len(lessons) == 0 {
+		return nil
+	}
+
+	for _, lesson := range lessons {
+		lesson.Id = 0
+		lesson.CourseId = courseId
+	}
+	return tx.Table(structures.COURSE_LESSON_TABLE_NAME).Create(&lessons).Error
+}
+
+func (c *CoursesProvider) getCourseLessons(db *gorm.DB, courseId int) ([]*structures.CourseLesson, error) {
+	lessons := []*structures.CourseLesson{}
+	err := db.Table(structures.COURSE_LESSON_TABLE_NAME).
+		Where("course_id = ?", courseId).
+		Order("position").
+		Find(&lessons).Error
+	if err != nil {
+		return nil, err
+	}
+	return lessons, nil
+}
+
+func (c *CoursesProvider) GetCourses(ctx context.Context) ([]*structures.Course, error) {
+	var courses []*structures.Course
+	err := c.Db.WithContext(ctx).Table(structures.COURSE_TABLE_NAME).Find(&courses).Error
+	if err != nil {
+		return nil, err
+	}
+	return courses, nil
+}
+
+func (c *CoursesProvider) GetCourseById(ctx context.Context, courseId int) (*structures.Course, error) {
+	var course *structures.Course
+	db := c.Db.WithContext(ctx)
+	err := db.Table(structures.COURSE_TABLE_NAME).First(&course, "id = ?", courseId).Error
+	if err != nil {
+		return nil, err
+	}
+
+	course.Lessons, err = c.getCourseLessons(db, courseId)
+	if err != nil {
+		return nil, err
+	}
+	return course, nil
+}
+
+func (c *CoursesProvider) CreateCourse(ctx context.Context, courseInfo structures.Course) (*structures.Course, error) {
+	err := c.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table(structures.COURSE_TABLE_NAME).Create(&courseInfo).Error
+		if err != nil {
+			return err
+		}
+		return replaceCourseLessons(tx, courseInfo.Id, courseInfo.Lessons)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &courseInfo, nil
+}
+
+func (c *CoursesProvider) UpdateCourse(ctx context.Context, updatedCourseInfo structures.Course) (*structures.Course, error) {
+	err := c.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table(structures.COURSE_TABLE_NAME).Updates(&updatedCourseInfo).Error
+		if err != nil {
+			return err
+		}
+		return replaceCourseLessons(tx, updatedCourseInfo.Id, updatedCourseInfo.Lessons)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updatedCourseInfo, nil
+}
+
+func (c *CoursesProvider) DeleteCourse(ctx context.Context, courseId int) (bool, error) {
+	var deleteCourse = structures.Course{Id: courseId}
+	err := c.Db.WithContext(ctx).Table(structures.COURSE_TABLE_NAME).Delete(&deleteCourse).Error
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func NewCoursesProvider(db *gorm.DB) *CoursesProvider {
+	return &CoursesProvider{
+		Db: db,
+	}
+}
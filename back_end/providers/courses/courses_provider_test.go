@@ -0,0 +1,159 @@
+package courses_provider
+
+import (
+	"context"
+	"testing"
+	"time"
+	"type_writer_api/helpers"
+	"type_writer_api/structures"
+	"type_writer_api/testing/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetCoursesSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	coursesProvider := NewCoursesProvider(mockGorm)
+
+	expectedRows := []structures.Course{
+		{Id: 1, Title: "home row", Description: "learn the home row", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{Id: 2, Title: "top row", Description: "learn the top row", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+
+	resultRows := sqlmock.NewRows([]string{"id", "title", "description", "created_at", "updated_at"})
+	for _, expectedRow := range expectedRows {
+		resultRows.AddRow(expectedRow.Id, expectedRow.Title, expectedRow.Description, expectedRow.CreatedAt, expectedRow.UpdatedAt)
+	}
+
+	mockDB.ExpectQuery(`SELECT \* FROM "courses"`).WillReturnRows(resultRows)
+
+	result, err := coursesProvider.GetCourses(context.Background())
+
+	if err != nil {
+		t.Fatalf("error in fetching courses %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("unexpected result length: expected %v, got %v", 2, len(result))
+	}
+
+	for indx, resultRow := range result {
+		err := helpers.CompareReflectedStructFields(*resultRow, expectedRows[indx])
+		if err != nil {
+			t.Fatalf("row %v failed: %v\n", indx, err.Error())
+		}
+	}
+}
+
+func TestGetCourseByIdSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	coursesProvider := NewCoursesProvider(mockGorm)
+
+	expectedRow := structures.Course{
+		Id:          1,
+		Title:       "home row",
+		Description: "learn the home row",
+		Lessons: []*structures.CourseLesson{
+			{Id: 1, CourseId: 1, Position: 1, TextId: 1, ActivityId: 1, MinWpm: 20, MinAccuracy: 90},
+			{Id: 2, CourseId: 1, Position: 2, TextId: 2, ActivityId: 1, MinWpm: 30, MinAccuracy: 95},
+		},
+	}
+
+	mockDB.ExpectQuery(`SELECT \* FROM "courses" WHERE id = .+ ORDER BY "courses"\."id" LIMIT .+`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description"}).AddRow(1, "home row", "learn the home row"))
+
+	lessonRows := sqlmock.NewRows([]string{"id", "course_id", "position", "text_id", "activity_id", "min_wpm", "min_accuracy"})
+	for _, lesson := range expectedRow.Lessons {
+		lessonRows.AddRow(lesson.Id, lesson.CourseId, lesson.Position, lesson.TextId, lesson.ActivityId, lesson.MinWpm, lesson.MinAccuracy)
+	}
+	mockDB.ExpectQuery(`SELECT \* FROM "course_lessons" WHERE course_id = .+ ORDER BY position`).WillReturnRows(lessonRows)
+
+	result, err := coursesProvider.GetCourseById(context.Background(), 1)
+
+	if err != nil {
+		t.Fatalf("error in fetching course %v", err)
+	}
+
+	if err := helpers.CompareReflectedStructFields(*result, expectedRow); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateCourseSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	coursesProvider := NewCoursesProvider(mockGorm)
+
+	inputCourse := structures.Course{
+		Title:       "home row",
+		Description: "learn the home row",
+		Lessons: []*structures.CourseLesson{
+			{Position: 1, TextId: 1, ActivityId: 1, MinWpm: 20, MinAccuracy: 90},
+		},
+	}
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`INSERT INTO "courses" \("title","description","created_at","updated_at"\) VALUES .+ RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mockDB.ExpectExec(`DELETE FROM course_lessons WHERE course_id = .+`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDB.ExpectQuery(`INSERT INTO "course_lessons" \("course_id","position","text_id","activity_id","min_wpm","min_accuracy","created_at","updated_at"\) VALUES .+ RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mockDB.ExpectCommit()
+
+	result, err := coursesProvider.CreateCourse(context.Background(), inputCourse)
+
+	if err != nil {
+		t.Fatalf("error in creating course %v", err)
+	}
+
+	if result.Id != 1 || result.Lessons[0].Id != 1 || result.Lessons[0].CourseId != 1 {
+		t.Fatalf("unexpected created course %+v, lesson %+v", result, result.Lessons[0])
+	}
+}
+
+func TestUpdateCourseSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	coursesProvider := NewCoursesProvider(mockGorm)
+
+	expectedRow := structures.Course{
+		Id:          1,
+		Title:       "home row",
+		Description: "learn the home row again",
+		Lessons:     []*structures.CourseLesson{},
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`UPDATE "courses" SET "title"=.+,"description"=.+,"created_at"=.+,"updated_at"=.+ WHERE "id" = .+`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectExec(`DELETE FROM course_lessons WHERE course_id = .+`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+	mockDB.ExpectCommit()
+
+	result, err := coursesProvider.UpdateCourse(context.Background(), expectedRow)
+
+	if err != nil {
+		t.Fatalf("error in updating course %v", err)
+	}
+
+	if err := helpers.CompareReflectedStructFields(*result, expectedRow); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteCourseSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	coursesProvider := NewCoursesProvider(mockGorm)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`FROM "courses" WHERE "courses"\."id" = .+`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectCommit()
+
+	result, err := coursesProvider.DeleteCourse(context.Background(), 1)
+
+	if err != nil {
+		t.Fatalf("error in deleting course %v", err)
+	}
+
+	if result != true {
+		t.Fatalf("unexpected result: expected %v, got %v", true, result)
+	}
+}
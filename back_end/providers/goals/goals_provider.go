@@ -0,0 +1,104 @@
+package goals_provider
+
+import (
+	"context"
+	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+)
+
+type GoalsProviderInterface interface {
+	GetUserGoals(ctx context.Context, userId int) ([]*structures.UserGoal, error)
+	CountUserGoals(ctx context.Context, userId int) (int, error)
+	CreateUserGoal(ctx context.Context, goalInfo structures.UserGoal) (*structures.UserGoal, error)
+	DeleteUserGoal(ctx context.Context, userId int, goalId int) (bool, error)
+	GetGoalSamples(ctx context.Context, userId int, metric string, limit int) ([]*structures.GoalSample, error)
+	GetPracticeDays(ctx context.Context, userId int) ([]string, error)
+}
+
+type GoalsProvider struct {
+	Db *gorm.DB
+}
+
+func (g *GoalsProvider) GetUserGoals(ctx context.Context, userId int) ([]*structures.UserGoal, error) {
+	goals := []*structures.UserGoal{}
+	err := g.Db.WithContext(ctx).Table(structures.USER_GOAL_TABLE_NAME).
+		Where("user_id = ?", userId).
+		Order("target_date, id").
+		Find(&goals).Error
+	if err != nil {
+		return nil, err
+	}
+	return goals, nil
+}
+
+func (g *GoalsProvider) CountUserGoals(ctx context.Context, userId int) (int, error) {
+	var count int64
+	err := g.Db.WithContext(ctx).Table(structures.USER_GOAL_TABLE_NAME).
+		Where("user_id = ?", userId).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func (g *GoalsProvider) CreateUserGoal(ctx context.Context, goalInfo structures.UserGoal) (*structures.UserGoal, error) {
+	err := g.Db.WithContext(ctx).Table(structures.USER_GOAL_TABLE_NAME).Create(&goalInfo).Error
+	if err != nil {
+		return nil, err
+	}
+	return &goalInfo, nil
+}
+
+// DeleteUserGoal removes a goal of the user, goals of other users are not
+// found
+func (g *GoalsProvider) DeleteUserGoal(ctx context.Context, userId int, goalId int) (bool, error) {
+	result := g.Db.WithContext(ctx).Table(structures.USER_GOAL_TABLE_NAME).
+		Where("id = ? AND user_id = ?", goalId, userId).
+		Delete(&structures.UserGoal{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, gorm.ErrRecordNotFound
+	}
+	return true, nil
+}
+
+// GetGoalSamples reads the value a metric had on the latest ranked scores of
+// a user
+func (g *GoalsProvider) GetGoalSamples(ctx context.Context, userId int, metric string, limit int) ([]*structures.GoalSample, error) {
+	samples := []*structures.GoalSample{}
+	err := g.Db.WithContext(ctx).Table(structures.SCORE_TABLE_NAME).
+		Select("COALESCE((result ->> ?)::double precision, 0) AS value, created_at", metric).
+		Where("user_id = ? AND review_status IN ?", userId, structures.RANKED_SCORE_REVIEWS).
+		Order("created_at DESC").Limit(limit).
+		Find(&samples).Error
+	if err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// GetPracticeDays lists every day a user has ranked scores on, days are
+// counted in the time zone of the user and come latest first
+func (g *GoalsProvider) GetPracticeDays(ctx context.Context, userId int) ([]string, error) {
+	days := []string{}
+	err := g.Db.WithContext(ctx).Table(structures.SCORE_TABLE_NAME).
+		Select("DISTINCT to_char(scores.created_at AT TIME ZONE users.time_zone, 'YYYY-MM-DD') AS day").
+		Joins("JOIN users ON users.id = scores.user_id").
+		Where("scores.user_id = ? AND scores.review_status IN ?", userId, structures.RANKED_SCORE_REVIEWS).
+		Order("day DESC").
+		Pluck("day", &days).Error
+	if err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+func NewGoalsProvider(db *gorm.DB) *GoalsProvider {
+	return &GoalsProvider{
+		Db: db,
+	}
+}
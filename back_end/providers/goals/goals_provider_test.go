@@ -0,0 +1,147 @@
+package goals_provider
+
+import (
+	"context"
+	"testing"
+	"time"
+	"type_writer_api/structures"
+	"type_writer_api/testing/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+)
+
+func TestGetUserGoalsSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	goalsProvider := NewGoalsProvider(mockGorm)
+
+	mockDB.ExpectQuery(`SELECT \* FROM "user_goals" WHERE user_id = \$1 ORDER BY target_date, id`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "metric", "target", "target_date"}).
+			AddRow(1, 2, "wpm", 80, "2026-12-01").
+			AddRow(2, 2, "accuracy", 98, "2027-01-01"))
+
+	result, err := goalsProvider.GetUserGoals(context.Background(), 2)
+
+	if err != nil {
+		t.Fatalf("error in fetching user goals %v", err)
+	}
+
+	if len(result) != 2 || result[0].Metric != "wpm" || result[0].Target != 80 || result[1].TargetDate != "2027-01-01" {
+		t.Fatalf("unexpected user goals: %v", result)
+	}
+}
+
+func TestCountUserGoalsSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	goalsProvider := NewGoalsProvider(mockGorm)
+
+	mockDB.ExpectQuery(`SELECT count\(\*\) FROM "user_goals" WHERE user_id = \$1`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	result, err := goalsProvider.CountUserGoals(context.Background(), 2)
+
+	if err != nil {
+		t.Fatalf("error in counting user goals %v", err)
+	}
+
+	if result != 3 {
+		t.Fatalf("unexpected result: expected %v, got %v", 3, result)
+	}
+}
+
+func TestCreateUserGoalSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	goalsProvider := NewGoalsProvider(mockGorm)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`INSERT INTO "user_goals" \("user_id","metric","target","target_date","created_at","updated_at"\) VALUES \(\$1,\$2,\$3,\$4,\$5,\$6\) RETURNING "id"`).
+		WithArgs(2, "wpm", 80.0, "2026-12-01", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mockDB.ExpectCommit()
+
+	result, err := goalsProvider.CreateUserGoal(context.Background(), structures.UserGoal{UserId: 2, Metric: "wpm", Target: 80, TargetDate: "2026-12-01"})
+
+	if err != nil {
+		t.Fatalf("error in creating user goal %v", err)
+	}
+
+	if result.Id != 4 || result.Metric != "wpm" {
+		t.Fatalf("unexpected user goal: %v", result)
+	}
+}
+
+func TestDeleteUserGoal(t *testing.T) {
+	data := []struct {
+		testName    string
+		affected    int64
+		expectedErr error
+	}{
+		{testName: "own goal", affected: 1},
+		{testName: "missing or another user's goal", affected: 0, expectedErr: gorm.ErrRecordNotFound},
+	}
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			mockGorm, mockDB := mocks.NewMockDB()
+			goalsProvider := NewGoalsProvider(mockGorm)
+
+			mockDB.ExpectBegin()
+			mockDB.ExpectExec(`DELETE FROM "user_goals" WHERE id = \$1 AND user_id = \$2`).
+				WithArgs(4, 2).
+				WillReturnResult(sqlmock.NewResult(0, testCase.affected))
+			mockDB.ExpectCommit()
+
+			result, err := goalsProvider.DeleteUserGoal(context.Background(), 2, 4)
+
+			if err != testCase.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+			}
+			if result != (testCase.expectedErr == nil) {
+				t.Fatalf("unexpected result %v", result)
+			}
+		})
+	}
+}
+
+func TestGetGoalSamplesSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	goalsProvider := NewGoalsProvider(mockGorm)
+
+	now := time.Now()
+	mockDB.ExpectQuery(`SELECT COALESCE\(\(result ->> \$1\)::double precision, 0\) AS value, created_at FROM "scores" WHERE user_id = \$2 AND review_status IN \(\$3,\$4\) ORDER BY created_at DESC LIMIT \$5`).
+		WithArgs("wpm", 2, structures.SCORE_REVIEW_CLEAN, structures.SCORE_REVIEW_APPROVED, structures.GOAL_REGRESSION_SCORES).
+		WillReturnRows(sqlmock.NewRows([]string{"value", "created_at"}).
+			AddRow(72.5, now).
+			AddRow(70, now.Add(-time.Hour)))
+
+	result, err := goalsProvider.GetGoalSamples(context.Background(), 2, "wpm", structures.GOAL_REGRESSION_SCORES)
+
+	if err != nil {
+		t.Fatalf("error in fetching goal samples %v", err)
+	}
+
+	if len(result) != 2 || result[0].Value != 72.5 || result[1].Value != 70 {
+		t.Fatalf("unexpected goal samples: %v", result)
+	}
+}
+
+func TestGetPracticeDaysSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	goalsProvider := NewGoalsProvider(mockGorm)
+
+	mockDB.ExpectQuery(`SELECT DISTINCT to_char\(scores.created_at AT TIME ZONE users.time_zone, 'YYYY-MM-DD'\) AS day FROM "scores" JOIN users ON users.id = scores.user_id WHERE scores.user_id = \$1 AND scores.review_status IN \(\$2,\$3\) ORDER BY day DESC`).
+		WithArgs(2, structures.SCORE_REVIEW_CLEAN, structures.SCORE_REVIEW_APPROVED).
+		WillReturnRows(sqlmock.NewRows([]string{"day"}).AddRow("2026-10-19").AddRow("2026-10-18"))
+
+	result, err := goalsProvider.GetPracticeDays(context.Background(), 2)
+
+	if err != nil {
+		t.Fatalf("error in fetching practice days %v", err)
+	}
+
+	if len(result) != 2 || result[0] != "2026-10-19" {
+		t.Fatalf("unexpected practice days: %v", result)
+	}
+}
@@ -0,0 +1,83 @@
+package keyboard_layouts_provider
+
+import (
+	"context"
+	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+)
+
+type KeyboardLayoutsProviderInterface interface {
+	GetKeyboardLayouts(ctx context.Context) ([]*structures.KeyboardLayout, error)
+	GetKeyboardLayoutByIdOrName(ctx context.Context, layoutId *int, name *string) (*structures.KeyboardLayout, error)
+	CreateKeyboardLayout(ctx context.Context, layoutInfo structures.KeyboardLayout) (*structures.KeyboardLayout, error)
+	UpdateKeyboardLayout(ctx context.Context, updatedLayoutInfo structures.KeyboardLayout) (*structures.KeyboardLayout, error)
+	DeleteKeyboardLayout(ctx context.Context, layoutId int) (bool, error)
+	CountLayoutUsers(ctx context.Context, name string) (int64, error)
+}
+
+type KeyboardLayoutsProvider struct {
+	Db *gorm.DB
+}
+
+func (k *KeyboardLayoutsProvider) GetKeyboardLayouts(ctx context.Context) ([]*structures.KeyboardLayout, error) {
+	var layouts []*structures.KeyboardLayout
+	err := k.Db.WithContext(ctx).Table(structures.KEYBOARD_LAYOUT_TABLE_NAME).Order("name").Find(&layouts).Error
+	if err != nil {
+		return nil, err
+	}
+	return layouts, nil
+}
+
+func (k *KeyboardLayoutsProvider) GetKeyboardLayoutByIdOrName(ctx context.Context, layoutId *int, name *string) (*structures.KeyboardLayout, error) {
+	var layout *structures.KeyboardLayout
+	err := k.Db.WithContext(ctx).Table(structures.KEYBOARD_LAYOUT_TABLE_NAME).
+		First(&layout, "id = ? OR name = ?", layoutId, name).Error
+	if err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+func (k *KeyboardLayoutsProvider) CreateKeyboardLayout(ctx context.Context, layoutInfo structures.KeyboardLayout) (*structures.KeyboardLayout, error) {
+	err := k.Db.WithContext(ctx).Table(structures.KEYBOARD_LAYOUT_TABLE_NAME).Create(&layoutInfo).Error
+	if err != nil {
+		return nil, err
+	}
+	return &layoutInfo, nil
+}
+
+func (k *KeyboardLayoutsProvider) UpdateKeyboardLayout(ctx context.Context, updatedLayoutInfo structures.KeyboardLayout) (*structures.KeyboardLayout, error) {
+	err := k.Db.WithContext(ctx).Table(structures.KEYBOARD_LAYOUT_TABLE_NAME).Updates(&updatedLayoutInfo).Error
+	if err != nil {
+		return nil, err
+	}
+	return &updatedLayoutInfo, nil
+}
+
+func (k *KeyboardLayoutsProvider) DeleteKeyboardLayout(ctx context.Context, layoutId int) (bool, error) {
+	var deleteLayout = structures.KeyboardLayout{Id: layoutId}
+	err := k.Db.WithContext(ctx).Table(structures.KEYBOARD_LAYOUT_TABLE_NAME).Delete(&deleteLayout).Error
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CountLayoutUsers counts the users who picked the layout as their preference
+func (k *KeyboardLayoutsProvider) CountLayoutUsers(ctx context.Context, name string) (int64, error) {
+	var count int64
+	err := k.Db.WithContext(ctx).Table(structures.USER_TABLE_NAME).
+		Where("keyboard_layout = ?", name).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func NewKeyboardLayoutsProvider(db *gorm.DB) *KeyboardLayoutsProvider {
+	return &KeyboardLayoutsProvider{
+		Db: db,
+	}
+}
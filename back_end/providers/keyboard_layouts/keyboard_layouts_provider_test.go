@@ -0,0 +1,161 @@
+package keyboard_layouts_provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+	"type_writer_api/helpers"
+	"type_writer_api/structures"
+	"type_writer_api/testing/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func testKeys() []*structures.KeyPosition {
+	return []*structures.KeyPosition{
+		{Char: "a", ShiftChar: "A", Row: structures.KEY_ROW_HOME, Column: 0, Finger: structures.FINGER_LEFT_PINKY},
+		{Char: " ", Row: structures.KEY_ROW_SPACE, Column: 0, Finger: structures.FINGER_THUMB},
+	}
+}
+
+func TestGetKeyboardLayoutsSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	keyboardLayoutsProvider := NewKeyboardLayoutsProvider(mockGorm)
+
+	expectedRows := []structures.KeyboardLayout{
+		{Id: 1, Name: "custom-1", Description: "first custom layout", Keys: testKeys(), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{Id: 2, Name: "custom-2", Description: "second custom layout", Keys: testKeys(), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+
+	resultRows := sqlmock.NewRows([]string{"id", "name", "description", "keys", "created_at", "updated_at"})
+	for _, expectedRow := range expectedRows {
+		keys, _ := json.Marshal(expectedRow.Keys)
+		resultRows.AddRow(expectedRow.Id, expectedRow.Name, expectedRow.Description, keys, expectedRow.CreatedAt, expectedRow.UpdatedAt)
+	}
+
+	mockDB.ExpectQuery(`SELECT \* FROM "keyboard_layouts" ORDER BY name`).WillReturnRows(resultRows)
+
+	result, err := keyboardLayoutsProvider.GetKeyboardLayouts(context.Background())
+
+	if err != nil {
+		t.Fatalf("error in fetching keyboard layouts %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("unexpected result length: expected %v, got %v", 2, len(result))
+	}
+
+	for indx, resultRow := range result {
+		if resultRow.Name != expectedRows[indx].Name || len(resultRow.Keys) != len(expectedRows[indx].Keys) {
+			t.Fatalf("row %v failed: got %+v\n", indx, resultRow)
+		}
+		for keyIdx, key := range resultRow.Keys {
+			if err := helpers.CompareReflectedStructFields(*key, *expectedRows[indx].Keys[keyIdx]); err != nil {
+				t.Fatalf("row %v key %v failed: %v\n", indx, keyIdx, err.Error())
+			}
+		}
+	}
+}
+
+func TestGetKeyboardLayoutByIdOrNameSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	keyboardLayoutsProvider := NewKeyboardLayoutsProvider(mockGorm)
+
+	keys, _ := json.Marshal(testKeys())
+	resultRows := sqlmock.NewRows([]string{"id", "name", "description", "keys"}).AddRow(1, "custom-1", "first custom layout", keys)
+
+	mockDB.ExpectQuery(`SELECT \* FROM "keyboard_layouts" WHERE id = .+ OR name = .+ ORDER BY "keyboard_layouts"\."id" LIMIT .+`).WillReturnRows(resultRows)
+
+	name := "custom-1"
+	result, err := keyboardLayoutsProvider.GetKeyboardLayoutByIdOrName(context.Background(), nil, &name)
+
+	if err != nil {
+		t.Fatalf("error in fetching keyboard layout %v", err)
+	}
+
+	if result.Id != 1 || len(result.Keys) != 2 || result.Keys[0].Finger != structures.FINGER_LEFT_PINKY {
+		t.Fatalf("unexpected keyboard layout %+v", result)
+	}
+}
+
+func TestCreateKeyboardLayoutSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	keyboardLayoutsProvider := NewKeyboardLayoutsProvider(mockGorm)
+
+	inputLayout := structures.KeyboardLayout{Name: "custom-1", Description: "first custom layout", Keys: testKeys()}
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`INSERT INTO "keyboard_layouts" \("name","description","keys","created_at","updated_at"\) VALUES .+ RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mockDB.ExpectCommit()
+
+	result, err := keyboardLayoutsProvider.CreateKeyboardLayout(context.Background(), inputLayout)
+
+	if err != nil {
+		t.Fatalf("error in creating keyboard layout %v", err)
+	}
+
+	if result.Id != 1 {
+		t.Fatalf("unexpected id: expected %v, got %v", 1, result.Id)
+	}
+}
+
+func TestUpdateKeyboardLayoutSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	keyboardLayoutsProvider := NewKeyboardLayoutsProvider(mockGorm)
+
+	expectedRow := structures.KeyboardLayout{Id: 1, Name: "custom-1", Description: "updated layout", Keys: testKeys(), CreatedAt: time.Now(), UpdatedAt: time.Now()}
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`UPDATE "keyboard_layouts" SET "name"=.+,"description"=.+,"keys"=.+,"created_at"=.+,"updated_at"=.+ WHERE "id" = .+`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectCommit()
+
+	result, err := keyboardLayoutsProvider.UpdateKeyboardLayout(context.Background(), expectedRow)
+
+	if err != nil {
+		t.Fatalf("error in updating keyboard layout %v", err)
+	}
+
+	if err := helpers.CompareReflectedStructFields(*result, expectedRow); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteKeyboardLayoutSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	keyboardLayoutsProvider := NewKeyboardLayoutsProvider(mockGorm)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`FROM "keyboard_layouts" WHERE "keyboard_layouts"\."id" = .+`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectCommit()
+
+	result, err := keyboardLayoutsProvider.DeleteKeyboardLayout(context.Background(), 1)
+
+	if err != nil {
+		t.Fatalf("error in deleting keyboard layout %v", err)
+	}
+
+	if result != true {
+		t.Fatalf("unexpected result: expected %v, got %v", true, result)
+	}
+}
+
+func TestCountLayoutUsersSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	keyboardLayoutsProvider := NewKeyboardLayoutsProvider(mockGorm)
+
+	mockDB.ExpectQuery(`SELECT count\(\*\) FROM "users" WHERE keyboard_layout = .+`).
+		WithArgs("my-layout").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	result, err := keyboardLayoutsProvider.CountLayoutUsers(context.Background(), "my-layout")
+
+	if err != nil {
+		t.Fatalf("error in counting layout users %v", err)
+	}
+
+	if result != 2 {
+		t.Fatalf("unexpected result: expected %v, got %v", 2, result)
+	}
+}
@@ -0,0 +1,97 @@
+package leaderboards_provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+)
+
+var ErrUnknownMetric = errors.New("unknown leaderboard metric")
+
+// leaderboardColumns maps the metrics a leaderboard ranks by to the column
+// they are read from, metrics never reach the query any other way. Results
+// missing a metric rank as zero instead of ahead of everyone
+var leaderboardColumns = map[string]string{
+	"wpm":         "COALESCE((scores.result ->> 'wpm')::numeric, 0)",
+	"raw_wpm":     "COALESCE((scores.result ->> 'raw_wpm')::numeric, 0)",
+	"cpm":         "COALESCE((scores.result ->> 'cpm')::numeric, 0)",
+	"accuracy":    "COALESCE((scores.result ->> 'accuracy')::numeric, 0)",
+	"consistency": "COALESCE((scores.result ->> 'consistency')::numeric, 0)",
+	"final_score": "scores.final_score",
+}
+
+type LeaderboardsProviderInterface interface {
+	GetLeaderboard(ctx context.Context, query structures.LeaderboardQuery) ([]*structures.LeaderboardEntry, error)
+	GetLeaderboardEntry(ctx context.Context, query structures.LeaderboardQuery, userId int) (*structures.LeaderboardEntry, error)
+}
+
+type LeaderboardsProvider struct {
+	Db *gorm.DB
+}
+
+// ranked keeps the best ranked score of every user and numbers them, the
+// ordering breaks every tie so ranks double as positions
+func (l *LeaderboardsProvider) ranked(ctx context.Context, query structures.LeaderboardQuery) (*gorm.DB, error) {
+	column, ok := leaderboardColumns[query.Metric]
+	if !ok {
+		return nil, ErrUnknownMetric
+	}
+	accuracy := leaderboardColumns["accuracy"]
+
+	best := l.Db.Table(structures.SCORE_TABLE_NAME).
+		Select(fmt.Sprintf("DISTINCT ON (scores.user_id) scores.user_id, scores.id AS score_id, %s AS value, %s AS accuracy, scores.created_at", column, accuracy)).
+		Where("scores.review_status IN ?", structures.RANKED_SCORE_REVIEWS)
+	if query.ActivityId != 0 {
+		best = best.Where("scores.activity_id = ?", query.ActivityId)
+	}
+	if query.TextId != 0 {
+		best = best.Where("scores.text_id = ?", query.TextId)
+	}
+	if !query.Since.IsZero() {
+		best = best.Where("scores.created_at >= ?", query.Since)
+	}
+	best = best.Order(fmt.Sprintf("scores.user_id, %s DESC, %s DESC, scores.created_at", column, accuracy))
+
+	ranked := l.Db.Table("(?) AS best", best).
+		Select("ROW_NUMBER() OVER (ORDER BY best.value DESC, best.accuracy DESC, best.created_at, best.user_id) AS rank, best.user_id, users.username, best.score_id, best.value, best.accuracy, best.created_at").
+		Joins("JOIN users ON users.id = best.user_id")
+
+	return l.Db.WithContext(ctx).Table("(?) AS ranked", ranked), nil
+}
+
+func (l *LeaderboardsProvider) GetLeaderboard(ctx context.Context, query structures.LeaderboardQuery) ([]*structures.LeaderboardEntry, error) {
+	entries := []*structures.LeaderboardEntry{}
+	ranked, err := l.ranked(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	err = ranked.Order("ranked.rank").Limit(query.Limit).Offset(query.Offset).Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetLeaderboardEntry finds where a user stands on the leaderboard, users
+// without a ranked score in it are not found
+func (l *LeaderboardsProvider) GetLeaderboardEntry(ctx context.Context, query structures.LeaderboardQuery, userId int) (*structures.LeaderboardEntry, error) {
+	var entry *structures.LeaderboardEntry
+	ranked, err := l.ranked(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	err = ranked.Where("ranked.user_id = ?", userId).Take(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func NewLeaderboardsProvider(db *gorm.DB) *LeaderboardsProvider {
+	return &LeaderboardsProvider{
+		Db: db,
+	}
+}
@@ -0,0 +1,82 @@
+package leaderboards_provider
+
+import (
+	"context"
+	"testing"
+	"time"
+	"type_writer_api/helpers"
+	"type_writer_api/structures"
+	"type_writer_api/testing/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetLeaderboardSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	leaderboardsProvider := NewLeaderboardsProvider(mockGorm)
+
+	since := time.Now().Add(-24 * time.Hour)
+	expectedRows := []structures.LeaderboardEntry{
+		{Rank: 1, UserId: 2, Username: "fast", ScoreId: 7, Value: 120, Accuracy: 98, CreatedAt: time.Now()},
+		{Rank: 2, UserId: 1, Username: "steady", ScoreId: 4, Value: 120, Accuracy: 96, CreatedAt: time.Now()},
+	}
+
+	resultRows := sqlmock.NewRows([]string{"rank", "user_id", "username", "score_id", "value", "accuracy", "created_at"})
+	for _, expectedRow := range expectedRows {
+		resultRows.AddRow(expectedRow.Rank, expectedRow.UserId, expectedRow.Username, expectedRow.ScoreId, expectedRow.Value, expectedRow.Accuracy, expectedRow.CreatedAt)
+	}
+
+	mockDB.ExpectQuery(`SELECT \* FROM \(SELECT ROW_NUMBER\(\) OVER \(ORDER BY best\.value DESC, best\.accuracy DESC, best\.created_at, best\.user_id\) AS rank, .+ FROM \(SELECT DISTINCT ON \(scores\.user_id\) .+COALESCE\(\(scores\.result ->> 'wpm'\)::numeric, 0\) AS value.+ FROM "scores" WHERE scores\.review_status IN .+ AND scores\.activity_id = .+ AND scores\.text_id = .+ AND scores\.created_at >= .+ ORDER BY scores\.user_id, .+\) AS best JOIN users ON users\.id = best\.user_id\) AS ranked ORDER BY ranked\.rank LIMIT .+ OFFSET .+`).
+		WithArgs(structures.SCORE_REVIEW_CLEAN, structures.SCORE_REVIEW_APPROVED, 1, 3, since, 10, 20).
+		WillReturnRows(resultRows)
+
+	result, err := leaderboardsProvider.GetLeaderboard(context.Background(), structures.LeaderboardQuery{ActivityId: 1, TextId: 3, Metric: "wpm", Since: since, Limit: 10, Offset: 20})
+
+	if err != nil {
+		t.Fatalf("error in fetching leaderboard %v", err)
+	}
+
+	if len(result) != len(expectedRows) {
+		t.Fatalf("unexpected result length: expected %v,\n got %v\n", len(expectedRows), len(result))
+	}
+
+	for indx, resultRow := range result {
+		err := helpers.CompareReflectedStructFields(*resultRow, expectedRows[indx])
+		if err != nil {
+			t.Fatalf("row %v failed: %v\n", indx, err.Error())
+		}
+	}
+}
+
+func TestGetLeaderboardEntrySuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	leaderboardsProvider := NewLeaderboardsProvider(mockGorm)
+
+	expectedRow := structures.LeaderboardEntry{Rank: 12, UserId: 2, Username: "regular", ScoreId: 9, Value: 340.5, Accuracy: 97, CreatedAt: time.Now()}
+
+	mockDB.ExpectQuery(`SELECT \* FROM \(SELECT ROW_NUMBER\(\) OVER .+ FROM \(SELECT DISTINCT ON \(scores\.user_id\) .+scores\.final_score AS value.+ FROM "scores" WHERE scores\.review_status IN .+\) AS best JOIN users ON users\.id = best\.user_id\) AS ranked WHERE ranked\.user_id = .+ LIMIT .+`).
+		WithArgs(structures.SCORE_REVIEW_CLEAN, structures.SCORE_REVIEW_APPROVED, 2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"rank", "user_id", "username", "score_id", "value", "accuracy", "created_at"}).
+			AddRow(expectedRow.Rank, expectedRow.UserId, expectedRow.Username, expectedRow.ScoreId, expectedRow.Value, expectedRow.Accuracy, expectedRow.CreatedAt))
+
+	result, err := leaderboardsProvider.GetLeaderboardEntry(context.Background(), structures.LeaderboardQuery{Metric: "final_score"}, 2)
+
+	if err != nil {
+		t.Fatalf("error in fetching leaderboard entry %v", err)
+	}
+
+	if err := helpers.CompareReflectedStructFields(*result, expectedRow); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetLeaderboardUnknownMetric(t *testing.T) {
+	mockGorm, _ := mocks.NewMockDB()
+	leaderboardsProvider := NewLeaderboardsProvider(mockGorm)
+
+	_, err := leaderboardsProvider.GetLeaderboard(context.Background(), structures.LeaderboardQuery{Metric: "result; DROP TABLE scores"})
+
+	if err != ErrUnknownMetric {
+		t.Fatalf("expected error: %v but got %v instead", ErrUnknownMetric, err)
+	}
+}
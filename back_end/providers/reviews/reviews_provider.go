@@ -0,0 +1,69 @@
+package reviews_provider
+
+import (
+	"context"
+	"time"
+	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReviewsProviderInterface interface {
+	GetReviewWords(ctx context.Context, userId int, words []string) ([]*structures.ReviewWord, error)
+	SaveReviewWords(ctx context.Context, words []*structures.ReviewWord) (bool, error)
+	GetDueReviewWords(ctx context.Context, userId int, now time.Time, limit int) ([]*structures.ReviewWord, error)
+}
+
+type ReviewsProvider struct {
+	Db *gorm.DB
+}
+
+// GetReviewWords reads the deck entries of the given words, words the user
+// never missed have none
+func (r *ReviewsProvider) GetReviewWords(ctx context.Context, userId int, words []string) ([]*structures.ReviewWord, error) {
+	reviewWords := []*structures.ReviewWord{}
+	err := r.Db.WithContext(ctx).Table(structures.REVIEW_WORD_TABLE_NAME).
+		Where("user_id = ? AND word IN ?", userId, words).
+		Order("word").
+		Find(&reviewWords).Error
+	if err != nil {
+		return nil, err
+	}
+	return reviewWords, nil
+}
+
+// SaveReviewWords stores the deck entries, replacing the schedule of words
+// already in the deck
+func (r *ReviewsProvider) SaveReviewWords(ctx context.Context, words []*structures.ReviewWord) (bool, error) {
+	err := r.Db.WithContext(ctx).Table(structures.REVIEW_WORD_TABLE_NAME).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "word"}},
+			DoUpdates: clause.AssignmentColumns([]string{"misses", "repetitions", "ease_factor", "interval_days", "due_at", "mastered", "last_reviewed_at", "updated_at"}),
+		}).
+		Create(&words).Error
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetDueReviewWords lists the words of a user due for review, the ones
+// overdue the longest first
+func (r *ReviewsProvider) GetDueReviewWords(ctx context.Context, userId int, now time.Time, limit int) ([]*structures.ReviewWord, error) {
+	reviewWords := []*structures.ReviewWord{}
+	err := r.Db.WithContext(ctx).Table(structures.REVIEW_WORD_TABLE_NAME).
+		Where("user_id = ? AND NOT mastered AND misses >= ? AND due_at <= ?", userId, structures.MIN_REVIEW_MISSES, now).
+		Order("due_at, word").Limit(limit).
+		Find(&reviewWords).Error
+	if err != nil {
+		return nil, err
+	}
+	return reviewWords, nil
+}
+
+func NewReviewsProvider(db *gorm.DB) *ReviewsProvider {
+	return &ReviewsProvider{
+		Db: db,
+	}
+}
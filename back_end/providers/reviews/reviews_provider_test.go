@@ -0,0 +1,75 @@
+package reviews_provider
+
+import (
+	"context"
+	"testing"
+	"time"
+	"type_writer_api/structures"
+	"type_writer_api/testing/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetReviewWordsSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	reviewsProvider := NewReviewsProvider(mockGorm)
+
+	mockDB.ExpectQuery(`SELECT \* FROM "review_words" WHERE user_id = \$1 AND word IN \(\$2,\$3\) ORDER BY word`).
+		WithArgs(2, "there", "which").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "word", "misses", "repetitions", "ease_factor", "interval_days", "due_at", "mastered"}).
+			AddRow(2, "there", 3, 1, 2.36, 1, time.Now(), false))
+
+	result, err := reviewsProvider.GetReviewWords(context.Background(), 2, []string{"there", "which"})
+
+	if err != nil {
+		t.Fatalf("error in fetching review words %v", err)
+	}
+
+	if len(result) != 1 || result[0].Word != "there" || result[0].Misses != 3 || result[0].EaseFactor != 2.36 {
+		t.Fatalf("unexpected review words: %v", result)
+	}
+}
+
+func TestSaveReviewWordsSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	reviewsProvider := NewReviewsProvider(mockGorm)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`INSERT INTO "review_words" \(.+\) VALUES .+ ON CONFLICT \("user_id","word"\) DO UPDATE SET "misses"="excluded"\."misses",.+"updated_at"="excluded"\."updated_at"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectCommit()
+
+	result, err := reviewsProvider.SaveReviewWords(context.Background(), []*structures.ReviewWord{
+		{UserId: 2, Word: "there", Misses: 1, IntervalDays: 1, EaseFactor: 2.18, DueAt: time.Now()},
+	})
+
+	if err != nil {
+		t.Fatalf("error in saving review words %v", err)
+	}
+
+	if result != true {
+		t.Fatalf("unexpected result: expected %v, got %v", true, result)
+	}
+}
+
+func TestGetDueReviewWordsSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	reviewsProvider := NewReviewsProvider(mockGorm)
+
+	now := time.Now()
+	mockDB.ExpectQuery(`SELECT \* FROM "review_words" WHERE user_id = \$1 AND NOT mastered AND misses >= \$2 AND due_at <= \$3 ORDER BY due_at, word LIMIT \$4`).
+		WithArgs(2, structures.MIN_REVIEW_MISSES, now, structures.MAX_REVIEW_DRILL_WORDS).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "word", "misses", "due_at"}).
+			AddRow(2, "which", 2, now.Add(-time.Hour)).
+			AddRow(2, "there", 4, now))
+
+	result, err := reviewsProvider.GetDueReviewWords(context.Background(), 2, now, structures.MAX_REVIEW_DRILL_WORDS)
+
+	if err != nil {
+		t.Fatalf("error in fetching due review words %v", err)
+	}
+
+	if len(result) != 2 || result[0].Word != "which" || result[1].Word != "there" {
+		t.Fatalf("unexpected due review words: %v", result)
+	}
+}
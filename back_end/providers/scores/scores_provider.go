@@ -2,26 +2,42 @@ package scores_provider
 
 import (
 	"context"
+	"time"
 	"type_writer_api/structures"
 
 	"gorm.io/gorm"
 )
 
 type ScoresProviderInterface interface {
-	GetScores(ctx context.Context) ([]*structures.Score, error)
+	GetScores(ctx context.Context, filter structures.ScoreFilter) ([]*structures.Score, error)
 	GetScoreById(ctx context.Context, scoreId int) (*structures.Score, error)
 	CreateScore(ctx context.Context, scoreInfo structures.Score) (*structures.Score, error)
 	UpdateScore(ctx context.Context, updatedtextInfo structures.Score) (*structures.Score, error)
 	DeleteScore(ctx context.Context, scoreId int) (bool, error)
+	GetScoreKeystrokes(ctx context.Context, scoreId int) (*structures.ScoreKeystrokeLog, error)
+	DeleteScoreKeystrokesBefore(ctx context.Context, before time.Time) (int64, error)
 }
 
 type ScoresProvider struct {
 	Db *gorm.DB
 }
 
-func (t *ScoresProvider) GetScores(ctx context.Context) ([]*structures.Score, error) {
+func (t *ScoresProvider) GetScores(ctx context.Context, filter structures.ScoreFilter) ([]*structures.Score, error) {
 	var scores []*structures.Score
-	err := t.Db.WithContext(ctx).Table(structures.SCORE_TABLE_NAME).Find(&scores).Error
+	query := t.Db.WithContext(ctx).Table(structures.SCORE_TABLE_NAME)
+	if filter.UserId != 0 {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+	if filter.ActivityId != 0 {
+		query = query.Where("activity_id = ?", filter.ActivityId)
+	}
+	if filter.TextId != 0 {
+		query = query.Where("text_id = ?", filter.TextId)
+	}
+	if len(filter.ReviewStatuses) != 0 {
+		query = query.Where("review_status IN ?", filter.ReviewStatuses)
+	}
+	err := query.Find(&scores).Error
 	if err != nil {
 		return nil, err
 	}
@@ -38,16 +54,34 @@ func (t *ScoresProvider) GetScoreById(ctx context.Context, scoreId int) (*struct
 	return score, nil
 }
 
+// CreateScore stores the score along with its keystroke log when it carries
+// one, a log that cannot be stored fails the whole score
 func (t *ScoresProvider) CreateScore(ctx context.Context, scoreInfo structures.Score) (*structures.Score, error) {
-	err := t.Db.WithContext(ctx).Table(structures.SCORE_TABLE_NAME).Create(&scoreInfo).Error
+	err := t.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createScore(tx, &scoreInfo)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return &scoreInfo, nil
 }
 
+func createScore(tx *gorm.DB, scoreInfo *structures.Score) error {
+	err := tx.Table(structures.SCORE_TABLE_NAME).Create(scoreInfo).Error
+	if err != nil {
+		return err
+	}
+	if scoreInfo.KeystrokeLog == nil {
+		return nil
+	}
+	scoreInfo.KeystrokeLog.ScoreId = scoreInfo.Id
+	return tx.Table(structures.SCORE_KEYSTROKES_TABLE_NAME).Create(scoreInfo.KeystrokeLog).Error
+}
+
 func (t *ScoresProvider) UpdateScore(ctx context.Context, updatedScoreInfo structures.Score) (*structures.Score, error) {
-	err := t.Db.WithContext(ctx).Table(structures.SCORE_TABLE_NAME).Updates(&updatedScoreInfo).Error
+	// every column is written so a final score can drop to 0, the caller
+	// always passes the whole score
+	err := t.Db.WithContext(ctx).Table(structures.SCORE_TABLE_NAME).Select("*").Omit("created_at").Updates(&updatedScoreInfo).Error
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +97,26 @@ func (t *ScoresProvider) DeleteScore(ctx context.Context, scoreId int) (bool, er
 	return true, nil
 }
 
+func (t *ScoresProvider) GetScoreKeystrokes(ctx context.Context, scoreId int) (*structures.ScoreKeystrokeLog, error) {
+	var keystrokeLog *structures.ScoreKeystrokeLog
+	err := t.Db.WithContext(ctx).Table(structures.SCORE_KEYSTROKES_TABLE_NAME).
+		First(&keystrokeLog, "score_id = ?", scoreId).Error
+	if err != nil {
+		return nil, err
+	}
+	return keystrokeLog, nil
+}
+
+// DeleteScoreKeystrokesBefore drops the keystroke logs stored before the
+// given time, the scores themselves are kept
+func (t *ScoresProvider) DeleteScoreKeystrokesBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := t.Db.WithContext(ctx).Exec("DELETE FROM score_keystrokes WHERE created_at < ?", before)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
 func NewScoresProvider(db *gorm.DB) *ScoresProvider {
 	return &ScoresProvider{
 		Db: db,
@@ -23,7 +23,7 @@ func TestGetScoresSuccess(t *testing.T) {
 			ActivityId: 1,
 			TextId:     1,
 			Duration:   60,
-			Result: 	map[string]any{"wpm": float64(300), "errors": float64(300)}, // type casting ints because json unmarshalls ints into floats64
+			Result: 	structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300},
 			CreatedAt:  time.Now(),
 			UpdatedAt:  time.Now(),
 		},
@@ -33,7 +33,7 @@ func TestGetScoresSuccess(t *testing.T) {
 			ActivityId: 2,
 			TextId:     2,
 			Duration:   60,
-			Result: 	map[string]any{"wpm": float64(300), "errors": float64(300)},
+			Result: 	structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300},
 			CreatedAt:  time.Now(),
 			UpdatedAt:  time.Now(),
 		},
@@ -68,9 +68,9 @@ func TestGetScoresSuccess(t *testing.T) {
 		)
 	}
 
-	mockDB.ExpectQuery(`SELECT \* FROM "scores"`).WillReturnRows(resultRows)
+	mockDB.ExpectQuery(`SELECT \* FROM "scores" WHERE user_id = .+`).WithArgs(1).WillReturnRows(resultRows)
 
-	result, err := scoresProvider.GetScores(context.Background())
+	result, err := scoresProvider.GetScores(context.Background(), structures.ScoreFilter{UserId: 1})
 
 	if err != nil {
 		t.Fatalf("error in fetching scores %v", err)
@@ -99,7 +99,7 @@ func TestGetScoreByIdOrNameSuccess(t *testing.T) {
 		ActivityId: 1,
 		TextId:     1,
 		Duration:   60,
-		Result: 	map[string]any{"wpm": float64(300), "errors": float64(300)},
+		Result: 	structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300},
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
@@ -152,37 +152,15 @@ func TestCreateScoreSuccess(t *testing.T) {
 		ActivityId: 1,
 		TextId:     1,
 		Duration:   60,
-		Result: 	map[string]any{"wpm": float64(300), "errors": float64(300)},
+		Result: 	structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300},
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
 
-	sResult, err := json.Marshal(expectedRow.Result)
-	if err != nil {
-		t.Fatalf("error in serializing text tags %v", err)
-	}
-
-	resultRows := sqlmock.NewRows([]string{
-		"id",
-		"user_id",
-		"activity_id",
-		"text_id",
-		"duration",
-		"result",
-		"created_at",
-		"updated_at",
-	}).AddRow(
-		expectedRow.Id,
-		expectedRow.UserId,
-		expectedRow.ActivityId,
-		expectedRow.TextId,
-		expectedRow.Duration,
-		sResult,
-		expectedRow.CreatedAt,
-		expectedRow.UpdatedAt,
-	)
-
-	mockDB.ExpectQuery(`SELECT \* FROM "scores" WHERE "scores"\."id" = .+ AND "scores"\."user_id" = .+ AND "scores"\."activity_id" = .+ AND "scores"\."text_id" = .+`).WillReturnRows(resultRows)
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`INSERT INTO "scores" \("user_id","activity_id","text_id","duration","result","final_score","review_status","flag_reasons","reviewed_at","created_at","updated_at","id"\) VALUES .+ RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedRow.Id))
+	mockDB.ExpectCommit()
 
 	result, err := scoresProvider.CreateScore(context.Background(), expectedRow)
 
@@ -205,13 +183,13 @@ func TestUpdateScoreSuccess(t *testing.T) {
 		ActivityId: 1,
 		TextId:     1,
 		Duration:   60,
-		Result: 	map[string]any{"wpm": float64(300), "errors": float64(300)},
+		Result: 	structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300},
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
 
 	mockDB.ExpectBegin()
-	mockDB.ExpectExec(`UPDATE "scores" SET "user_id"=.+,"activity_id"=.+,"text_id"=.+,"duration"=.+,"created_at"=.+,"updated_at"=.+ WHERE "id" = .+`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectExec(`UPDATE "scores" SET "user_id"=.+,"activity_id"=.+,"text_id"=.+,"duration"=.+,"result"=.+,"final_score"=.+,"updated_at"=.+ WHERE "id" = .+`).WillReturnResult(sqlmock.NewResult(1, 1))
 	mockDB.ExpectCommit()
 
 	result, err := scoresProvider.UpdateScore(context.Background(), expectedRow)
@@ -247,3 +225,113 @@ func TestDeleteScoreSuccess(t *testing.T) {
 		t.Fatalf("unexpected result: expected %v, got %v", true, result)
 	}
 }
+
+func TestCreateScoreWithKeystrokesSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	scoresProvider := NewScoresProvider(mockGorm)
+
+	inputScore := structures.Score{
+		UserId:       1,
+		ActivityId:   1,
+		TextId:       1,
+		Duration:     60,
+		Result:       structures.ScoreResult{Version: 1, Wpm: 300},
+		KeystrokeLog: &structures.ScoreKeystrokeLog{Encoding: structures.KEYSTROKE_LOG_ENCODING, EventCount: 2, Data: []byte{1, 2}},
+	}
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`INSERT INTO "scores" .+ RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mockDB.ExpectExec(`INSERT INTO "score_keystrokes" \("score_id","encoding","event_count","data","created_at"\) VALUES .+`).
+		WithArgs(4, structures.KEYSTROKE_LOG_ENCODING, 2, []byte{1, 2}, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectCommit()
+
+	result, err := scoresProvider.CreateScore(context.Background(), inputScore)
+
+	if err != nil {
+		t.Fatalf("error in creating score %v", err)
+	}
+
+	if result.Id != 4 || result.KeystrokeLog.ScoreId != 4 {
+		t.Fatalf("unexpected created score %+v, keystroke log %+v", result, result.KeystrokeLog)
+	}
+}
+
+func TestGetScoreKeystrokesSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	scoresProvider := NewScoresProvider(mockGorm)
+
+	expectedRow := structures.ScoreKeystrokeLog{ScoreId: 1, Encoding: structures.KEYSTROKE_LOG_ENCODING, EventCount: 2, Data: []byte{1, 2}, CreatedAt: time.Now()}
+
+	mockDB.ExpectQuery(`SELECT \* FROM "score_keystrokes" WHERE score_id = .+ ORDER BY "score_keystrokes"\."score_id" LIMIT .+`).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"score_id", "encoding", "event_count", "data", "created_at"}).
+			AddRow(expectedRow.ScoreId, expectedRow.Encoding, expectedRow.EventCount, expectedRow.Data, expectedRow.CreatedAt))
+
+	result, err := scoresProvider.GetScoreKeystrokes(context.Background(), 1)
+
+	if err != nil {
+		t.Fatalf("error in fetching keystroke log %v", err)
+	}
+
+	if err := helpers.CompareReflectedStructFields(*result, expectedRow); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteScoreKeystrokesBeforeSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	scoresProvider := NewScoresProvider(mockGorm)
+
+	before := time.Now()
+
+	mockDB.ExpectExec(`DELETE FROM score_keystrokes WHERE created_at < .+`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	result, err := scoresProvider.DeleteScoreKeystrokesBefore(context.Background(), before)
+
+	if err != nil {
+		t.Fatalf("error in purging keystroke logs %v", err)
+	}
+
+	if result != 3 {
+		t.Fatalf("unexpected result: expected %v, got %v", 3, result)
+	}
+}
+
+func TestGetScoresByReviewStatusSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	scoresProvider := NewScoresProvider(mockGorm)
+
+	expectedRow := structures.Score{
+		Id:           3,
+		UserId:       2,
+		ActivityId:   1,
+		TextId:       1,
+		Duration:     30,
+		Result:       structures.ScoreResult{Version: 1, Wpm: 90},
+		ReviewStatus: structures.SCORE_REVIEW_FLAGGED,
+		FlagReasons:  []string{structures.SCORE_FLAG_PASTE_BURST},
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	mockDB.ExpectQuery(`SELECT \* FROM "scores" WHERE review_status IN .+`).
+		WithArgs(structures.SCORE_REVIEW_FLAGGED).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "activity_id", "text_id", "duration", "result", "review_status", "flag_reasons", "created_at", "updated_at"}).
+			AddRow(expectedRow.Id, expectedRow.UserId, expectedRow.ActivityId, expectedRow.TextId, expectedRow.Duration, `{"version":1,"wpm":90}`, expectedRow.ReviewStatus, `["paste_burst"]`, expectedRow.CreatedAt, expectedRow.UpdatedAt))
+
+	result, err := scoresProvider.GetScores(context.Background(), structures.ScoreFilter{ReviewStatuses: []string{structures.SCORE_REVIEW_FLAGGED}})
+
+	if err != nil {
+		t.Fatalf("error in fetching scores %v", err)
+	}
+
+	if len(result) != 1 {
+		t.Fatalf("unexpected result length: expected %v,\n got %v\n", 1, len(result))
+	}
+
+	if err := helpers.CompareReflectedStructFields(*result[0], expectedRow); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,109 @@
+package sessions_provider
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+)
+
+type SessionsProviderInterface interface {
+	GetSessionById(ctx context.Context, sessionId int) (*structures.TypingSession, error)
+	CreateSession(ctx context.Context, sessionInfo structures.TypingSession) (*structures.TypingSession, error)
+	AppendSessionKeystrokes(ctx context.Context, sessionId int, stored int, keystrokes []*structures.Keystroke) (bool, error)
+	ExpireSession(ctx context.Context, sessionId int) error
+	FinishSession(ctx context.Context, sessionId int, finishedAt time.Time, scoreInfo structures.Score) (*structures.Score, error)
+}
+
+type SessionsProvider struct {
+	Db *gorm.DB
+}
+
+func (s *SessionsProvider) GetSessionById(ctx context.Context, sessionId int) (*structures.TypingSession, error) {
+	var session *structures.TypingSession
+	err := s.Db.WithContext(ctx).Table(structures.TYPING_SESSION_TABLE_NAME).First(&session, "id = ?", sessionId).Error
+	if err != nil {
+		return nil, err
+	}
+	session.KeystrokeCount = len(session.Keystrokes)
+	return session, nil
+}
+
+func (s *SessionsProvider) CreateSession(ctx context.Context, sessionInfo structures.TypingSession) (*structures.TypingSession, error) {
+	err := s.Db.WithContext(ctx).Table(structures.TYPING_SESSION_TABLE_NAME).Create(&sessionInfo).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sessionInfo, nil
+}
+
+// AppendSessionKeystrokes adds a batch to an active session, it reports false
+// when the session no longer holds the stored number of keystrokes so two
+// batches racing each other cannot both land
+func (s *SessionsProvider) AppendSessionKeystrokes(ctx context.Context, sessionId int, stored int, keystrokes []*structures.Keystroke) (bool, error) {
+	batch, err := json.Marshal(keystrokes)
+	if err != nil {
+		return false, err
+	}
+
+	result := s.Db.WithContext(ctx).Exec(
+		"UPDATE typing_sessions SET keystrokes = keystrokes || ?::jsonb WHERE id = ? AND status = ? AND jsonb_array_length(keystrokes) = ?",
+		string(batch), sessionId, structures.SESSION_STATUS_ACTIVE, stored,
+	)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected != 0, nil
+}
+
+func (s *SessionsProvider) ExpireSession(ctx context.Context, sessionId int) error {
+	return s.Db.WithContext(ctx).Table(structures.TYPING_SESSION_TABLE_NAME).
+		Where("id = ? AND status = ?", sessionId, structures.SESSION_STATUS_ACTIVE).
+		Update("status", structures.SESSION_STATUS_EXPIRED).Error
+}
+
+// FinishSession creates the score of a session with its keystroke log and
+// closes the session in one go, a session that is no longer active is left
+// untouched
+func (s *SessionsProvider) FinishSession(ctx context.Context, sessionId int, finishedAt time.Time, scoreInfo structures.Score) (*structures.Score, error) {
+	err := s.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table(structures.SCORE_TABLE_NAME).Create(&scoreInfo).Error
+		if err != nil {
+			return err
+		}
+		if scoreInfo.KeystrokeLog != nil {
+			scoreInfo.KeystrokeLog.ScoreId = scoreInfo.Id
+			err = tx.Table(structures.SCORE_KEYSTROKES_TABLE_NAME).Create(scoreInfo.KeystrokeLog).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		result := tx.Table(structures.TYPING_SESSION_TABLE_NAME).
+			Where("id = ? AND status = ?", sessionId, structures.SESSION_STATUS_ACTIVE).
+			Updates(map[string]any{
+				"status":      structures.SESSION_STATUS_FINISHED,
+				"finished_at": finishedAt,
+				"score_id":    scoreInfo.Id,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &scoreInfo, nil
+}
+
+func NewSessionsProvider(db *gorm.DB) *SessionsProvider {
+	return &SessionsProvider{
+		Db: db,
+	}
+}
@@ -0,0 +1,113 @@
+package sessions_provider
+
+import (
+	"context"
+	"testing"
+	"time"
+	"type_writer_api/structures"
+	"type_writer_api/testing/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetSessionByIdSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	sessionsProvider := NewSessionsProvider(mockGorm)
+
+	mockDB.ExpectQuery(`SELECT \* FROM "typing_sessions" WHERE id = .+ ORDER BY "typing_sessions"\."id" LIMIT .+`).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "activity_id", "text_id", "nonce", "status", "keystrokes"}).
+			AddRow(1, 1, 2, 3, "nonce", structures.SESSION_STATUS_ACTIVE, `[{"key":"a","offset":0},{"key":"b","offset":120}]`))
+
+	result, err := sessionsProvider.GetSessionById(context.Background(), 1)
+
+	if err != nil {
+		t.Fatalf("error in fetching session %v", err)
+	}
+
+	if result.KeystrokeCount != 2 || result.Keystrokes[1].Key != "b" || result.Keystrokes[1].Offset != 120 {
+		t.Fatalf("unexpected session keystrokes %+v", result.Keystrokes)
+	}
+}
+
+func TestAppendSessionKeystrokesSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	sessionsProvider := NewSessionsProvider(mockGorm)
+
+	mockDB.ExpectExec(`UPDATE typing_sessions SET keystrokes = keystrokes \|\| .+::jsonb WHERE id = .+ AND status = .+ AND jsonb_array_length\(keystrokes\) = .+`).
+		WithArgs(`[{"key":"c","offset":300}]`, 1, structures.SESSION_STATUS_ACTIVE, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err := sessionsProvider.AppendSessionKeystrokes(context.Background(), 1, 2, []*structures.Keystroke{{Key: "c", Offset: 300}})
+
+	if err != nil {
+		t.Fatalf("error in appending keystrokes %v", err)
+	}
+
+	if result != true {
+		t.Fatalf("unexpected result: expected %v, got %v", true, result)
+	}
+}
+
+func TestExpireSessionSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	sessionsProvider := NewSessionsProvider(mockGorm)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`UPDATE "typing_sessions" SET "status"=.+ WHERE id = .+ AND status = .+`).
+		WithArgs(structures.SESSION_STATUS_EXPIRED, 1, structures.SESSION_STATUS_ACTIVE).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectCommit()
+
+	err := sessionsProvider.ExpireSession(context.Background(), 1)
+
+	if err != nil {
+		t.Fatalf("error in expiring session %v", err)
+	}
+}
+
+func TestFinishSessionSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	sessionsProvider := NewSessionsProvider(mockGorm)
+
+	finishedAt := time.Now()
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`INSERT INTO "scores" \("user_id","activity_id","text_id","duration","result","final_score","review_status","flag_reasons","reviewed_at","created_at","updated_at"\) VALUES .+ RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mockDB.ExpectExec(`UPDATE "typing_sessions" SET .+ WHERE id = .+ AND status = .+`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectCommit()
+
+	result, err := sessionsProvider.FinishSession(context.Background(), 1, finishedAt, structures.Score{UserId: 1, ActivityId: 2, TextId: 3, Duration: 30, Result: structures.ScoreResult{Version: 1, Wpm: 60}})
+
+	if err != nil {
+		t.Fatalf("error in finishing session %v", err)
+	}
+
+	if result.Id != 5 {
+		t.Fatalf("unexpected score id: expected %v, got %v", 5, result.Id)
+	}
+}
+
+func TestFinishSessionNotActive(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	sessionsProvider := NewSessionsProvider(mockGorm)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`INSERT INTO "scores" .+ RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mockDB.ExpectExec(`UPDATE "typing_sessions" SET .+ WHERE id = .+ AND status = .+`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDB.ExpectRollback()
+
+	_, err := sessionsProvider.FinishSession(context.Background(), 1, time.Now(), structures.Score{UserId: 1, ActivityId: 2, TextId: 3})
+
+	if err == nil {
+		t.Fatal("expected finishing an inactive session to fail")
+	}
+
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
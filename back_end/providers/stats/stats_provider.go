@@ -0,0 +1,184 @@
+package stats_provider
+
+import (
+	"context"
+	"time"
+	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+)
+
+// Every stat only counts ranked scores, the same as the totals the scores
+// trigger keeps in user_stats and user_daily_stats
+const (
+	wpmColumn      = "COALESCE((scores.result ->> 'wpm')::numeric, 0)"
+	accuracyColumn = "COALESCE((scores.result ->> 'accuracy')::numeric, 0)"
+)
+
+type StatsProviderInterface interface {
+	GetUserTotals(ctx context.Context, userId int) (*structures.UserStatsTotals, error)
+	GetWpmPercentile(ctx context.Context, wpm float64) (float64, error)
+	GetRollingAverage(ctx context.Context, userId int, window int) (*structures.RollingAverage, error)
+	GetPersonalBests(ctx context.Context, userId int) ([]*structures.PersonalBest, error)
+	GetTrend(ctx context.Context, userId int, bucket string, since string) ([]*structures.TrendBucket, error)
+	GetUserKeystrokeLogs(ctx context.Context, userId int, since time.Time, limit int) ([]*structures.ScoreKeystrokeLog, error)
+	GetLatestKeystrokeLogs(ctx context.Context, limit int) ([]*structures.ScoreKeystrokeLog, error)
+	GetNgramBaselines(ctx context.Context) ([]*structures.NgramBaseline, error)
+	ReplaceNgramBaselines(ctx context.Context, baselines []*structures.NgramBaseline) (bool, error)
+	GetUserTextPlays(ctx context.Context, userId int) ([]*structures.TextPlay, error)
+}
+
+type StatsProvider struct {
+	Db *gorm.DB
+}
+
+func (s *StatsProvider) GetUserTotals(ctx context.Context, userId int) (*structures.UserStatsTotals, error) {
+	var totals *structures.UserStatsTotals
+	err := s.Db.WithContext(ctx).Table(structures.USER_STATS_TABLE_NAME).
+		Where("user_id = ?", userId).Take(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// GetWpmPercentile is the share of users, out of those with any score, whose
+// average wpm is lower than the given one
+func (s *StatsProvider) GetWpmPercentile(ctx context.Context, wpm float64) (float64, error) {
+	var percentile float64
+	err := s.Db.WithContext(ctx).Table(structures.USER_STATS_TABLE_NAME).
+		Select("COALESCE(round(100.0 * count(*) FILTER (WHERE wpm_sum / scores < ?) / NULLIF(count(*), 0), 2), 0)", wpm).
+		Scan(&percentile).Error
+	if err != nil {
+		return 0, err
+	}
+	return percentile, nil
+}
+
+func (s *StatsProvider) GetRollingAverage(ctx context.Context, userId int, window int) (*structures.RollingAverage, error) {
+	average := &structures.RollingAverage{}
+	latest := s.Db.Table(structures.SCORE_TABLE_NAME).
+		Select(wpmColumn+" AS wpm, "+accuracyColumn+" AS accuracy").
+		Where("scores.user_id = ? AND scores.review_status IN ?", userId, structures.RANKED_SCORE_REVIEWS).
+		Order("scores.created_at DESC").Limit(window)
+	err := s.Db.WithContext(ctx).Table("(?) AS latest", latest).
+		Select("count(*) AS scores, COALESCE(round(avg(latest.wpm), 2), 0) AS wpm, COALESCE(round(avg(latest.accuracy), 2), 0) AS accuracy").
+		Scan(average).Error
+	if err != nil {
+		return nil, err
+	}
+	average.Window = window
+	return average, nil
+}
+
+// GetPersonalBests finds the highest wpm a user reached on every activity and
+// text they typed, ties go to the more accurate and then the earlier score
+func (s *StatsProvider) GetPersonalBests(ctx context.Context, userId int) ([]*structures.PersonalBest, error) {
+	bests := []*structures.PersonalBest{}
+	err := s.Db.WithContext(ctx).Table(structures.SCORE_TABLE_NAME).
+		Select("DISTINCT ON (scores.activity_id, scores.text_id) scores.activity_id, scores.text_id, scores.id AS score_id, "+
+			wpmColumn+" AS wpm, "+accuracyColumn+" AS accuracy, scores.created_at").
+		Where("scores.user_id = ? AND scores.review_status IN ?", userId, structures.RANKED_SCORE_REVIEWS).
+		Order("scores.activity_id, scores.text_id, " + wpmColumn + " DESC, " + accuracyColumn + " DESC, scores.created_at").
+		Find(&bests).Error
+	if err != nil {
+		return nil, err
+	}
+	return bests, nil
+}
+
+// GetTrend adds up the daily totals of a user into day or week buckets from
+// the since date onwards, weeks start on Monday
+func (s *StatsProvider) GetTrend(ctx context.Context, userId int, bucket string, since string) ([]*structures.TrendBucket, error) {
+	trend := []*structures.TrendBucket{}
+	err := s.Db.WithContext(ctx).Table(structures.USER_DAILY_STATS_TABLE_NAME).
+		Select("to_char(date_trunc(?, day), 'YYYY-MM-DD') AS start, sum(scores) AS scores, sum(duration) AS duration, "+
+			"round((sum(wpm_sum) / sum(scores))::numeric, 2) AS wpm, round((sum(accuracy_sum) / sum(scores))::numeric, 2) AS accuracy", bucket).
+		Where("user_id = ? AND day >= ?", userId, since).
+		Group("start").Order("start").
+		Find(&trend).Error
+	if err != nil {
+		return nil, err
+	}
+	return trend, nil
+}
+
+// GetUserKeystrokeLogs reads the still encoded keystroke logs of the latest
+// scores of a user, a zero since reaches back as far as the logs are kept
+func (s *StatsProvider) GetUserKeystrokeLogs(ctx context.Context, userId int, since time.Time, limit int) ([]*structures.ScoreKeystrokeLog, error) {
+	keystrokeLogs := []*structures.ScoreKeystrokeLog{}
+	tx := s.Db.WithContext(ctx).Table(structures.SCORE_KEYSTROKES_TABLE_NAME).
+		Select("score_keystrokes.*").
+		Joins("JOIN scores ON scores.id = score_keystrokes.score_id").
+		Where("scores.user_id = ? AND scores.review_status IN ?", userId, structures.RANKED_SCORE_REVIEWS)
+	if !since.IsZero() {
+		tx = tx.Where("scores.created_at >= ?", since)
+	}
+	err := tx.Order("scores.created_at DESC").Limit(limit).Find(&keystrokeLogs).Error
+	if err != nil {
+		return nil, err
+	}
+	return keystrokeLogs, nil
+}
+
+// GetLatestKeystrokeLogs reads the latest keystroke logs of every user
+func (s *StatsProvider) GetLatestKeystrokeLogs(ctx context.Context, limit int) ([]*structures.ScoreKeystrokeLog, error) {
+	keystrokeLogs := []*structures.ScoreKeystrokeLog{}
+	err := s.Db.WithContext(ctx).Table(structures.SCORE_KEYSTROKES_TABLE_NAME).
+		Select("score_keystrokes.*").
+		Joins("JOIN scores ON scores.id = score_keystrokes.score_id").
+		Where("scores.review_status IN ?", structures.RANKED_SCORE_REVIEWS).
+		Order("score_keystrokes.created_at DESC").Limit(limit).
+		Find(&keystrokeLogs).Error
+	if err != nil {
+		return nil, err
+	}
+	return keystrokeLogs, nil
+}
+
+func (s *StatsProvider) GetNgramBaselines(ctx context.Context) ([]*structures.NgramBaseline, error) {
+	baselines := []*structures.NgramBaseline{}
+	err := s.Db.WithContext(ctx).Table(structures.NGRAM_BASELINE_TABLE_NAME).Find(&baselines).Error
+	if err != nil {
+		return nil, err
+	}
+	return baselines, nil
+}
+
+// ReplaceNgramBaselines swaps every baseline for the given ones at once
+func (s *StatsProvider) ReplaceNgramBaselines(ctx context.Context, baselines []*structures.NgramBaseline) (bool, error) {
+	err := s.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("DELETE FROM ngram_baselines").Error
+		if err != nil {
+			return err
+		}
+		if len(baselines) == 0 {
+			return nil
+		}
+		return tx.Table(structures.NGRAM_BASELINE_TABLE_NAME).Create(&baselines).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetUserTextPlays lists every text a user typed with when they last did
+func (s *StatsProvider) GetUserTextPlays(ctx context.Context, userId int) ([]*structures.TextPlay, error) {
+	plays := []*structures.TextPlay{}
+	err := s.Db.WithContext(ctx).Table(structures.SCORE_TABLE_NAME).
+		Select("scores.text_id, count(*) AS plays, max(scores.created_at) AS last_played_at").
+		Where("scores.user_id = ? AND scores.review_status IN ?", userId, structures.RANKED_SCORE_REVIEWS).
+		Group("scores.text_id").Order("scores.text_id").
+		Find(&plays).Error
+	if err != nil {
+		return nil, err
+	}
+	return plays, nil
+}
+
+func NewStatsProvider(db *gorm.DB) *StatsProvider {
+	return &StatsProvider{
+		Db: db,
+	}
+}
@@ -0,0 +1,306 @@
+package stats_provider
+
+import (
+	"context"
+	"testing"
+	"time"
+	"type_writer_api/helpers"
+	"type_writer_api/structures"
+	"type_writer_api/testing/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+)
+
+func TestGetUserTotalsSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	statsProvider := NewStatsProvider(mockGorm)
+
+	expectedRow := structures.UserStatsTotals{UserId: 2, Scores: 4, Duration: 240, WpmSum: 320, AccuracySum: 390}
+
+	mockDB.ExpectQuery(`SELECT \* FROM "user_stats" WHERE user_id = \$1 LIMIT \$2`).
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "scores", "duration", "wpm_sum", "accuracy_sum"}).
+			AddRow(expectedRow.UserId, expectedRow.Scores, expectedRow.Duration, expectedRow.WpmSum, expectedRow.AccuracySum))
+
+	result, err := statsProvider.GetUserTotals(context.Background(), 2)
+
+	if err != nil {
+		t.Fatalf("error in fetching user totals %v", err)
+	}
+
+	if err := helpers.CompareReflectedStructFields(*result, expectedRow); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetUserTotalsNotFound(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	statsProvider := NewStatsProvider(mockGorm)
+
+	mockDB.ExpectQuery(`SELECT \* FROM "user_stats" WHERE user_id = \$1 LIMIT \$2`).
+		WithArgs(3, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "scores", "duration", "wpm_sum", "accuracy_sum"}))
+
+	_, err := statsProvider.GetUserTotals(context.Background(), 3)
+
+	if err != gorm.ErrRecordNotFound {
+		t.Fatalf("expected error: %v but got %v instead", gorm.ErrRecordNotFound, err)
+	}
+}
+
+func TestGetWpmPercentileSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	statsProvider := NewStatsProvider(mockGorm)
+
+	mockDB.ExpectQuery(`SELECT COALESCE\(round\(100\.0 \* count\(\*\) FILTER \(WHERE wpm_sum / scores < \$1\) / NULLIF\(count\(\*\), 0\), 2\), 0\) FROM "user_stats"`).
+		WithArgs(80.0).
+		WillReturnRows(sqlmock.NewRows([]string{"percentile"}).AddRow(62.5))
+
+	result, err := statsProvider.GetWpmPercentile(context.Background(), 80)
+
+	if err != nil {
+		t.Fatalf("error in fetching percentile %v", err)
+	}
+
+	if result != 62.5 {
+		t.Fatalf("expected percentile: %v but got %v instead", 62.5, result)
+	}
+}
+
+func TestGetRollingAverageSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	statsProvider := NewStatsProvider(mockGorm)
+
+	expected := structures.RollingAverage{Window: 10, Scores: 3, Wpm: 81.33, Accuracy: 97.5}
+
+	mockDB.ExpectQuery(`SELECT count\(\*\) AS scores, .+ FROM \(SELECT .+ AS wpm, .+ AS accuracy FROM "scores" WHERE scores\.user_id = \$1 AND scores\.review_status IN \(\$2,\$3\) ORDER BY scores\.created_at DESC LIMIT \$4\) AS latest`).
+		WithArgs(2, structures.SCORE_REVIEW_CLEAN, structures.SCORE_REVIEW_APPROVED, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"scores", "wpm", "accuracy"}).AddRow(expected.Scores, expected.Wpm, expected.Accuracy))
+
+	result, err := statsProvider.GetRollingAverage(context.Background(), 2, 10)
+
+	if err != nil {
+		t.Fatalf("error in fetching rolling average %v", err)
+	}
+
+	if err := helpers.CompareReflectedStructFields(*result, expected); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetPersonalBestsSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	statsProvider := NewStatsProvider(mockGorm)
+
+	expectedRows := []structures.PersonalBest{
+		{ActivityId: 1, TextId: 1, ScoreId: 4, Wpm: 92, Accuracy: 98, CreatedAt: time.Now()},
+		{ActivityId: 1, TextId: 3, ScoreId: 9, Wpm: 75.5, Accuracy: 95, CreatedAt: time.Now()},
+	}
+
+	resultRows := sqlmock.NewRows([]string{"activity_id", "text_id", "score_id", "wpm", "accuracy", "created_at"})
+	for _, expectedRow := range expectedRows {
+		resultRows.AddRow(expectedRow.ActivityId, expectedRow.TextId, expectedRow.ScoreId, expectedRow.Wpm, expectedRow.Accuracy, expectedRow.CreatedAt)
+	}
+
+	mockDB.ExpectQuery(`SELECT DISTINCT ON \(scores\.activity_id, scores\.text_id\) .+ FROM "scores" WHERE scores\.user_id = \$1 AND scores\.review_status IN \(\$2,\$3\) ORDER BY scores\.activity_id, scores\.text_id, .+ DESC, .+ DESC, scores\.created_at`).
+		WithArgs(2, structures.SCORE_REVIEW_CLEAN, structures.SCORE_REVIEW_APPROVED).
+		WillReturnRows(resultRows)
+
+	result, err := statsProvider.GetPersonalBests(context.Background(), 2)
+
+	if err != nil {
+		t.Fatalf("error in fetching personal bests %v", err)
+	}
+
+	if len(result) != len(expectedRows) {
+		t.Fatalf("unexpected result length: expected %v,\n got %v\n", len(expectedRows), len(result))
+	}
+
+	for indx, resultRow := range result {
+		err := helpers.CompareReflectedStructFields(*resultRow, expectedRows[indx])
+		if err != nil {
+			t.Fatalf("row %v failed: %v\n", indx, err.Error())
+		}
+	}
+}
+
+func TestGetTrendSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	statsProvider := NewStatsProvider(mockGorm)
+
+	expectedRows := []structures.TrendBucket{
+		{Start: "2026-10-05", Scores: 6, Duration: 360, Wpm: 70.5, Accuracy: 96},
+		{Start: "2026-10-12", Scores: 2, Duration: 120, Wpm: 74, Accuracy: 97.25},
+	}
+
+	resultRows := sqlmock.NewRows([]string{"start", "scores", "duration", "wpm", "accuracy"})
+	for _, expectedRow := range expectedRows {
+		resultRows.AddRow(expectedRow.Start, expectedRow.Scores, expectedRow.Duration, expectedRow.Wpm, expectedRow.Accuracy)
+	}
+
+	mockDB.ExpectQuery(`SELECT to_char\(date_trunc\(\$1, day\), 'YYYY-MM-DD'\) AS start, .+ FROM "user_daily_stats" WHERE user_id = \$2 AND day >= \$3 GROUP BY "start" ORDER BY start`).
+		WithArgs(structures.STATS_BUCKET_WEEK, 2, "2026-04-20").
+		WillReturnRows(resultRows)
+
+	result, err := statsProvider.GetTrend(context.Background(), 2, structures.STATS_BUCKET_WEEK, "2026-04-20")
+
+	if err != nil {
+		t.Fatalf("error in fetching trend %v", err)
+	}
+
+	if len(result) != len(expectedRows) {
+		t.Fatalf("unexpected result length: expected %v,\n got %v\n", len(expectedRows), len(result))
+	}
+
+	for indx, resultRow := range result {
+		err := helpers.CompareReflectedStructFields(*resultRow, expectedRows[indx])
+		if err != nil {
+			t.Fatalf("row %v failed: %v\n", indx, err.Error())
+		}
+	}
+}
+
+func TestGetUserKeystrokeLogsSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	statsProvider := NewStatsProvider(mockGorm)
+
+	since := time.Now().Add(-7 * 24 * time.Hour)
+	expectedRows := []structures.ScoreKeystrokeLog{
+		{ScoreId: 9, Encoding: structures.KEYSTROKE_LOG_ENCODING, EventCount: 12, Data: []byte{1, 2}, CreatedAt: time.Now()},
+		{ScoreId: 4, Encoding: structures.KEYSTROKE_LOG_ENCODING, EventCount: 30, Data: []byte{3, 4}, CreatedAt: time.Now()},
+	}
+
+	resultRows := sqlmock.NewRows([]string{"score_id", "encoding", "event_count", "data", "created_at"})
+	for _, expectedRow := range expectedRows {
+		resultRows.AddRow(expectedRow.ScoreId, expectedRow.Encoding, expectedRow.EventCount, expectedRow.Data, expectedRow.CreatedAt)
+	}
+
+	mockDB.ExpectQuery(`SELECT score_keystrokes\.\* FROM "score_keystrokes" JOIN scores ON scores\.id = score_keystrokes\.score_id WHERE \(scores\.user_id = \$1 AND scores\.review_status IN \(\$2,\$3\)\) AND scores\.created_at >= \$4 ORDER BY scores\.created_at DESC LIMIT \$5`).
+		WithArgs(2, structures.SCORE_REVIEW_CLEAN, structures.SCORE_REVIEW_APPROVED, since, structures.MAX_KEYMAP_STATS_LOGS).
+		WillReturnRows(resultRows)
+
+	result, err := statsProvider.GetUserKeystrokeLogs(context.Background(), 2, since, structures.MAX_KEYMAP_STATS_LOGS)
+
+	if err != nil {
+		t.Fatalf("error in fetching keystroke logs %v", err)
+	}
+
+	if len(result) != len(expectedRows) {
+		t.Fatalf("unexpected result length: expected %v,\n got %v\n", len(expectedRows), len(result))
+	}
+
+	for indx, resultRow := range result {
+		err := helpers.CompareReflectedStructFields(*resultRow, expectedRows[indx])
+		if err != nil {
+			t.Fatalf("row %v failed: %v\n", indx, err.Error())
+		}
+	}
+}
+
+func TestGetLatestKeystrokeLogsSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	statsProvider := NewStatsProvider(mockGorm)
+
+	mockDB.ExpectQuery(`SELECT score_keystrokes\.\* FROM "score_keystrokes" JOIN scores ON scores\.id = score_keystrokes\.score_id WHERE scores\.review_status IN \(\$1,\$2\) ORDER BY score_keystrokes\.created_at DESC LIMIT \$3`).
+		WithArgs(structures.SCORE_REVIEW_CLEAN, structures.SCORE_REVIEW_APPROVED, structures.MAX_NGRAM_BASELINE_LOGS).
+		WillReturnRows(sqlmock.NewRows([]string{"score_id", "encoding", "event_count", "data", "created_at"}).
+			AddRow(9, structures.KEYSTROKE_LOG_ENCODING, 12, []byte{1, 2}, time.Now()))
+
+	result, err := statsProvider.GetLatestKeystrokeLogs(context.Background(), structures.MAX_NGRAM_BASELINE_LOGS)
+
+	if err != nil {
+		t.Fatalf("error in fetching keystroke logs %v", err)
+	}
+
+	if len(result) != 1 || result[0].ScoreId != 9 {
+		t.Fatalf("unexpected keystroke logs: %v", result)
+	}
+}
+
+func TestGetNgramBaselinesSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	statsProvider := NewStatsProvider(mockGorm)
+
+	expectedRows := []structures.NgramBaseline{
+		{Ngram: "th", Samples: 420, MedianLatency: 110, UpdatedAt: time.Now()},
+		{Ngram: "the", Samples: 300, MedianLatency: 240, UpdatedAt: time.Now()},
+	}
+
+	resultRows := sqlmock.NewRows([]string{"ngram", "samples", "median_latency", "updated_at"})
+	for _, expectedRow := range expectedRows {
+		resultRows.AddRow(expectedRow.Ngram, expectedRow.Samples, expectedRow.MedianLatency, expectedRow.UpdatedAt)
+	}
+
+	mockDB.ExpectQuery(`SELECT \* FROM "ngram_baselines"`).WillReturnRows(resultRows)
+
+	result, err := statsProvider.GetNgramBaselines(context.Background())
+
+	if err != nil {
+		t.Fatalf("error in fetching ngram baselines %v", err)
+	}
+
+	if len(result) != len(expectedRows) {
+		t.Fatalf("unexpected result length: expected %v,\n got %v\n", len(expectedRows), len(result))
+	}
+
+	for indx, resultRow := range result {
+		err := helpers.CompareReflectedStructFields(*resultRow, expectedRows[indx])
+		if err != nil {
+			t.Fatalf("row %v failed: %v\n", indx, err.Error())
+		}
+	}
+}
+
+func TestReplaceNgramBaselinesSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	statsProvider := NewStatsProvider(mockGorm)
+
+	baselines := []*structures.NgramBaseline{
+		{Ngram: "th", Samples: 420, MedianLatency: 110},
+		{Ngram: "he", Samples: 380, MedianLatency: 95},
+	}
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`DELETE FROM ngram_baselines`).WillReturnResult(sqlmock.NewResult(0, 5))
+	mockDB.ExpectExec(`INSERT INTO "ngram_baselines" \("ngram","samples","median_latency","updated_at"\) VALUES \(\$1,\$2,\$3,\$4\),\(\$5,\$6,\$7,\$8\)`).
+		WithArgs("th", 420, 110.0, sqlmock.AnyArg(), "he", 380, 95.0, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mockDB.ExpectCommit()
+
+	result, err := statsProvider.ReplaceNgramBaselines(context.Background(), baselines)
+
+	if err != nil {
+		t.Fatalf("error in replacing ngram baselines %v", err)
+	}
+
+	if !result {
+		t.Fatalf("expected baselines to be replaced")
+	}
+
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetUserTextPlaysSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	statsProvider := NewStatsProvider(mockGorm)
+
+	lastPlayed := time.Now()
+	mockDB.ExpectQuery(`SELECT scores\.text_id, count\(\*\) AS plays, max\(scores\.created_at\) AS last_played_at FROM "scores" WHERE scores\.user_id = \$1 AND scores\.review_status IN \(\$2,\$3\) GROUP BY "scores"\."text_id" ORDER BY scores\.text_id`).
+		WithArgs(2, structures.SCORE_REVIEW_CLEAN, structures.SCORE_REVIEW_APPROVED).
+		WillReturnRows(sqlmock.NewRows([]string{"text_id", "plays", "last_played_at"}).
+			AddRow(1, 3, lastPlayed).
+			AddRow(4, 1, lastPlayed))
+
+	result, err := statsProvider.GetUserTextPlays(context.Background(), 2)
+
+	if err != nil {
+		t.Fatalf("error in fetching text plays %v", err)
+	}
+
+	if len(result) != 2 || result[0].TextId != 1 || result[0].Plays != 3 || result[1].TextId != 4 {
+		t.Fatalf("unexpected text plays: %v", result)
+	}
+}
@@ -0,0 +1,102 @@
+package tags_provider
+
+import (
+	"context"
+	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+)
+
+type TagsProviderInterface interface {
+	GetTags(ctx context.Context) ([]*structures.Tag, error)
+	GetTagByIdOrName(ctx context.Context, tagId *int, name *string) (*structures.Tag, error)
+	CreateTag(ctx context.Context, tagInfo structures.Tag) (*structures.Tag, error)
+	UpdateTag(ctx context.Context, updatedTagInfo structures.Tag) (*structures.Tag, error)
+	DeleteTag(ctx context.Context, tagId int) (bool, error)
+	MergeTags(ctx context.Context, sourceId, targetId int) (bool, error)
+}
+
+type TagsProvider struct {
+	Db *gorm.DB
+}
+
+// usageCountSubquery counts the texts linked to each tag row
+func (t *TagsProvider) usageCountSubquery() *gorm.DB {
+	return t.Db.Table(structures.TEXT_TAG_TABLE_NAME).
+		Select("count(*)").
+		Where("text_tags.tag_id = tags.id")
+}
+
+func (t *TagsProvider) GetTags(ctx context.Context) ([]*structures.Tag, error) {
+	var tags []*structures.Tag
+	err := t.Db.WithContext(ctx).Table(structures.TAG_TABLE_NAME).
+		Select("tags.*, (?) AS usage_count", t.usageCountSubquery()).
+		Order("tags.name").
+		Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (t *TagsProvider) GetTagByIdOrName(ctx context.Context, tagId *int, name *string) (*structures.Tag, error) {
+	var tag *structures.Tag
+	err := t.Db.WithContext(ctx).Table(structures.TAG_TABLE_NAME).
+		Select("tags.*, (?) AS usage_count", t.usageCountSubquery()).
+		First(&tag, "id = ? OR name = ?", tagId, name).Error
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (t *TagsProvider) CreateTag(ctx context.Context, tagInfo structures.Tag) (*structures.Tag, error) {
+	var tag *structures.Tag
+	err := t.Db.WithContext(ctx).Table(structures.TAG_TABLE_NAME).FirstOrCreate(&tag, &tagInfo).Error
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (t *TagsProvider) UpdateTag(ctx context.Context, updatedTagInfo structures.Tag) (*structures.Tag, error) {
+	err := t.Db.WithContext(ctx).Table(structures.TAG_TABLE_NAME).Updates(&updatedTagInfo).Error
+	if err != nil {
+		return nil, err
+	}
+	return &updatedTagInfo, nil
+}
+
+func (t *TagsProvider) DeleteTag(ctx context.Context, tagId int) (bool, error) {
+	var deleteTag = structures.Tag{Id: tagId}
+	err := t.Db.WithContext(ctx).Table(structures.TAG_TABLE_NAME).Delete(&deleteTag).Error
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// MergeTags relinks every text tagged with the source tag to the target tag
+// and removes the source tag, all inside a single transaction
+func (t *TagsProvider) MergeTags(ctx context.Context, sourceId, targetId int) (bool, error) {
+	err := t.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(
+			"INSERT INTO text_tags (text_id, tag_id) SELECT text_id, ? FROM text_tags WHERE tag_id = ? ON CONFLICT DO NOTHING",
+			targetId, sourceId,
+		).Error
+		if err != nil {
+			return err
+		}
+		return tx.Table(structures.TAG_TABLE_NAME).Delete(&structures.Tag{Id: sourceId}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func NewTagsProvider(db *gorm.DB) *TagsProvider {
+	return &TagsProvider{
+		Db: db,
+	}
+}
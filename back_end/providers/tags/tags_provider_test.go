@@ -0,0 +1,220 @@
+package tags_provider
+
+import (
+	"context"
+	"testing"
+	"time"
+	"type_writer_api/helpers"
+	"type_writer_api/structures"
+	"type_writer_api/testing/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetTagsSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	tagsProvider := NewTagsProvider(mockGorm)
+
+	expectedRows := []structures.Tag{
+		{
+			Id:         1,
+			Name:       "classics",
+			UsageCount: 3,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		},
+		{
+			Id:         2,
+			Name:       "test tag",
+			UsageCount: 0,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		},
+	}
+
+	resultRows := sqlmock.NewRows([]string{
+		"id",
+		"name",
+		"usage_count",
+		"created_at",
+		"updated_at",
+	})
+
+	for _, expectedRow := range expectedRows {
+		resultRows.AddRow(
+			expectedRow.Id,
+			expectedRow.Name,
+			expectedRow.UsageCount,
+			expectedRow.CreatedAt,
+			expectedRow.UpdatedAt,
+		)
+	}
+
+	mockDB.ExpectQuery(`SELECT tags\.\*, \(SELECT count\(\*\) FROM "text_tags" WHERE text_tags\.tag_id = tags\.id\) AS usage_count FROM "tags" ORDER BY tags\.name`).WillReturnRows(resultRows)
+
+	result, err := tagsProvider.GetTags(context.Background())
+
+	if err != nil {
+		t.Fatalf("error in fetching tags %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("unexpected result length: expected %v, got %v", 2, len(result))
+	}
+
+	for indx, resultRow := range result {
+		err := helpers.CompareReflectedStructFields(*resultRow, expectedRows[indx])
+		if err != nil {
+			t.Fatalf("row %v failed: %v\n", indx, err.Error())
+		}
+	}
+}
+
+func TestGetTagByIdOrNameSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	tagsProvider := NewTagsProvider(mockGorm)
+
+	expectedRow := structures.Tag{
+		Id:         1,
+		Name:       "classics",
+		UsageCount: 3,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	resultRows := sqlmock.NewRows([]string{
+		"id",
+		"name",
+		"usage_count",
+		"created_at",
+		"updated_at",
+	}).AddRow(
+		expectedRow.Id,
+		expectedRow.Name,
+		expectedRow.UsageCount,
+		expectedRow.CreatedAt,
+		expectedRow.UpdatedAt,
+	)
+
+	mockDB.ExpectQuery(`SELECT tags\.\*, \(SELECT count\(\*\) .+\) AS usage_count FROM "tags" WHERE id = .+ OR name = .+ ORDER BY "tags"\."id" LIMIT .+`).WillReturnRows(resultRows)
+
+	inputId := 1
+	inputName := ""
+	result, err := tagsProvider.GetTagByIdOrName(context.Background(), &inputId, &inputName)
+
+	if err != nil {
+		t.Fatalf("error in fetching tag %v", err)
+	}
+
+	if err := helpers.CompareReflectedStructFields(*result, expectedRow); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateTagSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	tagsProvider := NewTagsProvider(mockGorm)
+
+	expectedRow := structures.Tag{
+		Id:        1,
+		Name:      "classics",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	resultRows := sqlmock.NewRows([]string{
+		"id",
+		"name",
+		"created_at",
+		"updated_at",
+	}).AddRow(
+		expectedRow.Id,
+		expectedRow.Name,
+		expectedRow.CreatedAt,
+		expectedRow.UpdatedAt,
+	)
+
+	mockDB.ExpectQuery(`SELECT \* FROM "tags" WHERE "tags"\."id" = .+ AND "tags"\."name" = .+ ORDER BY "tags"\."id" LIMIT .+`).WillReturnRows(resultRows)
+
+	result, err := tagsProvider.CreateTag(context.Background(), expectedRow)
+
+	if err != nil {
+		t.Fatalf("error in creating tag %v", err)
+	}
+
+	if err := helpers.CompareReflectedStructFields(*result, expectedRow); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateTagSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	tagsProvider := NewTagsProvider(mockGorm)
+
+	expectedRow := structures.Tag{
+		Id:         1,
+		Name:       "renamed",
+		UsageCount: 3,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`UPDATE "tags" SET "name"=.+,"created_at"=.+,"updated_at"=.+ WHERE "id" = .+`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectCommit()
+
+	result, err := tagsProvider.UpdateTag(context.Background(), expectedRow)
+
+	if err != nil {
+		t.Fatalf("error in updating tag %v", err)
+	}
+
+	if err := helpers.CompareReflectedStructFields(*result, expectedRow); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteTagSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	tagsProvider := NewTagsProvider(mockGorm)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`FROM "tags" WHERE "tags"\."id" = .+`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectCommit()
+
+	result, err := tagsProvider.DeleteTag(context.Background(), 1)
+
+	if err != nil {
+		t.Fatalf("error in deleting tag %v", err)
+	}
+
+	if result != true {
+		t.Fatalf("unexpected result: expected %v, got %v", true, result)
+	}
+}
+
+func TestMergeTagsSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	tagsProvider := NewTagsProvider(mockGorm)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`INSERT INTO text_tags \(text_id, tag_id\) SELECT text_id, .+ FROM text_tags WHERE tag_id = .+ ON CONFLICT DO NOTHING`).
+		WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mockDB.ExpectExec(`DELETE FROM "tags" WHERE "tags"\."id" = .+`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectCommit()
+
+	result, err := tagsProvider.MergeTags(context.Background(), 1, 2)
+
+	if err != nil {
+		t.Fatalf("error in merging tags %v", err)
+	}
+
+	if result != true {
+		t.Fatalf("unexpected result: expected %v, got %v", true, result)
+	}
+
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -2,26 +2,134 @@ package texts_provider
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"type_writer_api/structures"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TextsProviderInterface interface {
-	GetTexts(ctx context.Context) ([]*structures.Text, error)
+	GetTexts(ctx context.Context, filter structures.TextFilter) ([]*structures.Text, error)
 	GetTextByIdOrTitle(ctx context.Context, textId *int, title *string) (*structures.Text, error)
+	GetTextByShareSlug(ctx context.Context, shareSlug string) (*structures.Text, error)
+	GetDrillText(ctx context.Context, ownerId int, title string) (*structures.Text, error)
 	CreateText(ctx context.Context, textInfo structures.Text) (*structures.Text, error)
 	UpdateText(ctx context.Context, updatedtextInfo structures.Text) (*structures.Text, error)
 	DeleteText(ctx context.Context, textId int) (bool, error)
+	GetTextFingerprints(ctx context.Context) ([]*structures.TextFingerprint, error)
+	GetUnfingerprintedTexts(ctx context.Context) ([]*structures.Text, error)
+	UpdateTextFingerprint(ctx context.Context, textId int, fingerprint []uint32) (bool, error)
+	RateText(ctx context.Context, rating structures.TextRating) (*structures.TextRating, error)
+	DeleteTextRating(ctx context.Context, userId, textId int) (bool, error)
+	FavoriteText(ctx context.Context, userId, textId int) (bool, error)
+	UnfavoriteText(ctx context.Context, userId, textId int) (bool, error)
 }
 
 type TextsProvider struct {
 	Db *gorm.DB
 }
 
-func (t *TextsProvider) GetTexts(ctx context.Context) ([]*structures.Text, error) {
+// tagsSubquery aggregates the names of the tags linked to each text row into
+// the json array the tags field is serialized from
+func (t *TextsProvider) tagsSubquery() *gorm.DB {
+	return t.Db.Table(structures.TEXT_TAG_TABLE_NAME).
+		Select("COALESCE(jsonb_agg(tags.name ORDER BY tags.name), '[]'::jsonb)").
+		Joins("JOIN tags ON tags.id = text_tags.tag_id").
+		Where("text_tags.text_id = texts.id")
+}
+
+// textStatsSubqueries aggregate the ratings, favorites and plays of each text row
+func (t *TextsProvider) textStatsSubqueries() []interface{} {
+	return []interface{}{
+		t.Db.Table(structures.TEXT_RATING_TABLE_NAME).Select("COALESCE(AVG(rating), 0)").Where("text_ratings.text_id = texts.id"),
+		t.Db.Table(structures.TEXT_RATING_TABLE_NAME).Select("count(*)").Where("text_ratings.text_id = texts.id"),
+		t.Db.Table(structures.TEXT_FAVORITE_TABLE_NAME).Select("count(*)").Where("text_favorites.text_id = texts.id"),
+		t.Db.Table(structures.SCORE_TABLE_NAME).Select("count(*)").Where("scores.text_id = texts.id"),
+	}
+}
+
+func (t *TextsProvider) textsQuery(db *gorm.DB) *gorm.DB {
+	args := append([]interface{}{t.tagsSubquery()}, t.textStatsSubqueries()...)
+	return db.Table(structures.TEXT_TABLE_NAME).
+		Select("texts.*, (?) AS tags, (?) AS average_rating, (?) AS rating_count, (?) AS favorite_count, (?) AS play_count", args...)
+}
+
+// replaceTextTags links the text to exactly the given tag names, creating any
+// tag that does not exist yet
+func replaceTextTags(tx *gorm.DB, textId int, tagNames []string) error {
+	err := tx.Exec("DELETE FROM text_tags WHERE text_id = ?", textId).Error
+	if err != nil {
+		return err
+	}
+	if len(tagNames) == 0 {
+		return nil
+	}
+
+	tags := []structures.Tag{}
+	for _, name := range tagNames {
+		tags = append(tags, structures.Tag{Name: name})
+	}
+	err = tx.Table(structures.TAG_TABLE_NAME).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+		Create(&tags).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(
+		"INSERT INTO text_tags (text_id, tag_id) SELECT ?, id FROM tags WHERE name IN ? ON CONFLICT DO NOTHING",
+		textId, tagNames,
+	).Error
+}
+
+func (t *TextsProvider) GetTexts(ctx context.Context, filter structures.TextFilter) ([]*structures.Text, error) {
 	var texts []*structures.Text
-	err := t.Db.WithContext(ctx).Table(structures.TEXT_TABLE_NAME).Find(&texts).Error
+	query := t.textsQuery(t.Db.WithContext(ctx))
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filter.Status != "" {
+		conditions = append(conditions, "texts.status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.Visibility != "" {
+		conditions = append(conditions, "texts.visibility = ?")
+		args = append(args, filter.Visibility)
+	}
+	if len(conditions) != 0 {
+		catalog := strings.Join(conditions, " AND ")
+		if filter.ViewerId != 0 {
+			catalog = "(" + catalog + ") OR texts.owner_id = ?"
+			args = append(args, filter.ViewerId)
+		}
+		query = query.Where(catalog, args...)
+	}
+	if filter.Language != "" {
+		query = query.Where("texts.language = ?", filter.Language)
+	}
+	if filter.License != "" {
+		query = query.Where("texts.license = ?", filter.License)
+	}
+	if filter.FavoritedBy != 0 {
+		query = query.Where("texts.id IN (?)",
+			t.Db.Table(structures.TEXT_FAVORITE_TABLE_NAME).Select("text_id").Where("user_id = ?", filter.FavoritedBy),
+		)
+	}
+
+	switch filter.Sort {
+	case structures.TEXT_SORT_TOP_RATED:
+		query = query.Order("average_rating DESC, rating_count DESC, texts.id")
+	case structures.TEXT_SORT_MOST_PLAYED:
+		query = query.Order("play_count DESC, texts.id")
+	default:
+		query = query.Order("texts.id")
+	}
+
+	err := query.Find(&texts).Error
 	if err != nil {
 		return nil, err
 	}
@@ -30,7 +138,7 @@ func (t *TextsProvider) GetTexts(ctx context.Context) ([]*structures.Text, error
 
 func (t *TextsProvider) GetTextByIdOrTitle(ctx context.Context, textId *int, title *string) (*structures.Text, error) {
 	var text *structures.Text
-	err := t.Db.WithContext(ctx).Table(structures.TEXT_TABLE_NAME).
+	err := t.textsQuery(t.Db.WithContext(ctx)).
 		First(&text, "id = ? OR title = ?", textId, title).Error
 	if err != nil {
 		return nil, err
@@ -38,17 +146,52 @@ func (t *TextsProvider) GetTextByIdOrTitle(ctx context.Context, textId *int, tit
 	return text, nil
 }
 
-func (t *TextsProvider) CreateText(ctx context.Context, textInfo structures.Text) (*structures.Text, error) {
+func (t *TextsProvider) GetTextByShareSlug(ctx context.Context, shareSlug string) (*structures.Text, error) {
+	var text *structures.Text
+	err := t.textsQuery(t.Db.WithContext(ctx)).
+		First(&text, "share_slug = ?", shareSlug).Error
+	if err != nil {
+		return nil, err
+	}
+	return text, nil
+}
+
+// GetDrillText finds the oldest drill of a user going by the given title
+func (t *TextsProvider) GetDrillText(ctx context.Context, ownerId int, title string) (*structures.Text, error) {
 	var text *structures.Text
-	err := t.Db.WithContext(ctx).Table(structures.TEXT_TABLE_NAME).FirstOrCreate(&text, &textInfo).Error
+	err := t.textsQuery(t.Db.WithContext(ctx)).
+		Where("texts.owner_id = ? AND texts.text_type = ? AND texts.title = ?", ownerId, structures.TEXT_TYPE_DRILL, title).
+		First(&text).Error
 	if err != nil {
 		return nil, err
 	}
 	return text, nil
 }
 
+func (t *TextsProvider) CreateText(ctx context.Context, textInfo structures.Text) (*structures.Text, error) {
+	err := t.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table(structures.TEXT_TABLE_NAME).Create(&textInfo).Error
+		if err != nil {
+			return err
+		}
+		return replaceTextTags(tx, textInfo.Id, textInfo.Tags)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &textInfo, nil
+}
+
 func (t *TextsProvider) UpdateText(ctx context.Context, updatedTextInfo structures.Text) (*structures.Text, error) {
-	err := t.Db.WithContext(ctx).Table(structures.TEXT_TABLE_NAME).Updates(&updatedTextInfo).Error
+	err := t.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// every column is written so fields can be cleared, the caller always
+		// passes the whole text
+		err := tx.Table(structures.TEXT_TABLE_NAME).Select("*").Omit("created_at").Updates(&updatedTextInfo).Error
+		if err != nil {
+			return err
+		}
+		return replaceTextTags(tx, updatedTextInfo.Id, updatedTextInfo.Tags)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -64,6 +207,89 @@ func (t *TextsProvider) DeleteText(ctx context.Context, textId int) (bool, error
 	return true, nil
 }
 
+// GetTextFingerprints lists the fingerprint of every text that has one, without
+// loading the text bodies
+func (t *TextsProvider) GetTextFingerprints(ctx context.Context) ([]*structures.TextFingerprint, error) {
+	var fingerprints []*structures.TextFingerprint
+	err := t.Db.WithContext(ctx).Table(structures.TEXT_TABLE_NAME).
+		Select("id, title, status, owner_id, visibility, fingerprint").
+		Where("fingerprint IS NOT NULL").
+		Order("id").
+		Find(&fingerprints).Error
+	if err != nil {
+		return nil, err
+	}
+	return fingerprints, nil
+}
+
+func (t *TextsProvider) GetUnfingerprintedTexts(ctx context.Context) ([]*structures.Text, error) {
+	var texts []*structures.Text
+	err := t.textsQuery(t.Db.WithContext(ctx)).Where("texts.fingerprint IS NULL").Find(&texts).Error
+	if err != nil {
+		return nil, err
+	}
+	return texts, nil
+}
+
+func (t *TextsProvider) UpdateTextFingerprint(ctx context.Context, textId int, fingerprint []uint32) (bool, error) {
+	encoded, err := json.Marshal(fingerprint)
+	if err != nil {
+		return false, err
+	}
+	err = t.Db.WithContext(ctx).Table(structures.TEXT_TABLE_NAME).
+		Where("id = ?", textId).
+		UpdateColumn("fingerprint", string(encoded)).Error
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RateText stores the rating a user gives a text, replacing any earlier one
+func (t *TextsProvider) RateText(ctx context.Context, rating structures.TextRating) (*structures.TextRating, error) {
+	err := t.Db.WithContext(ctx).Table(structures.TEXT_RATING_TABLE_NAME).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "text_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rating", "updated_at"}),
+		}).
+		Create(&rating).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+func (t *TextsProvider) DeleteTextRating(ctx context.Context, userId, textId int) (bool, error) {
+	result := t.Db.WithContext(ctx).Table(structures.TEXT_RATING_TABLE_NAME).
+		Where("user_id = ? AND text_id = ?", userId, textId).
+		Delete(&structures.TextRating{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected != 0, nil
+}
+
+func (t *TextsProvider) FavoriteText(ctx context.Context, userId, textId int) (bool, error) {
+	favorite := structures.TextFavorite{UserId: userId, TextId: textId}
+	err := t.Db.WithContext(ctx).Table(structures.TEXT_FAVORITE_TABLE_NAME).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&favorite).Error
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (t *TextsProvider) UnfavoriteText(ctx context.Context, userId, textId int) (bool, error) {
+	result := t.Db.WithContext(ctx).Table(structures.TEXT_FAVORITE_TABLE_NAME).
+		Where("user_id = ? AND text_id = ?", userId, textId).
+		Delete(&structures.TextFavorite{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected != 0, nil
+}
+
 func NewTextsProvider(db *gorm.DB) *TextsProvider {
 	return &TextsProvider{
 		Db: db,
@@ -71,9 +71,9 @@ func TestGetTextsSuccess(t *testing.T) {
 		)
 	}
 
-	mockDB.ExpectQuery(`SELECT \* FROM "texts"`).WillReturnRows(resultRows)
+	mockDB.ExpectQuery(`SELECT texts\.\*, \(SELECT COALESCE\(jsonb_agg\(tags\.name ORDER BY tags\.name\), .+\) FROM "text_tags" JOIN tags .+\) AS tags, \(SELECT COALESCE\(AVG\(rating\), 0\) FROM "text_ratings" .+\) AS average_rating, .+ AS play_count FROM "texts" WHERE texts\.status = .+ ORDER BY texts\.id`).WillReturnRows(resultRows)
 
-	result, err := textsProvider.GetTexts(context.Background())
+	result, err := textsProvider.GetTexts(context.Background(), structures.TextFilter{Status: structures.TEXT_STATUS_APPROVED})
 
 	if err != nil {
 		t.Fatalf("error in fetching texts %v", err)
@@ -133,7 +133,7 @@ func TestGetTextByIdOrNameSuccess(t *testing.T) {
 		expectedRow.UpdatedAt,
 	)
 
-	mockDB.ExpectQuery(`SELECT \* FROM "texts" WHERE id = .+ ORDER BY "texts"\."id" LIMIT .+`).WillReturnRows(resultRows)
+	mockDB.ExpectQuery(`SELECT texts\.\*, \(SELECT .+ FROM "text_tags" .+\) AS tags, .+ AS play_count FROM "texts" WHERE id = .+ ORDER BY "texts"\."id" LIMIT .+`).WillReturnRows(resultRows)
 
 	inputId := 1
 	inputUsername := ""
@@ -152,47 +152,31 @@ func TestCreateTextSuccess(t *testing.T) {
 	mockGorm, mockDB := mocks.NewMockDB()
 	textsProvider := NewTextsProvider(mockGorm)
 
-	expectedRow := structures.Text{
-		Id:         1,
-		TextType:   "drill",
-		Title:      "test drill",
-		Difficulty: "easy",
-		Tags:		[]string{"test elem"},
-		TextBody:   "ffff jjjj fj fj",
-		TextLength: 15,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+	inputRow := structures.Text{
+		TextType:    "drill",
+		Title:       "test drill",
+		Difficulty:  "easy",
+		Tags:        []string{"test elem"},
+		TextBody:    "ffff jjjj fj fj",
+		TextLength:  15,
+		Language:    "en",
+		Status:      structures.TEXT_STATUS_APPROVED,
+		Visibility:  structures.TEXT_VISIBILITY_PUBLIC,
+		Fingerprint: []uint32{1, 2, 3},
 	}
+	expectedRow := inputRow
+	expectedRow.Id = 1
 
-	sTags, err := json.Marshal(expectedRow.Tags)
-	if err != nil {
-		t.Fatalf("error in serializing text tags %v", err)
-	}
-	resultRows := sqlmock.NewRows([]string{
-		"id",
-		"text_type",
-		"title",
-		"difficulty",
-		"tags",
-		"text_body",
-		"text_length",
-		"created_at",
-		"updated_at",
-	}).AddRow(
-		expectedRow.Id,
-		expectedRow.TextType,
-		expectedRow.Title,
-		expectedRow.Difficulty,
-		sTags,
-		expectedRow.TextBody,
-		expectedRow.TextLength,
-		expectedRow.CreatedAt,
-		expectedRow.UpdatedAt,
-	)
-
-	mockDB.ExpectQuery(`SELECT \* FROM "texts" WHERE "texts"\."id" = .+ AND "texts"\."text_type" = .+ AND "texts"\."title" = .+ AND "texts"\."difficulty" = .+ AND "texts"\."text_body" = .+ AND "texts"\."text_length" = .+ AND "texts"\."created_at" = .+ AND "texts"\."updated_at" = .+ ORDER BY "texts"\."id" LIMIT .+`).WillReturnRows(resultRows)
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`INSERT INTO "texts" \("text_type","title","difficulty","language","author","source","source_url","license","attribution_required","text_body","text_length","status","submitter_id","reviewer_notes","reviewed_at","owner_id","visibility","share_slug","fingerprint","created_at","updated_at"\) VALUES .+ RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mockDB.ExpectExec(`DELETE FROM text_tags WHERE text_id = .+`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDB.ExpectQuery(`INSERT INTO "tags" \("name","created_at","updated_at"\) VALUES .+ ON CONFLICT \("name"\) DO NOTHING RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mockDB.ExpectExec(`INSERT INTO text_tags \(text_id, tag_id\) SELECT .+ FROM tags WHERE name IN .+`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectCommit()
 
-	result, err := textsProvider.CreateText(context.Background(), expectedRow)
+	result, err := textsProvider.CreateText(context.Background(), inputRow)
 
 	if err != nil {
 		t.Fatalf("error in creating text %v", err)
@@ -220,7 +204,11 @@ func TestUpdateTextSuccess(t *testing.T) {
 	}
 
 	mockDB.ExpectBegin()
-	mockDB.ExpectExec(`UPDATE "texts" SET "text_type"=.+,"title"=.+,"difficulty"=.+,"tags"=.+,"text_body"=.+,"text_length"=.+,"created_at"=.+,"updated_at"=.+ WHERE "id" = .+`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectExec(`UPDATE "texts" SET "text_type"=.+,"title"=.+,"difficulty"=.+,"language"=.+,"author"=.+,"source"=.+,"source_url"=.+,"license"=.+,"attribution_required"=.+,"text_body"=.+,"text_length"=.+,"status"=.+,"submitter_id"=.+,"reviewer_notes"=.+,"reviewed_at"=.+,"owner_id"=.+,"visibility"=.+,"share_slug"=.+,"fingerprint"=.+,"updated_at"=.+ WHERE "id" = .+`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectExec(`DELETE FROM text_tags WHERE text_id = .+`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectQuery(`INSERT INTO "tags" \("name","created_at","updated_at"\) VALUES .+ ON CONFLICT \("name"\) DO NOTHING RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mockDB.ExpectExec(`INSERT INTO text_tags \(text_id, tag_id\) SELECT .+ FROM tags WHERE name IN .+`).WillReturnResult(sqlmock.NewResult(1, 1))
 	mockDB.ExpectCommit()
 
 	result, err := textsProvider.UpdateText(context.Background(), expectedRow)
@@ -256,3 +244,200 @@ func TestDeleteTextSuccess(t *testing.T) {
 		t.Fatalf("unexpected result: expected %v, got %v", true, result)
 	}
 }
+
+func TestGetTextFingerprintsSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	textsProvider := NewTextsProvider(mockGorm)
+
+	resultRows := sqlmock.NewRows([]string{"id", "title", "status", "owner_id", "visibility", "fingerprint"}).
+		AddRow(1, "test text 1", "approved", nil, "public", "[1,2,3]").
+		AddRow(2, "test text 2", "pending", 2, "private", "[4,5,6]")
+
+	mockDB.ExpectQuery(`SELECT id, title, status, owner_id, visibility, fingerprint FROM "texts" WHERE fingerprint IS NOT NULL ORDER BY id`).WillReturnRows(resultRows)
+
+	result, err := textsProvider.GetTextFingerprints(context.Background())
+
+	if err != nil {
+		t.Fatalf("error in fetching text fingerprints %v", err)
+	}
+
+	if len(result) != 2 || result[1].Status != "pending" || *result[1].OwnerId != 2 || len(result[1].Fingerprint) != 3 || result[1].Fingerprint[2] != 6 {
+		t.Fatalf("unexpected fingerprints %+v", result)
+	}
+}
+
+func TestUpdateTextFingerprintSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	textsProvider := NewTextsProvider(mockGorm)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`UPDATE "texts" SET "fingerprint"=.+ WHERE id = .+`).WithArgs("[1,2,3]", 1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectCommit()
+
+	result, err := textsProvider.UpdateTextFingerprint(context.Background(), 1, []uint32{1, 2, 3})
+
+	if err != nil {
+		t.Fatalf("error in updating text fingerprint %v", err)
+	}
+
+	if result != true {
+		t.Fatalf("unexpected result: expected %v, got %v", true, result)
+	}
+}
+
+func TestGetTextsWithViewerSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	textsProvider := NewTextsProvider(mockGorm)
+
+	resultRows := sqlmock.NewRows([]string{"id", "title", "status", "visibility", "owner_id"}).
+		AddRow(1, "test drill", "approved", "public", nil).
+		AddRow(2, "private drill", "draft", "private", 2)
+
+	mockDB.ExpectQuery(`FROM "texts" WHERE \(\(texts\.status = \$1 AND texts\.visibility = \$2\) OR texts\.owner_id = \$3\) AND texts\.language = \$4 AND texts\.license = \$5`).
+		WithArgs(structures.TEXT_STATUS_APPROVED, structures.TEXT_VISIBILITY_PUBLIC, 2, "en", "gutenberg").
+		WillReturnRows(resultRows)
+
+	result, err := textsProvider.GetTexts(context.Background(), structures.TextFilter{
+		Status:     structures.TEXT_STATUS_APPROVED,
+		Visibility: structures.TEXT_VISIBILITY_PUBLIC,
+		Language:   "en",
+		License:    "gutenberg",
+		ViewerId:   2,
+	})
+
+	if err != nil {
+		t.Fatalf("error in fetching texts %v", err)
+	}
+
+	if len(result) != 2 || result[1].Visibility != structures.TEXT_VISIBILITY_PRIVATE || *result[1].OwnerId != 2 {
+		t.Fatalf("unexpected texts %+v", result)
+	}
+}
+
+func TestGetTextByShareSlugSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	textsProvider := NewTextsProvider(mockGorm)
+
+	resultRows := sqlmock.NewRows([]string{"id", "title", "visibility", "share_slug"}).
+		AddRow(1, "test drill", "unlisted", "c2hhcmVkLXNsdWc")
+
+	mockDB.ExpectQuery(`FROM "texts" WHERE share_slug = \$1 ORDER BY "texts"\."id" LIMIT .+`).
+		WithArgs("c2hhcmVkLXNsdWc", 1).
+		WillReturnRows(resultRows)
+
+	result, err := textsProvider.GetTextByShareSlug(context.Background(), "c2hhcmVkLXNsdWc")
+
+	if err != nil {
+		t.Fatalf("error in fetching shared text %v", err)
+	}
+
+	if result.Id != 1 || result.Visibility != structures.TEXT_VISIBILITY_UNLISTED || *result.ShareSlug != "c2hhcmVkLXNsdWc" {
+		t.Fatalf("unexpected text %+v", result)
+	}
+}
+
+func TestGetDrillTextSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	textsProvider := NewTextsProvider(mockGorm)
+
+	resultRows := sqlmock.NewRows([]string{"id", "text_type", "title", "owner_id", "visibility"}).
+		AddRow(4, structures.TEXT_TYPE_DRILL, structures.REVIEW_DRILL_TITLE, 2, structures.TEXT_VISIBILITY_PRIVATE)
+
+	mockDB.ExpectQuery(`FROM "texts" WHERE texts\.owner_id = \$1 AND texts\.text_type = \$2 AND texts\.title = \$3 ORDER BY "texts"\."id" LIMIT .+`).
+		WithArgs(2, structures.TEXT_TYPE_DRILL, structures.REVIEW_DRILL_TITLE, 1).
+		WillReturnRows(resultRows)
+
+	result, err := textsProvider.GetDrillText(context.Background(), 2, structures.REVIEW_DRILL_TITLE)
+
+	if err != nil {
+		t.Fatalf("error in fetching drill text %v", err)
+	}
+
+	if result.Id != 4 || *result.OwnerId != 2 {
+		t.Fatalf("unexpected text %+v", result)
+	}
+}
+
+func TestGetTextsSortedSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	textsProvider := NewTextsProvider(mockGorm)
+
+	resultRows := sqlmock.NewRows([]string{"id", "title", "average_rating", "rating_count", "favorite_count", "play_count"}).
+		AddRow(2, "test drill 2", 4.5, 2, 1, 10).
+		AddRow(1, "test drill", 3, 1, 0, 2)
+
+	mockDB.ExpectQuery(`FROM "texts" WHERE texts\.id IN \(SELECT text_id FROM "text_favorites" WHERE user_id = \$1\) ORDER BY average_rating DESC, rating_count DESC, texts\.id`).
+		WithArgs(2).
+		WillReturnRows(resultRows)
+
+	result, err := textsProvider.GetTexts(context.Background(), structures.TextFilter{FavoritedBy: 2, Sort: structures.TEXT_SORT_TOP_RATED})
+
+	if err != nil {
+		t.Fatalf("error in fetching texts %v", err)
+	}
+
+	if len(result) != 2 || result[0].AverageRating != 4.5 || result[0].RatingCount != 2 || result[0].FavoriteCount != 1 || result[0].PlayCount != 10 {
+		t.Fatalf("unexpected texts %+v", result)
+	}
+}
+
+func TestRateTextSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	textsProvider := NewTextsProvider(mockGorm)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`INSERT INTO "text_ratings" \("user_id","text_id","rating","created_at","updated_at"\) VALUES .+ ON CONFLICT \("user_id","text_id"\) DO UPDATE SET "rating"="excluded"\."rating","updated_at"="excluded"\."updated_at"`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectCommit()
+
+	result, err := textsProvider.RateText(context.Background(), structures.TextRating{UserId: 2, TextId: 1, Rating: 4})
+
+	if err != nil {
+		t.Fatalf("error in rating text %v", err)
+	}
+
+	if result.Rating != 4 {
+		t.Fatalf("unexpected rating: expected %v, got %v", 4, result.Rating)
+	}
+}
+
+func TestFavoriteTextSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	textsProvider := NewTextsProvider(mockGorm)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`INSERT INTO "text_favorites" \("user_id","text_id","created_at"\) VALUES .+ ON CONFLICT DO NOTHING`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectCommit()
+
+	result, err := textsProvider.FavoriteText(context.Background(), 2, 1)
+
+	if err != nil {
+		t.Fatalf("error in favoriting text %v", err)
+	}
+
+	if result != true {
+		t.Fatalf("unexpected result: expected %v, got %v", true, result)
+	}
+}
+
+func TestUnfavoriteTextSuccess(t *testing.T) {
+	mockGorm, mockDB := mocks.NewMockDB()
+	textsProvider := NewTextsProvider(mockGorm)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`DELETE FROM "text_favorites" WHERE user_id = .+ AND text_id = .+`).
+		WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDB.ExpectCommit()
+
+	result, err := textsProvider.UnfavoriteText(context.Background(), 2, 1)
+
+	if err != nil {
+		t.Fatalf("error in unfavoriting text %v", err)
+	}
+
+	if result != false {
+		t.Fatalf("unexpected result: expected %v, got %v", false, result)
+	}
+}
@@ -0,0 +1,231 @@
+package achievements_service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+	"type_writer_api/engines"
+	"type_writer_api/helpers"
+	"type_writer_api/providers/achievements"
+	"type_writer_api/providers/texts"
+	"type_writer_api/providers/users"
+	"type_writer_api/structures"
+)
+
+// scores read at a time while backfilling
+const backfillBatchSize = 500
+
+type AchievementsServiceInterface interface {
+	GetUserAchievements(ctx context.Context, userId int) (*structures.UserAchievements, error)
+	BackfillAchievements(ctx context.Context) (int64, error)
+}
+
+type AchievementsService struct {
+	AchievementsProvider achievements_provider.AchievementsProviderInterface
+	UsersProvider        users_provider.UsersProviderInterface
+	TextsProvider        texts_provider.TextsProviderInterface
+	AchievementRules     *engines.AchievementRules
+	now                  func() time.Time
+}
+
+// GetUserAchievements lists every badge in the order they are defined along
+// with whether the user earned it, badges no longer defined are left out
+func (a *AchievementsService) GetUserAchievements(ctx context.Context, userId int) (*structures.UserAchievements, error) {
+	_, err := a.UsersProvider.GetUserByIdOrUsername(ctx, &userId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	userAchievements, err := a.AchievementsProvider.GetUserAchievements(ctx, userId)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get user achievements", "error", err)
+		return nil, err
+	}
+	earned := map[string]*structures.UserAchievement{}
+	for _, userAchievement := range userAchievements {
+		earned[userAchievement.BadgeId] = userAchievement
+	}
+
+	achievements := &structures.UserAchievements{UserId: userId, Achievements: []*structures.Achievement{}}
+	for _, badge := range a.AchievementRules.Badges() {
+		achievement := &structures.Achievement{Badge: *badge}
+		if userAchievement, ok := earned[badge.Id]; ok {
+			awardedAt := userAchievement.AwardedAt
+			achievement.Earned = true
+			achievement.ScoreId = userAchievement.ScoreId
+			achievement.AwardedAt = &awardedAt
+			achievements.Earned++
+		}
+		achievements.Achievements = append(achievements.Achievements, achievement)
+	}
+
+	result := achievements
+	return result, nil
+}
+
+// backfillStanding is what a backfill has seen of a user so far, days are
+// counted in the time zone of the user
+type backfillStanding struct {
+	loc       *time.Location
+	scores    int
+	practiced map[string]bool
+	earned    map[string]bool
+}
+
+// BackfillAchievements replays every ranked score in the order they were
+// stored against the badge rules, so badges added to the config reach the
+// scores that came before them. Badges already earned are kept, it returns
+// how many were awarded
+func (a *AchievementsService) BackfillAchievements(ctx context.Context) (int64, error) {
+	if len(a.AchievementRules.Badges()) == 0 {
+		return 0, nil
+	}
+
+	standings := map[int]*backfillStanding{}
+	difficulties := map[int]string{}
+	awardedAt := a.now()
+	var awarded int64
+	for afterId := 0; ; {
+		scores, err := a.AchievementsProvider.GetRankedScores(ctx, afterId, backfillBatchSize)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to get ranked scores", "error", err)
+			return awarded, err
+		}
+		if len(scores) == 0 {
+			break
+		}
+
+		for _, score := range scores {
+			afterId = score.Id
+			standing, ok := standings[score.UserId]
+			if !ok {
+				standing, err = a.newBackfillStanding(ctx, score.UserId)
+				if err != nil {
+					return awarded, err
+				}
+				standings[score.UserId] = standing
+			}
+			difficulty, ok := difficulties[score.TextId]
+			if !ok {
+				text, err := a.TextsProvider.GetTextByIdOrTitle(ctx, &score.TextId, nil)
+				if err != nil {
+					slog.ErrorContext(ctx, "failed to get text of score", "error", err, "score_id", score.Id)
+					return awarded, err
+				}
+				difficulty = text.Difficulty
+				difficulties[score.TextId] = difficulty
+			}
+
+			day := helpers.LocalDay(score.CreatedAt, standing.loc)
+			standing.scores++
+			standing.practiced[day] = true
+			scoreStanding := structures.ScoreStanding{
+				TotalScores: standing.scores,
+				StreakDays:  helpers.StreakDays(standing.practiced, day),
+				Difficulty:  difficulty,
+			}
+			earned := a.AchievementRules.Award(score, scoreStanding, standing.earned, awardedAt)
+			if len(earned) == 0 {
+				continue
+			}
+
+			count, err := a.AchievementsProvider.AwardAchievements(ctx, earned)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to award achievements", "error", err, "score_id", score.Id)
+				return awarded, err
+			}
+			awarded += count
+			for _, achievement := range earned {
+				standing.earned[achievement.BadgeId] = true
+			}
+		}
+	}
+
+	result := awarded
+	return result, nil
+}
+
+func (a *AchievementsService) newBackfillStanding(ctx context.Context, userId int) (*backfillStanding, error) {
+	user, err := a.UsersProvider.GetUserByIdOrUsername(ctx, &userId, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get user", "error", err)
+		return nil, err
+	}
+	userAchievements, err := a.AchievementsProvider.GetUserAchievements(ctx, userId)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get user achievements", "error", err)
+		return nil, err
+	}
+	standing := &backfillStanding{loc: helpers.UserLocation(user.TimeZone), practiced: map[string]bool{}, earned: map[string]bool{}}
+	for _, userAchievement := range userAchievements {
+		standing.earned[userAchievement.BadgeId] = true
+	}
+	return standing, nil
+}
+
+// ScoreEarnsBadges tells whether a score is worth checking against the badge
+// rules, only ranked scores of known users earn badges
+func ScoreEarnsBadges(rules *engines.AchievementRules, score *structures.Score) bool {
+	if rules == nil || len(rules.Badges()) == 0 {
+		return false
+	}
+	return score.UserId != 0 && slices.Contains(structures.RANKED_SCORE_REVIEWS, score.ReviewStatus)
+}
+
+// AwardScoreAchievements checks a ranked score against the badge rules and
+// awards the badges it earns, the standing of the user is taken as of the
+// score. Stored scores and finished sessions both award through it
+func AwardScoreAchievements(ctx context.Context, achievementsProvider achievements_provider.AchievementsProviderInterface, rules *engines.AchievementRules, score *structures.Score, text *structures.Text, now time.Time) error {
+	if !ScoreEarnsBadges(rules, score) {
+		return nil
+	}
+
+	total, err := achievementsProvider.CountRankedScores(ctx, score.UserId, score.CreatedAt)
+	if err != nil {
+		return err
+	}
+	days, err := achievementsProvider.GetScoreDays(ctx, score.UserId, score.CreatedAt, structures.MAX_STREAK_DAYS)
+	if err != nil {
+		return err
+	}
+	// days are counted in the time zone of the user, the latest of them is
+	// the day of the score itself
+	practiced := map[string]bool{}
+	latestDay := ""
+	for _, day := range days {
+		practiced[day] = true
+		latestDay = max(latestDay, day)
+	}
+	achievements, err := achievementsProvider.GetUserAchievements(ctx, score.UserId)
+	if err != nil {
+		return err
+	}
+	earned := map[string]bool{}
+	for _, achievement := range achievements {
+		earned[achievement.BadgeId] = true
+	}
+
+	standing := structures.ScoreStanding{
+		TotalScores: total,
+		StreakDays:  helpers.StreakDays(practiced, latestDay),
+		Difficulty:  text.Difficulty,
+	}
+	awarded := rules.Award(score, standing, earned, now)
+	if len(awarded) == 0 {
+		return nil
+	}
+
+	_, err = achievementsProvider.AwardAchievements(ctx, awarded)
+	return err
+}
+
+func NewAchievementsService(achievementsProvider achievements_provider.AchievementsProviderInterface, usersProvider users_provider.UsersProviderInterface, textsProvider texts_provider.TextsProviderInterface, achievementRules *engines.AchievementRules) *AchievementsService {
+	return &AchievementsService{
+		AchievementsProvider: achievementsProvider,
+		UsersProvider:        usersProvider,
+		TextsProvider:        textsProvider,
+		AchievementRules:     achievementRules,
+		now:                  time.Now,
+	}
+}
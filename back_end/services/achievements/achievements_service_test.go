@@ -0,0 +1,199 @@
+package achievements_service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"type_writer_api/engines"
+	"type_writer_api/structures"
+	mockProviders "type_writer_api/testing/mocks/providers"
+
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func testRules(t *testing.T) *engines.AchievementRules {
+	rules, err := engines.NewAchievementRules([]*structures.Badge{
+		{Id: "first_60_wpm", Name: "Sixty", Condition: "wpm >= 60"},
+		{Id: "3_tests", Name: "Hat trick", Condition: "total_scores >= 3"},
+		{Id: "2_day_streak", Name: "Two days", Condition: "streak_days >= 2"},
+		{Id: "flawless_hard_text", Name: "Flawless", Condition: "accuracy == 100 && difficulty == 3"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return rules
+}
+
+func TestGetUserAchievements(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	data := []struct {
+		testName       string
+		userErr        error
+		earned         []*structures.UserAchievement
+		expectedEarned []string
+		expectedErr    error
+	}{
+		{
+			testName:       "some badges earned",
+			earned:         []*structures.UserAchievement{{UserId: 2, BadgeId: "3_tests", AwardedAt: now}, {UserId: 2, BadgeId: "retired_badge", AwardedAt: now}},
+			expectedEarned: []string{"3_tests"},
+		},
+		{
+			testName:       "nothing earned yet",
+			earned:         []*structures.UserAchievement{},
+			expectedEarned: []string{},
+		},
+		{
+			testName:    "missing user",
+			userErr:     gorm.ErrRecordNotFound,
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.testName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockAchievementsProvider := mockProviders.NewMockAchievementsProviderInterface(ctrl)
+			mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+			mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+			achievementsService := NewAchievementsService(mockAchievementsProvider, mockUsersProvider, mockTextsProvider, testRules(t))
+			achievementsService.now = func() time.Time { return now }
+
+			userId := 2
+			mockUsersProvider.EXPECT().GetUserByIdOrUsername(gomock.Any(), &userId, nil).Return(&structures.User{Id: 2}, tt.userErr).Times(1)
+			if tt.userErr == nil {
+				mockAchievementsProvider.EXPECT().GetUserAchievements(gomock.Any(), 2).Return(tt.earned, nil).Times(1)
+			}
+
+			result, err := achievementsService.GetUserAchievements(context.Background(), 2)
+			if err != tt.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(result.Achievements) != 4 || result.Earned != len(tt.expectedEarned) {
+				t.Fatalf("unexpected achievements %+v", result)
+			}
+			earned := []string{}
+			for _, achievement := range result.Achievements {
+				if achievement.Earned != (achievement.AwardedAt != nil) {
+					t.Fatalf("unexpected award time for %q: %v", achievement.Id, achievement.AwardedAt)
+				}
+				if achievement.Earned {
+					earned = append(earned, achievement.Id)
+				}
+			}
+			if !slices.Equal(earned, tt.expectedEarned) {
+				t.Fatalf("expected earned badges %v but got %v instead", tt.expectedEarned, earned)
+			}
+		})
+	}
+}
+
+func TestBackfillAchievements(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAchievementsProvider := mockProviders.NewMockAchievementsProviderInterface(ctrl)
+	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	achievementsService := NewAchievementsService(mockAchievementsProvider, mockUsersProvider, mockTextsProvider, testRules(t))
+	achievementsService.now = func() time.Time { return now }
+
+	day := time.Date(2026, time.October, 10, 2, 0, 0, 0, time.UTC)
+	batch := []*structures.Score{
+		{Id: 1, UserId: 2, TextId: 1, Result: structures.ScoreResult{Wpm: 40, Accuracy: 100}, CreatedAt: day},
+		{Id: 2, UserId: 3, TextId: 1, Result: structures.ScoreResult{Wpm: 70, Accuracy: 95}, CreatedAt: day},
+	}
+	next := []*structures.Score{
+		{Id: 3, UserId: 2, TextId: 2, Result: structures.ScoreResult{Wpm: 65, Accuracy: 100}, CreatedAt: day.Add(3 * time.Hour)},
+		{Id: 4, UserId: 2, TextId: 1, Result: structures.ScoreResult{Wpm: 45, Accuracy: 90}, CreatedAt: day.Add(4 * time.Hour)},
+	}
+	mockAchievementsProvider.EXPECT().GetRankedScores(gomock.Any(), 0, backfillBatchSize).Return(batch, nil).Times(1)
+	mockAchievementsProvider.EXPECT().GetRankedScores(gomock.Any(), 2, backfillBatchSize).Return(next, nil).Times(1)
+	mockAchievementsProvider.EXPECT().GetRankedScores(gomock.Any(), 4, backfillBatchSize).Return([]*structures.Score{}, nil).Times(1)
+
+	// user 2 practices around midnight in New York, the scores land on the
+	// same UTC day but on two days of their own
+	mockUsersProvider.EXPECT().GetUserByIdOrUsername(gomock.Any(), gomock.Any(), nil).DoAndReturn(
+		func(_ context.Context, userId *int, _ *string) (*structures.User, error) {
+			timeZones := map[int]string{2: "America/New_York", 3: "UTC"}
+			return &structures.User{Id: *userId, TimeZone: timeZones[*userId]}, nil
+		},
+	).Times(2)
+	// user 3 earned the sixty badge before the backfill
+	mockAchievementsProvider.EXPECT().GetUserAchievements(gomock.Any(), 2).Return([]*structures.UserAchievement{}, nil).Times(1)
+	mockAchievementsProvider.EXPECT().GetUserAchievements(gomock.Any(), 3).Return([]*structures.UserAchievement{{UserId: 3, BadgeId: "first_60_wpm"}}, nil).Times(1)
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(gomock.Any(), gomock.Any(), nil).DoAndReturn(
+		func(_ context.Context, textId *int, _ *string) (*structures.Text, error) {
+			difficulties := map[int]string{1: "normal", 2: "hard"}
+			return &structures.Text{Id: *textId, Difficulty: difficulties[*textId]}, nil
+		},
+	).Times(2)
+
+	awarded := map[int][]string{}
+	mockAchievementsProvider.EXPECT().AwardAchievements(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, achievements []*structures.UserAchievement) (int64, error) {
+			for _, achievement := range achievements {
+				if achievement.AwardedAt != now {
+					t.Fatalf("unexpected award time %v", achievement.AwardedAt)
+				}
+				awarded[*achievement.ScoreId] = append(awarded[*achievement.ScoreId], achievement.BadgeId)
+			}
+			return int64(len(achievements)), nil
+		},
+	).AnyTimes()
+
+	result, err := achievementsService.BackfillAchievements(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := map[int][]string{
+		3: {"first_60_wpm", "2_day_streak", "flawless_hard_text"},
+		4: {"3_tests"},
+	}
+	if result != 4 || len(awarded) != len(expected) {
+		t.Fatalf("expected %v awarded but got %d: %v", expected, result, awarded)
+	}
+	for scoreId, badges := range expected {
+		if !slices.Equal(awarded[scoreId], badges) {
+			t.Fatalf("expected score %d to earn %v but got %v instead", scoreId, badges, awarded[scoreId])
+		}
+	}
+}
+
+func TestAwardScoreAchievements(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAchievementsProvider := mockProviders.NewMockAchievementsProviderInterface(ctrl)
+	rules := testRules(t)
+	text := &structures.Text{Id: 1, Difficulty: "normal"}
+
+	// flagged scores earn nothing until a moderator approves them
+	flagged := &structures.Score{Id: 8, UserId: 1, ReviewStatus: structures.SCORE_REVIEW_FLAGGED, Result: structures.ScoreResult{Version: 1, Wpm: 70, Accuracy: 95}, CreatedAt: now}
+	err := AwardScoreAchievements(context.Background(), mockAchievementsProvider, rules, flagged, text, now)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	score := &structures.Score{Id: 9, UserId: 1, ReviewStatus: structures.SCORE_REVIEW_CLEAN, Result: structures.ScoreResult{Version: 1, Wpm: 70, Accuracy: 95}, CreatedAt: now}
+	mockAchievementsProvider.EXPECT().CountRankedScores(context.Background(), 1, now).Return(2, nil).Times(1)
+	mockAchievementsProvider.EXPECT().GetScoreDays(context.Background(), 1, now, structures.MAX_STREAK_DAYS).Return([]string{"2026-10-19", "2026-10-18"}, nil).Times(1)
+	mockAchievementsProvider.EXPECT().GetUserAchievements(context.Background(), 1).Return([]*structures.UserAchievement{{UserId: 1, BadgeId: "first_60_wpm"}}, nil).Times(1)
+	mockAchievementsProvider.EXPECT().AwardAchievements(context.Background(), []*structures.UserAchievement{
+		{UserId: 1, BadgeId: "2_day_streak", ScoreId: &score.Id, AwardedAt: now},
+	}).Return(int64(1), nil).Times(1)
+
+	err = AwardScoreAchievements(context.Background(), mockAchievementsProvider, rules, score, text, now)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -2,23 +2,105 @@ package activities_service
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"type_writer_api/engines"
 	"type_writer_api/providers/activities"
 	"type_writer_api/structures"
 )
 
+var (
+	ErrInvalidActivityRules  = errors.New("invalid activity rules")
+	ErrUnknownActivityKind   = errors.New("unknown activity kind")
+	ErrInvalidScoringFormula = errors.New("invalid scoring formula")
+)
+
+var validTextTypes = map[string]bool{
+	structures.TEXT_TYPE_FULL_TEXT: true,
+	structures.TEXT_TYPE_DRILL:     true,
+}
+
 type ActivitiesServiceInterface interface {
 	GetActivities(ctx context.Context) ([]*structures.Activity, error)
 	GetActivityByIdOrName(ctx context.Context, activityId *int, name *string) (*structures.Activity, error)
 	CreateActivity(ctx context.Context, activityInfo structures.ActivityReq) (*structures.Activity, error)
 	UpdateActivity(ctx context.Context, activityInfo structures.ActivityReq, activityId int) (*structures.Activity, error)
 	DeleteActivity(ctx context.Context, activityId int) (bool, error)
+	GetActivityKinds(ctx context.Context) []string
+	EvaluateActivity(ctx context.Context, activityId int, input structures.ActivityInput) (*structures.ActivityResult, error)
+	TestScoringFormula(ctx context.Context, testInfo structures.ScoringFormulaTestReq) ([]*structures.ScoringFormulaSample, error)
 }
 
 type ActivitiesService struct {
 	ActivitiesProvider activities_provider.ActivitiesProviderInterface
 }
 
+// validateRules checks an activity rule set and fills in the schema version
+// when none was sent, rules written for a newer schema are refused
+func validateRules(rules *structures.ActivityRules) error {
+	if rules.SchemaVersion == 0 {
+		rules.SchemaVersion = structures.ACTIVITY_RULES_SCHEMA_VERSION
+	}
+	if rules.SchemaVersion < 0 || rules.SchemaVersion > structures.ACTIVITY_RULES_SCHEMA_VERSION {
+		return ErrInvalidActivityRules
+	}
+
+	if rules.TimeLimit < 0 || rules.TimeLimit > structures.MAX_ACTIVITY_TIME_LIMIT {
+		return ErrInvalidActivityRules
+	}
+	if rules.WordTarget < 0 || rules.WordTarget > structures.MAX_ACTIVITY_WORD_TARGET {
+		return ErrInvalidActivityRules
+	}
+	if rules.TimeLimit > 0 && rules.WordTarget > 0 {
+		return ErrInvalidActivityRules
+	}
+	// a test that stops on the first error leaves nothing to correct
+	if rules.StopOnError && rules.MustCorrectErrors {
+		return ErrInvalidActivityRules
+	}
+	if rules.AccuracyThreshold < 0 || rules.AccuracyThreshold > 100 {
+		return ErrInvalidActivityRules
+	}
+
+	var textTypes []string
+	seen := map[string]bool{}
+	for _, textType := range rules.AllowedTextTypes {
+		if !validTextTypes[textType] {
+			return ErrInvalidActivityRules
+		}
+		if !seen[textType] {
+			seen[textType] = true
+			textTypes = append(textTypes, textType)
+		}
+	}
+	rules.AllowedTextTypes = textTypes
+
+	return nil
+}
+
+// validateActivity checks the rules on their own and then against the engine
+// of the activity kind
+func validateActivity(activity *structures.Activity) error {
+	engine, ok := engines.ByKind(activity.Kind)
+	if !ok {
+		return ErrUnknownActivityKind
+	}
+
+	err := validateRules(&activity.Rules)
+	if err != nil {
+		return err
+	}
+	if engine.ValidateRules(activity.Rules) != nil {
+		return ErrInvalidActivityRules
+	}
+	if activity.ScoringFormula != "" {
+		if _, err := engines.ParseFormula(activity.ScoringFormula); err != nil {
+			return ErrInvalidScoringFormula
+		}
+	}
+	return nil
+}
+
 func (a *ActivitiesService) GetActivities(ctx context.Context) ([]*structures.Activity, error) {
 	var result []*structures.Activity
 
@@ -47,6 +129,12 @@ func (a *ActivitiesService) GetActivityByIdOrName(ctx context.Context, activityI
 func (a *ActivitiesService) CreateActivity(ctx context.Context, activityInfo structures.ActivityReq) (*structures.Activity, error) {
 	activityToCreate := structures.ConvertRequestToActivity(&activityInfo)
 
+	err := validateActivity(activityToCreate)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create activity", "error", err)
+		return nil, err
+	}
+
 	createdActivity, err := a.ActivitiesProvider.CreateActivity(ctx, *activityToCreate)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to create activity", "error", err)
@@ -71,6 +159,27 @@ func (a *ActivitiesService) UpdateActivity(ctx context.Context, activityInfo str
 	if activityInfo.Description != "" {
 		existingActivity.Description = activityInfo.Description
 	}
+	if activityInfo.Kind != "" {
+		existingActivity.Kind = activityInfo.Kind
+	}
+	if activityInfo.Rules != nil {
+		existingActivity.Rules = *activityInfo.Rules
+	}
+	// an empty formula puts the activity back on the default one, it is
+	// stored as such since empty fields are left alone on update
+	if activityInfo.ScoringFormula != nil {
+		existingActivity.ScoringFormula = *activityInfo.ScoringFormula
+		if existingActivity.ScoringFormula == "" {
+			existingActivity.ScoringFormula = structures.DEFAULT_SCORING_FORMULA
+		}
+	}
+	if activityInfo.Kind != "" || activityInfo.Rules != nil || activityInfo.ScoringFormula != nil {
+		err := validateActivity(existingActivity)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to update activity", "error", err)
+			return nil, err
+		}
+	}
 
 	updatedActivity, err := a.ActivitiesProvider.UpdateActivity(ctx, *existingActivity)
 	if err != nil {
@@ -91,6 +200,52 @@ func (t *ActivitiesService) DeleteActivity(ctx context.Context, activityId int)
 	return deleted, nil
 }
 
+func (a *ActivitiesService) GetActivityKinds(ctx context.Context) []string {
+	return engines.Kinds()
+}
+
+// EvaluateActivity plays a sample input through the engine of an activity so
+// admins can try its rules before anyone types it
+func (a *ActivitiesService) EvaluateActivity(ctx context.Context, activityId int, input structures.ActivityInput) (*structures.ActivityResult, error) {
+	activity, err := a.ActivitiesProvider.GetActivityByIdOrName(ctx, &activityId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	engine, ok := engines.ByKind(activity.Kind)
+	if !ok {
+		slog.ErrorContext(ctx, "failed to evaluate activity", "error", ErrUnknownActivityKind)
+		return nil, ErrUnknownActivityKind
+	}
+
+	activityResult, err := engine.ComputeResult(activity.Rules, input)
+	if err != nil {
+		return nil, err
+	}
+
+	result := activityResult
+	return result, nil
+}
+
+// TestScoringFormula scores every sample with a formula that is not saved
+// yet, metrics missing from a sample count as zero
+func (a *ActivitiesService) TestScoringFormula(ctx context.Context, testInfo structures.ScoringFormulaTestReq) ([]*structures.ScoringFormulaSample, error) {
+	if _, err := engines.ParseFormula(testInfo.Formula); err != nil {
+		return nil, ErrInvalidScoringFormula
+	}
+
+	var result []*structures.ScoringFormulaSample
+	for _, metrics := range testInfo.Samples {
+		finalScore, err := engines.EvaluateFormula(testInfo.Formula, metrics)
+		if err != nil {
+			return nil, ErrInvalidScoringFormula
+		}
+		result = append(result, &structures.ScoringFormulaSample{Metrics: metrics, FinalScore: finalScore})
+	}
+
+	return result, nil
+}
+
 func NewActivitiesService(activitiesProvider activities_provider.ActivitiesProviderInterface) *ActivitiesService {
 	return &ActivitiesService{
 		ActivitiesProvider: activitiesProvider,
@@ -16,12 +16,12 @@ import (
 func TestGetActivities(t *testing.T) {
 	var (
 		mockResult1 = []*structures.Activity{
-			{1, "test activity 1", "test activity 1 is first", time.Now(), time.Now()},
-			{2, "test activity 2", "test activity 2 is second", time.Now(), time.Now()},
+			{Id: 1, Name: "test activity 1", Description: "test activity 1 is first", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			{Id: 2, Name: "test activity 2", Description: "test activity 2 is second", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 		}
 		expectedResult1 = []*structures.Activity{
-			{1, "test activity 1", "test activity 1 is first", time.Now(), time.Now()},
-			{2, "test activity 2", "test activity 2 is second", time.Now(), time.Now()},
+			{Id: 1, Name: "test activity 1", Description: "test activity 1 is first", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			{Id: 2, Name: "test activity 2", Description: "test activity 2 is second", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 		}
 		mockResult2 = []*structures.Activity{}
 		expectedResult2 = []*structures.Activity{}
@@ -94,18 +94,18 @@ func TestGetActivityByIdOrName(t *testing.T) {
 			"valid id",
 			1,
 			"",
-			&structures.Activity{1, "test activity 1", "test activity 1 is first", time.Now(), time.Now()},
+			&structures.Activity{Id: 1, Name: "test activity 1", Description: "test activity 1 is first", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.Activity{1, "test activity 1", "test activity 1 is first", time.Now(), time.Now()},
+			&structures.Activity{Id: 1, Name: "test activity 1", Description: "test activity 1 is first", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
 		{
 			"valid name",
 			0,
 			"test activity 1",
-			&structures.Activity{1, "test activity 1", "test activity 1 is first", time.Now(), time.Now()},
+			&structures.Activity{Id: 1, Name: "test activity 1", Description: "test activity 1 is first", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.Activity{1, "test activity 1", "test activity 1 is first", time.Now(), time.Now()},
+			&structures.Activity{Id: 1, Name: "test activity 1", Description: "test activity 1 is first", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
 		{
@@ -155,10 +155,10 @@ func TestCreateActivity(t *testing.T) {
 	}{
 		{
 			"valid input activity request",
-			structures.ActivityReq{"test activity 1", "test activity 1 is first"},
-			&structures.Activity{1, "test activity created", "test activity after being returned by db", time.Now(), time.Now()},
+			structures.ActivityReq{Name: "test activity 1", Description: "test activity 1 is first"},
+			&structures.Activity{Id: 1, Name: "test activity created", Description: "test activity after being returned by db", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.Activity{1, "test activity created", "test activity after being returned by db", time.Now(), time.Now()},
+			&structures.Activity{Id: 1, Name: "test activity created", Description: "test activity after being returned by db", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
 	}
@@ -201,12 +201,12 @@ func TestUpdateActivity(t *testing.T) {
 	}{
 		{
 			"valid input activity request",
-			structures.ActivityReq{"test activity updated", "test activity after being updated"},
+			structures.ActivityReq{Name: "test activity updated", Description: "test activity after being updated"},
 			1,
-			&structures.Activity{1, "test activity created", "test activity after being returned by db", time.Now(), time.Now()},
-			&structures.Activity{1, "test activity updated", "test activity after being updated", time.Now(), time.Now()},
+			&structures.Activity{Id: 1, Name: "test activity created", Description: "test activity after being returned by db", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			&structures.Activity{Id: 1, Name: "test activity updated", Description: "test activity after being updated", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.Activity{1, "test activity updated", "test activity after being updated", time.Now(), time.Now()},
+			&structures.Activity{Id: 1, Name: "test activity updated", Description: "test activity after being updated", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
 	}
@@ -291,3 +291,310 @@ func TestDeleteActivity(t *testing.T) {
 		})
 	}
 }
+
+func TestActivityRules(t *testing.T) {
+	data := []struct {
+		testName      string
+		inputKind     string
+		inputRules    *structures.ActivityRules
+		expectedRules structures.ActivityRules
+		expectedErr   error
+	}{
+		{
+			testName:      "no rules default to the current schema",
+			expectedRules: structures.ActivityRules{SchemaVersion: structures.ACTIVITY_RULES_SCHEMA_VERSION},
+		},
+		{
+			testName:      "timed test",
+			inputKind:     structures.ACTIVITY_KIND_TIMED_TEST,
+			inputRules:    &structures.ActivityRules{TimeLimit: 60, AccuracyThreshold: 90, AllowedTextTypes: []string{"full-text", "drill", "full-text"}},
+			expectedRules: structures.ActivityRules{SchemaVersion: 1, TimeLimit: 60, AccuracyThreshold: 90, AllowedTextTypes: []string{"full-text", "drill"}},
+		},
+		{
+			testName:      "sudden death word race",
+			inputKind:     structures.ACTIVITY_KIND_SUDDEN_DEATH,
+			inputRules:    &structures.ActivityRules{SchemaVersion: 1, WordTarget: 50, StopOnError: true, BlindMode: true},
+			expectedRules: structures.ActivityRules{SchemaVersion: 1, WordTarget: 50, StopOnError: true, BlindMode: true},
+		},
+		{
+			testName:    "newer schema version",
+			inputRules:  &structures.ActivityRules{SchemaVersion: structures.ACTIVITY_RULES_SCHEMA_VERSION + 1},
+			expectedErr: ErrInvalidActivityRules,
+		},
+		{
+			testName:    "time limit and word target together",
+			inputKind:   structures.ACTIVITY_KIND_TIMED_TEST,
+			inputRules:  &structures.ActivityRules{TimeLimit: 60, WordTarget: 50},
+			expectedErr: ErrInvalidActivityRules,
+		},
+		{
+			testName:    "time limit too long",
+			inputRules:  &structures.ActivityRules{TimeLimit: structures.MAX_ACTIVITY_TIME_LIMIT + 1},
+			expectedErr: ErrInvalidActivityRules,
+		},
+		{
+			testName:    "negative word target",
+			inputRules:  &structures.ActivityRules{WordTarget: -1},
+			expectedErr: ErrInvalidActivityRules,
+		},
+		{
+			testName:    "stop on error with must correct errors",
+			inputRules:  &structures.ActivityRules{StopOnError: true, MustCorrectErrors: true},
+			expectedErr: ErrInvalidActivityRules,
+		},
+		{
+			testName:    "accuracy threshold above 100",
+			inputRules:  &structures.ActivityRules{AccuracyThreshold: 101},
+			expectedErr: ErrInvalidActivityRules,
+		},
+		{
+			testName:    "timed test without a time limit",
+			inputKind:   structures.ACTIVITY_KIND_TIMED_TEST,
+			inputRules:  &structures.ActivityRules{WordTarget: 50},
+			expectedErr: ErrInvalidActivityRules,
+		},
+		{
+			testName:    "word race without a word target",
+			inputKind:   structures.ACTIVITY_KIND_WORD_RACE,
+			inputRules:  &structures.ActivityRules{AccuracyThreshold: 95},
+			expectedErr: ErrInvalidActivityRules,
+		},
+		{
+			testName:    "sudden death that does not stop on error",
+			inputKind:   structures.ACTIVITY_KIND_SUDDEN_DEATH,
+			inputRules:  &structures.ActivityRules{TimeLimit: 30},
+			expectedErr: ErrInvalidActivityRules,
+		},
+		{
+			testName:    "zen with a clock",
+			inputRules:  &structures.ActivityRules{TimeLimit: 30},
+			expectedErr: ErrInvalidActivityRules,
+		},
+		{
+			testName:    "unknown kind",
+			inputKind:   "marathon",
+			expectedErr: ErrUnknownActivityKind,
+		},
+		{
+			testName:    "unknown text type",
+			inputRules:  &structures.ActivityRules{AllowedTextTypes: []string{"poem"}},
+			expectedErr: ErrInvalidActivityRules,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	activitiesService := NewActivitiesService(mockActivitiesProvider)
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			if testCase.expectedErr == nil {
+				mockActivitiesProvider.EXPECT().CreateActivity(context.Background(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, activity structures.Activity) (*structures.Activity, error) {
+						activity.Id = 1
+						return &activity, nil
+					},
+				).Times(1)
+			}
+
+			result, err := activitiesService.CreateActivity(context.Background(), structures.ActivityReq{Name: "test activity", Description: "test activity rules", Kind: testCase.inputKind, Rules: testCase.inputRules})
+
+			if testCase.expectedErr != nil {
+				if err != testCase.expectedErr {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err := helpers.CompareReflectedStructFields(result.Rules, testCase.expectedRules); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestUpdateActivityRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	activitiesService := NewActivitiesService(mockActivitiesProvider)
+
+	activityId := 1
+	existingActivity := func() *structures.Activity {
+		return &structures.Activity{Id: activityId, Name: "speed drill", Description: "type fast", Kind: structures.ACTIVITY_KIND_TIMED_TEST, Rules: structures.ActivityRules{SchemaVersion: 1, TimeLimit: 60}}
+	}
+
+	mockActivitiesProvider.EXPECT().GetActivityByIdOrName(context.Background(), &activityId, nil).Return(existingActivity(), nil).Times(3)
+	mockActivitiesProvider.EXPECT().UpdateActivity(context.Background(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, activity structures.Activity) (*structures.Activity, error) {
+			return &activity, nil
+		},
+	).Times(2)
+
+	result, err := activitiesService.UpdateActivity(context.Background(), structures.ActivityReq{Description: "type faster"}, activityId)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result.Rules.TimeLimit != 60 {
+		t.Fatalf("expected rules to be kept but got %+v", result.Rules)
+	}
+
+	result, err = activitiesService.UpdateActivity(context.Background(), structures.ActivityReq{Kind: structures.ACTIVITY_KIND_WORD_RACE, Rules: &structures.ActivityRules{WordTarget: 25}}, activityId)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result.Rules.TimeLimit != 0 || result.Rules.WordTarget != 25 || result.Rules.SchemaVersion != 1 {
+		t.Fatalf("expected rules to be replaced but got %+v", result.Rules)
+	}
+
+	_, err = activitiesService.UpdateActivity(context.Background(), structures.ActivityReq{Rules: &structures.ActivityRules{TimeLimit: -5}}, activityId)
+	if err != ErrInvalidActivityRules {
+		t.Fatalf("expected error: %v but got %v instead", ErrInvalidActivityRules, err)
+	}
+}
+
+func TestEvaluateActivity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	activitiesService := NewActivitiesService(mockActivitiesProvider)
+
+	activityId := 1
+	missingId := 99
+	mockActivitiesProvider.EXPECT().GetActivityByIdOrName(context.Background(), &activityId, nil).Return(&structures.Activity{
+		Id:    activityId,
+		Name:  "one minute",
+		Kind:  structures.ACTIVITY_KIND_TIMED_TEST,
+		Rules: structures.ActivityRules{SchemaVersion: 1, TimeLimit: 60},
+	}, nil).Times(1)
+	mockActivitiesProvider.EXPECT().GetActivityByIdOrName(context.Background(), &missingId, nil).Return(nil, gorm.ErrRecordNotFound).Times(1)
+
+	input := structures.ActivityInput{
+		TextType: structures.TEXT_TYPE_FULL_TEXT,
+		TextBody: "hi",
+		Keystrokes: []*structures.Keystroke{
+			{Key: "h", Offset: 250},
+			{Key: "i", Offset: 500},
+		},
+	}
+
+	result, err := activitiesService.EvaluateActivity(context.Background(), activityId, input)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result.Wpm != 48 || result.Accuracy != 100 || !result.Completed {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	_, err = activitiesService.EvaluateActivity(context.Background(), missingId, input)
+	if err != gorm.ErrRecordNotFound {
+		t.Fatalf("expected error: %v but got %v instead", gorm.ErrRecordNotFound, err)
+	}
+}
+
+func TestActivityScoringFormula(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	activitiesService := NewActivitiesService(mockActivitiesProvider)
+
+	mockActivitiesProvider.EXPECT().CreateActivity(context.Background(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, activity structures.Activity) (*structures.Activity, error) {
+			activity.Id = 1
+			return &activity, nil
+		},
+	).Times(1)
+
+	formula := "max(0, wpm - errors)"
+	result, err := activitiesService.CreateActivity(context.Background(), structures.ActivityReq{Name: "net speed", Description: "errors cost speed", ScoringFormula: &formula})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result.ScoringFormula != "max(0, wpm - errors)" {
+		t.Fatalf("unexpected scoring formula %q", result.ScoringFormula)
+	}
+
+	for _, invalidFormula := range []string{"speed - errors", "wpm / errors", "wpm < 60 ? 100", "wpm ** 400"} {
+		_, err = activitiesService.CreateActivity(context.Background(), structures.ActivityReq{Name: "net speed", Description: "errors cost speed", ScoringFormula: &invalidFormula})
+		if err != ErrInvalidScoringFormula {
+			t.Fatalf("expected error for %q: %v but got %v instead", invalidFormula, ErrInvalidScoringFormula, err)
+		}
+	}
+}
+
+func TestUpdateActivityScoringFormula(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	activitiesService := NewActivitiesService(mockActivitiesProvider)
+
+	activityId := 1
+	mockActivitiesProvider.EXPECT().GetActivityByIdOrName(context.Background(), &activityId, nil).Return(
+		&structures.Activity{Id: activityId, Name: "net speed", Kind: structures.ACTIVITY_KIND_ZEN, ScoringFormula: "max(0, wpm - errors)", Rules: structures.ActivityRules{SchemaVersion: 1}}, nil,
+	).Times(2)
+	mockActivitiesProvider.EXPECT().UpdateActivity(context.Background(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, activity structures.Activity) (*structures.Activity, error) {
+			return &activity, nil
+		},
+	).Times(2)
+
+	result, err := activitiesService.UpdateActivity(context.Background(), structures.ActivityReq{Description: "errors still cost speed"}, activityId)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result.ScoringFormula != "max(0, wpm - errors)" {
+		t.Fatalf("expected the formula to be kept but got %q", result.ScoringFormula)
+	}
+
+	cleared := ""
+	result, err = activitiesService.UpdateActivity(context.Background(), structures.ActivityReq{ScoringFormula: &cleared}, activityId)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result.ScoringFormula != structures.DEFAULT_SCORING_FORMULA {
+		t.Fatalf("expected the default formula but got %q", result.ScoringFormula)
+	}
+}
+
+func TestTestScoringFormula(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	activitiesService := NewActivitiesService(mockActivitiesProvider)
+
+	result, err := activitiesService.TestScoringFormula(context.Background(), structures.ScoringFormulaTestReq{
+		Formula: "wpm * (accuracy / 100) ** 2",
+		Samples: []map[string]float64{
+			{"wpm": 100, "accuracy": 90},
+			{"wpm": 50, "accuracy": 100},
+			{"wpm": 80},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expectedScores := []float64{81, 50, 0}
+	if len(result) != len(expectedScores) {
+		t.Fatalf("unexpected samples %v", result)
+	}
+	for idx, sample := range result {
+		if sample.FinalScore != expectedScores[idx] {
+			t.Fatalf("unexpected final score for sample %v: got %v, expected %v", idx, sample.FinalScore, expectedScores[idx])
+		}
+	}
+
+	_, err = activitiesService.TestScoringFormula(context.Background(), structures.ScoringFormulaTestReq{Formula: "wpm / errors", Samples: []map[string]float64{{"wpm": 60}}})
+	if err != ErrInvalidScoringFormula {
+		t.Fatalf("expected error: %v but got %v instead", ErrInvalidScoringFormula, err)
+	}
+}
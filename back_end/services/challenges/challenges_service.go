@@ -0,0 +1,365 @@
+package challenges_service
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"time"
+	"type_writer_api/helpers"
+	"type_writer_api/providers/activities"
+	"type_writer_api/providers/challenges"
+	"type_writer_api/providers/scores"
+	"type_writer_api/providers/texts"
+	"type_writer_api/providers/users"
+	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidChallengeDate = errors.New("invalid challenge date")
+	ErrInvalidTimeZone      = errors.New("invalid time zone")
+	ErrInvalidQueueEntry    = errors.New("invalid challenge queue entry")
+	ErrScoreNotOwned        = errors.New("score belongs to another user")
+	ErrScoreNotInChallenge  = errors.New("score was not made on the challenge")
+	ErrAlreadyAttempted     = errors.New("challenge already attempted")
+	ErrNotFirstScore        = errors.New("only the first score on the challenge can be entered")
+)
+
+// the earliest and latest offsets in use, a calendar day is open for as long
+// as it is that day in one of them
+const (
+	earliestZoneOffset = -12 * time.Hour
+	latestZoneOffset   = 14 * time.Hour
+)
+
+type ChallengesServiceInterface interface {
+	GetTodayChallenge(ctx context.Context, timeZone string) (*structures.DailyChallenge, error)
+	GetChallenge(ctx context.Context, date string) (*structures.DailyChallenge, error)
+	GetChallengeLeaderboard(ctx context.Context, date string) ([]*structures.ChallengeLeaderboardEntry, error)
+	CreateChallengeAttempt(ctx context.Context, date string, attemptInfo structures.ChallengeAttemptReq, userId int) (*structures.ChallengeAttempt, error)
+	GetChallengeQueue(ctx context.Context) ([]*structures.ChallengeQueueEntry, error)
+	CreateChallengeQueueEntry(ctx context.Context, entryInfo structures.ChallengeQueueEntryReq) (*structures.ChallengeQueueEntry, error)
+	DeleteChallengeQueueEntry(ctx context.Context, entryId int) (bool, error)
+}
+
+type ChallengesService struct {
+	ChallengesProvider challenges_provider.ChallengesProviderInterface
+	ActivitiesProvider activities_provider.ActivitiesProviderInterface
+	TextsProvider      texts_provider.TextsProviderInterface
+	ScoresProvider     scores_provider.ScoresProviderInterface
+	UsersProvider      users_provider.UsersProviderInterface
+	now                func() time.Time
+}
+
+func loadTimeZone(timeZone string) (*time.Location, error) {
+	if timeZone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, ErrInvalidTimeZone
+	}
+	return loc, nil
+}
+
+// isOpenDate tells whether the day has started somewhere and not yet ended
+// everywhere, only open days get a challenge scheduled
+func isOpenDate(date string, now time.Time) bool {
+	earliest := now.UTC().Add(earliestZoneOffset).Format(structures.CHALLENGE_DATE_FORMAT)
+	latest := now.UTC().Add(latestZoneOffset).Format(structures.CHALLENGE_DATE_FORMAT)
+	return date >= earliest && date <= latest
+}
+
+func dateHash(date, salt string) uint64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(date + salt))
+	return hash.Sum64()
+}
+
+func textTypeAllowed(activity *structures.Activity, text *structures.Text) bool {
+	return len(activity.Rules.AllowedTextTypes) == 0 || slices.Contains(activity.Rules.AllowedTextTypes, text.TextType)
+}
+
+// pickChallenge chooses the pair of a day out of the catalog, the same date
+// always lands on the same pair as long as the catalog does not change.
+// Competitive kinds are preferred over zen activities when there are any
+func pickChallenge(date string, activities []*structures.Activity, texts []*structures.Text) (*structures.DailyChallenge, bool) {
+	competitive := []*structures.Activity{}
+	for _, activity := range activities {
+		if activity.Kind != structures.ACTIVITY_KIND_ZEN {
+			competitive = append(competitive, activity)
+		}
+	}
+	if len(competitive) != 0 {
+		activities = competitive
+	}
+	if len(activities) == 0 || len(texts) == 0 {
+		return nil, false
+	}
+
+	slices.SortFunc(activities, func(a, b *structures.Activity) int { return a.Id - b.Id })
+	slices.SortFunc(texts, func(a, b *structures.Text) int { return a.Id - b.Id })
+
+	start := int(dateHash(date, "activity") % uint64(len(activities)))
+	for i := range activities {
+		activity := activities[(start+i)%len(activities)]
+		candidates := []*structures.Text{}
+		for _, text := range texts {
+			if textTypeAllowed(activity, text) {
+				candidates = append(candidates, text)
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		text := candidates[dateHash(date, "text")%uint64(len(candidates))]
+		return &structures.DailyChallenge{ChallengeDate: date, ActivityId: activity.Id, TextId: text.Id}, true
+	}
+	return nil, false
+}
+
+// scheduleChallenge stores the challenge of an open day, taking the front of
+// the admin queue or else a pick out of the public catalog
+func (c *ChallengesService) scheduleChallenge(ctx context.Context, date string) (*structures.DailyChallenge, error) {
+	queue, err := c.ChallengesProvider.GetChallengeQueue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		challenge    *structures.DailyChallenge
+		queueEntryId int
+	)
+	if len(queue) != 0 {
+		challenge = &structures.DailyChallenge{ChallengeDate: date, ActivityId: queue[0].ActivityId, TextId: queue[0].TextId, Curated: true}
+		queueEntryId = queue[0].Id
+	} else {
+		activities, err := c.ActivitiesProvider.GetActivities(ctx)
+		if err != nil {
+			return nil, err
+		}
+		texts, err := c.TextsProvider.GetTexts(ctx, structures.TextFilter{
+			Status:     structures.TEXT_STATUS_APPROVED,
+			Visibility: structures.TEXT_VISIBILITY_PUBLIC,
+		})
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		challenge, ok = pickChallenge(date, activities, texts)
+		if !ok {
+			return nil, gorm.ErrRecordNotFound
+		}
+	}
+
+	// another request may have scheduled the day meanwhile, whichever got
+	// stored first is the challenge
+	_, err = c.ChallengesProvider.ScheduleChallenge(ctx, *challenge, queueEntryId)
+	if err != nil {
+		return nil, err
+	}
+	return c.ChallengesProvider.GetChallengeByDate(ctx, date)
+}
+
+func (c *ChallengesService) GetTodayChallenge(ctx context.Context, timeZone string) (*structures.DailyChallenge, error) {
+	loc, err := loadTimeZone(timeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now().In(loc)
+	challenge, err := c.GetChallenge(ctx, now.Format(structures.CHALLENGE_DATE_FORMAT))
+	if err != nil {
+		return nil, err
+	}
+
+	startsAt := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	endsAt := startsAt.AddDate(0, 0, 1)
+	challenge.StartsAt = &startsAt
+	challenge.EndsAt = &endsAt
+
+	result := challenge
+	return result, nil
+}
+
+// GetChallenge returns the challenge of a day, days that are open somewhere
+// get theirs scheduled on first request while days yet to come stay hidden
+func (c *ChallengesService) GetChallenge(ctx context.Context, date string) (*structures.DailyChallenge, error) {
+	if _, err := time.Parse(structures.CHALLENGE_DATE_FORMAT, date); err != nil {
+		return nil, ErrInvalidChallengeDate
+	}
+
+	challenge, err := c.ChallengesProvider.GetChallengeByDate(ctx, date)
+	if err != nil && err == gorm.ErrRecordNotFound && isOpenDate(date, c.now()) {
+		challenge, err = c.scheduleChallenge(ctx, date)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			slog.ErrorContext(ctx, "failed to schedule challenge", "error", err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := challenge
+	return result, nil
+}
+
+// GetChallengeLeaderboard ranks the attempts of a day by final score, equal
+// scores share their rank
+func (c *ChallengesService) GetChallengeLeaderboard(ctx context.Context, date string) ([]*structures.ChallengeLeaderboardEntry, error) {
+	challenge, err := c.GetChallenge(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := c.ChallengesProvider.GetChallengeLeaderboard(ctx, challenge.Id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get challenge leaderboard", "error", err)
+		return nil, err
+	}
+
+	for indx, entry := range entries {
+		entry.Rank = indx + 1
+		if indx > 0 && entry.FinalScore == entries[indx-1].FinalScore {
+			entry.Rank = entries[indx-1].Rank
+		}
+	}
+
+	result := entries
+	return result, nil
+}
+
+// CreateChallengeAttempt enters the first score of the user on the challenge
+// pair as their ranked attempt, the score has to fall on the challenge day in
+// the time zone of their profile. The first score is looked for over the day
+// in every time zone, so moving the profile to another zone does not open up
+// a second try
+func (c *ChallengesService) CreateChallengeAttempt(ctx context.Context, date string, attemptInfo structures.ChallengeAttemptReq, userId int) (*structures.ChallengeAttempt, error) {
+	challenge, err := c.GetChallenge(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := c.ScoresProvider.GetScoreById(ctx, attemptInfo.ScoreId)
+	if err != nil {
+		return nil, err
+	}
+	if score.UserId != userId {
+		return nil, ErrScoreNotOwned
+	}
+
+	user, err := c.UsersProvider.GetUserByIdOrUsername(ctx, &userId, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create challenge attempt", "error", err)
+		return nil, err
+	}
+	loc := helpers.UserLocation(user.TimeZone)
+	day, _ := time.ParseInLocation(structures.CHALLENGE_DATE_FORMAT, challenge.ChallengeDate, loc)
+	startsAt := day.UTC()
+	endsAt := day.AddDate(0, 0, 1).UTC()
+	if score.ActivityId != challenge.ActivityId || score.TextId != challenge.TextId ||
+		score.CreatedAt.Before(startsAt) || !score.CreatedAt.Before(endsAt) {
+		return nil, ErrScoreNotInChallenge
+	}
+
+	// retries of the challenge are practice, only the first go counts
+	utcDay, _ := time.Parse(structures.CHALLENGE_DATE_FORMAT, challenge.ChallengeDate)
+	firstFrom := utcDay.Add(-structures.CHALLENGE_EARLIEST_OFFSET)
+	firstUntil := utcDay.AddDate(0, 0, 1).Add(structures.CHALLENGE_LATEST_OFFSET)
+	first, err := c.ChallengesProvider.GetFirstChallengeScore(ctx, userId, challenge.ActivityId, challenge.TextId, firstFrom, firstUntil)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create challenge attempt", "error", err)
+		return nil, err
+	}
+	if first.Id != score.Id {
+		return nil, ErrNotFirstScore
+	}
+
+	attempt := structures.ChallengeAttempt{
+		ChallengeId: challenge.Id,
+		UserId:      userId,
+		ScoreId:     score.Id,
+		CreatedAt:   c.now(),
+	}
+	created, err := c.ChallengesProvider.CreateChallengeAttempt(ctx, attempt)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create challenge attempt", "error", err)
+		return nil, err
+	}
+	if !created {
+		return nil, ErrAlreadyAttempted
+	}
+
+	result := &attempt
+	return result, nil
+}
+
+func (c *ChallengesService) GetChallengeQueue(ctx context.Context) ([]*structures.ChallengeQueueEntry, error) {
+	var result []*structures.ChallengeQueueEntry
+
+	entries, err := c.ChallengesProvider.GetChallengeQueue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+// CreateChallengeQueueEntry queues a pair for an upcoming day, the text has to
+// be in the public catalog and of a type the activity allows
+func (c *ChallengesService) CreateChallengeQueueEntry(ctx context.Context, entryInfo structures.ChallengeQueueEntryReq) (*structures.ChallengeQueueEntry, error) {
+	entryToCreate := structures.ConvertRequestToChallengeQueueEntry(&entryInfo)
+
+	activity, err := c.ActivitiesProvider.GetActivityByIdOrName(ctx, &entryToCreate.ActivityId, nil)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, ErrInvalidQueueEntry
+	} else if err != nil {
+		return nil, err
+	}
+	text, err := c.TextsProvider.GetTextByIdOrTitle(ctx, &entryToCreate.TextId, nil)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, ErrInvalidQueueEntry
+	} else if err != nil {
+		return nil, err
+	}
+	if text.Status != structures.TEXT_STATUS_APPROVED || text.Visibility != structures.TEXT_VISIBILITY_PUBLIC || !textTypeAllowed(activity, text) {
+		return nil, ErrInvalidQueueEntry
+	}
+
+	createdEntry, err := c.ChallengesProvider.CreateChallengeQueueEntry(ctx, *entryToCreate)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create challenge queue entry", "error", err)
+		return nil, err
+	}
+
+	result := createdEntry
+	return result, nil
+}
+
+func (c *ChallengesService) DeleteChallengeQueueEntry(ctx context.Context, entryId int) (bool, error) {
+	deleted, err := c.ChallengesProvider.DeleteChallengeQueueEntry(ctx, entryId)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete challenge queue entry", "error", err)
+		return false, err
+	}
+
+	return deleted, nil
+}
+
+func NewChallengesService(challengesProvider challenges_provider.ChallengesProviderInterface, activitiesProvider activities_provider.ActivitiesProviderInterface, textsProvider texts_provider.TextsProviderInterface, scoresProvider scores_provider.ScoresProviderInterface, usersProvider users_provider.UsersProviderInterface) *ChallengesService {
+	return &ChallengesService{
+		ChallengesProvider: challengesProvider,
+		ActivitiesProvider: activitiesProvider,
+		TextsProvider:      textsProvider,
+		ScoresProvider:     scoresProvider,
+		UsersProvider:      usersProvider,
+		now:                time.Now,
+	}
+}
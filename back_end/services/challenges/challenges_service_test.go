@@ -0,0 +1,452 @@
+package challenges_service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"type_writer_api/structures"
+	mockProviders "type_writer_api/testing/mocks/providers"
+
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func testCatalog() ([]*structures.Activity, []*structures.Text) {
+	activities := []*structures.Activity{
+		{Id: 1, Kind: structures.ACTIVITY_KIND_ZEN},
+		{Id: 2, Kind: structures.ACTIVITY_KIND_TIMED_TEST, Rules: structures.ActivityRules{AllowedTextTypes: []string{structures.TEXT_TYPE_DRILL}}},
+	}
+	texts := []*structures.Text{
+		{Id: 1, TextType: structures.TEXT_TYPE_FULL_TEXT},
+		{Id: 2, TextType: structures.TEXT_TYPE_DRILL},
+		{Id: 3, TextType: structures.TEXT_TYPE_FULL_TEXT},
+	}
+	return activities, texts
+}
+
+func TestGetChallenge(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 2, 0, 0, 0, time.UTC)
+	data := []struct {
+		testName          string
+		date              string
+		existing          *structures.DailyChallenge
+		queue             []*structures.ChallengeQueueEntry
+		expectedSchedule  *structures.DailyChallenge
+		expectedQueueId   int
+		expectedChallenge *structures.DailyChallenge
+		expectedErr       error
+	}{
+		{
+			testName:          "already scheduled",
+			date:              "2026-10-01",
+			existing:          &structures.DailyChallenge{Id: 1, ChallengeDate: "2026-10-01", ActivityId: 1, TextId: 1},
+			expectedChallenge: &structures.DailyChallenge{Id: 1, ChallengeDate: "2026-10-01", ActivityId: 1, TextId: 1},
+		},
+		{
+			testName:          "open day takes the front of the queue",
+			date:              "2026-10-19",
+			queue:             []*structures.ChallengeQueueEntry{{Id: 4, ActivityId: 1, TextId: 3}, {Id: 5, ActivityId: 2, TextId: 2}},
+			expectedSchedule:  &structures.DailyChallenge{ChallengeDate: "2026-10-19", ActivityId: 1, TextId: 3, Curated: true},
+			expectedQueueId:   4,
+			expectedChallenge: &structures.DailyChallenge{Id: 2, ChallengeDate: "2026-10-19", ActivityId: 1, TextId: 3, Curated: true},
+		},
+		{
+			testName:          "open day with an empty queue picks from the catalog",
+			date:              "2026-10-18",
+			queue:             []*structures.ChallengeQueueEntry{},
+			expectedSchedule:  &structures.DailyChallenge{ChallengeDate: "2026-10-18", ActivityId: 2, TextId: 2},
+			expectedChallenge: &structures.DailyChallenge{Id: 2, ChallengeDate: "2026-10-18", ActivityId: 2, TextId: 2},
+		},
+		{
+			testName:    "day not started anywhere",
+			date:        "2026-10-20",
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+		{
+			testName:    "past day never scheduled",
+			date:        "2026-10-10",
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+		{
+			testName:    "bad date",
+			date:        "2026-13-01",
+			expectedErr: ErrInvalidChallengeDate,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChallengesProvider := mockProviders.NewMockChallengesProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+	challengesService := NewChallengesService(mockChallengesProvider, mockActivitiesProvider, mockTextsProvider, mockScoresProvider, mockUsersProvider)
+	challengesService.now = func() time.Time { return now }
+	activities, texts := testCatalog()
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			if testCase.expectedErr != ErrInvalidChallengeDate {
+				if testCase.existing != nil {
+					mockChallengesProvider.EXPECT().GetChallengeByDate(context.Background(), testCase.date).Return(testCase.existing, nil).Times(1)
+				} else {
+					mockChallengesProvider.EXPECT().GetChallengeByDate(context.Background(), testCase.date).Return(nil, gorm.ErrRecordNotFound).Times(1)
+				}
+			}
+			if testCase.expectedSchedule != nil {
+				mockChallengesProvider.EXPECT().GetChallengeQueue(context.Background()).Return(testCase.queue, nil).Times(1)
+				if len(testCase.queue) == 0 {
+					mockActivitiesProvider.EXPECT().GetActivities(context.Background()).Return(activities, nil).Times(1)
+					mockTextsProvider.EXPECT().GetTexts(context.Background(), structures.TextFilter{
+						Status:     structures.TEXT_STATUS_APPROVED,
+						Visibility: structures.TEXT_VISIBILITY_PUBLIC,
+					}).Return(texts, nil).Times(1)
+				}
+				mockChallengesProvider.EXPECT().ScheduleChallenge(context.Background(), *testCase.expectedSchedule, testCase.expectedQueueId).Return(true, nil).Times(1)
+				mockChallengesProvider.EXPECT().GetChallengeByDate(context.Background(), testCase.date).Return(testCase.expectedChallenge, nil).Times(1)
+			}
+
+			result, err := challengesService.GetChallenge(context.Background(), testCase.date)
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if *result != *testCase.expectedChallenge {
+				t.Fatalf("expected challenge %+v, got %+v", testCase.expectedChallenge, result)
+			}
+		})
+	}
+}
+
+func TestPickChallenge(t *testing.T) {
+	activities, texts := testCatalog()
+
+	first, ok := pickChallenge("2026-10-19", activities, texts)
+	if !ok {
+		t.Fatal("expected a challenge to be picked")
+	}
+	for range 3 {
+		again, _ := pickChallenge("2026-10-19", activities, texts)
+		if *again != *first {
+			t.Fatalf("expected the same pick %+v, got %+v", first, again)
+		}
+	}
+	// the only competitive activity only allows drills
+	if first.ActivityId != 2 || first.TextId != 2 {
+		t.Fatalf("expected the timed test on the drill, got %+v", first)
+	}
+
+	zenOnly := []*structures.Activity{activities[0]}
+	picked := map[int]bool{}
+	for day := 1; day <= 28; day++ {
+		date := time.Date(2026, time.February, day, 0, 0, 0, 0, time.UTC).Format(structures.CHALLENGE_DATE_FORMAT)
+		challenge, ok := pickChallenge(date, zenOnly, texts)
+		if !ok || challenge.ActivityId != 1 {
+			t.Fatalf("expected the zen activity to be picked on %v, got %+v", date, challenge)
+		}
+		picked[challenge.TextId] = true
+	}
+	if len(picked) < 2 {
+		t.Fatalf("expected picks to rotate over the texts, got %v", picked)
+	}
+
+	if _, ok := pickChallenge("2026-10-19", activities[1:], texts[:1]); ok {
+		t.Fatal("expected no pick without an allowed text")
+	}
+}
+
+func TestGetTodayChallenge(t *testing.T) {
+	// late evening of the 18th in New York and already the 19th in Tokyo, the
+	// 20th has not started anywhere yet
+	now := time.Date(2026, time.October, 19, 2, 0, 0, 0, time.UTC)
+	data := []struct {
+		testName         string
+		timeZone         string
+		expectedDate     string
+		expectedStartsAt time.Time
+		expectedErr      error
+	}{
+		{
+			testName:         "utc by default",
+			expectedDate:     "2026-10-19",
+			expectedStartsAt: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			testName:         "still yesterday in new york",
+			timeZone:         "America/New_York",
+			expectedDate:     "2026-10-18",
+			expectedStartsAt: time.Date(2026, time.October, 18, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			testName:         "already today in tokyo",
+			timeZone:         "Asia/Tokyo",
+			expectedDate:     "2026-10-19",
+			expectedStartsAt: time.Date(2026, time.October, 18, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			testName:    "unknown time zone",
+			timeZone:    "Mars/Olympus",
+			expectedErr: ErrInvalidTimeZone,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChallengesProvider := mockProviders.NewMockChallengesProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+	challengesService := NewChallengesService(mockChallengesProvider, mockActivitiesProvider, mockTextsProvider, mockScoresProvider, mockUsersProvider)
+	challengesService.now = func() time.Time { return now }
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			if testCase.expectedErr == nil {
+				mockChallengesProvider.EXPECT().GetChallengeByDate(context.Background(), testCase.expectedDate).
+					Return(&structures.DailyChallenge{Id: 1, ChallengeDate: testCase.expectedDate}, nil).Times(1)
+			}
+
+			result, err := challengesService.GetTodayChallenge(context.Background(), testCase.timeZone)
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !result.StartsAt.Equal(testCase.expectedStartsAt) || result.EndsAt.Sub(*result.StartsAt) != 24*time.Hour {
+				t.Fatalf("unexpected day bounds %v - %v", result.StartsAt, result.EndsAt)
+			}
+		})
+	}
+}
+
+func TestCreateChallengeAttempt(t *testing.T) {
+	// late evening of the 18th in New York and already the 19th in Tokyo, the
+	// 20th has not started anywhere yet
+	now := time.Date(2026, time.October, 19, 2, 0, 0, 0, time.UTC)
+	challenge := &structures.DailyChallenge{Id: 1, ChallengeDate: "2026-10-18", ActivityId: 2, TextId: 2}
+	// the 18th anywhere on earth
+	firstFrom := time.Date(2026, time.October, 17, 10, 0, 0, 0, time.UTC)
+	firstUntil := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+
+	data := []struct {
+		testName    string
+		timeZone    string
+		score       *structures.Score
+		firstScore  *structures.Score
+		created     bool
+		expectedErr error
+	}{
+		{
+			testName:   "first score made on the day in the user time zone",
+			timeZone:   "America/New_York",
+			score:      &structures.Score{Id: 1, UserId: 1, ActivityId: 2, TextId: 2, CreatedAt: now.Add(-time.Hour)},
+			firstScore: &structures.Score{Id: 1},
+			created:    true,
+		},
+		{
+			testName:    "score made on the next day in utc",
+			timeZone:    "UTC",
+			score:       &structures.Score{Id: 1, UserId: 1, ActivityId: 2, TextId: 2, CreatedAt: now.Add(-time.Hour)},
+			expectedErr: ErrScoreNotInChallenge,
+		},
+		{
+			testName:    "score on another text",
+			timeZone:    "America/New_York",
+			score:       &structures.Score{Id: 1, UserId: 1, ActivityId: 2, TextId: 3, CreatedAt: now.Add(-time.Hour)},
+			expectedErr: ErrScoreNotInChallenge,
+		},
+		{
+			testName:    "score of another user",
+			score:       &structures.Score{Id: 1, UserId: 2, ActivityId: 2, TextId: 2, CreatedAt: now.Add(-time.Hour)},
+			expectedErr: ErrScoreNotOwned,
+		},
+		{
+			testName:    "best of several tries",
+			timeZone:    "America/New_York",
+			score:       &structures.Score{Id: 3, UserId: 1, ActivityId: 2, TextId: 2, CreatedAt: now.Add(-time.Hour)},
+			firstScore:  &structures.Score{Id: 1},
+			expectedErr: ErrNotFirstScore,
+		},
+		{
+			// the first try was on the 18th in Tokyo, the retry is still the
+			// 18th after moving the profile to New York
+			testName:    "retry after changing time zone",
+			timeZone:    "America/New_York",
+			score:       &structures.Score{Id: 3, UserId: 1, ActivityId: 2, TextId: 2, CreatedAt: now.Add(-time.Hour)},
+			firstScore:  &structures.Score{Id: 1, CreatedAt: time.Date(2026, time.October, 18, 10, 0, 0, 0, time.UTC)},
+			expectedErr: ErrNotFirstScore,
+		},
+		{
+			testName:    "second attempt",
+			timeZone:    "America/New_York",
+			score:       &structures.Score{Id: 1, UserId: 1, ActivityId: 2, TextId: 2, CreatedAt: now.Add(-time.Hour)},
+			firstScore:  &structures.Score{Id: 1},
+			created:     false,
+			expectedErr: ErrAlreadyAttempted,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChallengesProvider := mockProviders.NewMockChallengesProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+	challengesService := NewChallengesService(mockChallengesProvider, mockActivitiesProvider, mockTextsProvider, mockScoresProvider, mockUsersProvider)
+	challengesService.now = func() time.Time { return now }
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			userId := 1
+			attempt := structures.ChallengeAttemptReq{ScoreId: testCase.score.Id}
+			mockChallengesProvider.EXPECT().GetChallengeByDate(context.Background(), challenge.ChallengeDate).Return(challenge, nil).Times(1)
+			mockScoresProvider.EXPECT().GetScoreById(context.Background(), testCase.score.Id).Return(testCase.score, nil).Times(1)
+			if testCase.score.UserId == userId {
+				mockUsersProvider.EXPECT().GetUserByIdOrUsername(context.Background(), &userId, nil).Return(&structures.User{Id: userId, TimeZone: testCase.timeZone}, nil).Times(1)
+			}
+			if testCase.firstScore != nil {
+				mockChallengesProvider.EXPECT().GetFirstChallengeScore(context.Background(), userId, challenge.ActivityId, challenge.TextId, firstFrom, firstUntil).Return(testCase.firstScore, nil).Times(1)
+			}
+			if testCase.created || testCase.expectedErr == ErrAlreadyAttempted {
+				mockChallengesProvider.EXPECT().CreateChallengeAttempt(context.Background(), structures.ChallengeAttempt{
+					ChallengeId: challenge.Id,
+					UserId:      userId,
+					ScoreId:     testCase.score.Id,
+					CreatedAt:   now,
+				}).Return(testCase.created, nil).Times(1)
+			}
+
+			result, err := challengesService.CreateChallengeAttempt(context.Background(), challenge.ChallengeDate, attempt, userId)
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if result.ChallengeId != challenge.Id || result.ScoreId != testCase.score.Id {
+				t.Fatalf("unexpected attempt %+v", result)
+			}
+		})
+	}
+}
+
+func TestGetChallengeLeaderboard(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 2, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChallengesProvider := mockProviders.NewMockChallengesProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+	challengesService := NewChallengesService(mockChallengesProvider, mockActivitiesProvider, mockTextsProvider, mockScoresProvider, mockUsersProvider)
+	challengesService.now = func() time.Time { return now }
+
+	mockChallengesProvider.EXPECT().GetChallengeByDate(context.Background(), "2026-10-18").
+		Return(&structures.DailyChallenge{Id: 1, ChallengeDate: "2026-10-18"}, nil).Times(1)
+	mockChallengesProvider.EXPECT().GetChallengeLeaderboard(context.Background(), 1).Return([]*structures.ChallengeLeaderboardEntry{
+		{UserId: 3, FinalScore: 80},
+		{UserId: 1, FinalScore: 70},
+		{UserId: 2, FinalScore: 70},
+		{UserId: 4, FinalScore: 50},
+	}, nil).Times(1)
+
+	result, err := challengesService.GetChallengeLeaderboard(context.Background(), "2026-10-18")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expectedRanks := []int{1, 2, 2, 4}
+	for indx, entry := range result {
+		if entry.Rank != expectedRanks[indx] {
+			t.Fatalf("entry %v: expected rank %v, got %v", indx, expectedRanks[indx], entry.Rank)
+		}
+	}
+}
+
+func TestCreateChallengeQueueEntry(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 2, 0, 0, 0, time.UTC)
+	activities, texts := testCatalog()
+
+	data := []struct {
+		testName    string
+		entry       structures.ChallengeQueueEntryReq
+		text        *structures.Text
+		expectedErr error
+	}{
+		{
+			testName: "public text of an allowed type",
+			entry:    structures.ChallengeQueueEntryReq{ActivityId: 2, TextId: 2},
+			text:     &structures.Text{Id: 2, TextType: texts[1].TextType, Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC},
+		},
+		{
+			testName:    "text type not allowed",
+			entry:       structures.ChallengeQueueEntryReq{ActivityId: 2, TextId: 1},
+			text:        &structures.Text{Id: 1, TextType: texts[0].TextType, Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC},
+			expectedErr: ErrInvalidQueueEntry,
+		},
+		{
+			testName:    "private text",
+			entry:       structures.ChallengeQueueEntryReq{ActivityId: 2, TextId: 2},
+			text:        &structures.Text{Id: 2, TextType: texts[1].TextType, Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PRIVATE},
+			expectedErr: ErrInvalidQueueEntry,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChallengesProvider := mockProviders.NewMockChallengesProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+	challengesService := NewChallengesService(mockChallengesProvider, mockActivitiesProvider, mockTextsProvider, mockScoresProvider, mockUsersProvider)
+	challengesService.now = func() time.Time { return now }
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			mockActivitiesProvider.EXPECT().GetActivityByIdOrName(context.Background(), &testCase.entry.ActivityId, nil).Return(activities[1], nil).Times(1)
+			mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &testCase.entry.TextId, nil).Return(testCase.text, nil).Times(1)
+			if testCase.expectedErr == nil {
+				expectedInsert := structures.ChallengeQueueEntry{ActivityId: testCase.entry.ActivityId, TextId: testCase.entry.TextId}
+				mockChallengesProvider.EXPECT().CreateChallengeQueueEntry(context.Background(), expectedInsert).Return(&expectedInsert, nil).Times(1)
+			}
+
+			_, err := challengesService.CreateChallengeQueueEntry(context.Background(), testCase.entry)
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package courses_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"type_writer_api/providers/courses"
+	"type_writer_api/providers/scores"
+	"type_writer_api/structures"
+)
+
+var ErrInvalidLesson = errors.New("invalid course lesson")
+
+type CoursesServiceInterface interface {
+	GetCourses(ctx context.Context) ([]*structures.Course, error)
+	GetCourseById(ctx context.Context, courseId int) (*structures.Course, error)
+	CreateCourse(ctx context.Context, courseInfo structures.CourseReq) (*structures.Course, error)
+	UpdateCourse(ctx context.Context, courseInfo structures.CourseReq, courseId int) (*structures.Course, error)
+	DeleteCourse(ctx context.Context, courseId int) (bool, error)
+	GetCourseProgress(ctx context.Context, courseId, userId int) (*structures.CourseProgress, error)
+}
+
+type CoursesService struct {
+	CoursesProvider courses_provider.CoursesProviderInterface
+	ScoresProvider  scores_provider.ScoresProviderInterface
+}
+
+func validateLessons(lessons []*structures.CourseLesson) error {
+	for _, lesson := range lessons {
+		if lesson.TextId <= 0 || lesson.ActivityId <= 0 {
+			return ErrInvalidLesson
+		}
+		if lesson.MinWpm < 0 || lesson.MinAccuracy < 0 || lesson.MinAccuracy > 100 {
+			return ErrInvalidLesson
+		}
+	}
+	return nil
+}
+
+// scoreAccuracy is the accuracy percentage of a score, derived from the typed
+// letters and errors when the result does not carry it
+func scoreAccuracy(result structures.ScoreResult) float64 {
+	if result.Accuracy != 0 || result.Letters <= 0 {
+		return result.Accuracy
+	}
+	return max(0, float64(result.Letters-result.Errors)/float64(result.Letters)*100)
+}
+
+func (c *CoursesService) GetCourses(ctx context.Context) ([]*structures.Course, error) {
+	var result []*structures.Course
+
+	courses, err := c.CoursesProvider.GetCourses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, course := range courses {
+		result = append(result, course)
+	}
+
+	return result, nil
+}
+
+func (c *CoursesService) GetCourseById(ctx context.Context, courseId int) (*structures.Course, error) {
+	course, err := c.CoursesProvider.GetCourseById(ctx, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := course
+	return result, nil
+}
+
+func (c *CoursesService) CreateCourse(ctx context.Context, courseInfo structures.CourseReq) (*structures.Course, error) {
+	courseToCreate := structures.ConvertRequestToCourse(&courseInfo)
+
+	err := validateLessons(courseToCreate.Lessons)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create course", "error", err)
+		return nil, err
+	}
+
+	createdCourse, err := c.CoursesProvider.CreateCourse(ctx, *courseToCreate)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create course", "error", err)
+		return nil, err
+	}
+
+	result := createdCourse
+	return result, nil
+}
+
+func (c *CoursesService) UpdateCourse(ctx context.Context, courseInfo structures.CourseReq, courseId int) (*structures.Course, error) {
+	existingCourse, err := c.CoursesProvider.GetCourseById(ctx, courseId)
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update course", "error", err)
+		return nil, err
+	}
+
+	if courseInfo.Title != "" {
+		existingCourse.Title = courseInfo.Title
+	}
+	if courseInfo.Description != "" {
+		existingCourse.Description = courseInfo.Description
+	}
+	if len(courseInfo.Lessons) != 0 {
+		existingCourse.Lessons = structures.ConvertRequestToCourse(&courseInfo).Lessons
+		err := validateLessons(existingCourse.Lessons)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to update course", "error", err)
+			return nil, err
+		}
+	}
+
+	updatedCourse, err := c.CoursesProvider.UpdateCourse(ctx, *existingCourse)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update course", "error", err)
+		return nil, err
+	}
+
+	result := updatedCourse
+	return result, nil
+}
+
+func (c *CoursesService) DeleteCourse(ctx context.Context, courseId int) (bool, error) {
+	deleted, err := c.CoursesProvider.DeleteCourse(ctx, courseId)
+	if err != nil {
+		return false, err
+	}
+
+	return deleted, nil
+}
+
+// GetCourseProgress walks the course lessons in order, the first lesson is
+// always unlocked and every following one unlocks once one of the user's ranked
+// scores on the previous lesson's text and activity meets its pass criteria
+func (c *CoursesService) GetCourseProgress(ctx context.Context, courseId, userId int) (*structures.CourseProgress, error) {
+	course, err := c.CoursesProvider.GetCourseById(ctx, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	scores, err := c.ScoresProvider.GetScores(ctx, structures.ScoreFilter{UserId: userId, ReviewStatuses: structures.RANKED_SCORE_REVIEWS})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to fetch user scores", "error", err)
+		return nil, err
+	}
+
+	progress := &structures.CourseProgress{
+		CourseId:     course.Id,
+		UserId:       userId,
+		TotalLessons: len(course.Lessons),
+		Lessons:      []*structures.LessonProgress{},
+	}
+
+	unlocked := true
+	for _, lesson := range course.Lessons {
+		lessonProgress := &structures.LessonProgress{
+			LessonId:    lesson.Id,
+			Position:    lesson.Position,
+			TextId:      lesson.TextId,
+			ActivityId:  lesson.ActivityId,
+			MinWpm:      lesson.MinWpm,
+			MinAccuracy: lesson.MinAccuracy,
+			Unlocked:    unlocked,
+		}
+
+		for _, score := range scores {
+			if score.TextId != lesson.TextId || score.ActivityId != lesson.ActivityId {
+				continue
+			}
+			wpm := score.Result.Wpm
+			accuracy := scoreAccuracy(score.Result)
+
+			lessonProgress.Attempts++
+			lessonProgress.BestWpm = max(lessonProgress.BestWpm, wpm)
+			lessonProgress.BestAccuracy = max(lessonProgress.BestAccuracy, accuracy)
+			if unlocked && wpm >= lesson.MinWpm && accuracy >= lesson.MinAccuracy {
+				lessonProgress.Passed = true
+			}
+		}
+
+		if lessonProgress.Passed {
+			progress.CompletedLessons++
+		} else if unlocked {
+			position := lesson.Position
+			progress.CurrentLesson = &position
+		}
+
+		progress.Lessons = append(progress.Lessons, lessonProgress)
+		unlocked = lessonProgress.Passed
+	}
+	progress.Completed = progress.TotalLessons > 0 && progress.CompletedLessons == progress.TotalLessons
+
+	return progress, nil
+}
+
+func NewCoursesService(coursesProvider courses_provider.CoursesProviderInterface, scoresProvider scores_provider.ScoresProviderInterface) *CoursesService {
+	return &CoursesService{
+		CoursesProvider: coursesProvider,
+		ScoresProvider:  scoresProvider,
+	}
+}
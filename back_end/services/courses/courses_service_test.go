@@ -0,0 +1,263 @@
+package courses_service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"type_writer_api/helpers"
+	"type_writer_api/structures"
+	mockProviders "type_writer_api/testing/mocks/providers"
+
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func testCourse() *structures.Course {
+	return &structures.Course{
+		Id:    1,
+		Title: "home row",
+		Lessons: []*structures.CourseLesson{
+			{Id: 1, CourseId: 1, Position: 1, TextId: 1, ActivityId: 1, MinWpm: 20, MinAccuracy: 90},
+			{Id: 2, CourseId: 1, Position: 2, TextId: 2, ActivityId: 1, MinWpm: 30, MinAccuracy: 95},
+			{Id: 3, CourseId: 1, Position: 3, TextId: 3, ActivityId: 2, MinWpm: 40, MinAccuracy: 95},
+		},
+	}
+}
+
+func TestCreateCourse(t *testing.T) {
+	data := []struct {
+		testName       string
+		inputCourse    structures.CourseReq
+		expectedInsert *structures.Course
+		expectedErr    error
+	}{
+		{
+			"lessons get ordered positions",
+			structures.CourseReq{
+				Title: "home row",
+				Lessons: []*structures.CourseLessonReq{
+					{TextId: 1, ActivityId: 1, MinWpm: 20, MinAccuracy: 90},
+					{TextId: 2, ActivityId: 1, MinWpm: 30, MinAccuracy: 95},
+				},
+			},
+			&structures.Course{
+				Title: "home row",
+				Lessons: []*structures.CourseLesson{
+					{Position: 1, TextId: 1, ActivityId: 1, MinWpm: 20, MinAccuracy: 90},
+					{Position: 2, TextId: 2, ActivityId: 1, MinWpm: 30, MinAccuracy: 95},
+				},
+			},
+			nil,
+		},
+		{
+			"lesson without text",
+			structures.CourseReq{
+				Title:   "home row",
+				Lessons: []*structures.CourseLessonReq{{ActivityId: 1}},
+			},
+			nil,
+			ErrInvalidLesson,
+		},
+		{
+			"accuracy criteria over 100",
+			structures.CourseReq{
+				Title:   "home row",
+				Lessons: []*structures.CourseLessonReq{{TextId: 1, ActivityId: 1, MinAccuracy: 101}},
+			},
+			nil,
+			ErrInvalidLesson,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCoursesProvider := mockProviders.NewMockCoursesProviderInterface(ctrl)
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	coursesService := NewCoursesService(mockCoursesProvider, mockScoresProvider)
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			if testCase.expectedInsert != nil {
+				mockCoursesProvider.EXPECT().CreateCourse(context.Background(), *testCase.expectedInsert).Return(testCase.expectedInsert, nil).Times(1)
+			}
+
+			result, err := coursesService.CreateCourse(context.Background(), testCase.inputCourse)
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+			} else {
+				if err := helpers.CompareReflectedStructFields(*result, *testCase.expectedInsert); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateCourse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCoursesProvider := mockProviders.NewMockCoursesProviderInterface(ctrl)
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	coursesService := NewCoursesService(mockCoursesProvider, mockScoresProvider)
+
+	expectedResult := testCourse()
+	expectedResult.Description = "updated description"
+
+	mockCoursesProvider.EXPECT().GetCourseById(context.Background(), 1).Return(testCourse(), nil).Times(1)
+	mockCoursesProvider.EXPECT().UpdateCourse(
+		context.Background(),
+		gomock.Cond(func(input structures.Course) bool { return helpers.CompareReflectedStructFields(input, *expectedResult) == nil }),
+	).Return(expectedResult, nil).Times(1)
+
+	result, err := coursesService.UpdateCourse(context.Background(), structures.CourseReq{Description: "updated description"}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := helpers.CompareReflectedStructFields(*result, *expectedResult); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetCourseProgress(t *testing.T) {
+	data := []struct {
+		testName          string
+		mockScores        []*structures.Score
+		expectedCompleted int
+		expectedCurrent   *int
+		expectedUnlocked  []bool
+		expectedPassed    []bool
+	}{
+		{
+			"no scores only unlocks the first lesson",
+			[]*structures.Score{},
+			0,
+			func() *int { position := 1; return &position }(),
+			[]bool{true, false, false},
+			[]bool{false, false, false},
+		},
+		{
+			"passing the first lesson unlocks the second",
+			[]*structures.Score{
+				{Id: 1, UserId: 1, ActivityId: 1, TextId: 1, Result: structures.ScoreResult{Version: 1, Wpm: 18, Accuracy: 99}},
+				{Id: 2, UserId: 1, ActivityId: 1, TextId: 1, Result: structures.ScoreResult{Version: 1, Wpm: 25, Letters: 100, Errors: 5}},
+			},
+			1,
+			func() *int { position := 2; return &position }(),
+			[]bool{true, true, false},
+			[]bool{true, false, false},
+		},
+		{
+			"scores on a locked lesson do not count",
+			[]*structures.Score{
+				{Id: 1, UserId: 1, ActivityId: 1, TextId: 2, Result: structures.ScoreResult{Version: 1, Wpm: 80, Accuracy: 100}},
+			},
+			0,
+			func() *int { position := 1; return &position }(),
+			[]bool{true, false, false},
+			[]bool{false, false, false},
+		},
+		{
+			"criteria must be met by the same score",
+			[]*structures.Score{
+				{Id: 1, UserId: 1, ActivityId: 1, TextId: 1, Result: structures.ScoreResult{Version: 1, Wpm: 80, Accuracy: 70}},
+				{Id: 2, UserId: 1, ActivityId: 1, TextId: 1, Result: structures.ScoreResult{Version: 1, Wpm: 10, Accuracy: 100}},
+			},
+			0,
+			func() *int { position := 1; return &position }(),
+			[]bool{true, false, false},
+			[]bool{false, false, false},
+		},
+		{
+			"completed course",
+			[]*structures.Score{
+				{Id: 1, UserId: 1, ActivityId: 1, TextId: 1, Result: structures.ScoreResult{Version: 1, Wpm: 25, Accuracy: 95}},
+				{Id: 2, UserId: 1, ActivityId: 1, TextId: 2, Result: structures.ScoreResult{Version: 1, Wpm: 35, Accuracy: 96}},
+				{Id: 3, UserId: 1, ActivityId: 2, TextId: 3, Result: structures.ScoreResult{Version: 1, Wpm: 45, Accuracy: 97}},
+			},
+			3,
+			nil,
+			[]bool{true, true, true},
+			[]bool{true, true, true},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCoursesProvider := mockProviders.NewMockCoursesProviderInterface(ctrl)
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	coursesService := NewCoursesService(mockCoursesProvider, mockScoresProvider)
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			mockCoursesProvider.EXPECT().GetCourseById(context.Background(), 1).Return(testCourse(), nil).Times(1)
+			mockScoresProvider.EXPECT().GetScores(context.Background(), structures.ScoreFilter{UserId: 1, ReviewStatuses: structures.RANKED_SCORE_REVIEWS}).Return(testCase.mockScores, nil).Times(1)
+
+			result, err := coursesService.GetCourseProgress(context.Background(), 1, 1)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if result.CompletedLessons != testCase.expectedCompleted {
+				t.Fatalf("expected %v completed lessons, got %v", testCase.expectedCompleted, result.CompletedLessons)
+			}
+			if result.Completed != (testCase.expectedCompleted == result.TotalLessons) {
+				t.Fatalf("unexpected completed flag %v", result.Completed)
+			}
+			if (result.CurrentLesson == nil) != (testCase.expectedCurrent == nil) ||
+				(result.CurrentLesson != nil && *result.CurrentLesson != *testCase.expectedCurrent) {
+				t.Fatalf("expected current lesson %v, got %v", testCase.expectedCurrent, result.CurrentLesson)
+			}
+			for idx, lesson := range result.Lessons {
+				if lesson.Unlocked != testCase.expectedUnlocked[idx] || lesson.Passed != testCase.expectedPassed[idx] {
+					t.Fatalf("lesson %v: expected unlocked=%v passed=%v, got unlocked=%v passed=%v",
+						idx+1, testCase.expectedUnlocked[idx], testCase.expectedPassed[idx], lesson.Unlocked, lesson.Passed)
+				}
+			}
+		})
+	}
+}
+
+func TestGetCourseProgressNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCoursesProvider := mockProviders.NewMockCoursesProviderInterface(ctrl)
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	coursesService := NewCoursesService(mockCoursesProvider, mockScoresProvider)
+
+	mockCoursesProvider.EXPECT().GetCourseById(context.Background(), 99).Return(nil, gorm.ErrRecordNotFound).Times(1)
+
+	_, err := coursesService.GetCourseProgress(context.Background(), 99, 1)
+	if err != gorm.ErrRecordNotFound {
+		t.Fatalf("expected error: %v but got %v instead", gorm.ErrRecordNotFound, err)
+	}
+}
+
+func TestGetCourses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCoursesProvider := mockProviders.NewMockCoursesProviderInterface(ctrl)
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	coursesService := NewCoursesService(mockCoursesProvider, mockScoresProvider)
+
+	mockResult := []*structures.Course{
+		{Id: 1, Title: "home row", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+	mockCoursesProvider.EXPECT().GetCourses(context.Background()).Return(mockResult, nil).Times(1)
+
+	result, err := coursesService.GetCourses(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("slice length missmatch: got %v, expected %v", len(result), 1)
+	}
+}
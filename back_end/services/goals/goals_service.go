@@ -0,0 +1,164 @@
+package goals_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+	"type_writer_api/helpers"
+	"type_writer_api/providers/goals"
+	"type_writer_api/providers/users"
+	"type_writer_api/structures"
+)
+
+var (
+	ErrGoalsForbidden = errors.New("goals belong to another user")
+	ErrInvalidGoal    = errors.New("invalid goal")
+	ErrTooManyGoals   = errors.New("too many goals")
+)
+
+type GoalsServiceInterface interface {
+	GetUserStreak(ctx context.Context, userId int) (*structures.UserStreak, error)
+	GetUserGoals(ctx context.Context, userId int, viewerId int, viewerType string) (*structures.UserGoals, error)
+	CreateUserGoal(ctx context.Context, goalInfo structures.UserGoalReq, userId int, viewerId int, viewerType string) (*structures.GoalProgress, error)
+	DeleteUserGoal(ctx context.Context, userId int, goalId int, viewerId int, viewerType string) (bool, error)
+}
+
+type GoalsService struct {
+	GoalsProvider goals_provider.GoalsProviderInterface
+	UsersProvider users_provider.UsersProviderInterface
+	now           func() time.Time
+}
+
+// GetUserStreak counts the days in a row a user practiced on, days start and
+// end at midnight in the time zone of the user
+func (g *GoalsService) GetUserStreak(ctx context.Context, userId int) (*structures.UserStreak, error) {
+	user, err := g.UsersProvider.GetUserByIdOrUsername(ctx, &userId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := g.GoalsProvider.GetPracticeDays(ctx, userId)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get practice days", "error", err)
+		return nil, err
+	}
+
+	result := helpers.UserStreak(userId, user.TimeZone, days, g.now())
+	return result, nil
+}
+
+// ownGoals checks the viewer may see the goals of the user, returning the user
+func (g *GoalsService) ownGoals(ctx context.Context, userId int, viewerId int, viewerType string) (*structures.User, error) {
+	if userId != viewerId && viewerType != structures.USER_TYPE_ADMIN {
+		return nil, ErrGoalsForbidden
+	}
+	return g.UsersProvider.GetUserByIdOrUsername(ctx, &userId, nil)
+}
+
+// GetUserGoals lists the goals of a user, the nearest target date first,
+// along with where the latest scores project each of them to land
+func (g *GoalsService) GetUserGoals(ctx context.Context, userId int, viewerId int, viewerType string) (*structures.UserGoals, error) {
+	user, err := g.ownGoals(ctx, userId, viewerId, viewerType)
+	if err != nil {
+		return nil, err
+	}
+
+	goals, err := g.GoalsProvider.GetUserGoals(ctx, userId)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get user goals", "error", err)
+		return nil, err
+	}
+
+	// goals on the same metric share their samples
+	samples := map[string][]*structures.GoalSample{}
+	userGoals := &structures.UserGoals{UserId: userId, TimeZone: user.TimeZone, Goals: []*structures.GoalProgress{}}
+	for _, goal := range goals {
+		if _, ok := samples[goal.Metric]; !ok {
+			samples[goal.Metric], err = g.GoalsProvider.GetGoalSamples(ctx, userId, goal.Metric, structures.GOAL_REGRESSION_SCORES)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to get goal samples", "error", err)
+				return nil, err
+			}
+		}
+		userGoals.Goals = append(userGoals.Goals, helpers.ProjectGoal(goal, samples[goal.Metric], user.TimeZone, g.now()))
+	}
+
+	result := userGoals
+	return result, nil
+}
+
+// CreateUserGoal sets a new goal for a user, the target date cannot be over
+// yet in the time zone of the user
+func (g *GoalsService) CreateUserGoal(ctx context.Context, goalInfo structures.UserGoalReq, userId int, viewerId int, viewerType string) (*structures.GoalProgress, error) {
+	user, err := g.ownGoals(ctx, userId, viewerId, viewerType)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(structures.GOAL_METRICS, goalInfo.Metric) || goalInfo.Target <= 0 {
+		return nil, ErrInvalidGoal
+	}
+	if slices.Contains(structures.GOAL_PERCENT_METRICS, goalInfo.Metric) && goalInfo.Target > 100 {
+		return nil, ErrInvalidGoal
+	}
+	targetDate, err := time.Parse(time.DateOnly, goalInfo.TargetDate)
+	if err != nil {
+		return nil, ErrInvalidGoal
+	}
+	today := helpers.LocalDay(g.now(), helpers.UserLocation(user.TimeZone))
+	if targetDate.Format(time.DateOnly) < today {
+		return nil, ErrInvalidGoal
+	}
+
+	count, err := g.GoalsProvider.CountUserGoals(ctx, userId)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create user goal", "error", err)
+		return nil, err
+	}
+	if count >= structures.MAX_USER_GOALS {
+		return nil, ErrTooManyGoals
+	}
+
+	goal, err := g.GoalsProvider.CreateUserGoal(ctx, structures.UserGoal{
+		UserId:     userId,
+		Metric:     goalInfo.Metric,
+		Target:     goalInfo.Target,
+		TargetDate: targetDate.Format(time.DateOnly),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create user goal", "error", err)
+		return nil, err
+	}
+	samples, err := g.GoalsProvider.GetGoalSamples(ctx, userId, goal.Metric, structures.GOAL_REGRESSION_SCORES)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get goal samples", "error", err)
+		return nil, err
+	}
+
+	result := helpers.ProjectGoal(goal, samples, user.TimeZone, g.now())
+	return result, nil
+}
+
+func (g *GoalsService) DeleteUserGoal(ctx context.Context, userId int, goalId int, viewerId int, viewerType string) (bool, error) {
+	if userId != viewerId && viewerType != structures.USER_TYPE_ADMIN {
+		return false, ErrGoalsForbidden
+	}
+
+	deleted, err := g.GoalsProvider.DeleteUserGoal(ctx, userId, goalId)
+	if err != nil {
+		return false, err
+	}
+
+	result := deleted
+	return result, nil
+}
+
+func NewGoalsService(goalsProvider goals_provider.GoalsProviderInterface, usersProvider users_provider.UsersProviderInterface) *GoalsService {
+	return &GoalsService{
+		GoalsProvider: goalsProvider,
+		UsersProvider: usersProvider,
+		now:           time.Now,
+	}
+}
@@ -0,0 +1,294 @@
+package goals_service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"type_writer_api/helpers"
+	"type_writer_api/structures"
+	mockProviders "type_writer_api/testing/mocks/providers"
+
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+// dailySamples has one score a day up to now, the first value is the oldest
+func dailySamples(now time.Time, values ...float64) []*structures.GoalSample {
+	samples := []*structures.GoalSample{}
+	for idx, value := range values {
+		samples = append([]*structures.GoalSample{{Value: value, CreatedAt: now.AddDate(0, 0, idx-len(values)+1)}}, samples...)
+	}
+	return samples
+}
+
+func TestGetUserStreak(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 3, 0, 0, 0, time.UTC)
+	data := []struct {
+		testName    string
+		timeZone    string
+		days        []string
+		userErr     error
+		expected    *structures.UserStreak
+		expectedErr error
+	}{
+		{
+			testName: "practiced today in the user's time zone",
+			timeZone: "America/Los_Angeles",
+			days:     []string{"2026-10-18", "2026-10-17", "2026-10-16", "2026-10-14"},
+			expected: &structures.UserStreak{UserId: 2, TimeZone: "America/Los_Angeles", Current: 3, Longest: 3, PracticedToday: true, LastPracticed: "2026-10-18"},
+		},
+		{
+			testName: "streak still alive from yesterday",
+			timeZone: "UTC",
+			days:     []string{"2026-10-18", "2026-10-17", "2026-10-16", "2026-10-14"},
+			expected: &structures.UserStreak{UserId: 2, TimeZone: "UTC", Current: 3, Longest: 3, LastPracticed: "2026-10-18"},
+		},
+		{
+			testName: "broken streak",
+			timeZone: "UTC",
+			days:     []string{"2026-10-16", "2026-09-30", "2026-09-29", "2026-09-28", "2026-09-27"},
+			expected: &structures.UserStreak{UserId: 2, TimeZone: "UTC", Current: 0, Longest: 4, LastPracticed: "2026-10-16"},
+		},
+		{
+			testName: "never practiced",
+			timeZone: "UTC",
+			days:     []string{},
+			expected: &structures.UserStreak{UserId: 2, TimeZone: "UTC"},
+		},
+		{
+			testName:    "missing user",
+			userErr:     gorm.ErrRecordNotFound,
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.testName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockGoalsProvider := mockProviders.NewMockGoalsProviderInterface(ctrl)
+			mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+			goalsService := NewGoalsService(mockGoalsProvider, mockUsersProvider)
+			goalsService.now = func() time.Time { return now }
+
+			userId := 2
+			mockUsersProvider.EXPECT().GetUserByIdOrUsername(gomock.Any(), &userId, nil).Return(&structures.User{Id: 2, TimeZone: tt.timeZone}, tt.userErr).Times(1)
+			if tt.userErr == nil {
+				mockGoalsProvider.EXPECT().GetPracticeDays(gomock.Any(), 2).Return(tt.days, nil).Times(1)
+			}
+
+			streak, err := goalsService.GetUserStreak(context.Background(), 2)
+			if err != tt.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if err := helpers.CompareReflectedStructFields(*streak, *tt.expected); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestGetUserGoals(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 3, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockGoalsProvider := mockProviders.NewMockGoalsProviderInterface(ctrl)
+	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+	goalsService := NewGoalsService(mockGoalsProvider, mockUsersProvider)
+	goalsService.now = func() time.Time { return now }
+
+	userId := 2
+	mockUsersProvider.EXPECT().GetUserByIdOrUsername(gomock.Any(), &userId, nil).Return(&structures.User{Id: 2, TimeZone: "UTC"}, nil).Times(1)
+	goals := []*structures.UserGoal{
+		{Id: 1, UserId: 2, Metric: "wpm", Target: 65, TargetDate: "2026-11-01"},
+		{Id: 2, UserId: 2, Metric: "accuracy", Target: 98, TargetDate: "2026-11-15"},
+		{Id: 3, UserId: 2, Metric: "wpm", Target: 80, TargetDate: "2026-12-01"},
+		{Id: 4, UserId: 2, Metric: "wpm", Target: 90, TargetDate: "2026-11-01"},
+	}
+	mockGoalsProvider.EXPECT().GetUserGoals(gomock.Any(), 2).Return(goals, nil).Times(1)
+	mockGoalsProvider.EXPECT().GetGoalSamples(gomock.Any(), 2, "wpm", structures.GOAL_REGRESSION_SCORES).
+		Return(dailySamples(now, 60, 61, 62, 63, 64, 65, 66, 67, 68, 69), nil).Times(1)
+	mockGoalsProvider.EXPECT().GetGoalSamples(gomock.Any(), 2, "accuracy", structures.GOAL_REGRESSION_SCORES).
+		Return(dailySamples(now, 96, 95, 96, 95, 96, 95), nil).Times(1)
+
+	result, err := goalsService.GetUserGoals(context.Background(), 2, 2, structures.USER_TYPE_REGULAR)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := []structures.GoalProgress{
+		{UserGoal: *goals[0], Samples: 10, Current: 69, Trend: 1, Achieved: true, ProjectedDate: "2026-10-19", OnTrack: true},
+		{UserGoal: *goals[1], Samples: 6, Current: 95.29, Trend: -0.09},
+		{UserGoal: *goals[2], Samples: 10, Current: 69, Trend: 1, ProjectedDate: "2026-10-30", OnTrack: true},
+		{UserGoal: *goals[3], Samples: 10, Current: 69, Trend: 1, ProjectedDate: "2026-11-09"},
+	}
+	if result.UserId != 2 || result.TimeZone != "UTC" || len(result.Goals) != len(expected) {
+		t.Fatalf("unexpected goals %+v", result)
+	}
+	for idx, progress := range result.Goals {
+		if err := helpers.CompareReflectedStructFields(*progress, expected[idx]); err != nil {
+			t.Fatalf("goal %d: %v", progress.Id, err)
+		}
+	}
+}
+
+func TestGetUserGoalsForbidden(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 3, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockGoalsProvider := mockProviders.NewMockGoalsProviderInterface(ctrl)
+	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+	goalsService := NewGoalsService(mockGoalsProvider, mockUsersProvider)
+	goalsService.now = func() time.Time { return now }
+
+	_, err := goalsService.GetUserGoals(context.Background(), 2, 3, structures.USER_TYPE_REGULAR)
+	if err != ErrGoalsForbidden {
+		t.Fatalf("expected error: %v but got %v instead", ErrGoalsForbidden, err)
+	}
+}
+
+func TestCreateUserGoal(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 3, 0, 0, 0, time.UTC)
+	data := []struct {
+		testName    string
+		goal        structures.UserGoalReq
+		timeZone    string
+		viewerId    int
+		goals       int
+		expectedErr error
+	}{
+		{
+			testName: "new goal",
+			goal:     structures.UserGoalReq{Metric: "wpm", Target: 80, TargetDate: "2026-12-01"},
+			timeZone: "UTC",
+			viewerId: 2,
+		},
+		{
+			testName: "due today",
+			goal:     structures.UserGoalReq{Metric: "cpm", Target: 400, TargetDate: "2026-10-18"},
+			timeZone: "America/Los_Angeles",
+			viewerId: 2,
+		},
+		{
+			testName:    "already over in the user's time zone",
+			goal:        structures.UserGoalReq{Metric: "wpm", Target: 80, TargetDate: "2026-10-18"},
+			timeZone:    "UTC",
+			viewerId:    2,
+			expectedErr: ErrInvalidGoal,
+		},
+		{
+			testName:    "unknown metric",
+			goal:        structures.UserGoalReq{Metric: "errors", Target: 1, TargetDate: "2026-12-01"},
+			timeZone:    "UTC",
+			viewerId:    2,
+			expectedErr: ErrInvalidGoal,
+		},
+		{
+			testName:    "accuracy over 100",
+			goal:        structures.UserGoalReq{Metric: "accuracy", Target: 101, TargetDate: "2026-12-01"},
+			timeZone:    "UTC",
+			viewerId:    2,
+			expectedErr: ErrInvalidGoal,
+		},
+		{
+			testName:    "bad date",
+			goal:        structures.UserGoalReq{Metric: "wpm", Target: 80, TargetDate: "December"},
+			timeZone:    "UTC",
+			viewerId:    2,
+			expectedErr: ErrInvalidGoal,
+		},
+		{
+			testName:    "too many goals",
+			goal:        structures.UserGoalReq{Metric: "wpm", Target: 80, TargetDate: "2026-12-01"},
+			timeZone:    "UTC",
+			viewerId:    2,
+			goals:       structures.MAX_USER_GOALS,
+			expectedErr: ErrTooManyGoals,
+		},
+		{
+			testName:    "another user",
+			goal:        structures.UserGoalReq{Metric: "wpm", Target: 80, TargetDate: "2026-12-01"},
+			viewerId:    3,
+			expectedErr: ErrGoalsForbidden,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.testName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockGoalsProvider := mockProviders.NewMockGoalsProviderInterface(ctrl)
+			mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+			goalsService := NewGoalsService(mockGoalsProvider, mockUsersProvider)
+			goalsService.now = func() time.Time { return now }
+
+			userId := 2
+			if tt.expectedErr != ErrGoalsForbidden {
+				mockUsersProvider.EXPECT().GetUserByIdOrUsername(gomock.Any(), &userId, nil).Return(&structures.User{Id: 2, TimeZone: tt.timeZone}, nil).Times(1)
+			}
+			if tt.expectedErr == nil || tt.expectedErr == ErrTooManyGoals {
+				mockGoalsProvider.EXPECT().CountUserGoals(gomock.Any(), 2).Return(tt.goals, nil).Times(1)
+			}
+			if tt.expectedErr == nil {
+				mockGoalsProvider.EXPECT().CreateUserGoal(gomock.Any(), structures.UserGoal{UserId: 2, Metric: tt.goal.Metric, Target: tt.goal.Target, TargetDate: tt.goal.TargetDate}).DoAndReturn(
+					func(_ context.Context, goal structures.UserGoal) (*structures.UserGoal, error) {
+						goal.Id = 1
+						return &goal, nil
+					},
+				).Times(1)
+				mockGoalsProvider.EXPECT().GetGoalSamples(gomock.Any(), 2, tt.goal.Metric, structures.GOAL_REGRESSION_SCORES).Return([]*structures.GoalSample{}, nil).Times(1)
+			}
+
+			progress, err := goalsService.CreateUserGoal(context.Background(), tt.goal, 2, tt.viewerId, structures.USER_TYPE_REGULAR)
+			if err != tt.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", tt.expectedErr, err)
+			}
+			if err == nil && (progress.Id != 1 || progress.Samples != 0 || progress.ProjectedDate != "") {
+				t.Fatalf("unexpected goal progress %+v", progress)
+			}
+		})
+	}
+}
+
+func TestDeleteUserGoal(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 3, 0, 0, 0, time.UTC)
+	data := []struct {
+		testName    string
+		viewerId    int
+		viewerType  string
+		providerErr error
+		expectedErr error
+	}{
+		{testName: "own goal", viewerId: 2, viewerType: structures.USER_TYPE_REGULAR},
+		{testName: "admin", viewerId: 1, viewerType: structures.USER_TYPE_ADMIN},
+		{testName: "missing goal", viewerId: 2, viewerType: structures.USER_TYPE_REGULAR, providerErr: gorm.ErrRecordNotFound, expectedErr: gorm.ErrRecordNotFound},
+		{testName: "another user", viewerId: 3, viewerType: structures.USER_TYPE_REGULAR, expectedErr: ErrGoalsForbidden},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.testName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockGoalsProvider := mockProviders.NewMockGoalsProviderInterface(ctrl)
+			mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+			goalsService := NewGoalsService(mockGoalsProvider, mockUsersProvider)
+			goalsService.now = func() time.Time { return now }
+
+			if tt.expectedErr != ErrGoalsForbidden {
+				mockGoalsProvider.EXPECT().DeleteUserGoal(gomock.Any(), 2, 4).Return(tt.providerErr == nil, tt.providerErr).Times(1)
+			}
+
+			deleted, err := goalsService.DeleteUserGoal(context.Background(), 2, 4, tt.viewerId, tt.viewerType)
+			if err != tt.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", tt.expectedErr, err)
+			}
+			if deleted != (err == nil) {
+				t.Fatalf("unexpected result %v", deleted)
+			}
+		})
+	}
+}
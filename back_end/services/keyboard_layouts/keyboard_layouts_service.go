@@ -0,0 +1,271 @@
+package keyboard_layouts_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"type_writer_api/helpers"
+	"type_writer_api/providers/keyboard_layouts"
+	"type_writer_api/providers/texts"
+	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidLayout   = errors.New("invalid keyboard layout")
+	ErrLayoutNameTaken = errors.New("keyboard layout name already in use")
+	ErrLayoutInUse     = errors.New("keyboard layout is the preference of some users")
+)
+
+var validRows = map[string]bool{
+	structures.KEY_ROW_NUMBER: true,
+	structures.KEY_ROW_TOP:    true,
+	structures.KEY_ROW_HOME:   true,
+	structures.KEY_ROW_BOTTOM: true,
+	structures.KEY_ROW_SPACE:  true,
+}
+
+var validFingers = map[string]bool{
+	structures.FINGER_LEFT_PINKY:   true,
+	structures.FINGER_LEFT_RING:    true,
+	structures.FINGER_LEFT_MIDDLE:  true,
+	structures.FINGER_LEFT_INDEX:   true,
+	structures.FINGER_THUMB:        true,
+	structures.FINGER_RIGHT_INDEX:  true,
+	structures.FINGER_RIGHT_MIDDLE: true,
+	structures.FINGER_RIGHT_RING:   true,
+	structures.FINGER_RIGHT_PINKY:  true,
+}
+
+type KeyboardLayoutsServiceInterface interface {
+	GetKeyboardLayouts(ctx context.Context) ([]*structures.KeyboardLayout, error)
+	GetKeyboardLayoutByIdOrName(ctx context.Context, layoutId *int, name *string) (*structures.KeyboardLayout, error)
+	CreateKeyboardLayout(ctx context.Context, layoutInfo structures.KeyboardLayoutReq) (*structures.KeyboardLayout, error)
+	UpdateKeyboardLayout(ctx context.Context, layoutInfo structures.KeyboardLayoutReq, layoutId int) (*structures.KeyboardLayout, error)
+	DeleteKeyboardLayout(ctx context.Context, layoutId int) (bool, error)
+	GetTextKeyProfile(ctx context.Context, textId int, layoutName string) (*structures.KeyProfile, error)
+}
+
+type KeyboardLayoutsService struct {
+	KeyboardLayoutsProvider keyboard_layouts_provider.KeyboardLayoutsProviderInterface
+	TextsProvider           texts_provider.TextsProviderInterface
+}
+
+func normalizeLayoutName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), "-"))
+}
+
+// validateKeys makes sure every key sits on a known row under a known finger
+// and that no character can be produced by two different keys
+func validateKeys(keys []*structures.KeyPosition) error {
+	if len(keys) == 0 {
+		return ErrInvalidLayout
+	}
+
+	produced := map[string]bool{}
+	for _, key := range keys {
+		if key == nil || key.Char == "" || !validRows[key.Row] || !validFingers[key.Finger] {
+			return ErrInvalidLayout
+		}
+		for _, char := range []string{key.Char, key.ShiftChar} {
+			if char == "" {
+				continue
+			}
+			if produced[char] {
+				return ErrInvalidLayout
+			}
+			produced[char] = true
+		}
+	}
+	return nil
+}
+
+// checkLayoutName makes sure no builtin or other custom layout goes by the
+// name already, layoutId is the layout being renamed if any
+func (k *KeyboardLayoutsService) checkLayoutName(ctx context.Context, name string, layoutId int) error {
+	if _, ok := helpers.BuiltinKeyboardLayout(name); ok {
+		return ErrLayoutNameTaken
+	}
+
+	existingLayout, err := k.KeyboardLayoutsProvider.GetKeyboardLayoutByIdOrName(ctx, nil, &name)
+	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if existingLayout.Id != layoutId {
+		return ErrLayoutNameTaken
+	}
+	return nil
+}
+
+// checkLayoutUnused refuses to pull a layout from under the users who picked
+// it, their preference would point at nothing
+func (k *KeyboardLayoutsService) checkLayoutUnused(ctx context.Context, name string) error {
+	users, err := k.KeyboardLayoutsProvider.CountLayoutUsers(ctx, name)
+	if err != nil {
+		return err
+	}
+	if users != 0 {
+		return ErrLayoutInUse
+	}
+	return nil
+}
+
+func (k *KeyboardLayoutsService) GetKeyboardLayouts(ctx context.Context) ([]*structures.KeyboardLayout, error) {
+	result := helpers.BuiltinKeyboardLayouts()
+
+	layouts, err := k.KeyboardLayoutsProvider.GetKeyboardLayouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, layout := range layouts {
+		result = append(result, layout)
+	}
+
+	return result, nil
+}
+
+// GetKeyboardLayoutByIdOrName resolves builtin layouts by name before falling
+// back to the custom layouts stored in the db
+func (k *KeyboardLayoutsService) GetKeyboardLayoutByIdOrName(ctx context.Context, layoutId *int, name *string) (*structures.KeyboardLayout, error) {
+	if name != nil {
+		if builtin, ok := helpers.BuiltinKeyboardLayout(*name); ok {
+			return builtin, nil
+		}
+		normalized := normalizeLayoutName(*name)
+		name = &normalized
+	}
+
+	layout, err := k.KeyboardLayoutsProvider.GetKeyboardLayoutByIdOrName(ctx, layoutId, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := layout
+	return result, nil
+}
+
+func (k *KeyboardLayoutsService) CreateKeyboardLayout(ctx context.Context, layoutInfo structures.KeyboardLayoutReq) (*structures.KeyboardLayout, error) {
+	layoutToCreate := structures.ConvertRequestToKeyboardLayout(&layoutInfo)
+	layoutToCreate.Name = normalizeLayoutName(layoutToCreate.Name)
+
+	if layoutToCreate.Name == "" {
+		return nil, ErrInvalidLayout
+	}
+	err := validateKeys(layoutToCreate.Keys)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create keyboard layout", "error", err)
+		return nil, err
+	}
+	err = k.checkLayoutName(ctx, layoutToCreate.Name, 0)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create keyboard layout", "error", err)
+		return nil, err
+	}
+
+	createdLayout, err := k.KeyboardLayoutsProvider.CreateKeyboardLayout(ctx, *layoutToCreate)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create keyboard layout", "error", err)
+		return nil, err
+	}
+
+	result := createdLayout
+	return result, nil
+}
+
+func (k *KeyboardLayoutsService) UpdateKeyboardLayout(ctx context.Context, layoutInfo structures.KeyboardLayoutReq, layoutId int) (*structures.KeyboardLayout, error) {
+	existingLayout, err := k.KeyboardLayoutsProvider.GetKeyboardLayoutByIdOrName(ctx, &layoutId, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update keyboard layout", "error", err)
+		return nil, err
+	}
+
+	name := normalizeLayoutName(layoutInfo.Name)
+	if name != "" && name != existingLayout.Name {
+		if err := k.checkLayoutName(ctx, name, existingLayout.Id); err != nil {
+			slog.ErrorContext(ctx, "failed to update keyboard layout", "error", err)
+			return nil, err
+		}
+		if err := k.checkLayoutUnused(ctx, existingLayout.Name); err != nil {
+			slog.ErrorContext(ctx, "failed to update keyboard layout", "error", err)
+			return nil, err
+		}
+		existingLayout.Name = name
+	}
+	if layoutInfo.Description != "" {
+		existingLayout.Description = layoutInfo.Description
+	}
+	if len(layoutInfo.Keys) != 0 {
+		err := validateKeys(layoutInfo.Keys)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to update keyboard layout", "error", err)
+			return nil, err
+		}
+		existingLayout.Keys = layoutInfo.Keys
+	}
+
+	updatedLayout, err := k.KeyboardLayoutsProvider.UpdateKeyboardLayout(ctx, *existingLayout)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update keyboard layout", "error", err)
+		return nil, err
+	}
+
+	result := updatedLayout
+	return result, nil
+}
+
+func (k *KeyboardLayoutsService) DeleteKeyboardLayout(ctx context.Context, layoutId int) (bool, error) {
+	existingLayout, err := k.KeyboardLayoutsProvider.GetKeyboardLayoutByIdOrName(ctx, &layoutId, nil)
+	if err != nil {
+		return false, err
+	}
+	if err := k.checkLayoutUnused(ctx, existingLayout.Name); err != nil {
+		slog.ErrorContext(ctx, "failed to delete keyboard layout", "error", err)
+		return false, err
+	}
+
+	deleted, err := k.KeyboardLayoutsProvider.DeleteKeyboardLayout(ctx, layoutId)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete keyboard layout", "error", err)
+		return false, err
+	}
+
+	return deleted, nil
+}
+
+// GetTextKeyProfile breaks an approved text down into the physical keys and
+// fingers needed to type it on the given layout, qwerty when none is given
+func (k *KeyboardLayoutsService) GetTextKeyProfile(ctx context.Context, textId int, layoutName string) (*structures.KeyProfile, error) {
+	if layoutName == "" {
+		layoutName = structures.DEFAULT_KEYBOARD_LAYOUT
+	}
+
+	layout, err := k.GetKeyboardLayoutByIdOrName(ctx, nil, &layoutName)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := k.TextsProvider.GetTextByIdOrTitle(ctx, &textId, nil)
+	if err != nil {
+		return nil, err
+	}
+	if text.Status != structures.TEXT_STATUS_APPROVED || text.Visibility != structures.TEXT_VISIBILITY_PUBLIC {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	profile := helpers.BuildKeyProfile(layout, text.TextBody)
+	profile.TextId = text.Id
+
+	return &profile, nil
+}
+
+func NewKeyboardLayoutsService(keyboardLayoutsProvider keyboard_layouts_provider.KeyboardLayoutsProviderInterface, textsProvider texts_provider.TextsProviderInterface) *KeyboardLayoutsService {
+	return &KeyboardLayoutsService{
+		KeyboardLayoutsProvider: keyboardLayoutsProvider,
+		TextsProvider:           textsProvider,
+	}
+}
@@ -0,0 +1,299 @@
+package keyboard_layouts_service
+
+import (
+	"context"
+	"testing"
+
+	"type_writer_api/structures"
+	mockProviders "type_writer_api/testing/mocks/providers"
+
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func testKeys() []*structures.KeyPosition {
+	return []*structures.KeyPosition{
+		{Char: "a", ShiftChar: "A", Row: structures.KEY_ROW_HOME, Column: 0, Finger: structures.FINGER_LEFT_PINKY},
+		{Char: "b", ShiftChar: "B", Row: structures.KEY_ROW_HOME, Column: 1, Finger: structures.FINGER_LEFT_RING},
+		{Char: " ", Row: structures.KEY_ROW_SPACE, Column: 0, Finger: structures.FINGER_THUMB},
+	}
+}
+
+func TestGetKeyboardLayouts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	keyboardLayoutsService := NewKeyboardLayoutsService(mockKeyboardLayoutsProvider, mockTextsProvider)
+
+	mockKeyboardLayoutsProvider.EXPECT().GetKeyboardLayouts(context.Background()).Return([]*structures.KeyboardLayout{
+		{Id: 1, Name: "custom-1", Keys: testKeys()},
+	}, nil).Times(1)
+
+	result, err := keyboardLayoutsService.GetKeyboardLayouts(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expectedNames := []string{"qwerty", "dvorak", "colemak", "azerty", "qwertz", "custom-1"}
+	if len(result) != len(expectedNames) {
+		t.Fatalf("slice length missmatch: got %v, expected %v", len(result), len(expectedNames))
+	}
+	for idx, layout := range result {
+		if layout.Name != expectedNames[idx] {
+			t.Fatalf("expected layout %v at %v, got %v", expectedNames[idx], idx, layout.Name)
+		}
+		if layout.Builtin != (idx < 5) {
+			t.Fatalf("unexpected builtin flag for %v", layout.Name)
+		}
+	}
+}
+
+func TestGetKeyboardLayoutByIdOrName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	keyboardLayoutsService := NewKeyboardLayoutsService(mockKeyboardLayoutsProvider, mockTextsProvider)
+
+	builtinName := "Colemak"
+	result, err := keyboardLayoutsService.GetKeyboardLayoutByIdOrName(context.Background(), nil, &builtinName)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result.Name != "colemak" || !result.Builtin {
+		t.Fatalf("expected builtin colemak layout, got %+v", result)
+	}
+
+	customName := "My Layout"
+	normalizedName := "my-layout"
+	mockKeyboardLayoutsProvider.EXPECT().GetKeyboardLayoutByIdOrName(context.Background(), nil, &normalizedName).Return(nil, gorm.ErrRecordNotFound).Times(1)
+
+	_, err = keyboardLayoutsService.GetKeyboardLayoutByIdOrName(context.Background(), nil, &customName)
+	if err != gorm.ErrRecordNotFound {
+		t.Fatalf("expected error: %v but got %v instead", gorm.ErrRecordNotFound, err)
+	}
+}
+
+func TestCreateKeyboardLayout(t *testing.T) {
+	data := []struct {
+		testName       string
+		inputLayout    structures.KeyboardLayoutReq
+		expectLookup   bool
+		existingLayout *structures.KeyboardLayout
+		expectedInsert *structures.KeyboardLayout
+		expectedErr    error
+	}{
+		{
+			"valid custom layout",
+			structures.KeyboardLayoutReq{Name: " My  Layout ", Keys: testKeys()},
+			true,
+			nil,
+			&structures.KeyboardLayout{Name: "my-layout", Keys: testKeys()},
+			nil,
+		},
+		{
+			"builtin name",
+			structures.KeyboardLayoutReq{Name: "Dvorak", Keys: testKeys()},
+			false,
+			nil,
+			nil,
+			ErrLayoutNameTaken,
+		},
+		{
+			"custom name already taken",
+			structures.KeyboardLayoutReq{Name: "my layout", Keys: testKeys()},
+			true,
+			&structures.KeyboardLayout{Id: 2, Name: "my-layout", Keys: testKeys()},
+			nil,
+			ErrLayoutNameTaken,
+		},
+		{
+			"no keys",
+			structures.KeyboardLayoutReq{Name: "empty"},
+			false,
+			nil,
+			nil,
+			ErrInvalidLayout,
+		},
+		{
+			"unknown finger",
+			structures.KeyboardLayoutReq{Name: "bad", Keys: []*structures.KeyPosition{
+				{Char: "a", Row: structures.KEY_ROW_HOME, Finger: "left_toe"},
+			}},
+			false,
+			nil,
+			nil,
+			ErrInvalidLayout,
+		},
+		{
+			"character on two keys",
+			structures.KeyboardLayoutReq{Name: "bad", Keys: []*structures.KeyPosition{
+				{Char: "a", Row: structures.KEY_ROW_HOME, Finger: structures.FINGER_LEFT_PINKY},
+				{Char: "s", ShiftChar: "a", Row: structures.KEY_ROW_HOME, Column: 1, Finger: structures.FINGER_LEFT_RING},
+			}},
+			false,
+			nil,
+			nil,
+			ErrInvalidLayout,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	keyboardLayoutsService := NewKeyboardLayoutsService(mockKeyboardLayoutsProvider, mockTextsProvider)
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			if testCase.expectLookup {
+				name := "my-layout"
+				var lookupErr error
+				if testCase.existingLayout == nil {
+					lookupErr = gorm.ErrRecordNotFound
+				}
+				mockKeyboardLayoutsProvider.EXPECT().GetKeyboardLayoutByIdOrName(context.Background(), nil, &name).Return(testCase.existingLayout, lookupErr).Times(1)
+			}
+			if testCase.expectedInsert != nil {
+				created := *testCase.expectedInsert
+				created.Id = 1
+				mockKeyboardLayoutsProvider.EXPECT().CreateKeyboardLayout(context.Background(), *testCase.expectedInsert).Return(&created, nil).Times(1)
+			}
+
+			result, err := keyboardLayoutsService.CreateKeyboardLayout(context.Background(), testCase.inputLayout)
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+			} else if err != nil || result.Id != 1 {
+				t.Fatalf("unexpected result %+v, error %v", result, err)
+			}
+		})
+	}
+}
+
+func TestKeyboardLayoutInUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	keyboardLayoutsService := NewKeyboardLayoutsService(mockKeyboardLayoutsProvider, mockTextsProvider)
+
+	layoutId := 3
+	newName := "renamed"
+	mockKeyboardLayoutsProvider.EXPECT().GetKeyboardLayoutByIdOrName(context.Background(), &layoutId, nil).DoAndReturn(
+		func(context.Context, *int, *string) (*structures.KeyboardLayout, error) {
+			return &structures.KeyboardLayout{Id: layoutId, Name: "my-layout", Keys: testKeys()}, nil
+		},
+	).Times(3)
+	mockKeyboardLayoutsProvider.EXPECT().GetKeyboardLayoutByIdOrName(context.Background(), nil, &newName).Return(nil, gorm.ErrRecordNotFound).Times(1)
+	mockKeyboardLayoutsProvider.EXPECT().CountLayoutUsers(context.Background(), "my-layout").Return(int64(2), nil).Times(2)
+
+	_, err := keyboardLayoutsService.UpdateKeyboardLayout(context.Background(), structures.KeyboardLayoutReq{Name: "Renamed"}, layoutId)
+	if err != ErrLayoutInUse {
+		t.Fatalf("expected error: %v but got %v instead", ErrLayoutInUse, err)
+	}
+
+	_, err = keyboardLayoutsService.DeleteKeyboardLayout(context.Background(), layoutId)
+	if err != ErrLayoutInUse {
+		t.Fatalf("expected error: %v but got %v instead", ErrLayoutInUse, err)
+	}
+
+	// keeping the name leaves the users of the layout alone
+	mockKeyboardLayoutsProvider.EXPECT().UpdateKeyboardLayout(context.Background(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, layout structures.KeyboardLayout) (*structures.KeyboardLayout, error) {
+			return &layout, nil
+		},
+	).Times(1)
+
+	updated, err := keyboardLayoutsService.UpdateKeyboardLayout(context.Background(), structures.KeyboardLayoutReq{Name: "My Layout", Description: "mine"}, layoutId)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if updated.Name != "my-layout" || updated.Description != "mine" {
+		t.Fatalf("unexpected layout %+v", updated)
+	}
+}
+
+func TestGetTextKeyProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	keyboardLayoutsService := NewKeyboardLayoutsService(mockKeyboardLayoutsProvider, mockTextsProvider)
+
+	textId := 1
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &textId, nil).Return(
+		&structures.Text{Id: 1, TextBody: "Sed dj, lk€", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC}, nil,
+	).Times(2)
+
+	qwerty, err := keyboardLayoutsService.GetTextKeyProfile(context.Background(), textId, "")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if qwerty.Layout != "qwerty" || qwerty.TextId != 1 {
+		t.Fatalf("unexpected profile header %+v", qwerty)
+	}
+	if qwerty.Keystrokes != 10 || qwerty.ShiftedKeystrokes != 1 || qwerty.Unmapped != 1 {
+		t.Fatalf("unexpected keystroke counts %+v", qwerty)
+	}
+	if len(qwerty.UnmappedChars) != 1 || qwerty.UnmappedChars[0] != "€" {
+		t.Fatalf("unexpected unmapped chars %v", qwerty.UnmappedChars)
+	}
+	// s d d j l k on the home row, "e" on the top row, "," on the bottom row and two spaces
+	if qwerty.Rows[structures.KEY_ROW_HOME] != 6 || qwerty.Rows[structures.KEY_ROW_TOP] != 1 || qwerty.Rows[structures.KEY_ROW_BOTTOM] != 1 || qwerty.Rows[structures.KEY_ROW_SPACE] != 2 {
+		t.Fatalf("unexpected row counts %v", qwerty.Rows)
+	}
+	if qwerty.Fingers[structures.FINGER_LEFT_MIDDLE] != 3 || qwerty.Fingers[structures.FINGER_THUMB] != 2 {
+		t.Fatalf("unexpected finger counts %v", qwerty.Fingers)
+	}
+	// "dj" switches hands and "ed" is typed with the left middle finger twice
+	if qwerty.HandAlternations != 1 || qwerty.SameFingerBigrams != 1 {
+		t.Fatalf("unexpected transitions alternations=%v same finger=%v", qwerty.HandAlternations, qwerty.SameFingerBigrams)
+	}
+
+	dvorak, err := keyboardLayoutsService.GetTextKeyProfile(context.Background(), textId, "dvorak")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if dvorak.Rows[structures.KEY_ROW_HOME] == qwerty.Rows[structures.KEY_ROW_HOME] {
+		t.Fatalf("expected dvorak to spread the text differently, got %v", dvorak.Rows)
+	}
+}
+
+func TestGetTextKeyProfileHidesUnapprovedTexts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	keyboardLayoutsService := NewKeyboardLayoutsService(mockKeyboardLayoutsProvider, mockTextsProvider)
+
+	textId := 2
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &textId, nil).Return(
+		&structures.Text{Id: 2, TextBody: "pending text", Status: structures.TEXT_STATUS_PENDING, Visibility: structures.TEXT_VISIBILITY_PUBLIC}, nil,
+	).Times(1)
+
+	_, err := keyboardLayoutsService.GetTextKeyProfile(context.Background(), textId, "qwerty")
+	if err != gorm.ErrRecordNotFound {
+		t.Fatalf("expected error: %v but got %v instead", gorm.ErrRecordNotFound, err)
+	}
+
+	privateTextId := 3
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &privateTextId, nil).Return(
+		&structures.Text{Id: 3, TextBody: "private text", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PRIVATE}, nil,
+	).Times(1)
+
+	_, err = keyboardLayoutsService.GetTextKeyProfile(context.Background(), privateTextId, "qwerty")
+	if err != gorm.ErrRecordNotFound {
+		t.Fatalf("expected error: %v but got %v instead", gorm.ErrRecordNotFound, err)
+	}
+}
@@ -0,0 +1,143 @@
+package leaderboards_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+	"type_writer_api/providers/leaderboards"
+	"type_writer_api/structures"
+)
+
+var (
+	ErrInvalidMetric = errors.New("invalid leaderboard metric")
+	ErrInvalidWindow = errors.New("invalid leaderboard window")
+	ErrInvalidPage   = errors.New("invalid leaderboard page")
+)
+
+type LeaderboardsServiceInterface interface {
+	GetLeaderboard(ctx context.Context, query structures.LeaderboardQuery) (*structures.Leaderboard, error)
+	GetUserRank(ctx context.Context, query structures.LeaderboardQuery, userId int) (*structures.LeaderboardEntry, error)
+	GetAroundUser(ctx context.Context, query structures.LeaderboardQuery, userId int, radius int) (*structures.Leaderboard, error)
+}
+
+type LeaderboardsService struct {
+	LeaderboardsProvider leaderboards_provider.LeaderboardsProviderInterface
+	now                  func() time.Time
+}
+
+// normalizeQuery fills in the defaults of a query and works out where its
+// window starts
+func (l *LeaderboardsService) normalizeQuery(query structures.LeaderboardQuery) (structures.LeaderboardQuery, error) {
+	if query.Metric == "" {
+		query.Metric = structures.DEFAULT_LEADERBOARD_METRIC
+	}
+	if !slices.Contains(structures.LEADERBOARD_METRICS, query.Metric) {
+		return query, ErrInvalidMetric
+	}
+
+	if query.Window == "" {
+		query.Window = structures.LEADERBOARD_WINDOW_ALL
+	}
+	window, ok := structures.LEADERBOARD_WINDOWS[query.Window]
+	if !ok {
+		return query, ErrInvalidWindow
+	}
+	query.Since = time.Time{}
+	if window > 0 {
+		query.Since = l.now().Add(-window)
+	}
+
+	if query.Limit == 0 {
+		query.Limit = structures.DEFAULT_LEADERBOARD_LIMIT
+	}
+	if query.Limit < 0 || query.Limit > structures.MAX_LEADERBOARD_LIMIT || query.Offset < 0 {
+		return query, ErrInvalidPage
+	}
+	return query, nil
+}
+
+func newLeaderboard(query structures.LeaderboardQuery, entries []*structures.LeaderboardEntry) *structures.Leaderboard {
+	return &structures.Leaderboard{
+		ActivityId: query.ActivityId,
+		TextId:     query.TextId,
+		Window:     query.Window,
+		Metric:     query.Metric,
+		Entries:    entries,
+	}
+}
+
+// GetLeaderboard ranks the best score of every user, flagged and rejected
+// scores are left out
+func (l *LeaderboardsService) GetLeaderboard(ctx context.Context, query structures.LeaderboardQuery) (*structures.Leaderboard, error) {
+	query, err := l.normalizeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := l.LeaderboardsProvider.GetLeaderboard(ctx, query)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get leaderboard", "error", err)
+		return nil, err
+	}
+
+	result := newLeaderboard(query, entries)
+	return result, nil
+}
+
+func (l *LeaderboardsService) GetUserRank(ctx context.Context, query structures.LeaderboardQuery, userId int) (*structures.LeaderboardEntry, error) {
+	query, err := l.normalizeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := l.LeaderboardsProvider.GetLeaderboardEntry(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := entry
+	return result, nil
+}
+
+// GetAroundUser lists the users ranked right above and below the user along
+// with the user, radius is how many of each. The limit and offset of the
+// query are replaced
+func (l *LeaderboardsService) GetAroundUser(ctx context.Context, query structures.LeaderboardQuery, userId int, radius int) (*structures.Leaderboard, error) {
+	if radius == 0 {
+		radius = structures.DEFAULT_LEADERBOARD_RADIUS
+	}
+	if radius < 0 || radius > structures.MAX_LEADERBOARD_RADIUS {
+		return nil, ErrInvalidPage
+	}
+	query.Limit, query.Offset = 0, 0
+	query, err := l.normalizeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := l.LeaderboardsProvider.GetLeaderboardEntry(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// ranks are positions, so the rows around the user are a page
+	query.Offset = max(0, entry.Rank-1-radius)
+	query.Limit = entry.Rank + radius - query.Offset
+	entries, err := l.LeaderboardsProvider.GetLeaderboard(ctx, query)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get leaderboard around user", "error", err)
+		return nil, err
+	}
+
+	result := newLeaderboard(query, entries)
+	return result, nil
+}
+
+func NewLeaderboardsService(leaderboardsProvider leaderboards_provider.LeaderboardsProviderInterface) *LeaderboardsService {
+	return &LeaderboardsService{
+		LeaderboardsProvider: leaderboardsProvider,
+		now:                  time.Now,
+	}
+}
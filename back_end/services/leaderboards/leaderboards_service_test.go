@@ -0,0 +1,190 @@
+package leaderboards_service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"type_writer_api/helpers"
+	"type_writer_api/structures"
+	mockProviders "type_writer_api/testing/mocks/providers"
+
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestGetLeaderboard(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	data := []struct {
+		testName      string
+		query         structures.LeaderboardQuery
+		expectedQuery *structures.LeaderboardQuery
+		expectedErr   error
+	}{
+		{
+			testName:      "defaults",
+			query:         structures.LeaderboardQuery{ActivityId: 1},
+			expectedQuery: &structures.LeaderboardQuery{ActivityId: 1, Window: structures.LEADERBOARD_WINDOW_ALL, Metric: structures.DEFAULT_LEADERBOARD_METRIC, Limit: structures.DEFAULT_LEADERBOARD_LIMIT},
+		},
+		{
+			testName:      "rolling window",
+			query:         structures.LeaderboardQuery{TextId: 2, Window: structures.LEADERBOARD_WINDOW_WEEK, Metric: "accuracy", Limit: 10, Offset: 20},
+			expectedQuery: &structures.LeaderboardQuery{TextId: 2, Window: structures.LEADERBOARD_WINDOW_WEEK, Metric: "accuracy", Since: now.Add(-7 * 24 * time.Hour), Limit: 10, Offset: 20},
+		},
+		{
+			testName:    "unknown metric",
+			query:       structures.LeaderboardQuery{Metric: "errors"},
+			expectedErr: ErrInvalidMetric,
+		},
+		{
+			testName:    "unknown window",
+			query:       structures.LeaderboardQuery{Window: "year"},
+			expectedErr: ErrInvalidWindow,
+		},
+		{
+			testName:    "limit too large",
+			query:       structures.LeaderboardQuery{Limit: structures.MAX_LEADERBOARD_LIMIT + 1},
+			expectedErr: ErrInvalidPage,
+		},
+		{
+			testName:    "negative offset",
+			query:       structures.LeaderboardQuery{Offset: -1},
+			expectedErr: ErrInvalidPage,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.testName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockLeaderboardsProvider := mockProviders.NewMockLeaderboardsProviderInterface(ctrl)
+			leaderboardsService := NewLeaderboardsService(mockLeaderboardsProvider)
+			leaderboardsService.now = func() time.Time { return now }
+
+			entries := []*structures.LeaderboardEntry{{Rank: 1, UserId: 2, Value: 90}}
+			if tt.expectedQuery != nil {
+				mockLeaderboardsProvider.EXPECT().GetLeaderboard(gomock.Any(), *tt.expectedQuery).Return(entries, nil)
+			}
+
+			leaderboard, err := leaderboardsService.GetLeaderboard(context.Background(), tt.query)
+			if err != tt.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			expected := &structures.Leaderboard{
+				ActivityId: tt.expectedQuery.ActivityId,
+				TextId:     tt.expectedQuery.TextId,
+				Window:     tt.expectedQuery.Window,
+				Metric:     tt.expectedQuery.Metric,
+				Entries:    entries,
+			}
+			if err := helpers.CompareReflectedStructFields(*leaderboard, *expected); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestGetUserRank(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	data := []struct {
+		testName    string
+		providerErr error
+		expectedErr error
+	}{
+		{testName: "ranked"},
+		{testName: "not ranked", providerErr: gorm.ErrRecordNotFound, expectedErr: gorm.ErrRecordNotFound},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.testName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockLeaderboardsProvider := mockProviders.NewMockLeaderboardsProviderInterface(ctrl)
+			leaderboardsService := NewLeaderboardsService(mockLeaderboardsProvider)
+			leaderboardsService.now = func() time.Time { return now }
+
+			expectedQuery := structures.LeaderboardQuery{Window: structures.LEADERBOARD_WINDOW_ALL, Metric: structures.DEFAULT_LEADERBOARD_METRIC, Limit: structures.DEFAULT_LEADERBOARD_LIMIT}
+			entry := &structures.LeaderboardEntry{Rank: 3, UserId: 2, Value: 80}
+			if tt.providerErr != nil {
+				entry = nil
+			}
+			mockLeaderboardsProvider.EXPECT().GetLeaderboardEntry(gomock.Any(), expectedQuery, 2).Return(entry, tt.providerErr)
+
+			result, err := leaderboardsService.GetUserRank(context.Background(), structures.LeaderboardQuery{}, 2)
+			if err != tt.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", tt.expectedErr, err)
+			}
+			if err == nil && result != entry {
+				t.Fatalf("expected entry: %v but got %v instead", entry, result)
+			}
+		})
+	}
+}
+
+func TestGetAroundUser(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	data := []struct {
+		testName       string
+		rank           int
+		radius         int
+		expectedOffset int
+		expectedLimit  int
+		expectedErr    error
+	}{
+		{
+			testName:       "middle of the board",
+			rank:           20,
+			radius:         3,
+			expectedOffset: 16,
+			expectedLimit:  7,
+		},
+		{
+			testName:       "near the top",
+			rank:           2,
+			radius:         3,
+			expectedOffset: 0,
+			expectedLimit:  5,
+		},
+		{
+			testName:       "default radius",
+			rank:           10,
+			expectedOffset: 10 - 1 - structures.DEFAULT_LEADERBOARD_RADIUS,
+			expectedLimit:  2*structures.DEFAULT_LEADERBOARD_RADIUS + 1,
+		},
+		{
+			testName:    "radius too large",
+			radius:      structures.MAX_LEADERBOARD_RADIUS + 1,
+			expectedErr: ErrInvalidPage,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.testName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockLeaderboardsProvider := mockProviders.NewMockLeaderboardsProviderInterface(ctrl)
+			leaderboardsService := NewLeaderboardsService(mockLeaderboardsProvider)
+			leaderboardsService.now = func() time.Time { return now }
+
+			entries := []*structures.LeaderboardEntry{{Rank: tt.rank, UserId: 2}}
+			if tt.expectedErr == nil {
+				query := structures.LeaderboardQuery{ActivityId: 1, Window: structures.LEADERBOARD_WINDOW_ALL, Metric: structures.DEFAULT_LEADERBOARD_METRIC, Limit: structures.DEFAULT_LEADERBOARD_LIMIT}
+				mockLeaderboardsProvider.EXPECT().GetLeaderboardEntry(gomock.Any(), query, 2).Return(&structures.LeaderboardEntry{Rank: tt.rank, UserId: 2}, nil)
+				query.Offset = tt.expectedOffset
+				query.Limit = tt.expectedLimit
+				mockLeaderboardsProvider.EXPECT().GetLeaderboard(gomock.Any(), query).Return(entries, nil)
+			}
+
+			leaderboard, err := leaderboardsService.GetAroundUser(context.Background(), structures.LeaderboardQuery{ActivityId: 1, Limit: 7, Offset: 3}, 2, tt.radius)
+			if err != tt.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", tt.expectedErr, err)
+			}
+			if err == nil && len(leaderboard.Entries) != len(entries) {
+				t.Fatalf("expected entries: %v but got %v instead", entries, leaderboard.Entries)
+			}
+		})
+	}
+}
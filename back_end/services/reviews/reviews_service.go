@@ -0,0 +1,112 @@
+package reviews_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+	"type_writer_api/helpers"
+	"type_writer_api/providers/reviews"
+	"type_writer_api/providers/texts"
+	"type_writer_api/providers/users"
+	"type_writer_api/services/texts"
+	"type_writer_api/structures"
+)
+
+var (
+	ErrReviewDeckForbidden = errors.New("review deck belongs to another user")
+	ErrNoDueWords          = errors.New("no words due for review")
+)
+
+type ReviewsServiceInterface interface {
+	CreateReviewDrill(ctx context.Context, userId int, viewerId int, viewerType string) (*structures.ReviewDrill, error)
+}
+
+type ReviewsService struct {
+	ReviewsProvider reviews_provider.ReviewsProviderInterface
+	UsersProvider   users_provider.UsersProviderInterface
+	TextsProvider   texts_provider.TextsProviderInterface
+	now             func() time.Time
+}
+
+// CreateReviewDrill writes the private drill of a user out of their words due
+// for review, the ones overdue the longest first, over the drill made before.
+// Typing the drill grades them like any other score does
+func (r *ReviewsService) CreateReviewDrill(ctx context.Context, userId int, viewerId int, viewerType string) (*structures.ReviewDrill, error) {
+	if userId != viewerId && viewerType != structures.USER_TYPE_ADMIN {
+		return nil, ErrReviewDeckForbidden
+	}
+
+	_, err := r.UsersProvider.GetUserByIdOrUsername(ctx, &userId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	due, err := r.ReviewsProvider.GetDueReviewWords(ctx, userId, r.now(), structures.MAX_REVIEW_DRILL_WORDS)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get due review words", "error", err)
+		return nil, err
+	}
+	if len(due) == 0 {
+		return nil, ErrNoDueWords
+	}
+	words := make([]string, 0, len(due))
+	for _, reviewWord := range due {
+		words = append(words, reviewWord.Word)
+	}
+
+	body := helpers.BuildWordDrill(words)
+	drill := structures.Text{
+		TextType:    structures.TEXT_TYPE_DRILL,
+		Title:       structures.REVIEW_DRILL_TITLE,
+		Difficulty:  "easy",
+		Language:    helpers.DEFAULT_LANGUAGE,
+		TextBody:    body,
+		TextLength:  len(body),
+		Status:      structures.TEXT_STATUS_APPROVED,
+		SubmitterId: &userId,
+		OwnerId:     &userId,
+		Visibility:  structures.TEXT_VISIBILITY_PRIVATE,
+		Fingerprint: helpers.TextFingerprint(body),
+	}
+	text, err := texts_service.SaveDrillText(ctx, r.TextsProvider, drill)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create review drill", "error", err)
+		return nil, err
+	}
+
+	result := &structures.ReviewDrill{Words: words, Text: text}
+	return result, nil
+}
+
+// UpdateReviewDeck grades the words typed on a text against the review deck of
+// the user, both stored scores and finished sessions feed the deck through it
+func UpdateReviewDeck(ctx context.Context, reviewsProvider reviews_provider.ReviewsProviderInterface, userId int, text *structures.Text, events []*structures.KeystrokeEvent, now time.Time) error {
+	typed := helpers.TypedWords(text.TextBody, events)
+	if userId == 0 || len(typed) == 0 {
+		return nil
+	}
+
+	deck, err := reviewsProvider.GetReviewWords(ctx, userId, slices.Sorted(maps.Keys(typed)))
+	if err != nil {
+		return err
+	}
+	reviewed := helpers.GradeReviewWords(userId, typed, deck, now)
+	if len(reviewed) == 0 {
+		return nil
+	}
+
+	_, err = reviewsProvider.SaveReviewWords(ctx, reviewed)
+	return err
+}
+
+func NewReviewsService(reviewsProvider reviews_provider.ReviewsProviderInterface, usersProvider users_provider.UsersProviderInterface, textsProvider texts_provider.TextsProviderInterface) *ReviewsService {
+	return &ReviewsService{
+		ReviewsProvider: reviewsProvider,
+		UsersProvider:   usersProvider,
+		TextsProvider:   textsProvider,
+		now:             time.Now,
+	}
+}
@@ -0,0 +1,155 @@
+package reviews_service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"type_writer_api/helpers"
+	"type_writer_api/structures"
+	mockProviders "type_writer_api/testing/mocks/providers"
+
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestCreateReviewDrill(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	data := []struct {
+		testName      string
+		viewerId      int
+		viewerType    string
+		userErr       error
+		due           []*structures.ReviewWord
+		existingDrill *structures.Text
+		expectedWords []string
+		expectedErr   error
+	}{
+		{
+			testName:      "due words",
+			due:           []*structures.ReviewWord{{UserId: 2, Word: "which", Misses: 2}, {UserId: 2, Word: "there", Misses: 4}},
+			expectedWords: []string{"which", "there"},
+		},
+		{
+			testName:      "previous drill rewritten",
+			due:           []*structures.ReviewWord{{UserId: 2, Word: "which", Misses: 2}},
+			existingDrill: &structures.Text{Id: 7, TextBody: "there there"},
+			expectedWords: []string{"which"},
+		},
+		{
+			testName:      "admin drilling another user",
+			viewerId:      1,
+			viewerType:    structures.USER_TYPE_ADMIN,
+			due:           []*structures.ReviewWord{{UserId: 2, Word: "there", Misses: 4}},
+			expectedWords: []string{"there"},
+		},
+		{
+			testName:    "nothing due",
+			due:         []*structures.ReviewWord{},
+			expectedErr: ErrNoDueWords,
+		},
+		{
+			testName:    "another user",
+			viewerId:    3,
+			viewerType:  structures.USER_TYPE_REGULAR,
+			expectedErr: ErrReviewDeckForbidden,
+		},
+		{
+			testName:    "unknown user",
+			userErr:     gorm.ErrRecordNotFound,
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.testName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+			mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+			mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+			reviewsService := NewReviewsService(mockReviewsProvider, mockUsersProvider, mockTextsProvider)
+			reviewsService.now = func() time.Time { return now }
+
+			if tt.expectedErr != ErrReviewDeckForbidden {
+				userId := 2
+				mockUsersProvider.EXPECT().GetUserByIdOrUsername(gomock.Any(), &userId, nil).Return(&structures.User{Id: 2}, tt.userErr)
+			}
+			if tt.due != nil {
+				mockReviewsProvider.EXPECT().GetDueReviewWords(gomock.Any(), 2, now, structures.MAX_REVIEW_DRILL_WORDS).Return(tt.due, nil)
+			}
+			if tt.expectedWords != nil {
+				saveDrill := func(ctx context.Context, text structures.Text) (*structures.Text, error) {
+					if text.OwnerId == nil || *text.OwnerId != 2 || text.Visibility != structures.TEXT_VISIBILITY_PRIVATE || text.TextType != structures.TEXT_TYPE_DRILL {
+						t.Fatalf("expected a private drill owned by user 2 but got %+v instead", text)
+					}
+					if text.TextLength != len(text.TextBody) || !slices.Equal(text.Fingerprint, helpers.TextFingerprint(text.TextBody)) {
+						t.Fatalf("expected the length and fingerprint of the drill body but got %+v instead", text)
+					}
+					text.Id = 7
+					return &text, nil
+				}
+				if tt.existingDrill != nil {
+					mockTextsProvider.EXPECT().GetDrillText(gomock.Any(), 2, structures.REVIEW_DRILL_TITLE).Return(tt.existingDrill, nil)
+					mockTextsProvider.EXPECT().UpdateText(gomock.Any(), gomock.Any()).DoAndReturn(saveDrill)
+				} else {
+					mockTextsProvider.EXPECT().GetDrillText(gomock.Any(), 2, structures.REVIEW_DRILL_TITLE).Return(nil, gorm.ErrRecordNotFound)
+					mockTextsProvider.EXPECT().CreateText(gomock.Any(), gomock.Any()).DoAndReturn(saveDrill)
+				}
+			}
+
+			viewerId, viewerType := 2, structures.USER_TYPE_REGULAR
+			if tt.viewerId != 0 {
+				viewerId, viewerType = tt.viewerId, tt.viewerType
+			}
+
+			drill, err := reviewsService.CreateReviewDrill(context.Background(), 2, viewerId, viewerType)
+			if err != tt.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if !slices.Equal(drill.Words, tt.expectedWords) {
+				t.Fatalf("expected words: %v but got %v instead", tt.expectedWords, drill.Words)
+			}
+			words := strings.Fields(drill.Text.TextBody)
+			if drill.Text.Id != 7 || len(words) != structures.REVIEW_DRILL_WORDS || words[0] != tt.expectedWords[0] {
+				t.Fatalf("expected the drill to repeat the due words but got %q instead", drill.Text.TextBody)
+			}
+		})
+	}
+}
+
+func TestUpdateReviewDeck(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+
+	text := &structures.Text{Id: 1, TextBody: "hello world"}
+	events := []*structures.KeystrokeEvent{}
+	for indx, char := range "hello " {
+		events = append(events, &structures.KeystrokeEvent{Key: string(char), Offset: indx * 100, Expected: string(char), Correct: true})
+	}
+
+	// anonymous scores have no deck to grade
+	err := UpdateReviewDeck(context.Background(), mockReviewsProvider, 0, text, events, now)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	mockReviewsProvider.EXPECT().GetReviewWords(context.Background(), 1, []string{"hello"}).
+		Return([]*structures.ReviewWord{{UserId: 1, Word: "hello", Misses: 2, EaseFactor: 2.5, DueAt: now.Add(-time.Hour)}}, nil).Times(1)
+	mockReviewsProvider.EXPECT().SaveReviewWords(context.Background(), []*structures.ReviewWord{
+		{UserId: 1, Word: "hello", Misses: 2, Repetitions: 1, EaseFactor: 2.5, IntervalDays: 1, DueAt: now.AddDate(0, 0, 1), LastReviewedAt: &now},
+	}).Return(true, nil).Times(1)
+
+	err = UpdateReviewDeck(context.Background(), mockReviewsProvider, 1, text, events, now)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -2,9 +2,30 @@ package scores_service
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"math"
+	"time"
+	"type_writer_api/engines"
+	"type_writer_api/helpers"
+	"type_writer_api/providers/achievements"
+	"type_writer_api/providers/activities"
+	"type_writer_api/providers/reviews"
 	"type_writer_api/providers/scores"
+	"type_writer_api/providers/texts"
+	"type_writer_api/services/achievements"
+	"type_writer_api/services/reviews"
+	"type_writer_api/services/texts"
 	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrKeystrokesForbidden = errors.New("keystroke log belongs to another user")
+	ErrUnknownActivityKind = errors.New("unknown activity kind")
+	ErrResultMismatch      = errors.New("result does not match the keystrokes")
+	ErrScoreNotFlagged     = errors.New("score is not flagged for review")
 )
 
 type ScoresServiceInterface interface {
@@ -13,16 +34,118 @@ type ScoresServiceInterface interface {
 	CreateScore(ctx context.Context, scoreInfo structures.ScoreReq) (*structures.Score, error)
 	UpdateScore(ctx context.Context, scoreInfo structures.ScoreReq, scoreId int) (*structures.Score, error)
 	DeleteScore(ctx context.Context, scoreId int) (bool, error)
+	GetScoreKeystrokes(ctx context.Context, scoreId int, viewerId int, viewerType string) (*structures.ScoreKeystrokeLog, error)
+	PurgeKeystrokeLogs(ctx context.Context) (int64, error)
+	GetFlaggedScores(ctx context.Context) ([]*structures.Score, error)
+	ReviewScore(ctx context.Context, scoreId int, approved bool) (*structures.Score, error)
 }
 
 type ScoresService struct {
-	ScoresProvider scores_provider.ScoresProviderInterface
+	ScoresProvider     scores_provider.ScoresProviderInterface
+	TextsProvider      texts_provider.TextsProviderInterface
+	ActivitiesProvider activities_provider.ActivitiesProviderInterface
+	ReviewsProvider    reviews_provider.ReviewsProviderInterface
+	// badges are awarded by these rules, no rules award nothing
+	AchievementsProvider achievements_provider.AchievementsProviderInterface
+	AchievementRules     *engines.AchievementRules
+	// keystroke logs older than this are purged, zero keeps them forever
+	KeystrokeLogRetention time.Duration
+	now                   func() time.Time
+}
+
+// scoreFinal runs the result of a score through the scoring formula of its
+// activity, a formula that cannot score the result falls back to the default
+// one
+func (a *ScoresService) scoreFinal(ctx context.Context, score *structures.Score) error {
+	activity, err := a.ActivitiesProvider.GetActivityByIdOrName(ctx, &score.ActivityId, nil)
+	if err != nil {
+		return err
+	}
+
+	finalScore, err := engines.FinalScore(activity.ScoringFormula, engines.ResultMetrics(score.Result, score.Duration))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to evaluate scoring formula", "activity_id", activity.Id, "error", err)
+	}
+	score.FinalScore = finalScore
+	return nil
 }
 
+// verifyScore replays the submitted keystrokes against the text of the score,
+// the recomputed result replaces the claimed one when there was a claim to
+// check and the keystroke log is rebuilt from the replay. The timing still
+// comes from the client, so these scores are always flagged as unverified
+// for review, along with anything no person could type
+func (a *ScoresService) verifyScore(ctx context.Context, score *structures.Score, submitted []*structures.KeystrokeEvent, claimed bool) error {
+	if len(submitted) == 0 {
+		score.ReviewStatus = structures.SCORE_REVIEW_FLAGGED
+		score.FlagReasons = []string{structures.SCORE_FLAG_UNVERIFIED}
+		return nil
+	}
+	if len(submitted) > structures.MAX_KEYSTROKE_LOG_EVENTS {
+		return helpers.ErrKeystrokeLogTooLarge
+	}
+
+	activity, err := a.ActivitiesProvider.GetActivityByIdOrName(ctx, &score.ActivityId, nil)
+	if err != nil {
+		return err
+	}
+	text, err := a.TextsProvider.GetTextByIdOrTitle(ctx, &score.TextId, nil)
+	if err != nil {
+		return err
+	}
+	engine, ok := engines.ByKind(activity.Kind)
+	if !ok {
+		return ErrUnknownActivityKind
+	}
+
+	keystrokes := make([]*structures.Keystroke, 0, len(submitted))
+	for _, event := range submitted {
+		if event == nil || event.Key == "" {
+			return engines.ErrInvalidKeystrokes
+		}
+		keystrokes = append(keystrokes, &structures.Keystroke{Key: event.Key, Offset: event.Offset})
+	}
+	input := structures.ActivityInput{
+		TextType:   text.TextType,
+		TextBody:   text.TextBody,
+		Keystrokes: keystrokes,
+	}
+	activityResult, err := engine.ComputeResult(activity.Rules, input)
+	if err != nil {
+		return err
+	}
+	if claimed && !engines.ResultMatches(score.Result, activityResult) {
+		return ErrResultMismatch
+	}
+	extra := score.Result.Extra
+	score.Result = engines.ScoreResult(activityResult)
+	score.Result.Extra = extra
+	if score.Duration == 0 {
+		score.Duration = max(1, int(math.Round(float64(activityResult.Duration)/1000)))
+	}
+
+	events, err := engines.KeystrokeEvents(activity.Rules, input)
+	if err != nil {
+		return err
+	}
+	score.KeystrokeLog, err = helpers.NewKeystrokeLog(events)
+	if err != nil {
+		return err
+	}
+	score.KeystrokeLog.Events = events
+
+	// only a finished session makes a score clean
+	score.ReviewStatus = structures.SCORE_REVIEW_FLAGGED
+	score.FlagReasons = append([]string{structures.SCORE_FLAG_UNVERIFIED}, engines.CheckPlausibility(keystrokes)...)
+	return nil
+}
+
+// GetScores lists the ranked scores, flagged and rejected ones are only listed
+// for moderation
 func (a *ScoresService) GetScores(ctx context.Context) ([]*structures.Score, error) {
 	var results []*structures.Score
 
-	scores, err := a.ScoresProvider.GetScores(ctx)
+	scores, err := a.ScoresProvider.GetScores(ctx, structures.ScoreFilter{ReviewStatuses: structures.RANKED_SCORE_REVIEWS})
 	if err != nil {
 		return nil, err
 	}
@@ -31,6 +154,10 @@ func (a *ScoresService) GetScores(ctx context.Context) ([]*structures.Score, err
 		results = append(results, score)
 	}
 
+	if err := texts_service.AttachAttributions(ctx, a.TextsProvider, results...); err != nil {
+		return nil, err
+	}
+
 	return results, nil
 }
 
@@ -39,19 +166,82 @@ func (a *ScoresService) GetScoreById(ctx context.Context, scoreId int) (*structu
 	if err != nil {
 		return nil, err
 	}
+	if err := texts_service.AttachAttributions(ctx, a.TextsProvider, score); err != nil {
+		return nil, err
+	}
 
 	result := score
 	return result, nil
 }
 
+// updateReviewDeck looks up the text of a score to grade its words against
+// the review deck of its user
+func (a *ScoresService) updateReviewDeck(ctx context.Context, score *structures.Score, events []*structures.KeystrokeEvent) error {
+	if score.UserId == 0 {
+		return nil
+	}
+	text, err := a.TextsProvider.GetTextByIdOrTitle(ctx, &score.TextId, nil)
+	if err != nil {
+		return err
+	}
+	return reviews_service.UpdateReviewDeck(ctx, a.ReviewsProvider, score.UserId, text, events, a.now())
+}
+
+// scoreAchievements awards the badges of a score, the text is only looked up
+// when there are badges the score could earn
+func (a *ScoresService) scoreAchievements(ctx context.Context, score *structures.Score) error {
+	if !achievements_service.ScoreEarnsBadges(a.AchievementRules, score) {
+		return nil
+	}
+	text, err := a.TextsProvider.GetTextByIdOrTitle(ctx, &score.TextId, nil)
+	if err != nil {
+		return err
+	}
+	return achievements_service.AwardScoreAchievements(ctx, a.AchievementsProvider, a.AchievementRules, score, text, a.now())
+}
+
 func (a *ScoresService) CreateScore(ctx context.Context, scoreInfo structures.ScoreReq) (*structures.Score, error) {
 	scoreToCreate := structures.ConvertRequestToScore(&scoreInfo)
 
+	var err error
+	scoreToCreate.Result, err = helpers.ParseScoreResult(scoreInfo.Result)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create score", "error", err)
+		return nil, err
+	}
+
+	err = a.verifyScore(ctx, scoreToCreate, scoreInfo.Keystrokes, len(scoreInfo.Result) != 0)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create score", "error", err)
+		return nil, err
+	}
+
+	err = a.scoreFinal(ctx, scoreToCreate)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create score", "error", err)
+		return nil, err
+	}
+
 	createdScore, err := a.ScoresProvider.CreateScore(ctx, *scoreToCreate)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to create score", "error", err)
 		return nil, err
 	}
+	if err := texts_service.AttachAttributions(ctx, a.TextsProvider, createdScore); err != nil {
+		slog.ErrorContext(ctx, "failed to create score", "error", err)
+		return nil, err
+	}
+
+	// the score is stored by now, a deck that could not be updated catches up
+	// with the next score instead of failing this one
+	if scoreToCreate.KeystrokeLog != nil {
+		if err := a.updateReviewDeck(ctx, createdScore, scoreToCreate.KeystrokeLog.Events); err != nil {
+			slog.ErrorContext(ctx, "failed to update review deck", "error", err, "score_id", createdScore.Id)
+		}
+	}
+	if err := a.scoreAchievements(ctx, createdScore); err != nil {
+		slog.ErrorContext(ctx, "failed to award achievements", "error", err, "score_id", createdScore.Id)
+	}
 
 	result := createdScore
 	return result, nil
@@ -69,7 +259,16 @@ func (a *ScoresService) UpdateScore(ctx context.Context, scoreInfo structures.Sc
 		existingScore.Duration = scoreInfo.Duration
 	}
 	if len(scoreInfo.Result) != 0 {
-		existingScore.Result = scoreInfo.Result
+		existingScore.Result, err = helpers.ParseScoreResult(scoreInfo.Result)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to update score", "error", err)
+			return nil, err
+		}
+	}
+	err = a.scoreFinal(ctx, existingScore)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update score", "error", err)
+		return nil, err
 	}
 
 	updatedScore, err := a.ScoresProvider.UpdateScore(ctx, *existingScore)
@@ -77,6 +276,10 @@ func (a *ScoresService) UpdateScore(ctx context.Context, scoreInfo structures.Sc
 		slog.ErrorContext(ctx, "failed to update score", "error", err)
 		return nil, err
 	}
+	if err := texts_service.AttachAttributions(ctx, a.TextsProvider, updatedScore); err != nil {
+		slog.ErrorContext(ctx, "failed to update score", "error", err)
+		return nil, err
+	}
 
 	result := updatedScore
 	return result, nil
@@ -91,8 +294,113 @@ func (t *ScoresService) DeleteScore(ctx context.Context, scoreId int) (bool, err
 	return deleted, nil
 }
 
-func NewScoresService(scoresProvider scores_provider.ScoresProviderInterface) *ScoresService {
+// expiredBefore is the creation time under which keystroke logs are past
+// their retention, the zero time when they are kept forever
+func (t *ScoresService) expiredBefore() time.Time {
+	if t.KeystrokeLogRetention <= 0 {
+		return time.Time{}
+	}
+	return t.now().Add(-t.KeystrokeLogRetention)
+}
+
+// GetScoreKeystrokes decodes the keystroke log of a score for its owner or an
+// admin, logs past their retention are gone even before the next purge
+func (t *ScoresService) GetScoreKeystrokes(ctx context.Context, scoreId int, viewerId int, viewerType string) (*structures.ScoreKeystrokeLog, error) {
+	score, err := t.ScoresProvider.GetScoreById(ctx, scoreId)
+	if err != nil {
+		return nil, err
+	}
+	if score.UserId != viewerId && viewerType != structures.USER_TYPE_ADMIN {
+		return nil, ErrKeystrokesForbidden
+	}
+
+	keystrokeLog, err := t.ScoresProvider.GetScoreKeystrokes(ctx, scoreId)
+	if err != nil {
+		return nil, err
+	}
+	if keystrokeLog.CreatedAt.Before(t.expiredBefore()) {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	keystrokeLog.Events, err = helpers.DecodeKeystrokeLog(keystrokeLog.Data)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode keystroke log", "score_id", scoreId, "error", err)
+		return nil, err
+	}
+
+	result := keystrokeLog
+	return result, nil
+}
+
+func (t *ScoresService) PurgeKeystrokeLogs(ctx context.Context) (int64, error) {
+	before := t.expiredBefore()
+	if before.IsZero() {
+		return 0, nil
+	}
+
+	purged, err := t.ScoresProvider.DeleteScoreKeystrokesBefore(ctx, before)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to purge keystroke logs", "error", err)
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+// GetFlaggedScores lists the scores waiting for a review
+func (t *ScoresService) GetFlaggedScores(ctx context.Context) ([]*structures.Score, error) {
+	scores, err := t.ScoresProvider.GetScores(ctx, structures.ScoreFilter{ReviewStatuses: []string{structures.SCORE_REVIEW_FLAGGED}})
+	if err != nil {
+		return nil, err
+	}
+	if err := texts_service.AttachAttributions(ctx, t.TextsProvider, scores...); err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
+
+// ReviewScore settles a flagged score, approved scores are ranked like any
+// clean one and rejected scores are kept out of rankings for good
+func (t *ScoresService) ReviewScore(ctx context.Context, scoreId int, approved bool) (*structures.Score, error) {
+	score, err := t.ScoresProvider.GetScoreById(ctx, scoreId)
+	if err != nil {
+		return nil, err
+	}
+	if score.ReviewStatus != structures.SCORE_REVIEW_FLAGGED {
+		return nil, ErrScoreNotFlagged
+	}
+
+	score.ReviewStatus = structures.SCORE_REVIEW_REJECTED
+	if approved {
+		score.ReviewStatus = structures.SCORE_REVIEW_APPROVED
+	}
+	reviewedAt := t.now()
+	score.ReviewedAt = &reviewedAt
+
+	reviewedScore, err := t.ScoresProvider.UpdateScore(ctx, *score)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to review score", "error", err)
+		return nil, err
+	}
+	// approved scores earn badges the same way clean ones did when created
+	if err := t.scoreAchievements(ctx, reviewedScore); err != nil {
+		slog.ErrorContext(ctx, "failed to award achievements", "error", err, "score_id", reviewedScore.Id)
+	}
+
+	result := reviewedScore
+	return result, nil
+}
+
+func NewScoresService(scoresProvider scores_provider.ScoresProviderInterface, textsProvider texts_provider.TextsProviderInterface, activitiesProvider activities_provider.ActivitiesProviderInterface, reviewsProvider reviews_provider.ReviewsProviderInterface, achievementsProvider achievements_provider.AchievementsProviderInterface, achievementRules *engines.AchievementRules, keystrokeLogRetention time.Duration) *ScoresService {
 	return &ScoresService{
-		ScoresProvider: scoresProvider,
+		ScoresProvider:        scoresProvider,
+		TextsProvider:         textsProvider,
+		ActivitiesProvider:    activitiesProvider,
+		ReviewsProvider:       reviewsProvider,
+		AchievementsProvider:  achievementsProvider,
+		AchievementRules:      achievementRules,
+		KeystrokeLogRetention: keystrokeLogRetention,
+		now:                   time.Now,
 	}
 }
@@ -2,9 +2,11 @@ package scores_service
 
 import (
 	"context"
+	"slices"
 	"testing"
 	"time"
 
+	"type_writer_api/engines"
 	"type_writer_api/helpers"
 	"type_writer_api/structures"
 	mockProviders "type_writer_api/testing/mocks/providers"
@@ -16,12 +18,12 @@ import (
 func TestGetScores(t *testing.T) {
 	var (
 		mockResult1 = []*structures.Score{
-			{1, 1, 1, 1, 60, map[string]any{ "wpm": 300, "errors": 300 }, time.Now(), time.Now()},
-			{2, 1, 1, 1, 60, map[string]any{ "wpm": 300, "errors": 300 }, time.Now(), time.Now()},
+			{Id: 1, UserId: 1, ActivityId: 1, TextId: 1, Duration: 60, Result: structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300}, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			{Id: 2, UserId: 1, ActivityId: 1, TextId: 1, Duration: 60, Result: structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300}, CreatedAt: time.Now(), UpdatedAt: time.Now()},
 		}
 		expectedResult1 = []*structures.Score{
-			{1, 1, 1, 1, 60, map[string]any{ "wpm": 300, "errors": 300 }, time.Now(), time.Now()},
-			{2, 1, 1, 1, 60, map[string]any{ "wpm": 300, "errors": 300 }, time.Now(), time.Now()},
+			{Id: 1, UserId: 1, ActivityId: 1, TextId: 1, Duration: 60, Result: structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300}, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			{Id: 2, UserId: 1, ActivityId: 1, TextId: 1, Duration: 60, Result: structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300}, CreatedAt: time.Now(), UpdatedAt: time.Now()},
 		}
 		mockResult2 = []*structures.Score{}
 		expectedResult2 = []*structures.Score{}
@@ -53,11 +55,16 @@ func TestGetScores(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
-	scoresService := NewScoresService(mockScoresProvider)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+	scoresService := NewScoresService(mockScoresProvider, mockTextsProvider, mockActivitiesProvider, mockReviewsProvider, nil, nil, 0)
+
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), gomock.Any(), nil).Return(&structures.Text{Id: 1}, nil).AnyTimes()
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
-			mockScoresProvider.EXPECT().GetScores(context.Background()).Return(testCase.mockResult, testCase.mockErr).Times(1)
+			mockScoresProvider.EXPECT().GetScores(context.Background(), structures.ScoreFilter{ReviewStatuses: structures.RANKED_SCORE_REVIEWS}).Return(testCase.mockResult, testCase.mockErr).Times(1)
 
 			result, err := scoresService.GetScores(context.Background())
 
@@ -92,9 +99,9 @@ func TestGetScoreById(t *testing.T) {
 		{
 			"valid id",
 			1,
-			&structures.Score{1, 1, 1, 1, 60, map[string]any{ "wpm": 300, "errors": 300 }, time.Now(), time.Now()},
+			&structures.Score{Id: 1, UserId: 1, ActivityId: 1, TextId: 1, Duration: 60, Result: structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300}, CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.Score{1, 1, 1, 1, 60, map[string]any{ "wpm": 300, "errors": 300 }, time.Now(), time.Now()},
+			&structures.Score{Id: 1, UserId: 1, ActivityId: 1, TextId: 1, Duration: 60, Result: structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300}, CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
 		{
@@ -111,7 +118,12 @@ func TestGetScoreById(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
-	scoresService := NewScoresService(mockScoresProvider)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+	scoresService := NewScoresService(mockScoresProvider, mockTextsProvider, mockActivitiesProvider, mockReviewsProvider, nil, nil, 0)
+
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), gomock.Any(), nil).Return(&structures.Text{Id: 1}, nil).AnyTimes()
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
@@ -143,10 +155,10 @@ func TestCreateScore(t *testing.T) {
 	}{
 		{
 			"valid input score request",
-			structures.ScoreReq{1, 1, 1, 60, map[string]any{ "wpm": 300, "errors": 300 }},
-			&structures.Score{1, 1, 1, 1, 60, map[string]any{ "wpm": 300, "errors": 300 }, time.Now(), time.Now()},
+			structures.ScoreReq{UserId: 1, ActivityId: 1, TextId: 1, Duration: 60, Result: map[string]any{ "wpm": 300, "errors": 300 }},
+			&structures.Score{Id: 1, UserId: 1, ActivityId: 1, TextId: 1, Duration: 60, Result: structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300}, FinalScore: 300, CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.Score{1, 1, 1, 1, 60, map[string]any{ "wpm": 300, "errors": 300 }, time.Now(), time.Now()},
+			&structures.Score{Id: 1, UserId: 1, ActivityId: 1, TextId: 1, Duration: 60, Result: structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300}, FinalScore: 300, CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
 	}
@@ -155,11 +167,22 @@ func TestCreateScore(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
-	scoresService := NewScoresService(mockScoresProvider)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+	scoresService := NewScoresService(mockScoresProvider, mockTextsProvider, mockActivitiesProvider, mockReviewsProvider, nil, nil, 0)
+
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), gomock.Any(), nil).Return(&structures.Text{Id: 1}, nil).AnyTimes()
+	mockActivitiesProvider.EXPECT().GetActivityByIdOrName(context.Background(), gomock.Any(), nil).Return(&structures.Activity{Id: 1}, nil).AnyTimes()
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
-			mockScoresProvider.EXPECT().CreateScore(context.Background(), *structures.ConvertRequestToScore(&testCase.inputScore)).Return(testCase.mockResult, testCase.mockErr).Times(1)
+			scoreToCreate := *structures.ConvertRequestToScore(&testCase.inputScore)
+			scoreToCreate.Result = testCase.expectedResult.Result
+			scoreToCreate.FinalScore = testCase.expectedResult.FinalScore
+			scoreToCreate.ReviewStatus = structures.SCORE_REVIEW_FLAGGED
+			scoreToCreate.FlagReasons = []string{structures.SCORE_FLAG_UNVERIFIED}
+			mockScoresProvider.EXPECT().CreateScore(context.Background(), scoreToCreate).Return(testCase.mockResult, testCase.mockErr).Times(1)
 
 			result, err := scoresService.CreateScore(context.Background(), testCase.inputScore)
 
@@ -189,12 +212,12 @@ func TestUpdateScore(t *testing.T) {
 	}{
 		{
 			"valid input score request",
-			structures.ScoreReq{1, 1, 1, 60, map[string]any{ "wpm": 300, "errors": 300 }},
+			structures.ScoreReq{UserId: 1, ActivityId: 1, TextId: 1, Duration: 60, Result: map[string]any{ "wpm": 300, "errors": 300 }},
 			1,
-			&structures.Score{1, 1, 1, 1, 60, map[string]any{ "wpm": 300, "errors": 300 }, time.Now(), time.Now()},
-			&structures.Score{1, 1, 1, 1, 60, map[string]any{ "wpm": 300, "errors": 300 }, time.Now(), time.Now()},
+			&structures.Score{Id: 1, UserId: 1, ActivityId: 1, TextId: 1, Duration: 60, Result: structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300}, FinalScore: 300, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			&structures.Score{Id: 1, UserId: 1, ActivityId: 1, TextId: 1, Duration: 60, Result: structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300}, FinalScore: 300, CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.Score{1, 1, 1, 1, 60, map[string]any{ "wpm": 300, "errors": 300 }, time.Now(), time.Now()},
+			&structures.Score{Id: 1, UserId: 1, ActivityId: 1, TextId: 1, Duration: 60, Result: structures.ScoreResult{Version: 1, Wpm: 300, Errors: 300}, FinalScore: 300, CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
 	}
@@ -203,7 +226,13 @@ func TestUpdateScore(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
-	scoresService := NewScoresService(mockScoresProvider)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+	scoresService := NewScoresService(mockScoresProvider, mockTextsProvider, mockActivitiesProvider, mockReviewsProvider, nil, nil, 0)
+
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), gomock.Any(), nil).Return(&structures.Text{Id: 1}, nil).AnyTimes()
+	mockActivitiesProvider.EXPECT().GetActivityByIdOrName(context.Background(), gomock.Any(), nil).Return(&structures.Activity{Id: 1}, nil).AnyTimes()
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
@@ -259,7 +288,12 @@ func TestDeleteActivity(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
-	scoresService := NewScoresService(mockScoresProvider)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+	scoresService := NewScoresService(mockScoresProvider, mockTextsProvider, mockActivitiesProvider, mockReviewsProvider, nil, nil, 0)
+
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), gomock.Any(), nil).Return(&structures.Text{Id: 1}, nil).AnyTimes()
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
@@ -279,3 +313,742 @@ func TestDeleteActivity(t *testing.T) {
 		})
 	}
 }
+
+func TestScoreAttribution(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+	scoresService := NewScoresService(mockScoresProvider, mockTextsProvider, mockActivitiesProvider, mockReviewsProvider, nil, nil, 0)
+
+	mockScoresProvider.EXPECT().GetScores(context.Background(), structures.ScoreFilter{ReviewStatuses: structures.RANKED_SCORE_REVIEWS}).Return([]*structures.Score{
+		{Id: 1, UserId: 1, ActivityId: 1, TextId: 1},
+		{Id: 2, UserId: 1, ActivityId: 1, TextId: 2},
+		{Id: 3, UserId: 2, ActivityId: 1, TextId: 1},
+		{Id: 4, UserId: 2, ActivityId: 1, TextId: 3},
+	}, nil).Times(1)
+
+	gutenbergText, freeText, missingText := 1, 2, 3
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &gutenbergText, nil).Return(&structures.Text{
+		Id: 1, Title: "chapter one", Author: "Jane Austen", Source: "Pride and Prejudice", SourceUrl: "https://www.gutenberg.org/ebooks/1342", License: "gutenberg", AttributionRequired: true,
+	}, nil).Times(1)
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &freeText, nil).Return(&structures.Text{
+		Id: 2, Title: "home row drill", License: "public-domain",
+	}, nil).Times(1)
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &missingText, nil).Return(nil, gorm.ErrRecordNotFound).Times(1)
+
+	result, err := scoresService.GetScores(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := "Pride and Prejudice by Jane Austen (https://www.gutenberg.org/ebooks/1342), Project Gutenberg License"
+	expectedAttributions := []string{expected, "", expected, ""}
+	for idx, score := range result {
+		if score.Attribution != expectedAttributions[idx] {
+			t.Fatalf("unexpected attribution for score %v: got %q, expected %q", score.Id, score.Attribution, expectedAttributions[idx])
+		}
+	}
+}
+
+func TestScoreFinalScore(t *testing.T) {
+	data := []struct {
+		testName           string
+		formula            string
+		result             map[string]any
+		expectedFinalScore float64
+	}{
+		{
+			testName:           "default formula scores wpm",
+			result:             map[string]any{"wpm": 72.5, "accuracy": 90},
+			expectedFinalScore: 72.5,
+		},
+		{
+			testName:           "wpm times accuracy squared",
+			formula:            "wpm * (accuracy / 100) ** 2",
+			result:             map[string]any{"wpm": 100, "accuracy": 90.0},
+			expectedFinalScore: 81,
+		},
+		{
+			testName:           "penalty for uncorrected errors",
+			formula:            "max(0, wpm - (errors - corrected) * 2)",
+			result:             map[string]any{"wpm": 60, "errors": 5, "corrected": 2},
+			expectedFinalScore: 54,
+		},
+		{
+			testName:           "missing metrics count as zero",
+			formula:            "wpm - errors",
+			result:             map[string]any{"wpm": 60},
+			expectedFinalScore: 60,
+		},
+		{
+			testName:           "formula that cannot score the result",
+			formula:            "wpm / errors",
+			result:             map[string]any{"wpm": 60, "errors": 0},
+			expectedFinalScore: 60,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+	scoresService := NewScoresService(mockScoresProvider, mockTextsProvider, mockActivitiesProvider, mockReviewsProvider, nil, nil, 0)
+
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), gomock.Any(), nil).Return(&structures.Text{Id: 1}, nil).AnyTimes()
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			mockActivitiesProvider.EXPECT().GetActivityByIdOrName(context.Background(), gomock.Any(), nil).Return(&structures.Activity{Id: 1, ScoringFormula: testCase.formula}, nil).Times(1)
+			mockScoresProvider.EXPECT().CreateScore(context.Background(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, score structures.Score) (*structures.Score, error) {
+					score.Id = 1
+					return &score, nil
+				},
+			).Times(1)
+
+			result, err := scoresService.CreateScore(context.Background(), structures.ScoreReq{UserId: 1, ActivityId: 1, TextId: 1, Duration: 60, Result: testCase.result})
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if result.FinalScore != testCase.expectedFinalScore {
+				t.Fatalf("unexpected final score: got %v, expected %v", result.FinalScore, testCase.expectedFinalScore)
+			}
+		})
+	}
+
+	missingActivity := 99
+	mockActivitiesProvider.EXPECT().GetActivityByIdOrName(context.Background(), &missingActivity, nil).Return(nil, gorm.ErrRecordNotFound).Times(1)
+
+	_, err := scoresService.CreateScore(context.Background(), structures.ScoreReq{UserId: 1, ActivityId: missingActivity, TextId: 1, Result: map[string]any{"wpm": 60}})
+	if err != gorm.ErrRecordNotFound {
+		t.Fatalf("expected error: %v but got %v instead", gorm.ErrRecordNotFound, err)
+	}
+}
+
+func TestCreateScoreKeystrokeLog(t *testing.T) {
+	tooMany := make([]*structures.KeystrokeEvent, structures.MAX_KEYSTROKE_LOG_EVENTS+1)
+	for indx := range tooMany {
+		tooMany[indx] = &structures.KeystrokeEvent{Key: "a", Offset: indx}
+	}
+	typed := []*structures.KeystrokeEvent{
+		{Key: "h", Offset: 0}, {Key: "u", Offset: 150}, {Key: "Backspace", Offset: 300}, {Key: "e", Offset: 450},
+		{Key: "l", Offset: 600}, {Key: "l", Offset: 750}, {Key: "o", Offset: 900}, {Key: " ", Offset: 1050},
+		{Key: "w", Offset: 1200}, {Key: "o", Offset: 1350}, {Key: "r", Offset: 1500}, {Key: "l", Offset: 1650},
+		{Key: "d", Offset: 1800},
+	}
+	pasted := []*structures.KeystrokeEvent{}
+	for indx, char := range "hello world" {
+		pasted = append(pasted, &structures.KeystrokeEvent{Key: string(char), Offset: indx})
+	}
+
+	data := []struct {
+		testName        string
+		keystrokes      []*structures.KeystrokeEvent
+		result          map[string]any
+		expectedEvents  []*structures.KeystrokeEvent
+		expectedReview  string
+		expectedReasons []string
+		expectedErr     error
+	}{
+		{
+			testName:   "log rebuilt from the replay but still unverified",
+			keystrokes: typed,
+			result:     map[string]any{"wpm": 73, "accuracy": 92, "errors": 1},
+			expectedEvents: []*structures.KeystrokeEvent{
+				{Key: "h", Offset: 0, Expected: "h", Correct: true}, {Key: "u", Offset: 150, Expected: "e"},
+				{Key: "Backspace", Offset: 300, Backspace: true}, {Key: "e", Offset: 450, Expected: "e", Correct: true},
+				{Key: "l", Offset: 600, Expected: "l", Correct: true}, {Key: "l", Offset: 750, Expected: "l", Correct: true},
+				{Key: "o", Offset: 900, Expected: "o", Correct: true}, {Key: " ", Offset: 1050, Expected: " ", Correct: true},
+				{Key: "w", Offset: 1200, Expected: "w", Correct: true}, {Key: "o", Offset: 1350, Expected: "o", Correct: true},
+				{Key: "r", Offset: 1500, Expected: "r", Correct: true}, {Key: "l", Offset: 1650, Expected: "l", Correct: true},
+				{Key: "d", Offset: 1800, Expected: "d", Correct: true},
+			},
+			expectedReview:  structures.SCORE_REVIEW_FLAGGED,
+			expectedReasons: []string{structures.SCORE_FLAG_UNVERIFIED},
+		},
+		{
+			testName:        "score without a log",
+			result:          map[string]any{"wpm": 60},
+			expectedReview:  structures.SCORE_REVIEW_FLAGGED,
+			expectedReasons: []string{structures.SCORE_FLAG_UNVERIFIED},
+		},
+		{
+			testName:        "pasted text",
+			keystrokes:      pasted,
+			expectedReview:  structures.SCORE_REVIEW_FLAGGED,
+			expectedReasons: []string{structures.SCORE_FLAG_UNVERIFIED, structures.SCORE_FLAG_KEY_INTERVALS, structures.SCORE_FLAG_PASTE_BURST},
+		},
+		{
+			testName:    "claimed result disagrees",
+			keystrokes:  typed,
+			result:      map[string]any{"wpm": 300},
+			expectedErr: ErrResultMismatch,
+		},
+		{
+			testName:    "log over the event limit",
+			keystrokes:  tooMany,
+			expectedErr: helpers.ErrKeystrokeLogTooLarge,
+		},
+		{
+			testName:    "event without a key",
+			keystrokes:  []*structures.KeystrokeEvent{{Offset: 10}},
+			expectedErr: engines.ErrInvalidKeystrokes,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+	scoresService := NewScoresService(mockScoresProvider, mockTextsProvider, mockActivitiesProvider, mockReviewsProvider, nil, nil, 0)
+
+	activity := &structures.Activity{Id: 1, Kind: structures.ACTIVITY_KIND_ZEN, Rules: structures.ActivityRules{MustCorrectErrors: true}}
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), gomock.Any(), nil).Return(&structures.Text{Id: 1, TextBody: "hello world"}, nil).AnyTimes()
+	mockActivitiesProvider.EXPECT().GetActivityByIdOrName(context.Background(), gomock.Any(), nil).Return(activity, nil).AnyTimes()
+	mockReviewsProvider.EXPECT().GetReviewWords(context.Background(), 1, []string{"hello", "world"}).Return([]*structures.ReviewWord{}, nil).AnyTimes()
+	mockReviewsProvider.EXPECT().SaveReviewWords(context.Background(), gomock.Any()).Return(true, nil).AnyTimes()
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			var stored structures.Score
+			if testCase.expectedErr == nil {
+				mockScoresProvider.EXPECT().CreateScore(context.Background(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, score structures.Score) (*structures.Score, error) {
+						stored = score
+						score.Id = 1
+						return &score, nil
+					},
+				).Times(1)
+			}
+
+			_, err := scoresService.CreateScore(context.Background(), structures.ScoreReq{UserId: 1, ActivityId: 1, TextId: 1, Result: testCase.result, Keystrokes: testCase.keystrokes})
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if stored.ReviewStatus != testCase.expectedReview || !slices.Equal(stored.FlagReasons, testCase.expectedReasons) {
+				t.Fatalf("unexpected review %v %v", stored.ReviewStatus, stored.FlagReasons)
+			}
+			if testCase.keystrokes == nil {
+				if stored.KeystrokeLog != nil {
+					t.Fatalf("expected no keystroke log, got %+v", stored.KeystrokeLog)
+				}
+				return
+			}
+			if stored.KeystrokeLog == nil || stored.KeystrokeLog.Encoding != structures.KEYSTROKE_LOG_ENCODING || stored.Duration < 1 {
+				t.Fatalf("unexpected keystroke log %+v", stored.KeystrokeLog)
+			}
+			if testCase.expectedEvents == nil {
+				return
+			}
+			events, err := helpers.DecodeKeystrokeLog(stored.KeystrokeLog.Data)
+			if err != nil {
+				t.Fatalf("unexpected error decoding the log %v", err)
+			}
+			if len(events) != len(testCase.expectedEvents) {
+				t.Fatalf("expected %v events, got %v", len(testCase.expectedEvents), len(events))
+			}
+			for indx, event := range events {
+				if *event != *testCase.expectedEvents[indx] {
+					t.Fatalf("event %v: expected %+v, got %+v", indx, testCase.expectedEvents[indx], event)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateScoreReviewDeck(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	missedHello := []*structures.KeystrokeEvent{
+		{Key: "h", Offset: 0}, {Key: "u", Offset: 150}, {Key: "Backspace", Offset: 300}, {Key: "e", Offset: 450},
+		{Key: "l", Offset: 600}, {Key: "l", Offset: 750}, {Key: "o", Offset: 900}, {Key: " ", Offset: 1050},
+		{Key: "w", Offset: 1200}, {Key: "o", Offset: 1350}, {Key: "r", Offset: 1500}, {Key: "l", Offset: 1650},
+		{Key: "d", Offset: 1800},
+	}
+	clean := []*structures.KeystrokeEvent{}
+	for indx, char := range "hello world" {
+		clean = append(clean, &structures.KeystrokeEvent{Key: string(char), Offset: indx*150 + indx%3*20})
+	}
+
+	data := []struct {
+		testName      string
+		keystrokes    []*structures.KeystrokeEvent
+		deck          []*structures.ReviewWord
+		saveErr       error
+		expectedSaved []*structures.ReviewWord
+	}{
+		{
+			testName:   "missed word joins the deck",
+			keystrokes: missedHello,
+			expectedSaved: []*structures.ReviewWord{
+				{UserId: 1, Word: "hello", Misses: 1, EaseFactor: 2.18, IntervalDays: 1, DueAt: now.AddDate(0, 0, 1)},
+			},
+		},
+		{
+			testName:   "missed word starts over and due word moves along",
+			keystrokes: missedHello,
+			deck: []*structures.ReviewWord{
+				{UserId: 1, Word: "hello", Misses: 2, Repetitions: 2, EaseFactor: 2.5, IntervalDays: 6, DueAt: now.AddDate(0, 0, 3)},
+				{UserId: 1, Word: "world", Misses: 3, Repetitions: 2, EaseFactor: 2.5, IntervalDays: 6, DueAt: now.Add(-time.Hour)},
+			},
+			expectedSaved: []*structures.ReviewWord{
+				{UserId: 1, Word: "hello", Misses: 3, EaseFactor: 2.18, IntervalDays: 1, DueAt: now.AddDate(0, 0, 1)},
+				{UserId: 1, Word: "world", Misses: 3, Repetitions: 3, EaseFactor: 2.5, IntervalDays: 15, DueAt: now.AddDate(0, 0, 15)},
+			},
+		},
+		{
+			testName:   "clean words not due yet are left alone",
+			keystrokes: clean,
+			deck: []*structures.ReviewWord{
+				{UserId: 1, Word: "world", Misses: 3, Repetitions: 1, EaseFactor: 2.5, IntervalDays: 1, DueAt: now.AddDate(0, 0, 1)},
+			},
+		},
+		{
+			testName:   "mastered on the fifth clean review",
+			keystrokes: clean,
+			deck: []*structures.ReviewWord{
+				{UserId: 1, Word: "hello", Misses: 2, Repetitions: 4, EaseFactor: 2.5, IntervalDays: 15, DueAt: now},
+			},
+			expectedSaved: []*structures.ReviewWord{
+				{UserId: 1, Word: "hello", Misses: 2, Repetitions: 5, EaseFactor: 2.5, IntervalDays: 38, Mastered: true, DueAt: now.AddDate(0, 0, 38)},
+			},
+		},
+		{
+			testName:   "deck failing to save keeps the score",
+			keystrokes: missedHello,
+			saveErr:    gorm.ErrInvalidDB,
+			expectedSaved: []*structures.ReviewWord{
+				{UserId: 1, Word: "hello", Misses: 1, EaseFactor: 2.18, IntervalDays: 1, DueAt: now.AddDate(0, 0, 1)},
+			},
+		},
+	}
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+			mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+			mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+			mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+			scoresService := NewScoresService(mockScoresProvider, mockTextsProvider, mockActivitiesProvider, mockReviewsProvider, nil, nil, 0)
+			scoresService.now = func() time.Time { return now }
+
+			activity := &structures.Activity{Id: 1, Kind: structures.ACTIVITY_KIND_ZEN, Rules: structures.ActivityRules{MustCorrectErrors: true}}
+			mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), gomock.Any(), nil).Return(&structures.Text{Id: 1, TextBody: "hello world"}, nil).AnyTimes()
+			mockActivitiesProvider.EXPECT().GetActivityByIdOrName(context.Background(), gomock.Any(), nil).Return(activity, nil).AnyTimes()
+			mockScoresProvider.EXPECT().CreateScore(context.Background(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, score structures.Score) (*structures.Score, error) {
+					score.Id = 1
+					return &score, nil
+				},
+			).Times(1)
+			mockReviewsProvider.EXPECT().GetReviewWords(context.Background(), 1, []string{"hello", "world"}).Return(testCase.deck, nil).Times(1)
+
+			var saved []*structures.ReviewWord
+			if testCase.expectedSaved != nil {
+				mockReviewsProvider.EXPECT().SaveReviewWords(context.Background(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, words []*structures.ReviewWord) (bool, error) {
+						saved = words
+						return testCase.saveErr == nil, testCase.saveErr
+					},
+				).Times(1)
+			}
+
+			_, err := scoresService.CreateScore(context.Background(), structures.ScoreReq{UserId: 1, ActivityId: 1, TextId: 1, Keystrokes: testCase.keystrokes})
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if len(saved) != len(testCase.expectedSaved) {
+				t.Fatalf("expected %d saved words but got %d instead", len(testCase.expectedSaved), len(saved))
+			}
+			for indx, expected := range testCase.expectedSaved {
+				got := *saved[indx]
+				if !got.DueAt.Equal(expected.DueAt) || got.LastReviewedAt == nil || !got.LastReviewedAt.Equal(now) {
+					t.Fatalf("unexpected schedule for %q: due %v, reviewed %v", expected.Word, got.DueAt, got.LastReviewedAt)
+				}
+				got.LastReviewedAt = nil
+				if err := helpers.CompareReflectedStructFields(got, *expected); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestGetScoreKeystrokes(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	events := []*structures.KeystrokeEvent{{Key: "a", Offset: 0, Expected: "a", Correct: true}}
+	data, err := helpers.EncodeKeystrokeLog(events)
+	if err != nil {
+		t.Fatalf("unexpected error encoding the log %v", err)
+	}
+
+	testCases := []struct {
+		testName    string
+		viewerId    int
+		viewerType  string
+		storedAt    time.Time
+		expectedErr error
+	}{
+		{
+			testName:   "owner",
+			viewerId:   1,
+			viewerType: structures.USER_TYPE_REGULAR,
+			storedAt:   now.Add(-24 * time.Hour),
+		},
+		{
+			testName:   "admin",
+			viewerId:   2,
+			viewerType: structures.USER_TYPE_ADMIN,
+			storedAt:   now.Add(-24 * time.Hour),
+		},
+		{
+			testName:    "another user",
+			viewerId:    2,
+			viewerType:  structures.USER_TYPE_REGULAR,
+			expectedErr: ErrKeystrokesForbidden,
+		},
+		{
+			testName:    "past its retention",
+			viewerId:    1,
+			viewerType:  structures.USER_TYPE_REGULAR,
+			storedAt:    now.Add(-31 * 24 * time.Hour),
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+	scoresService := NewScoresService(mockScoresProvider, mockTextsProvider, mockActivitiesProvider, mockReviewsProvider, nil, nil, 30*24*time.Hour)
+	scoresService.now = func() time.Time { return now }
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			mockScoresProvider.EXPECT().GetScoreById(context.Background(), 1).Return(&structures.Score{Id: 1, UserId: 1}, nil).Times(1)
+			if testCase.expectedErr != ErrKeystrokesForbidden {
+				mockScoresProvider.EXPECT().GetScoreKeystrokes(context.Background(), 1).Return(&structures.ScoreKeystrokeLog{
+					ScoreId:    1,
+					Encoding:   structures.KEYSTROKE_LOG_ENCODING,
+					EventCount: 1,
+					Data:       data,
+					CreatedAt:  testCase.storedAt,
+				}, nil).Times(1)
+			}
+
+			result, err := scoresService.GetScoreKeystrokes(context.Background(), 1, testCase.viewerId, testCase.viewerType)
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if len(result.Events) != 1 || *result.Events[0] != *events[0] {
+				t.Fatalf("unexpected events %+v", result.Events)
+			}
+		})
+	}
+}
+
+func TestPurgeKeystrokeLogs(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+
+	// without a retention nothing is ever purged
+	keepForever := NewScoresService(mockScoresProvider, mockTextsProvider, mockActivitiesProvider, mockReviewsProvider, nil, nil, 0)
+	purged, err := keepForever.PurgeKeystrokeLogs(context.Background())
+	if err != nil || purged != 0 {
+		t.Fatalf("unexpected purge %v, %v", purged, err)
+	}
+
+	scoresService := NewScoresService(mockScoresProvider, mockTextsProvider, mockActivitiesProvider, mockReviewsProvider, nil, nil, 30*24*time.Hour)
+	scoresService.now = func() time.Time { return now }
+	mockScoresProvider.EXPECT().DeleteScoreKeystrokesBefore(context.Background(), now.Add(-30*24*time.Hour)).Return(int64(3), nil).Times(1)
+
+	purged, err = scoresService.PurgeKeystrokeLogs(context.Background())
+	if err != nil || purged != 3 {
+		t.Fatalf("unexpected purge %v, %v", purged, err)
+	}
+}
+
+func TestReviewScore(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+
+	data := []struct {
+		testName       string
+		approved       bool
+		mockScore      *structures.Score
+		mockErr        error
+		expectedStatus string
+		expectedErr    error
+	}{
+		{
+			testName:       "flagged score approved",
+			approved:       true,
+			mockScore:      &structures.Score{Id: 1, ReviewStatus: structures.SCORE_REVIEW_FLAGGED, FlagReasons: []string{structures.SCORE_FLAG_PASTE_BURST}},
+			expectedStatus: structures.SCORE_REVIEW_APPROVED,
+		},
+		{
+			testName:       "flagged score rejected",
+			mockScore:      &structures.Score{Id: 1, ReviewStatus: structures.SCORE_REVIEW_FLAGGED, FlagReasons: []string{structures.SCORE_FLAG_UNVERIFIED}},
+			expectedStatus: structures.SCORE_REVIEW_REJECTED,
+		},
+		{
+			testName:    "clean score",
+			approved:    true,
+			mockScore:   &structures.Score{Id: 1, ReviewStatus: structures.SCORE_REVIEW_CLEAN},
+			expectedErr: ErrScoreNotFlagged,
+		},
+		{
+			testName:    "missing score",
+			mockErr:     gorm.ErrRecordNotFound,
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+	scoresService := NewScoresService(mockScoresProvider, mockTextsProvider, mockActivitiesProvider, mockReviewsProvider, nil, nil, 0)
+	scoresService.now = func() time.Time { return now }
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			mockScoresProvider.EXPECT().GetScoreById(context.Background(), 1).Return(testCase.mockScore, testCase.mockErr).Times(1)
+			if testCase.expectedErr == nil {
+				mockScoresProvider.EXPECT().UpdateScore(context.Background(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, score structures.Score) (*structures.Score, error) {
+						return &score, nil
+					},
+				).Times(1)
+			}
+
+			result, err := scoresService.ReviewScore(context.Background(), 1, testCase.approved)
+
+			if testCase.expectedErr != nil {
+				if err != testCase.expectedErr {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if result.ReviewStatus != testCase.expectedStatus || result.ReviewedAt == nil || !result.ReviewedAt.Equal(now) {
+				t.Fatalf("unexpected review %v at %v", result.ReviewStatus, result.ReviewedAt)
+			}
+			if !slices.Equal(result.FlagReasons, testCase.mockScore.FlagReasons) {
+				t.Fatalf("expected flag reasons %v to be kept, got %v", testCase.mockScore.FlagReasons, result.FlagReasons)
+			}
+		})
+	}
+}
+
+func TestReviewScoreAchievements(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	rules, err := engines.NewAchievementRules([]*structures.Badge{
+		{Id: "first_60_wpm", Name: "Sixty", Condition: "wpm >= 60"},
+		{Id: "7_day_streak", Name: "Week streak", Condition: "streak_days >= 7"},
+		{Id: "flawless_hard_text", Name: "Flawless", Condition: "accuracy == 100 && difficulty == 3"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	week := []string{}
+	for day := range 7 {
+		week = append(week, now.AddDate(0, 0, -day).Format(time.DateOnly))
+	}
+
+	data := []struct {
+		testName        string
+		approved        bool
+		days            []string
+		earned          []*structures.UserAchievement
+		expectedAwarded []string
+	}{
+		{
+			testName:        "approved score earns badges",
+			approved:        true,
+			days:            week,
+			expectedAwarded: []string{"first_60_wpm", "7_day_streak", "flawless_hard_text"},
+		},
+		{
+			testName:        "badges already earned are not awarded again",
+			approved:        true,
+			days:            week[:3],
+			earned:          []*structures.UserAchievement{{UserId: 2, BadgeId: "first_60_wpm"}},
+			expectedAwarded: []string{"flawless_hard_text"},
+		},
+		{
+			testName: "rejected score earns nothing",
+		},
+	}
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+			mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+			mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+			mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+			mockAchievementsProvider := mockProviders.NewMockAchievementsProviderInterface(ctrl)
+			scoresService := NewScoresService(mockScoresProvider, mockTextsProvider, mockActivitiesProvider, mockReviewsProvider, mockAchievementsProvider, rules, 0)
+			scoresService.now = func() time.Time { return now }
+
+			score := &structures.Score{Id: 5, UserId: 2, TextId: 3, ReviewStatus: structures.SCORE_REVIEW_FLAGGED, Result: structures.ScoreResult{Wpm: 72, Accuracy: 100}, CreatedAt: now}
+			mockScoresProvider.EXPECT().GetScoreById(context.Background(), 5).Return(score, nil).Times(1)
+			mockScoresProvider.EXPECT().UpdateScore(context.Background(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, score structures.Score) (*structures.Score, error) {
+					return &score, nil
+				},
+			).Times(1)
+
+			var awarded []*structures.UserAchievement
+			if testCase.approved {
+				mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), gomock.Any(), nil).Return(&structures.Text{Id: 3, Difficulty: "hard"}, nil).Times(1)
+				mockAchievementsProvider.EXPECT().CountRankedScores(context.Background(), 2, now).Return(12, nil).Times(1)
+				mockAchievementsProvider.EXPECT().GetScoreDays(context.Background(), 2, now, structures.MAX_STREAK_DAYS).Return(testCase.days, nil).Times(1)
+				mockAchievementsProvider.EXPECT().GetUserAchievements(context.Background(), 2).Return(testCase.earned, nil).Times(1)
+				mockAchievementsProvider.EXPECT().AwardAchievements(context.Background(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, achievements []*structures.UserAchievement) (int64, error) {
+						awarded = achievements
+						return int64(len(achievements)), nil
+					},
+				).Times(1)
+			}
+
+			_, err := scoresService.ReviewScore(context.Background(), 5, testCase.approved)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			badges := []string{}
+			for _, achievement := range awarded {
+				if achievement.UserId != 2 || achievement.ScoreId == nil || *achievement.ScoreId != 5 || !achievement.AwardedAt.Equal(now) {
+					t.Fatalf("unexpected achievement %+v", achievement)
+				}
+				badges = append(badges, achievement.BadgeId)
+			}
+			if !slices.Equal(badges, testCase.expectedAwarded) && len(badges)+len(testCase.expectedAwarded) != 0 {
+				t.Fatalf("expected badges %v but got %v instead", testCase.expectedAwarded, badges)
+			}
+		})
+	}
+}
+
+func TestCreateScoreResultSchema(t *testing.T) {
+	data := []struct {
+		testName       string
+		result         map[string]any
+		expectedResult structures.ScoreResult
+		expectedErr    error
+	}{
+		{
+			testName:       "legacy names renamed and unknown fields kept",
+			result:         map[string]any{"wpm": 60.0, "lpm": 300.0, "letters": 100.0, "mode": "words"},
+			expectedResult: structures.ScoreResult{Version: 1, Wpm: 60, Cpm: 300, Letters: 100, Extra: map[string]any{"mode": "words"}},
+		},
+		{
+			testName:       "current name wins over the legacy one",
+			result:         map[string]any{"cpm": 250, "lpm": 300, "extra": map[string]any{"layout": "dvorak"}},
+			expectedResult: structures.ScoreResult{Version: 1, Cpm: 250, Extra: map[string]any{"layout": "dvorak"}},
+		},
+		{
+			testName:    "newer schema version",
+			result:      map[string]any{"version": 2, "wpm": 60},
+			expectedErr: helpers.ErrUnsupportedResultVersion,
+		},
+		{
+			testName:    "accuracy over 100",
+			result:      map[string]any{"accuracy": 120},
+			expectedErr: helpers.ErrInvalidScoreResult,
+		},
+		{
+			testName:    "metric that is not a number",
+			result:      map[string]any{"wpm": "fast"},
+			expectedErr: helpers.ErrInvalidScoreResult,
+		},
+		{
+			testName:    "fractional error count",
+			result:      map[string]any{"errors": 1.5},
+			expectedErr: helpers.ErrInvalidScoreResult,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockScoresProvider := mockProviders.NewMockScoresProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+	scoresService := NewScoresService(mockScoresProvider, mockTextsProvider, mockActivitiesProvider, mockReviewsProvider, nil, nil, 0)
+
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), gomock.Any(), nil).Return(&structures.Text{Id: 1}, nil).AnyTimes()
+	mockActivitiesProvider.EXPECT().GetActivityByIdOrName(context.Background(), gomock.Any(), nil).Return(&structures.Activity{Id: 1}, nil).AnyTimes()
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			if testCase.expectedErr == nil {
+				mockScoresProvider.EXPECT().CreateScore(context.Background(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, score structures.Score) (*structures.Score, error) {
+						return &score, nil
+					},
+				).Times(1)
+			}
+
+			result, err := scoresService.CreateScore(context.Background(), structures.ScoreReq{UserId: 1, ActivityId: 1, TextId: 1, Duration: 60, Result: testCase.result})
+
+			if testCase.expectedErr != nil {
+				if err != testCase.expectedErr {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err := helpers.CompareReflectedStructFields(result.Result, testCase.expectedResult); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
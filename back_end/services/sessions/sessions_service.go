@@ -0,0 +1,315 @@
+package sessions_service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"math"
+	"slices"
+	"time"
+	"type_writer_api/engines"
+	"type_writer_api/helpers"
+	"type_writer_api/providers/achievements"
+	"type_writer_api/providers/activities"
+	"type_writer_api/providers/reviews"
+	"type_writer_api/providers/sessions"
+	"type_writer_api/providers/texts"
+	"type_writer_api/services/achievements"
+	"type_writer_api/services/reviews"
+	"type_writer_api/services/texts"
+	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownActivityKind = errors.New("unknown activity kind")
+	ErrInvalidNonce        = errors.New("invalid session nonce")
+	ErrSessionNotActive    = errors.New("session is no longer active")
+	ErrSessionExpired      = errors.New("session expired")
+	ErrSessionConflict     = errors.New("session changed while adding keystrokes")
+)
+
+type SessionsServiceInterface interface {
+	StartSession(ctx context.Context, sessionInfo structures.TypingSessionReq, userId int, userType string) (*structures.TypingSession, error)
+	GetSession(ctx context.Context, sessionId int, userId int) (*structures.TypingSession, error)
+	AddSessionEvents(ctx context.Context, sessionId int, eventsInfo structures.SessionEventsReq, userId int) (*structures.TypingSession, error)
+	FinishSession(ctx context.Context, sessionId int, finishInfo structures.SessionFinishReq, userId int) (*structures.Score, error)
+}
+
+type SessionsService struct {
+	SessionsProvider   sessions_provider.SessionsProviderInterface
+	ActivitiesProvider activities_provider.ActivitiesProviderInterface
+	TextsProvider      texts_provider.TextsProviderInterface
+	ReviewsProvider    reviews_provider.ReviewsProviderInterface
+	// badges are awarded by these rules, no rules award nothing
+	AchievementsProvider achievements_provider.AchievementsProviderInterface
+	AchievementRules     *engines.AchievementRules
+	now                  func() time.Time
+}
+
+// canTypeText tells whether the user may start a session on the text, that is
+// any approved text they can reach, or one of their own
+func canTypeText(text *structures.Text, userId int, userType string) bool {
+	if userType == structures.USER_TYPE_ADMIN || (text.OwnerId != nil && *text.OwnerId == userId) {
+		return true
+	}
+	return text.Status == structures.TEXT_STATUS_APPROVED && text.Visibility != structures.TEXT_VISIBILITY_PRIVATE
+}
+
+func sessionLifetime(rules structures.ActivityRules) time.Duration {
+	if rules.TimeLimit > 0 {
+		return time.Duration(rules.TimeLimit)*time.Second + structures.SESSION_GRACE_PERIOD
+	}
+	return structures.SESSION_TTL
+}
+
+// elapsedMillis is how far into the session the server clock is, keystroke
+// offsets may not claim to be further along than that
+func elapsedMillis(session *structures.TypingSession, now time.Time) int {
+	return int((now.Sub(session.StartedAt) + structures.SESSION_CLOCK_TOLERANCE) / time.Millisecond)
+}
+
+// validateBatch checks a batch carries on from the stored keystrokes without
+// going back in time or ahead of the server clock
+func validateBatch(stored, batch []*structures.Keystroke, elapsed int) error {
+	if len(batch) == 0 || len(stored)+len(batch) > structures.MAX_SESSION_KEYSTROKES {
+		return engines.ErrInvalidKeystrokes
+	}
+
+	last := 0
+	if len(stored) != 0 {
+		last = stored[len(stored)-1].Offset
+	}
+	for _, keystroke := range batch {
+		if keystroke == nil || keystroke.Key == "" || keystroke.Offset < last || keystroke.Offset > elapsed {
+			return engines.ErrInvalidKeystrokes
+		}
+		last = keystroke.Offset
+	}
+	return nil
+}
+
+func (s *SessionsService) ownSession(ctx context.Context, sessionId int, userId int) (*structures.TypingSession, error) {
+	session, err := s.SessionsProvider.GetSessionById(ctx, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserId != userId {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return session, nil
+}
+
+// activeSession loads a session the user can still type on, an active session
+// past its expiry is marked expired on the way
+func (s *SessionsService) activeSession(ctx context.Context, sessionId int, userId int, nonce string) (*structures.TypingSession, error) {
+	session, err := s.ownSession(ctx, sessionId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(session.Nonce), []byte(nonce)) != 1 {
+		return nil, ErrInvalidNonce
+	}
+	if session.Status != structures.SESSION_STATUS_ACTIVE {
+		return nil, ErrSessionNotActive
+	}
+	if s.now().After(session.ExpiresAt) {
+		if err := s.SessionsProvider.ExpireSession(ctx, session.Id); err != nil {
+			return nil, err
+		}
+		return nil, ErrSessionExpired
+	}
+	return session, nil
+}
+
+func (s *SessionsService) StartSession(ctx context.Context, sessionInfo structures.TypingSessionReq, userId int, userType string) (*structures.TypingSession, error) {
+	activity, err := s.ActivitiesProvider.GetActivityByIdOrName(ctx, &sessionInfo.ActivityId, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := engines.ByKind(activity.Kind); !ok {
+		slog.ErrorContext(ctx, "failed to start session", "error", ErrUnknownActivityKind)
+		return nil, ErrUnknownActivityKind
+	}
+
+	text, err := s.TextsProvider.GetTextByIdOrTitle(ctx, &sessionInfo.TextId, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !canTypeText(text, userId, userType) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if len(activity.Rules.AllowedTextTypes) != 0 && !slices.Contains(activity.Rules.AllowedTextTypes, text.TextType) {
+		return nil, engines.ErrTextTypeNotAllowed
+	}
+
+	nonce, err := helpers.RandomSlug(structures.SESSION_NONCE_BYTES)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to start session", "error", err)
+		return nil, err
+	}
+
+	startedAt := s.now()
+	createdSession, err := s.SessionsProvider.CreateSession(ctx, structures.TypingSession{
+		UserId:     userId,
+		ActivityId: activity.Id,
+		TextId:     text.Id,
+		Nonce:      nonce,
+		Status:     structures.SESSION_STATUS_ACTIVE,
+		Keystrokes: []*structures.Keystroke{},
+		StartedAt:  startedAt,
+		ExpiresAt:  startedAt.Add(sessionLifetime(activity.Rules)),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to start session", "error", err)
+		return nil, err
+	}
+
+	result := createdSession
+	return result, nil
+}
+
+func (s *SessionsService) GetSession(ctx context.Context, sessionId int, userId int) (*structures.TypingSession, error) {
+	session, err := s.ownSession(ctx, sessionId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == structures.SESSION_STATUS_ACTIVE && s.now().After(session.ExpiresAt) {
+		if err := s.SessionsProvider.ExpireSession(ctx, session.Id); err != nil {
+			slog.ErrorContext(ctx, "failed to expire session", "error", err)
+			return nil, err
+		}
+		session.Status = structures.SESSION_STATUS_EXPIRED
+	}
+
+	result := session
+	return result, nil
+}
+
+func (s *SessionsService) AddSessionEvents(ctx context.Context, sessionId int, eventsInfo structures.SessionEventsReq, userId int) (*structures.TypingSession, error) {
+	session, err := s.activeSession(ctx, sessionId, userId, eventsInfo.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateBatch(session.Keystrokes, eventsInfo.Keystrokes, elapsedMillis(session, s.now()))
+	if err != nil {
+		return nil, err
+	}
+
+	appended, err := s.SessionsProvider.AppendSessionKeystrokes(ctx, session.Id, len(session.Keystrokes), eventsInfo.Keystrokes)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to add session events", "error", err)
+		return nil, err
+	}
+	if !appended {
+		return nil, ErrSessionConflict
+	}
+
+	session.Keystrokes = append(session.Keystrokes, eventsInfo.Keystrokes...)
+	session.KeystrokeCount = len(session.Keystrokes)
+
+	result := session
+	return result, nil
+}
+
+// FinishSession replays the keystrokes of a session through its activity
+// engine and stores the outcome as a score with its keystroke log, the
+// duration of the score is the time the server saw the session running
+func (s *SessionsService) FinishSession(ctx context.Context, sessionId int, finishInfo structures.SessionFinishReq, userId int) (*structures.Score, error) {
+	session, err := s.activeSession(ctx, sessionId, userId, finishInfo.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	activity, err := s.ActivitiesProvider.GetActivityByIdOrName(ctx, &session.ActivityId, nil)
+	if err != nil {
+		return nil, err
+	}
+	text, err := s.TextsProvider.GetTextByIdOrTitle(ctx, &session.TextId, nil)
+	if err != nil {
+		return nil, err
+	}
+	engine, ok := engines.ByKind(activity.Kind)
+	if !ok {
+		slog.ErrorContext(ctx, "failed to finish session", "error", ErrUnknownActivityKind)
+		return nil, ErrUnknownActivityKind
+	}
+
+	input := structures.ActivityInput{
+		TextType:   text.TextType,
+		TextBody:   text.TextBody,
+		Keystrokes: session.Keystrokes,
+	}
+	activityResult, err := engine.ComputeResult(activity.Rules, input)
+	if err != nil {
+		return nil, err
+	}
+	finishedAt := s.now()
+	score := structures.Score{
+		UserId:     session.UserId,
+		ActivityId: session.ActivityId,
+		TextId:     session.TextId,
+		Duration:   max(1, int(math.Round(finishedAt.Sub(session.StartedAt).Seconds()))),
+		Result:     engines.ScoreResult(activityResult),
+	}
+	// the server kept the time, the keystrokes themselves can still be
+	// scripted
+	score.ReviewStatus = structures.SCORE_REVIEW_CLEAN
+	score.FlagReasons = engines.CheckPlausibility(session.Keystrokes)
+	if len(score.FlagReasons) != 0 {
+		score.ReviewStatus = structures.SCORE_REVIEW_FLAGGED
+	}
+	score.FinalScore, err = engines.FinalScore(activity.ScoringFormula, engines.ResultMetrics(score.Result, score.Duration))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to evaluate scoring formula", "activity_id", activity.Id, "error", err)
+	}
+
+	// the score stands on its own when its keystroke log cannot be kept
+	events, err := engines.KeystrokeEvents(activity.Rules, input)
+	if err == nil {
+		score.KeystrokeLog, err = helpers.NewKeystrokeLog(events)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to build keystroke log", "session_id", session.Id, "error", err)
+	}
+
+	createdScore, err := s.SessionsProvider.FinishSession(ctx, session.Id, finishedAt, score)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, ErrSessionNotActive
+	} else if err != nil {
+		slog.ErrorContext(ctx, "failed to finish session", "error", err)
+		return nil, err
+	}
+
+	// the score is stored by now, a missing credit line or a deck that could
+	// not be updated is not worth failing the finished session over
+	if err := texts_service.AttachAttributions(ctx, s.TextsProvider, createdScore); err != nil {
+		slog.ErrorContext(ctx, "failed to attach attribution", "error", err, "score_id", createdScore.Id)
+	}
+	if score.KeystrokeLog != nil {
+		if err := reviews_service.UpdateReviewDeck(ctx, s.ReviewsProvider, createdScore.UserId, text, events, s.now()); err != nil {
+			slog.ErrorContext(ctx, "failed to update review deck", "error", err, "score_id", createdScore.Id)
+		}
+	}
+	if err := achievements_service.AwardScoreAchievements(ctx, s.AchievementsProvider, s.AchievementRules, createdScore, text, s.now()); err != nil {
+		slog.ErrorContext(ctx, "failed to award achievements", "error", err, "score_id", createdScore.Id)
+	}
+
+	result := createdScore
+	return result, nil
+}
+
+func NewSessionsService(sessionsProvider sessions_provider.SessionsProviderInterface, activitiesProvider activities_provider.ActivitiesProviderInterface, textsProvider texts_provider.TextsProviderInterface, reviewsProvider reviews_provider.ReviewsProviderInterface, achievementsProvider achievements_provider.AchievementsProviderInterface, achievementRules *engines.AchievementRules) *SessionsService {
+	return &SessionsService{
+		SessionsProvider:     sessionsProvider,
+		ActivitiesProvider:   activitiesProvider,
+		TextsProvider:        textsProvider,
+		ReviewsProvider:      reviewsProvider,
+		AchievementsProvider: achievementsProvider,
+		AchievementRules:     achievementRules,
+		now:                  time.Now,
+	}
+}
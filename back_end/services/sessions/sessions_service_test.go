@@ -0,0 +1,337 @@
+package sessions_service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"type_writer_api/engines"
+	"type_writer_api/structures"
+	mockProviders "type_writer_api/testing/mocks/providers"
+
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func typed(text string, step int) []*structures.Keystroke {
+	keystrokes := []*structures.Keystroke{}
+	for indx, char := range text {
+		keystrokes = append(keystrokes, &structures.Keystroke{Key: string(char), Offset: indx * step})
+	}
+	return keystrokes
+}
+
+func testSession(now time.Time, startedAgo time.Duration, keystrokes []*structures.Keystroke) *structures.TypingSession {
+	return &structures.TypingSession{
+		Id:             1,
+		UserId:         1,
+		ActivityId:     1,
+		TextId:         1,
+		Nonce:          "nonce",
+		Status:         structures.SESSION_STATUS_ACTIVE,
+		Keystrokes:     keystrokes,
+		KeystrokeCount: len(keystrokes),
+		StartedAt:      now.Add(-startedAgo),
+		ExpiresAt:      now.Add(-startedAgo).Add(structures.SESSION_TTL),
+	}
+}
+
+func TestStartSession(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	ownerId := 2
+
+	data := []struct {
+		testName          string
+		userId            int
+		activity          *structures.Activity
+		text              *structures.Text
+		expectedExpiresAt time.Time
+		expectedErr       error
+	}{
+		{
+			testName:          "untimed activity on a catalog text",
+			userId:            1,
+			activity:          &structures.Activity{Id: 1, Kind: structures.ACTIVITY_KIND_ZEN},
+			text:              &structures.Text{Id: 1, Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC},
+			expectedExpiresAt: now.Add(structures.SESSION_TTL),
+		},
+		{
+			testName:          "timed activity expires past its limit",
+			userId:            1,
+			activity:          &structures.Activity{Id: 1, Kind: structures.ACTIVITY_KIND_TIMED_TEST, Rules: structures.ActivityRules{TimeLimit: 60}},
+			text:              &structures.Text{Id: 1, Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_UNLISTED},
+			expectedExpiresAt: now.Add(time.Minute + structures.SESSION_GRACE_PERIOD),
+		},
+		{
+			testName:          "own private text",
+			userId:            2,
+			activity:          &structures.Activity{Id: 1, Kind: structures.ACTIVITY_KIND_ZEN},
+			text:              &structures.Text{Id: 1, OwnerId: &ownerId, Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PRIVATE},
+			expectedExpiresAt: now.Add(structures.SESSION_TTL),
+		},
+		{
+			testName:    "private text of someone else",
+			userId:      1,
+			activity:    &structures.Activity{Id: 1, Kind: structures.ACTIVITY_KIND_ZEN},
+			text:        &structures.Text{Id: 1, OwnerId: &ownerId, Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PRIVATE},
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+		{
+			testName:    "text type not allowed",
+			userId:      1,
+			activity:    &structures.Activity{Id: 1, Kind: structures.ACTIVITY_KIND_ZEN, Rules: structures.ActivityRules{AllowedTextTypes: []string{structures.TEXT_TYPE_DRILL}}},
+			text:        &structures.Text{Id: 1, TextType: structures.TEXT_TYPE_FULL_TEXT, Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC},
+			expectedErr: engines.ErrTextTypeNotAllowed,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionsProvider := mockProviders.NewMockSessionsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+	mockAchievementsProvider := mockProviders.NewMockAchievementsProviderInterface(ctrl)
+	sessionsService := NewSessionsService(mockSessionsProvider, mockActivitiesProvider, mockTextsProvider, mockReviewsProvider, mockAchievementsProvider, nil)
+	sessionsService.now = func() time.Time { return now }
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			mockActivitiesProvider.EXPECT().GetActivityByIdOrName(context.Background(), gomock.Any(), nil).Return(testCase.activity, nil).Times(1)
+			mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), gomock.Any(), nil).Return(testCase.text, nil).Times(1)
+			if testCase.expectedErr == nil {
+				mockSessionsProvider.EXPECT().CreateSession(context.Background(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, session structures.TypingSession) (*structures.TypingSession, error) {
+						session.Id = 1
+						return &session, nil
+					}).Times(1)
+			}
+
+			result, err := sessionsService.StartSession(context.Background(), structures.TypingSessionReq{ActivityId: 1, TextId: 1}, testCase.userId, structures.USER_TYPE_REGULAR)
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if result.Nonce == "" || result.Status != structures.SESSION_STATUS_ACTIVE || result.UserId != testCase.userId {
+				t.Fatalf("unexpected session %+v", result)
+			}
+			if !result.StartedAt.Equal(now) || !result.ExpiresAt.Equal(testCase.expectedExpiresAt) {
+				t.Fatalf("expected session from %v to %v, got %v to %v", now, testCase.expectedExpiresAt, result.StartedAt, result.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestAddSessionEvents(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	data := []struct {
+		testName      string
+		session       *structures.TypingSession
+		userId        int
+		events        structures.SessionEventsReq
+		expectAppend  bool
+		appended      bool
+		expectExpire  bool
+		expectedCount int
+		expectedErr   error
+	}{
+		{
+			testName:      "batch carrying on from the stored keystrokes",
+			session:       testSession(now, 10*time.Second, typed("ab", 100)),
+			userId:        1,
+			events:        structures.SessionEventsReq{Nonce: "nonce", Keystrokes: []*structures.Keystroke{{Key: "c", Offset: 300}}},
+			expectAppend:  true,
+			appended:      true,
+			expectedCount: 3,
+		},
+		{
+			testName:    "batch going back in time",
+			session:     testSession(now, 10*time.Second, typed("ab", 100)),
+			userId:      1,
+			events:      structures.SessionEventsReq{Nonce: "nonce", Keystrokes: []*structures.Keystroke{{Key: "c", Offset: 50}}},
+			expectedErr: engines.ErrInvalidKeystrokes,
+		},
+		{
+			testName:    "batch ahead of the server clock",
+			session:     testSession(now, time.Second, nil),
+			userId:      1,
+			events:      structures.SessionEventsReq{Nonce: "nonce", Keystrokes: []*structures.Keystroke{{Key: "a", Offset: 5000}}},
+			expectedErr: engines.ErrInvalidKeystrokes,
+		},
+		{
+			testName:    "wrong nonce",
+			session:     testSession(now, time.Second, nil),
+			userId:      1,
+			events:      structures.SessionEventsReq{Nonce: "guess", Keystrokes: typed("a", 0)},
+			expectedErr: ErrInvalidNonce,
+		},
+		{
+			testName:    "session of another user",
+			session:     testSession(now, time.Second, nil),
+			userId:      2,
+			events:      structures.SessionEventsReq{Nonce: "nonce", Keystrokes: typed("a", 0)},
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+		{
+			testName:     "expired session",
+			session:      testSession(now, structures.SESSION_TTL+time.Second, nil),
+			userId:       1,
+			events:       structures.SessionEventsReq{Nonce: "nonce", Keystrokes: typed("a", 0)},
+			expectExpire: true,
+			expectedErr:  ErrSessionExpired,
+		},
+		{
+			testName:     "concurrent batch",
+			session:      testSession(now, time.Second, nil),
+			userId:       1,
+			events:       structures.SessionEventsReq{Nonce: "nonce", Keystrokes: typed("a", 0)},
+			expectAppend: true,
+			appended:     false,
+			expectedErr:  ErrSessionConflict,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionsProvider := mockProviders.NewMockSessionsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+	mockAchievementsProvider := mockProviders.NewMockAchievementsProviderInterface(ctrl)
+	sessionsService := NewSessionsService(mockSessionsProvider, mockActivitiesProvider, mockTextsProvider, mockReviewsProvider, mockAchievementsProvider, nil)
+	sessionsService.now = func() time.Time { return now }
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			mockSessionsProvider.EXPECT().GetSessionById(context.Background(), 1).Return(testCase.session, nil).Times(1)
+			if testCase.expectExpire {
+				mockSessionsProvider.EXPECT().ExpireSession(context.Background(), 1).Return(nil).Times(1)
+			}
+			if testCase.expectAppend {
+				mockSessionsProvider.EXPECT().AppendSessionKeystrokes(context.Background(), 1, len(testCase.session.Keystrokes), testCase.events.Keystrokes).
+					Return(testCase.appended, nil).Times(1)
+			}
+
+			result, err := sessionsService.AddSessionEvents(context.Background(), 1, testCase.events, testCase.userId)
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if result.KeystrokeCount != testCase.expectedCount {
+				t.Fatalf("expected %v keystrokes, got %v", testCase.expectedCount, result.KeystrokeCount)
+			}
+		})
+	}
+}
+
+func TestFinishSession(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionsProvider := mockProviders.NewMockSessionsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+	mockAchievementsProvider := mockProviders.NewMockAchievementsProviderInterface(ctrl)
+	sessionsService := NewSessionsService(mockSessionsProvider, mockActivitiesProvider, mockTextsProvider, mockReviewsProvider, mockAchievementsProvider, nil)
+	sessionsService.now = func() time.Time { return now }
+	rules, err := engines.NewAchievementRules([]*structures.Badge{
+		{Id: "first_100_wpm", Name: "Triple digits", Condition: "wpm >= 100"},
+		{Id: "100_tests", Name: "Centurion", Condition: "total_scores >= 100"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	sessionsService.AchievementRules = rules
+
+	// ten letters typed in one second, the session itself ran for 30 seconds
+	session := testSession(now, 30*time.Second, typed("hello word", 100))
+	session.Keystrokes[9].Offset = 1000
+
+	mockSessionsProvider.EXPECT().GetSessionById(context.Background(), 1).Return(session, nil).Times(1)
+	mockActivitiesProvider.EXPECT().GetActivityByIdOrName(context.Background(), &session.ActivityId, nil).
+		Return(&structures.Activity{Id: 1, Kind: structures.ACTIVITY_KIND_ZEN, ScoringFormula: "wpm * accuracy / 100"}, nil).Times(1)
+	// once to replay the session and once more to credit the author
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &session.TextId, nil).
+		Return(&structures.Text{Id: 1, TextType: structures.TEXT_TYPE_FULL_TEXT, TextBody: "hello world", Author: "Jane Doe", AttributionRequired: true}, nil).Times(2)
+	mockSessionsProvider.EXPECT().FinishSession(context.Background(), 1, now, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, sessionId int, finishedAt time.Time, score structures.Score) (*structures.Score, error) {
+			score.Id = 1
+			return &score, nil
+		}).Times(1)
+	// typing stopped in the middle of "world", only "hello" made it through
+	mockReviewsProvider.EXPECT().GetReviewWords(context.Background(), 1, []string{"hello"}).
+		Return([]*structures.ReviewWord{{UserId: 1, Word: "hello", Misses: 2, EaseFactor: 2.5, DueAt: now.Add(-time.Hour)}}, nil).Times(1)
+	mockReviewsProvider.EXPECT().SaveReviewWords(context.Background(), []*structures.ReviewWord{
+		{UserId: 1, Word: "hello", Misses: 2, Repetitions: 1, EaseFactor: 2.5, IntervalDays: 1, DueAt: now.AddDate(0, 0, 1), LastReviewedAt: &now},
+	}).Return(true, nil).Times(1)
+	mockAchievementsProvider.EXPECT().CountRankedScores(context.Background(), 1, gomock.Any()).Return(12, nil).Times(1)
+	mockAchievementsProvider.EXPECT().GetScoreDays(context.Background(), 1, gomock.Any(), structures.MAX_STREAK_DAYS).Return([]string{"2026-10-19"}, nil).Times(1)
+	mockAchievementsProvider.EXPECT().GetUserAchievements(context.Background(), 1).Return([]*structures.UserAchievement{}, nil).Times(1)
+	scoreId := 1
+	mockAchievementsProvider.EXPECT().AwardAchievements(context.Background(), []*structures.UserAchievement{
+		{UserId: 1, BadgeId: "first_100_wpm", ScoreId: &scoreId, AwardedAt: now},
+	}).Return(int64(1), nil).Times(1)
+
+	result, err := sessionsService.FinishSession(context.Background(), 1, structures.SessionFinishReq{Nonce: "nonce"}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if result.UserId != 1 || result.ActivityId != 1 || result.TextId != 1 || result.Duration != 30 {
+		t.Fatalf("unexpected score %+v", result)
+	}
+	if result.Result.Version != structures.SCORE_RESULT_VERSION || result.Result.Wpm != 108 || result.Result.Accuracy != 90 {
+		t.Fatalf("unexpected result %v", result.Result)
+	}
+	if result.FinalScore != 97.2 {
+		t.Fatalf("expected final score %v, got %v", 97.2, result.FinalScore)
+	}
+	if result.KeystrokeLog == nil || result.KeystrokeLog.EventCount != 10 {
+		t.Fatalf("expected a keystroke log of 10 events, got %+v", result.KeystrokeLog)
+	}
+	if result.ReviewStatus != structures.SCORE_REVIEW_CLEAN || len(result.FlagReasons) != 0 {
+		t.Fatalf("expected a clean score, got %v %v", result.ReviewStatus, result.FlagReasons)
+	}
+	if result.Attribution != "Jane Doe" {
+		t.Fatalf("expected attribution %q, got %q", "Jane Doe", result.Attribution)
+	}
+}
+
+func TestFinishSessionNotActive(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionsProvider := mockProviders.NewMockSessionsProviderInterface(ctrl)
+	mockActivitiesProvider := mockProviders.NewMockActivitiesProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	mockReviewsProvider := mockProviders.NewMockReviewsProviderInterface(ctrl)
+	mockAchievementsProvider := mockProviders.NewMockAchievementsProviderInterface(ctrl)
+	sessionsService := NewSessionsService(mockSessionsProvider, mockActivitiesProvider, mockTextsProvider, mockReviewsProvider, mockAchievementsProvider, nil)
+	sessionsService.now = func() time.Time { return now }
+
+	session := testSession(now, time.Minute, typed("a", 0))
+	session.Status = structures.SESSION_STATUS_FINISHED
+	mockSessionsProvider.EXPECT().GetSessionById(context.Background(), 1).Return(session, nil).Times(1)
+
+	_, err := sessionsService.FinishSession(context.Background(), 1, structures.SessionFinishReq{Nonce: "nonce"}, 1)
+	if err != ErrSessionNotActive {
+		t.Fatalf("expected error: %v but got %v instead", ErrSessionNotActive, err)
+	}
+}
@@ -0,0 +1,486 @@
+package stats_service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"type_writer_api/helpers"
+	"type_writer_api/providers/keyboard_layouts"
+	"type_writer_api/providers/stats"
+	"type_writer_api/providers/texts"
+	"type_writer_api/providers/users"
+	"type_writer_api/services/texts"
+	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidBucket           = errors.New("invalid stats bucket")
+	ErrInvalidWindow           = errors.New("invalid stats window")
+	ErrInvalidKeyboardLayout   = errors.New("unknown keyboard layout")
+	ErrKeystrokeStatsForbidden = errors.New("keystroke stats belong to another user")
+	ErrNoWeakNgrams            = errors.New("not enough keystrokes to find weak sequences")
+)
+
+type StatsServiceInterface interface {
+	GetUserStats(ctx context.Context, userId int, bucket string) (*structures.UserStats, error)
+	GetUserKeymapStats(ctx context.Context, userId int, viewerId int, viewerType string, window string, layoutName string) (*structures.KeymapStats, error)
+	RefreshNgramBaselines(ctx context.Context) (int, error)
+	GetUserNgramStats(ctx context.Context, userId int, viewerId int, viewerType string, window string) (*structures.NgramStats, error)
+	CreateNgramDrill(ctx context.Context, userId int, viewerId int, viewerType string) (*structures.NgramDrill, error)
+	GetUserRecommendations(ctx context.Context, userId int, viewerId int, viewerType string) (*structures.Recommendations, error)
+}
+
+type StatsService struct {
+	StatsProvider           stats_provider.StatsProviderInterface
+	UsersProvider           users_provider.UsersProviderInterface
+	KeyboardLayoutsProvider keyboard_layouts_provider.KeyboardLayoutsProviderInterface
+	TextsProvider           texts_provider.TextsProviderInterface
+	RecommendationWeights   structures.RecommendationFactors
+	now                     func() time.Time
+}
+
+// trendStart is the first day of the oldest bucket a trend goes back to,
+// days are UTC like the daily totals they are read from
+func (s *StatsService) trendStart(bucket string) string {
+	year, month, day := s.now().UTC().Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	buckets := structures.STATS_TREND_BUCKETS[bucket]
+	if bucket == structures.STATS_BUCKET_WEEK {
+		monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		return monday.AddDate(0, 0, -7*(buckets-1)).Format(time.DateOnly)
+	}
+	return today.AddDate(0, 0, -(buckets - 1)).Format(time.DateOnly)
+}
+
+func roundStat(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// GetUserStats puts together how a user has been doing, the totals come
+// from the running sums kept next to the scores and everything else only
+// reads the latest scores of the user
+func (s *StatsService) GetUserStats(ctx context.Context, userId int, bucket string) (*structures.UserStats, error) {
+	if bucket == "" {
+		bucket = structures.STATS_BUCKET_DAY
+	}
+	if _, ok := structures.STATS_TREND_BUCKETS[bucket]; !ok {
+		return nil, ErrInvalidBucket
+	}
+
+	_, err := s.UsersProvider.GetUserByIdOrUsername(ctx, &userId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &structures.UserStats{UserId: userId, Bucket: bucket}
+
+	// users without scores have no totals yet
+	totals, err := s.StatsProvider.GetUserTotals(ctx, userId)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		slog.ErrorContext(ctx, "failed to get user stat totals", "error", err)
+		return nil, err
+	}
+	if err == nil && totals.Scores > 0 {
+		stats.Scores = totals.Scores
+		stats.TotalDuration = totals.Duration
+		stats.Wpm = roundStat(totals.WpmSum / float64(totals.Scores))
+		stats.Accuracy = roundStat(totals.AccuracySum / float64(totals.Scores))
+
+		stats.Percentile, err = s.StatsProvider.GetWpmPercentile(ctx, totals.WpmSum/float64(totals.Scores))
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to get wpm percentile", "error", err)
+			return nil, err
+		}
+	}
+
+	stats.Rolling = []*structures.RollingAverage{}
+	for _, window := range structures.STATS_ROLLING_WINDOWS {
+		average, err := s.StatsProvider.GetRollingAverage(ctx, userId, window)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to get rolling average", "error", err)
+			return nil, err
+		}
+		stats.Rolling = append(stats.Rolling, average)
+	}
+
+	stats.Bests, err = s.StatsProvider.GetPersonalBests(ctx, userId)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get personal bests", "error", err)
+		return nil, err
+	}
+
+	stats.Trend, err = s.StatsProvider.GetTrend(ctx, userId, bucket, s.trendStart(bucket))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get stats trend", "error", err)
+		return nil, err
+	}
+
+	result := stats
+	return result, nil
+}
+
+// resolveKeyboardLayout finds a builtin layout by name before looking through
+// the stored custom layouts
+func (s *StatsService) resolveKeyboardLayout(ctx context.Context, name string) (*structures.KeyboardLayout, error) {
+	if builtin, ok := helpers.BuiltinKeyboardLayout(name); ok {
+		return builtin, nil
+	}
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	layout, err := s.KeyboardLayoutsProvider.GetKeyboardLayoutByIdOrName(ctx, nil, &name)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, ErrInvalidKeyboardLayout
+	} else if err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// windowSince is when a leaderboard window started, zero for all time
+func (s *StatsService) windowSince(window string) (time.Time, error) {
+	duration, ok := structures.LEADERBOARD_WINDOWS[window]
+	if !ok {
+		return time.Time{}, ErrInvalidWindow
+	}
+	if duration == 0 {
+		return time.Time{}, nil
+	}
+	return s.now().Add(-duration), nil
+}
+
+func decodeKeystrokeLogs(ctx context.Context, keystrokeLogs []*structures.ScoreKeystrokeLog) ([][]*structures.KeystrokeEvent, error) {
+	logs := make([][]*structures.KeystrokeEvent, 0, len(keystrokeLogs))
+	for _, keystrokeLog := range keystrokeLogs {
+		events, err := helpers.DecodeKeystrokeLog(keystrokeLog.Data)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to decode keystroke log", "error", err, "score_id", keystrokeLog.ScoreId)
+			return nil, err
+		}
+		logs = append(logs, events)
+	}
+	return logs, nil
+}
+
+// userKeystrokeLogs decodes the keystroke logs of the latest scores of a
+// user, only the user and admins get to read them like the logs themselves
+func (s *StatsService) userKeystrokeLogs(ctx context.Context, userId int, viewerId int, viewerType string, since time.Time) (*structures.User, [][]*structures.KeystrokeEvent, error) {
+	if userId != viewerId && viewerType != structures.USER_TYPE_ADMIN {
+		return nil, nil, ErrKeystrokeStatsForbidden
+	}
+
+	user, err := s.UsersProvider.GetUserByIdOrUsername(ctx, &userId, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keystrokeLogs, err := s.StatsProvider.GetUserKeystrokeLogs(ctx, userId, since, structures.MAX_KEYMAP_STATS_LOGS)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get user keystroke logs", "error", err)
+		return nil, nil, err
+	}
+
+	logs, err := decodeKeystrokeLogs(ctx, keystrokeLogs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, logs, nil
+}
+
+// GetUserKeymapStats works out which keys and fingers a user misses most from
+// the keystroke logs of their latest scores. Windows are the same as the
+// leaderboard ones and the layout defaults to the one the user prefers
+func (s *StatsService) GetUserKeymapStats(ctx context.Context, userId int, viewerId int, viewerType string, window string, layoutName string) (*structures.KeymapStats, error) {
+	if window == "" {
+		window = structures.LEADERBOARD_WINDOW_ALL
+	}
+	since, err := s.windowSince(window)
+	if err != nil {
+		return nil, err
+	}
+
+	user, logs, err := s.userKeystrokeLogs(ctx, userId, viewerId, viewerType, since)
+	if err != nil {
+		return nil, err
+	}
+	if layoutName == "" {
+		layoutName = user.KeyboardLayout
+	}
+	if layoutName == "" {
+		layoutName = structures.DEFAULT_KEYBOARD_LAYOUT
+	}
+	layout, err := s.resolveKeyboardLayout(ctx, layoutName)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := helpers.BuildKeymapStats(layout, logs)
+	stats.UserId = userId
+	stats.Window = window
+
+	result := &stats
+	return result, nil
+}
+
+// RefreshNgramBaselines rebuilds the sequence baselines from the latest
+// keystroke logs of everyone, sequences without enough samples are dropped.
+// It returns how many baselines there are now
+func (s *StatsService) RefreshNgramBaselines(ctx context.Context) (int, error) {
+	keystrokeLogs, err := s.StatsProvider.GetLatestKeystrokeLogs(ctx, structures.MAX_NGRAM_BASELINE_LOGS)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get keystroke logs", "error", err)
+		return 0, err
+	}
+	logs, err := decodeKeystrokeLogs(ctx, keystrokeLogs)
+	if err != nil {
+		return 0, err
+	}
+
+	baselines := []*structures.NgramBaseline{}
+	for ngram, samples := range helpers.NgramLatencies(logs) {
+		if len(samples) < structures.MIN_NGRAM_BASELINE_SAMPLES {
+			continue
+		}
+		baselines = append(baselines, &structures.NgramBaseline{
+			Ngram:         ngram,
+			Samples:       len(samples),
+			MedianLatency: helpers.MedianLatency(samples),
+			UpdatedAt:     s.now(),
+		})
+	}
+	slices.SortFunc(baselines, func(a, b *structures.NgramBaseline) int {
+		return strings.Compare(a.Ngram, b.Ngram)
+	})
+
+	_, err = s.StatsProvider.ReplaceNgramBaselines(ctx, baselines)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to replace ngram baselines", "error", err)
+		return 0, err
+	}
+
+	result := len(baselines)
+	return result, nil
+}
+
+func (s *StatsService) ngramBaselines(ctx context.Context) (map[string]float64, error) {
+	baselineRows, err := s.StatsProvider.GetNgramBaselines(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get ngram baselines", "error", err)
+		return nil, err
+	}
+	baselines := map[string]float64{}
+	for _, baseline := range baselineRows {
+		baselines[baseline.Ngram] = baseline.MedianLatency
+	}
+	return baselines, nil
+}
+
+// weakNgrams keeps the sequences a user is slower on than usual, only those
+// are worth drilling. Trigrams come first as they hold their bigrams
+func weakNgrams(stats *structures.NgramStats) []string {
+	ngrams := []string{}
+	for _, latency := range slices.Concat(stats.Trigrams, stats.Bigrams) {
+		if latency.Ratio > 1 {
+			ngrams = append(ngrams, latency.Ngram)
+		}
+	}
+	return ngrams
+}
+
+// GetUserNgramStats ranks the bigrams and trigrams a user types slowest next
+// to how long everyone else takes on them
+func (s *StatsService) GetUserNgramStats(ctx context.Context, userId int, viewerId int, viewerType string, window string) (*structures.NgramStats, error) {
+	if window == "" {
+		window = structures.LEADERBOARD_WINDOW_ALL
+	}
+	since, err := s.windowSince(window)
+	if err != nil {
+		return nil, err
+	}
+
+	_, logs, err := s.userKeystrokeLogs(ctx, userId, viewerId, viewerType, since)
+	if err != nil {
+		return nil, err
+	}
+
+	baselines, err := s.ngramBaselines(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	latencies := helpers.NgramLatencies(logs)
+	result := &structures.NgramStats{
+		UserId:   userId,
+		Window:   window,
+		Scores:   len(logs),
+		Bigrams:  helpers.RankNgrams(latencies, baselines, 2),
+		Trigrams: helpers.RankNgrams(latencies, baselines, 3),
+	}
+	return result, nil
+}
+
+// CreateNgramDrill writes the private drill text of a user out of catalog
+// words rich in the sequences slowing them down, slower ones come first. The
+// drill replaces the one made before
+func (s *StatsService) CreateNgramDrill(ctx context.Context, userId int, viewerId int, viewerType string) (*structures.NgramDrill, error) {
+	stats, err := s.GetUserNgramStats(ctx, userId, viewerId, viewerType, structures.LEADERBOARD_WINDOW_ALL)
+	if err != nil {
+		return nil, err
+	}
+
+	ngrams := weakNgrams(stats)
+	if len(ngrams) == 0 {
+		return nil, ErrNoWeakNgrams
+	}
+
+	catalog, err := s.TextsProvider.GetTexts(ctx, structures.TextFilter{
+		Status:     structures.TEXT_STATUS_APPROVED,
+		Visibility: structures.TEXT_VISIBILITY_PUBLIC,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get catalog texts", "error", err)
+		return nil, err
+	}
+	bodies := make([]string, 0, len(catalog))
+	for _, text := range catalog {
+		bodies = append(bodies, text.TextBody)
+	}
+
+	body := helpers.BuildNgramDrill(ngrams, bodies)
+	drill := structures.Text{
+		TextType:    structures.TEXT_TYPE_DRILL,
+		Title:       structures.NGRAM_DRILL_TITLE,
+		Difficulty:  "easy",
+		Language:    helpers.DEFAULT_LANGUAGE,
+		TextBody:    body,
+		TextLength:  len(body),
+		Status:      structures.TEXT_STATUS_APPROVED,
+		SubmitterId: &userId,
+		OwnerId:     &userId,
+		Visibility:  structures.TEXT_VISIBILITY_PRIVATE,
+		Fingerprint: helpers.TextFingerprint(body),
+	}
+	text, err := texts_service.SaveDrillText(ctx, s.TextsProvider, drill)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create ngram drill", "error", err)
+		return nil, err
+	}
+
+	result := &structures.NgramDrill{Ngrams: ngrams, Text: text}
+	return result, nil
+}
+
+// weakKeys are the keys a user misses most, keys never missed are left out
+func weakKeys(stats structures.KeymapStats) []string {
+	missed := []*structures.KeyStats{}
+	for _, keyStats := range stats.Keys {
+		if keyStats.Errors > 0 {
+			missed = append(missed, keyStats)
+		}
+	}
+	slices.SortStableFunc(missed, func(a, b *structures.KeyStats) int {
+		return cmp.Or(cmp.Compare(a.Accuracy, b.Accuracy), cmp.Compare(b.Errors, a.Errors), strings.Compare(a.Key, b.Key))
+	})
+
+	keys := []string{}
+	for _, keyStats := range missed[:min(len(missed), structures.MAX_WEAK_KEYS)] {
+		keys = append(keys, keyStats.Key)
+	}
+	return keys
+}
+
+// GetUserRecommendations picks the texts and drills a user would get the
+// most out of next. Texts are favored when they are full of the keys and
+// sequences the user struggles with, suit their current pace, were not typed
+// lately and are rated well, each of those weighing as configured
+func (s *StatsService) GetUserRecommendations(ctx context.Context, userId int, viewerId int, viewerType string) (*structures.Recommendations, error) {
+	user, logs, err := s.userKeystrokeLogs(ctx, userId, viewerId, viewerType, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	layoutName := user.KeyboardLayout
+	if layoutName == "" {
+		layoutName = structures.DEFAULT_KEYBOARD_LAYOUT
+	}
+	layout, err := s.resolveKeyboardLayout(ctx, layoutName)
+	if err != nil {
+		return nil, err
+	}
+
+	baselines, err := s.ngramBaselines(ctx)
+	if err != nil {
+		return nil, err
+	}
+	latencies := helpers.NgramLatencies(logs)
+	ngrams := weakNgrams(&structures.NgramStats{
+		Bigrams:  helpers.RankNgrams(latencies, baselines, 2),
+		Trigrams: helpers.RankNgrams(latencies, baselines, 3),
+	})
+
+	average, err := s.StatsProvider.GetRollingAverage(ctx, userId, structures.STATS_ROLLING_WINDOWS[0])
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get rolling average", "error", err)
+		return nil, err
+	}
+
+	plays, err := s.StatsProvider.GetUserTextPlays(ctx, userId)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get user text plays", "error", err)
+		return nil, err
+	}
+	lastPlayed := map[int]time.Time{}
+	for _, play := range plays {
+		lastPlayed[play.TextId] = play.LastPlayedAt
+	}
+
+	// the catalog along with the drills of the user
+	texts, err := s.TextsProvider.GetTexts(ctx, structures.TextFilter{
+		Status:     structures.TEXT_STATUS_APPROVED,
+		Visibility: structures.TEXT_VISIBILITY_PUBLIC,
+		ViewerId:   userId,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get candidate texts", "error", err)
+		return nil, err
+	}
+	candidates := []*structures.Text{}
+	for _, text := range texts {
+		if text.Status == structures.TEXT_STATUS_APPROVED {
+			candidates = append(candidates, text)
+		}
+	}
+
+	profile := helpers.RecommendationProfile{
+		Wpm:        average.Wpm,
+		WeakKeys:   weakKeys(helpers.BuildKeymapStats(layout, logs)),
+		WeakNgrams: ngrams,
+		LastPlayed: lastPlayed,
+		Now:        s.now(),
+	}
+	result := &structures.Recommendations{
+		UserId:     userId,
+		Wpm:        profile.Wpm,
+		WeakKeys:   profile.WeakKeys,
+		WeakNgrams: profile.WeakNgrams,
+		Texts:      helpers.RankRecommendations(candidates, profile, s.RecommendationWeights),
+	}
+	return result, nil
+}
+
+func NewStatsService(statsProvider stats_provider.StatsProviderInterface, usersProvider users_provider.UsersProviderInterface, keyboardLayoutsProvider keyboard_layouts_provider.KeyboardLayoutsProviderInterface, textsProvider texts_provider.TextsProviderInterface, recommendationWeights structures.RecommendationFactors) *StatsService {
+	return &StatsService{
+		StatsProvider:           statsProvider,
+		UsersProvider:           usersProvider,
+		KeyboardLayoutsProvider: keyboardLayoutsProvider,
+		TextsProvider:           textsProvider,
+		RecommendationWeights:   recommendationWeights,
+		now:                     time.Now,
+	}
+}
@@ -0,0 +1,584 @@
+package stats_service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"type_writer_api/helpers"
+	"type_writer_api/structures"
+	mockProviders "type_writer_api/testing/mocks/providers"
+
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestGetUserStats(t *testing.T) {
+	// a Monday
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	bests := []*structures.PersonalBest{{ActivityId: 1, TextId: 1, ScoreId: 4, Wpm: 92, Accuracy: 98}}
+	trend := []*structures.TrendBucket{{Start: "2026-10-12", Scores: 3, Duration: 180, Wpm: 80, Accuracy: 97}}
+
+	data := []struct {
+		testName      string
+		bucket        string
+		userErr       error
+		totals        *structures.UserStatsTotals
+		totalsErr     error
+		expectedSince string
+		expectedStats *structures.UserStats
+		expectedErr   error
+	}{
+		{
+			testName:      "daily trend",
+			totals:        &structures.UserStatsTotals{UserId: 2, Scores: 3, Duration: 180, WpmSum: 241, AccuracySum: 291},
+			expectedSince: "2026-09-20",
+			expectedStats: &structures.UserStats{
+				UserId:        2,
+				Scores:        3,
+				TotalDuration: 180,
+				Wpm:           80.33,
+				Accuracy:      97,
+				Percentile:    62.5,
+				Rolling:       []*structures.RollingAverage{{Window: 10, Scores: 3, Wpm: 80.33, Accuracy: 97}, {Window: 100, Scores: 3, Wpm: 80.33, Accuracy: 97}},
+				Bests:         bests,
+				Bucket:        structures.STATS_BUCKET_DAY,
+				Trend:         trend,
+			},
+		},
+		{
+			testName:      "weekly trend",
+			bucket:        structures.STATS_BUCKET_WEEK,
+			totals:        &structures.UserStatsTotals{UserId: 2, Scores: 3, Duration: 180, WpmSum: 241, AccuracySum: 291},
+			expectedSince: "2026-04-27",
+			expectedStats: &structures.UserStats{
+				UserId:        2,
+				Scores:        3,
+				TotalDuration: 180,
+				Wpm:           80.33,
+				Accuracy:      97,
+				Percentile:    62.5,
+				Rolling:       []*structures.RollingAverage{{Window: 10, Scores: 3, Wpm: 80.33, Accuracy: 97}, {Window: 100, Scores: 3, Wpm: 80.33, Accuracy: 97}},
+				Bests:         bests,
+				Bucket:        structures.STATS_BUCKET_WEEK,
+				Trend:         trend,
+			},
+		},
+		{
+			testName:      "no scores yet",
+			totalsErr:     gorm.ErrRecordNotFound,
+			expectedSince: "2026-09-20",
+			expectedStats: &structures.UserStats{
+				UserId:  2,
+				Rolling: []*structures.RollingAverage{{Window: 10, Scores: 3, Wpm: 80.33, Accuracy: 97}, {Window: 100, Scores: 3, Wpm: 80.33, Accuracy: 97}},
+				Bests:   bests,
+				Bucket:  structures.STATS_BUCKET_DAY,
+				Trend:   trend,
+			},
+		},
+		{
+			testName:    "unknown bucket",
+			bucket:      "month",
+			expectedErr: ErrInvalidBucket,
+		},
+		{
+			testName:    "unknown user",
+			userErr:     gorm.ErrRecordNotFound,
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.testName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStatsProvider := mockProviders.NewMockStatsProviderInterface(ctrl)
+			mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+			mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+			mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+			statsService := NewStatsService(mockStatsProvider, mockUsersProvider, mockKeyboardLayoutsProvider, mockTextsProvider, structures.DEFAULT_RECOMMENDATION_WEIGHTS)
+			statsService.now = func() time.Time { return now }
+
+			if tt.expectedErr != ErrInvalidBucket {
+				userId := 2
+				mockUsersProvider.EXPECT().GetUserByIdOrUsername(gomock.Any(), &userId, nil).Return(&structures.User{Id: 2}, tt.userErr)
+			}
+			if tt.expectedStats != nil {
+				mockStatsProvider.EXPECT().GetUserTotals(gomock.Any(), 2).Return(tt.totals, tt.totalsErr)
+				if tt.totals != nil {
+					mockStatsProvider.EXPECT().GetWpmPercentile(gomock.Any(), tt.totals.WpmSum/float64(tt.totals.Scores)).Return(62.5, nil)
+				}
+				for _, window := range structures.STATS_ROLLING_WINDOWS {
+					mockStatsProvider.EXPECT().GetRollingAverage(gomock.Any(), 2, window).Return(&structures.RollingAverage{Window: window, Scores: 3, Wpm: 80.33, Accuracy: 97}, nil)
+				}
+				mockStatsProvider.EXPECT().GetPersonalBests(gomock.Any(), 2).Return(bests, nil)
+				mockStatsProvider.EXPECT().GetTrend(gomock.Any(), 2, tt.expectedStats.Bucket, tt.expectedSince).Return(trend, nil)
+			}
+
+			stats, err := statsService.GetUserStats(context.Background(), 2, tt.bucket)
+			if err != tt.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if err := helpers.CompareReflectedStructFields(*stats, *tt.expectedStats); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestGetUserKeymapStats(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	// "the" typed as "tge" with the g fixed, then "The" typed as "Tye"
+	first, _ := helpers.EncodeKeystrokeLog([]*structures.KeystrokeEvent{
+		{Key: "t", Offset: 0, Expected: "t", Correct: true},
+		{Key: "g", Offset: 120, Expected: "h"},
+		{Key: structures.KEY_BACKSPACE, Offset: 300, Backspace: true},
+		{Key: "h", Offset: 420, Expected: "h", Correct: true},
+		{Key: "e", Offset: 530, Expected: "e", Correct: true},
+	})
+	second, _ := helpers.EncodeKeystrokeLog([]*structures.KeystrokeEvent{
+		{Key: "T", Offset: 0, Expected: "T", Correct: true},
+		{Key: "y", Offset: 140, Expected: "h"},
+		{Key: "e", Offset: 260, Expected: "e", Correct: true},
+	})
+
+	data := []struct {
+		testName      string
+		window        string
+		layout        string
+		userLayout    string
+		viewerId      int
+		viewerType    string
+		expectedSince time.Time
+		expectedStats *structures.KeymapStats
+		expectedErr   error
+	}{
+		{
+			testName:   "preferred layout over the whole history",
+			userLayout: "dvorak",
+			expectedStats: &structures.KeymapStats{
+				UserId: 2,
+				Layout: "dvorak",
+				Window: structures.LEADERBOARD_WINDOW_ALL,
+				Scores: 2,
+				Keys: []*structures.KeyStats{
+					{Key: "e", Row: structures.KEY_ROW_HOME, Finger: structures.FINGER_LEFT_MIDDLE, Attempts: 2, Accuracy: 100},
+					{Key: "h", Row: structures.KEY_ROW_HOME, Finger: structures.FINGER_RIGHT_INDEX, Attempts: 3, Errors: 2, Accuracy: 33.33},
+					{Key: "t", Row: structures.KEY_ROW_HOME, Finger: structures.FINGER_RIGHT_MIDDLE, Attempts: 2, Accuracy: 100},
+				},
+				Fingers: []*structures.FingerStats{
+					{Finger: structures.FINGER_LEFT_MIDDLE, Attempts: 2},
+					{Finger: structures.FINGER_RIGHT_INDEX, Attempts: 3, Errors: 2, ErrorRate: 66.67},
+					{Finger: structures.FINGER_RIGHT_MIDDLE, Attempts: 2},
+				},
+				Confusions: []*structures.ConfusionPair{
+					{Expected: "h", Typed: "g", Count: 1},
+					{Expected: "h", Typed: "y", Count: 1},
+				},
+			},
+		},
+		{
+			testName:      "last week on the default layout",
+			window:        structures.LEADERBOARD_WINDOW_WEEK,
+			expectedSince: now.Add(-7 * 24 * time.Hour),
+			expectedStats: &structures.KeymapStats{
+				UserId: 2,
+				Layout: structures.DEFAULT_KEYBOARD_LAYOUT,
+				Window: structures.LEADERBOARD_WINDOW_WEEK,
+				Scores: 2,
+				Keys: []*structures.KeyStats{
+					{Key: "e", Row: structures.KEY_ROW_TOP, Finger: structures.FINGER_LEFT_MIDDLE, Attempts: 2, Accuracy: 100},
+					{Key: "h", Row: structures.KEY_ROW_HOME, Finger: structures.FINGER_RIGHT_INDEX, Attempts: 3, Errors: 2, Accuracy: 33.33},
+					{Key: "t", Row: structures.KEY_ROW_TOP, Finger: structures.FINGER_LEFT_INDEX, Attempts: 2, Accuracy: 100},
+				},
+				Fingers: []*structures.FingerStats{
+					{Finger: structures.FINGER_LEFT_MIDDLE, Attempts: 2},
+					{Finger: structures.FINGER_LEFT_INDEX, Attempts: 2},
+					{Finger: structures.FINGER_RIGHT_INDEX, Attempts: 3, Errors: 2, ErrorRate: 66.67},
+				},
+				Confusions: []*structures.ConfusionPair{
+					{Expected: "h", Typed: "g", Count: 1},
+					{Expected: "h", Typed: "y", Count: 1},
+				},
+			},
+		},
+		{
+			testName:    "unknown window",
+			window:      "year",
+			expectedErr: ErrInvalidWindow,
+		},
+		{
+			testName:    "another user",
+			viewerId:    3,
+			viewerType:  structures.USER_TYPE_REGULAR,
+			expectedErr: ErrKeystrokeStatsForbidden,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.testName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStatsProvider := mockProviders.NewMockStatsProviderInterface(ctrl)
+			mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+			mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+			mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+			statsService := NewStatsService(mockStatsProvider, mockUsersProvider, mockKeyboardLayoutsProvider, mockTextsProvider, structures.DEFAULT_RECOMMENDATION_WEIGHTS)
+			statsService.now = func() time.Time { return now }
+
+			if tt.expectedStats != nil {
+				userId := 2
+				mockUsersProvider.EXPECT().GetUserByIdOrUsername(gomock.Any(), &userId, nil).Return(&structures.User{Id: 2, KeyboardLayout: tt.userLayout}, nil)
+				mockStatsProvider.EXPECT().GetUserKeystrokeLogs(gomock.Any(), 2, tt.expectedSince, structures.MAX_KEYMAP_STATS_LOGS).
+					Return([]*structures.ScoreKeystrokeLog{{ScoreId: 5, Data: first}, {ScoreId: 4, Data: second}}, nil)
+			}
+
+			viewerId, viewerType := 2, structures.USER_TYPE_REGULAR
+			if tt.viewerId != 0 {
+				viewerId, viewerType = tt.viewerId, tt.viewerType
+			}
+
+			stats, err := statsService.GetUserKeymapStats(context.Background(), 2, viewerId, viewerType, tt.window, tt.layout)
+			if err != tt.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if err := helpers.CompareReflectedStructFields(*stats, *tt.expectedStats); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// slowTheLogs types "the" three times, always taking long on "he"
+func slowTheLogs() []*structures.ScoreKeystrokeLog {
+	keystrokeLogs := []*structures.ScoreKeystrokeLog{}
+	for idx := 0; idx < 3; idx++ {
+		data, _ := helpers.EncodeKeystrokeLog([]*structures.KeystrokeEvent{
+			{Key: "t", Offset: 0, Expected: "t", Correct: true},
+			{Key: "h", Offset: 100, Expected: "h", Correct: true},
+			{Key: "e", Offset: 400, Expected: "e", Correct: true},
+		})
+		keystrokeLogs = append(keystrokeLogs, &structures.ScoreKeystrokeLog{ScoreId: idx + 1, Data: data})
+	}
+	return keystrokeLogs
+}
+
+func TestRefreshNgramBaselines(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	keystrokeLogs := []*structures.ScoreKeystrokeLog{}
+	for idx := 0; idx < structures.MIN_NGRAM_BASELINE_SAMPLES; idx++ {
+		keystrokeLogs = append(keystrokeLogs, slowTheLogs()[0])
+	}
+	keystrokeLogs = append(keystrokeLogs, &structures.ScoreKeystrokeLog{ScoreId: 9, Data: func() []byte {
+		data, _ := helpers.EncodeKeystrokeLog([]*structures.KeystrokeEvent{
+			{Key: "a", Offset: 0, Expected: "a", Correct: true},
+			{Key: "t", Offset: 150, Expected: "t", Correct: true},
+		})
+		return data
+	}()})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStatsProvider := mockProviders.NewMockStatsProviderInterface(ctrl)
+	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+	mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	statsService := NewStatsService(mockStatsProvider, mockUsersProvider, mockKeyboardLayoutsProvider, mockTextsProvider, structures.DEFAULT_RECOMMENDATION_WEIGHTS)
+	statsService.now = func() time.Time { return now }
+
+	expected := []*structures.NgramBaseline{
+		{Ngram: "he", Samples: structures.MIN_NGRAM_BASELINE_SAMPLES, MedianLatency: 300, UpdatedAt: now},
+		{Ngram: "th", Samples: structures.MIN_NGRAM_BASELINE_SAMPLES, MedianLatency: 100, UpdatedAt: now},
+		{Ngram: "the", Samples: structures.MIN_NGRAM_BASELINE_SAMPLES, MedianLatency: 400, UpdatedAt: now},
+	}
+	mockStatsProvider.EXPECT().GetLatestKeystrokeLogs(gomock.Any(), structures.MAX_NGRAM_BASELINE_LOGS).Return(keystrokeLogs, nil)
+	mockStatsProvider.EXPECT().ReplaceNgramBaselines(gomock.Any(), expected).Return(true, nil)
+
+	count, err := statsService.RefreshNgramBaselines(context.Background())
+	if err != nil {
+		t.Fatalf("expected error: %v but got %v instead", nil, err)
+	}
+	if count != len(expected) {
+		t.Fatalf("expected %d baselines but got %d instead", len(expected), count)
+	}
+}
+
+func TestGetUserNgramStats(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	data := []struct {
+		testName      string
+		window        string
+		viewerId      int
+		viewerType    string
+		expectedStats *structures.NgramStats
+		expectedErr   error
+	}{
+		{
+			testName: "compared to the baselines",
+			expectedStats: &structures.NgramStats{
+				UserId: 2,
+				Window: structures.LEADERBOARD_WINDOW_ALL,
+				Scores: 3,
+				Bigrams: []*structures.NgramLatency{
+					{Ngram: "he", Samples: 3, MedianLatency: 300, BaselineLatency: 150, Ratio: 2},
+					{Ngram: "th", Samples: 3, MedianLatency: 100, BaselineLatency: 100, Ratio: 1},
+				},
+				Trigrams: []*structures.NgramLatency{
+					{Ngram: "the", Samples: 3, MedianLatency: 400, BaselineLatency: 400, Ratio: 1},
+				},
+			},
+		},
+		{
+			testName:    "unknown window",
+			window:      "year",
+			expectedErr: ErrInvalidWindow,
+		},
+		{
+			testName:    "another user",
+			viewerId:    3,
+			viewerType:  structures.USER_TYPE_REGULAR,
+			expectedErr: ErrKeystrokeStatsForbidden,
+		},
+		{
+			testName:   "admin looking at another user",
+			viewerId:   1,
+			viewerType: structures.USER_TYPE_ADMIN,
+			expectedStats: &structures.NgramStats{
+				UserId: 2,
+				Window: structures.LEADERBOARD_WINDOW_ALL,
+				Scores: 3,
+				Bigrams: []*structures.NgramLatency{
+					{Ngram: "he", Samples: 3, MedianLatency: 300, BaselineLatency: 150, Ratio: 2},
+					{Ngram: "th", Samples: 3, MedianLatency: 100, BaselineLatency: 100, Ratio: 1},
+				},
+				Trigrams: []*structures.NgramLatency{
+					{Ngram: "the", Samples: 3, MedianLatency: 400, BaselineLatency: 400, Ratio: 1},
+				},
+			},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.testName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStatsProvider := mockProviders.NewMockStatsProviderInterface(ctrl)
+			mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+			mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+			mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+			statsService := NewStatsService(mockStatsProvider, mockUsersProvider, mockKeyboardLayoutsProvider, mockTextsProvider, structures.DEFAULT_RECOMMENDATION_WEIGHTS)
+			statsService.now = func() time.Time { return now }
+
+			if tt.expectedStats != nil {
+				userId := 2
+				mockUsersProvider.EXPECT().GetUserByIdOrUsername(gomock.Any(), &userId, nil).Return(&structures.User{Id: 2}, nil)
+				mockStatsProvider.EXPECT().GetUserKeystrokeLogs(gomock.Any(), 2, time.Time{}, structures.MAX_KEYMAP_STATS_LOGS).Return(slowTheLogs(), nil)
+				mockStatsProvider.EXPECT().GetNgramBaselines(gomock.Any()).Return([]*structures.NgramBaseline{
+					{Ngram: "he", Samples: 40, MedianLatency: 150},
+					{Ngram: "th", Samples: 40, MedianLatency: 100},
+				}, nil)
+			}
+
+			viewerId, viewerType := 2, structures.USER_TYPE_REGULAR
+			if tt.viewerId != 0 {
+				viewerId, viewerType = tt.viewerId, tt.viewerType
+			}
+
+			stats, err := statsService.GetUserNgramStats(context.Background(), 2, viewerId, viewerType, tt.window)
+			if err != tt.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if err := helpers.CompareReflectedStructFields(*stats, *tt.expectedStats); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestCreateNgramDrill(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	data := []struct {
+		testName       string
+		baselines      []*structures.NgramBaseline
+		existingDrill  *structures.Text
+		expectedNgrams []string
+		expectedErr    error
+	}{
+		{
+			testName:       "drill the slow sequences",
+			baselines:      []*structures.NgramBaseline{{Ngram: "he", Samples: 40, MedianLatency: 150}},
+			expectedNgrams: []string{"he"},
+		},
+		{
+			testName:       "previous drill rewritten",
+			baselines:      []*structures.NgramBaseline{{Ngram: "he", Samples: 40, MedianLatency: 150}},
+			existingDrill:  &structures.Text{Id: 7, TextBody: "older drill"},
+			expectedNgrams: []string{"he"},
+		},
+		{
+			testName:    "nothing slower than usual",
+			baselines:   []*structures.NgramBaseline{{Ngram: "he", Samples: 40, MedianLatency: 300}, {Ngram: "the", Samples: 40, MedianLatency: 400}},
+			expectedErr: ErrNoWeakNgrams,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.testName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStatsProvider := mockProviders.NewMockStatsProviderInterface(ctrl)
+			mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+			mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+			mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+			statsService := NewStatsService(mockStatsProvider, mockUsersProvider, mockKeyboardLayoutsProvider, mockTextsProvider, structures.DEFAULT_RECOMMENDATION_WEIGHTS)
+			statsService.now = func() time.Time { return now }
+
+			userId := 2
+			mockUsersProvider.EXPECT().GetUserByIdOrUsername(gomock.Any(), &userId, nil).Return(&structures.User{Id: 2}, nil)
+			mockStatsProvider.EXPECT().GetUserKeystrokeLogs(gomock.Any(), 2, time.Time{}, structures.MAX_KEYMAP_STATS_LOGS).Return(slowTheLogs(), nil)
+			mockStatsProvider.EXPECT().GetNgramBaselines(gomock.Any()).Return(tt.baselines, nil)
+			if tt.expectedErr == nil {
+				mockTextsProvider.EXPECT().GetTexts(gomock.Any(), structures.TextFilter{Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC}).
+					Return([]*structures.Text{{Id: 1, TextBody: "Where were the others?"}}, nil)
+				saveDrill := func(ctx context.Context, text structures.Text) (*structures.Text, error) {
+					if text.OwnerId == nil || *text.OwnerId != 2 || text.Visibility != structures.TEXT_VISIBILITY_PRIVATE {
+						t.Fatalf("expected a private drill owned by user 2 but got %+v instead", text)
+					}
+					if text.TextLength != len(text.TextBody) || !slices.Equal(text.Fingerprint, helpers.TextFingerprint(text.TextBody)) {
+						t.Fatalf("expected the length and fingerprint of the drill body but got %+v instead", text)
+					}
+					text.Id = 7
+					return &text, nil
+				}
+				if tt.existingDrill != nil {
+					mockTextsProvider.EXPECT().GetDrillText(gomock.Any(), 2, structures.NGRAM_DRILL_TITLE).Return(tt.existingDrill, nil)
+					mockTextsProvider.EXPECT().UpdateText(gomock.Any(), gomock.Any()).DoAndReturn(saveDrill)
+				} else {
+					mockTextsProvider.EXPECT().GetDrillText(gomock.Any(), 2, structures.NGRAM_DRILL_TITLE).Return(nil, gorm.ErrRecordNotFound)
+					mockTextsProvider.EXPECT().CreateText(gomock.Any(), gomock.Any()).DoAndReturn(saveDrill)
+				}
+			}
+
+			drill, err := statsService.CreateNgramDrill(context.Background(), 2, 2, structures.USER_TYPE_REGULAR)
+			if err != tt.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if !slices.Equal(drill.Ngrams, tt.expectedNgrams) {
+				t.Fatalf("expected ngrams: %v but got %v instead", tt.expectedNgrams, drill.Ngrams)
+			}
+			if drill.Text.Id != 7 || !strings.HasPrefix(drill.Text.TextBody, "others the where ") {
+				t.Fatalf("expected the drill to be made of words holding the sequences but got %q instead", drill.Text.TextBody)
+			}
+		})
+	}
+}
+
+func TestGetUserRecommendations(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	missedS, _ := helpers.EncodeKeystrokeLog([]*structures.KeystrokeEvent{
+		{Key: "x", Offset: 0, Expected: "s"},
+		{Key: "s", Offset: 150, Expected: "s", Correct: true},
+	})
+	keystrokeLogs := append(slowTheLogs(), &structures.ScoreKeystrokeLog{ScoreId: 4, Data: missedS})
+
+	sells := &structures.Text{Id: 1, Difficulty: "normal", TextBody: "Shes, she sells!", Status: structures.TEXT_STATUS_APPROVED, AverageRating: 5, RatingCount: 2}
+	other := &structures.Text{Id: 2, Difficulty: "easy", TextBody: "the other", Status: structures.TEXT_STATUS_APPROVED}
+	abc := &structures.Text{Id: 3, Difficulty: "hard", TextBody: "abc", Status: structures.TEXT_STATUS_APPROVED, AverageRating: 1, RatingCount: 1}
+	pending := &structures.Text{Id: 4, Difficulty: "easy", TextBody: "she", Status: structures.TEXT_STATUS_PENDING}
+
+	data := []struct {
+		testName      string
+		weights       *structures.RecommendationFactors
+		viewerId      int
+		viewerType    string
+		expectedTexts []*structures.Recommendation
+		expectedErr   error
+	}{
+		{
+			testName: "default weights",
+			expectedTexts: []*structures.Recommendation{
+				{Text: sells, Score: 10.0001, Factors: structures.RecommendationFactors{WeakKeys: 1, WeakNgrams: 0.6667, Difficulty: 1, Novelty: 1, Rating: 1}},
+				{Text: other, Score: 4.7858, Factors: structures.RecommendationFactors{WeakNgrams: 1, Difficulty: 0.5, Novelty: 0.1429, Rating: 0.5}},
+				{Text: abc, Score: 3, Factors: structures.RecommendationFactors{Difficulty: 0.5, Novelty: 1}},
+			},
+		},
+		{
+			testName: "novelty only, ties go to the lower id",
+			weights:  &structures.RecommendationFactors{Novelty: 1},
+			expectedTexts: []*structures.Recommendation{
+				{Text: sells, Score: 1, Factors: structures.RecommendationFactors{WeakKeys: 1, WeakNgrams: 0.6667, Difficulty: 1, Novelty: 1, Rating: 1}},
+				{Text: abc, Score: 1, Factors: structures.RecommendationFactors{Difficulty: 0.5, Novelty: 1}},
+				{Text: other, Score: 0.1429, Factors: structures.RecommendationFactors{WeakNgrams: 1, Difficulty: 0.5, Novelty: 0.1429, Rating: 0.5}},
+			},
+		},
+		{
+			testName:    "another user",
+			viewerId:    3,
+			viewerType:  structures.USER_TYPE_REGULAR,
+			expectedErr: ErrKeystrokeStatsForbidden,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.testName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStatsProvider := mockProviders.NewMockStatsProviderInterface(ctrl)
+			mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+			mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+			mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+			statsService := NewStatsService(mockStatsProvider, mockUsersProvider, mockKeyboardLayoutsProvider, mockTextsProvider, structures.DEFAULT_RECOMMENDATION_WEIGHTS)
+			statsService.now = func() time.Time { return now }
+			if tt.weights != nil {
+				statsService.RecommendationWeights = *tt.weights
+			}
+
+			if tt.expectedErr == nil {
+				userId := 2
+				mockUsersProvider.EXPECT().GetUserByIdOrUsername(gomock.Any(), &userId, nil).Return(&structures.User{Id: 2}, nil)
+				mockStatsProvider.EXPECT().GetUserKeystrokeLogs(gomock.Any(), 2, time.Time{}, structures.MAX_KEYMAP_STATS_LOGS).Return(keystrokeLogs, nil)
+				mockStatsProvider.EXPECT().GetNgramBaselines(gomock.Any()).Return([]*structures.NgramBaseline{
+					{Ngram: "he", Samples: 40, MedianLatency: 150},
+					{Ngram: "th", Samples: 40, MedianLatency: 100},
+				}, nil)
+				mockStatsProvider.EXPECT().GetRollingAverage(gomock.Any(), 2, structures.STATS_ROLLING_WINDOWS[0]).
+					Return(&structures.RollingAverage{Window: structures.STATS_ROLLING_WINDOWS[0], Scores: 4, Wpm: 55, Accuracy: 96}, nil)
+				mockStatsProvider.EXPECT().GetUserTextPlays(gomock.Any(), 2).
+					Return([]*structures.TextPlay{{TextId: 2, Plays: 3, LastPlayedAt: now.Add(-48 * time.Hour)}}, nil)
+				mockTextsProvider.EXPECT().GetTexts(gomock.Any(), structures.TextFilter{Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, ViewerId: 2}).
+					Return([]*structures.Text{sells, other, abc, pending}, nil)
+			}
+
+			viewerId, viewerType := 2, structures.USER_TYPE_REGULAR
+			if tt.viewerId != 0 {
+				viewerId, viewerType = tt.viewerId, tt.viewerType
+			}
+
+			recommendations, err := statsService.GetUserRecommendations(context.Background(), 2, viewerId, viewerType)
+			if err != tt.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			expected := structures.Recommendations{UserId: 2, Wpm: 55, WeakKeys: []string{"s"}, WeakNgrams: []string{"he"}, Texts: tt.expectedTexts}
+			if err := helpers.CompareReflectedStructFields(*recommendations, expected); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package tags_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"type_writer_api/helpers"
+	"type_writer_api/providers/tags"
+	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidTagName = errors.New("invalid tag name")
+	ErrTagNameTaken   = errors.New("tag name already in use")
+	ErrSelfMerge      = errors.New("cannot merge a tag into itself")
+)
+
+type TagsServiceInterface interface {
+	GetTags(ctx context.Context) ([]*structures.Tag, error)
+	GetTagByIdOrName(ctx context.Context, tagId *int, name *string) (*structures.Tag, error)
+	CreateTag(ctx context.Context, tagInfo structures.TagReq) (*structures.Tag, error)
+	RenameTag(ctx context.Context, tagInfo structures.TagReq, tagId int) (*structures.Tag, error)
+	DeleteTag(ctx context.Context, tagId int) (bool, error)
+	MergeTags(ctx context.Context, sourceId, targetId int) (*structures.Tag, error)
+}
+
+type TagsService struct {
+	TagsProvider tags_provider.TagsProviderInterface
+}
+
+func (t *TagsService) GetTags(ctx context.Context) ([]*structures.Tag, error) {
+	var result []*structures.Tag
+
+	tags, err := t.TagsProvider.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tag := range tags {
+		result = append(result, tag)
+	}
+
+	return result, nil
+}
+
+func (t *TagsService) GetTagByIdOrName(ctx context.Context, tagId *int, name *string) (*structures.Tag, error) {
+	if name != nil {
+		normalized := helpers.NormalizeTag(*name)
+		name = &normalized
+	}
+
+	tag, err := t.TagsProvider.GetTagByIdOrName(ctx, tagId, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := tag
+	return result, nil
+}
+
+func (t *TagsService) CreateTag(ctx context.Context, tagInfo structures.TagReq) (*structures.Tag, error) {
+	tagToCreate := structures.ConvertRequestToTag(&tagInfo)
+	tagToCreate.Name = helpers.NormalizeTag(tagToCreate.Name)
+	if tagToCreate.Name == "" {
+		return nil, ErrInvalidTagName
+	}
+
+	err := helpers.ValidateTags([]string{tagToCreate.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	createdTag, err := t.TagsProvider.CreateTag(ctx, *tagToCreate)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create tag", "error", err)
+		return nil, err
+	}
+
+	result := createdTag
+	return result, nil
+}
+
+// RenameTag changes the name of an existing tag, since texts reference tags by
+// id every linked text picks up the new name. Renaming onto a name already in
+// use is rejected, MergeTags should be used for that instead
+func (t *TagsService) RenameTag(ctx context.Context, tagInfo structures.TagReq, tagId int) (*structures.Tag, error) {
+	newName := helpers.NormalizeTag(tagInfo.Name)
+	if newName == "" {
+		return nil, ErrInvalidTagName
+	}
+
+	err := helpers.ValidateTags([]string{newName})
+	if err != nil {
+		return nil, err
+	}
+
+	existingTag, err := t.TagsProvider.GetTagByIdOrName(ctx, &tagId, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to rename tag", "error", err)
+		return nil, err
+	}
+
+	if existingTag.Name == newName {
+		return existingTag, nil
+	}
+
+	conflictingTag, err := t.TagsProvider.GetTagByIdOrName(ctx, nil, &newName)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		slog.ErrorContext(ctx, "failed to rename tag", "error", err)
+		return nil, err
+	} else if conflictingTag != nil {
+		return nil, ErrTagNameTaken
+	}
+
+	existingTag.Name = newName
+
+	updatedTag, err := t.TagsProvider.UpdateTag(ctx, *existingTag)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to rename tag", "error", err)
+		return nil, err
+	}
+
+	result := updatedTag
+	return result, nil
+}
+
+func (t *TagsService) DeleteTag(ctx context.Context, tagId int) (bool, error) {
+	deleted, err := t.TagsProvider.DeleteTag(ctx, tagId)
+	if err != nil {
+		return false, err
+	}
+
+	return deleted, nil
+}
+
+// MergeTags folds the source tag into the target tag, every text linked to the
+// source ends up linked to the target and the source tag is removed
+func (t *TagsService) MergeTags(ctx context.Context, sourceId, targetId int) (*structures.Tag, error) {
+	if sourceId == targetId {
+		return nil, ErrSelfMerge
+	}
+
+	for _, tagId := range []int{sourceId, targetId} {
+		_, err := t.TagsProvider.GetTagByIdOrName(ctx, &tagId, nil)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to merge tags", "error", err)
+			return nil, err
+		}
+	}
+
+	_, err := t.TagsProvider.MergeTags(ctx, sourceId, targetId)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to merge tags", "error", err)
+		return nil, err
+	}
+
+	mergedTag, err := t.TagsProvider.GetTagByIdOrName(ctx, &targetId, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to merge tags", "error", err)
+		return nil, err
+	}
+
+	result := mergedTag
+	return result, nil
+}
+
+func NewTagsService(tagsProvider tags_provider.TagsProviderInterface) *TagsService {
+	return &TagsService{
+		TagsProvider: tagsProvider,
+	}
+}
@@ -0,0 +1,305 @@
+package tags_service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"type_writer_api/helpers"
+	"type_writer_api/structures"
+	mockProviders "type_writer_api/testing/mocks/providers"
+
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestGetTags(t *testing.T) {
+	var (
+		mockResult1 = []*structures.Tag{
+			{Id: 1, Name: "classics", UsageCount: 3, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			{Id: 2, Name: "test tag", UsageCount: 1, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		}
+		expectedResult1 = []*structures.Tag{
+			{Id: 1, Name: "classics", UsageCount: 3, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			{Id: 2, Name: "test tag", UsageCount: 1, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		}
+		mockResult2     = []*structures.Tag{}
+		expectedResult2 = []*structures.Tag{}
+	)
+	data := []struct {
+		testName       string
+		mockResult     []*structures.Tag
+		mockErr        error
+		expectedResult []*structures.Tag
+		expectedErr    error
+	}{
+		{
+			"success",
+			mockResult1,
+			nil,
+			expectedResult1,
+			nil,
+		},
+		{
+			"empty result",
+			mockResult2,
+			nil,
+			expectedResult2,
+			nil,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTagsProvider := mockProviders.NewMockTagsProviderInterface(ctrl)
+	tagsService := NewTagsService(mockTagsProvider)
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			mockTagsProvider.EXPECT().GetTags(context.Background()).Return(testCase.mockResult, testCase.mockErr).Times(1)
+
+			result, err := tagsService.GetTags(context.Background())
+
+			if testCase.expectedErr != nil {
+				if err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+			}
+			if len(result) != len(testCase.expectedResult) {
+				t.Fatalf("slice length missmatch: got %v, expected %v", len(result), len(testCase.expectedResult))
+			}
+
+			for idx, tag := range result {
+				err := helpers.CompareReflectedStructFields(*tag, *testCase.expectedResult[idx])
+				if err != nil {
+					t.Fatalf("row %v failed: %v\n", idx, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestCreateTag(t *testing.T) {
+	data := []struct {
+		testName       string
+		inputTag       structures.TagReq
+		expectedInsert *structures.Tag
+		mockResult     *structures.Tag
+		expectedResult *structures.Tag
+		expectedErr    error
+	}{
+		{
+			"normalizes tag name",
+			structures.TagReq{Name: "  Test   Tag "},
+			&structures.Tag{Name: "test tag"},
+			&structures.Tag{Id: 1, Name: "test tag", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			&structures.Tag{Id: 1, Name: "test tag", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			nil,
+		},
+		{
+			"blank tag name",
+			structures.TagReq{Name: "   "},
+			nil,
+			nil,
+			nil,
+			ErrInvalidTagName,
+		},
+		{
+			"tag name longer than the column",
+			structures.TagReq{Name: strings.Repeat("a", structures.MAX_TAG_NAME_LENGTH+1)},
+			nil,
+			nil,
+			nil,
+			helpers.ErrTagTooLong,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTagsProvider := mockProviders.NewMockTagsProviderInterface(ctrl)
+	tagsService := NewTagsService(mockTagsProvider)
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			if testCase.expectedInsert != nil {
+				mockTagsProvider.EXPECT().CreateTag(context.Background(), *testCase.expectedInsert).Return(testCase.mockResult, nil).Times(1)
+			}
+
+			result, err := tagsService.CreateTag(context.Background(), testCase.inputTag)
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+			} else {
+				if err := helpers.CompareReflectedStructFields(*result, *testCase.expectedResult); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestRenameTag(t *testing.T) {
+	data := []struct {
+		testName          string
+		inputTag          structures.TagReq
+		inputUpdateId     int
+		mockQueryResult   *structures.Tag
+		mockConflict      *structures.Tag
+		mockConflictErr   error
+		expectedUpdate    bool
+		expectedResult    *structures.Tag
+		expectedErr       error
+	}{
+		{
+			"valid rename",
+			structures.TagReq{Name: "Classic Books"},
+			1,
+			&structures.Tag{Id: 1, Name: "classics", UsageCount: 3},
+			nil,
+			gorm.ErrRecordNotFound,
+			true,
+			&structures.Tag{Id: 1, Name: "classic books", UsageCount: 3},
+			nil,
+		},
+		{
+			"name taken by another tag",
+			structures.TagReq{Name: "test tag"},
+			1,
+			&structures.Tag{Id: 1, Name: "classics", UsageCount: 3},
+			&structures.Tag{Id: 2, Name: "test tag", UsageCount: 1},
+			nil,
+			false,
+			nil,
+			ErrTagNameTaken,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTagsProvider := mockProviders.NewMockTagsProviderInterface(ctrl)
+	tagsService := NewTagsService(mockTagsProvider)
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			newName := helpers.NormalizeTag(testCase.inputTag.Name)
+			mockTagsProvider.EXPECT().GetTagByIdOrName(context.Background(), &testCase.inputUpdateId, nil).Return(testCase.mockQueryResult, nil).Times(1)
+			mockTagsProvider.EXPECT().GetTagByIdOrName(context.Background(), nil, &newName).Return(testCase.mockConflict, testCase.mockConflictErr).Times(1)
+			if testCase.expectedUpdate {
+				mockTagsProvider.EXPECT().UpdateTag(
+					context.Background(),
+					gomock.Cond(func(input structures.Tag) bool { return helpers.CompareReflectedStructFields(input, *testCase.expectedResult) == nil }),
+				).Return(testCase.expectedResult, nil).Times(1)
+			}
+
+			result, err := tagsService.RenameTag(context.Background(), testCase.inputTag, testCase.inputUpdateId)
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+			} else {
+				if err := helpers.CompareReflectedStructFields(*result, *testCase.expectedResult); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	data := []struct {
+		testName       string
+		inputSourceId  int
+		inputTargetId  int
+		mockSourceErr  error
+		expectedMerge  bool
+		expectedResult *structures.Tag
+		expectedErr    error
+	}{
+		{
+			"valid merge",
+			1,
+			2,
+			nil,
+			true,
+			&structures.Tag{Id: 2, Name: "test tag", UsageCount: 4},
+			nil,
+		},
+		{
+			"self merge",
+			1,
+			1,
+			nil,
+			false,
+			nil,
+			ErrSelfMerge,
+		},
+		{
+			"source not found",
+			99,
+			2,
+			gorm.ErrRecordNotFound,
+			false,
+			nil,
+			gorm.ErrRecordNotFound,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTagsProvider := mockProviders.NewMockTagsProviderInterface(ctrl)
+	tagsService := NewTagsService(mockTagsProvider)
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			if testCase.inputSourceId != testCase.inputTargetId {
+				mockTagsProvider.EXPECT().GetTagByIdOrName(context.Background(), &testCase.inputSourceId, nil).
+					Return(&structures.Tag{Id: testCase.inputSourceId}, testCase.mockSourceErr).Times(1)
+			}
+			if testCase.expectedMerge {
+				mockTagsProvider.EXPECT().GetTagByIdOrName(context.Background(), &testCase.inputTargetId, nil).
+					Return(&structures.Tag{Id: testCase.inputTargetId}, nil).Times(1)
+				mockTagsProvider.EXPECT().MergeTags(context.Background(), testCase.inputSourceId, testCase.inputTargetId).Return(true, nil).Times(1)
+				mockTagsProvider.EXPECT().GetTagByIdOrName(context.Background(), &testCase.inputTargetId, nil).
+					Return(testCase.expectedResult, nil).Times(1)
+			}
+
+			result, err := tagsService.MergeTags(context.Background(), testCase.inputSourceId, testCase.inputTargetId)
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+			} else {
+				if err := helpers.CompareReflectedStructFields(*result, *testCase.expectedResult); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTagsProvider := mockProviders.NewMockTagsProviderInterface(ctrl)
+	tagsService := NewTagsService(mockTagsProvider)
+
+	mockTagsProvider.EXPECT().DeleteTag(context.Background(), 1).Return(true, nil).Times(1)
+
+	result, err := tagsService.DeleteTag(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result != true {
+		t.Fatalf("expected %v, got %v", true, result)
+	}
+}
@@ -2,64 +2,449 @@ package texts_service
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"sort"
+	"time"
+	"type_writer_api/helpers"
 	"type_writer_api/providers/texts"
 	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidTextStatus = errors.New("invalid text status")
+	ErrTextNotPending    = errors.New("text is not pending review")
+	ErrInvalidLanguage   = errors.New("invalid text language")
+	ErrUntypeableText    = errors.New("text contains untypeable characters")
+	ErrDuplicateText     = errors.New("text is a near duplicate of an existing text")
+	ErrInvalidVisibility = errors.New("invalid text visibility")
+	ErrTextNotOwned      = errors.New("text belongs to another user")
+	ErrInvalidLicense    = errors.New("invalid text license")
+	ErrInvalidSourceUrl  = errors.New("invalid text source url")
+	ErrInvalidRating     = errors.New("invalid text rating")
+	ErrInvalidSort       = errors.New("invalid text sort")
 )
 
 type TextsServiceInterface interface {
-	GetTexts(ctx context.Context) ([]*structures.Text, error)
-	GetTextByIdOrTitle(ctx context.Context, textId *int, title *string) (*structures.Text, error)
-	CreateText(ctx context.Context, textInfo structures.TextReq) (*structures.Text, error)
-	UpdateText(ctx context.Context, textInfo structures.TextReq, textId int) (*structures.Text, error)
-	DeleteText(ctx context.Context, textId int) (bool, error)
+	GetTexts(ctx context.Context, filter structures.TextFilter) ([]*structures.Text, error)
+	GetTextByIdOrTitle(ctx context.Context, textId *int, title *string, viewerId int, viewerType string) (*structures.Text, error)
+	GetTextByShareSlug(ctx context.Context, shareSlug string, viewerId int, viewerType string) (*structures.Text, error)
+	CreateText(ctx context.Context, textInfo structures.TextReq, submitterId int, submitterType string) (*structures.Text, error)
+	UpdateText(ctx context.Context, textInfo structures.TextReq, textId int, callerId int, callerType string) (*structures.Text, error)
+	DeleteText(ctx context.Context, textId int, callerId int, callerType string) (bool, error)
+	GetModerationTexts(ctx context.Context, status string) ([]*structures.Text, error)
+	ReviewText(ctx context.Context, reviewInfo structures.TextReviewReq, textId int, approved bool) (*structures.Text, error)
+	LintText(ctx context.Context, lintInfo structures.TextLintReq) (*structures.TextLintResult, error)
+	GetLicenses(ctx context.Context) ([]*structures.License, error)
+	RateText(ctx context.Context, ratingInfo structures.TextRatingReq, textId int, userId int, userType string) (*structures.TextRating, error)
+	DeleteTextRating(ctx context.Context, textId int, userId int) (bool, error)
+	FavoriteText(ctx context.Context, textId int, userId int, userType string) (bool, error)
+	UnfavoriteText(ctx context.Context, textId int, userId int) (bool, error)
+	GetFavoriteTexts(ctx context.Context, userId int, viewerId int, viewerType string) ([]*structures.Text, error)
+	GetSimilarTexts(ctx context.Context, textId int, viewerId int, viewerType string) ([]*structures.SimilarText, error)
+	BackfillFingerprints(ctx context.Context) (int, error)
 }
 
 type TextsService struct {
 	TextsProvider texts_provider.TextsProviderInterface
+	Normalizer    *helpers.TextNormalizer
+}
+
+// normalizeBody runs a text body through the normalization pipeline, bodies
+// still holding flagged characters are refused
+func (t *TextsService) normalizeBody(body string) (string, error) {
+	normalized, issues := t.Normalizer.Normalize(body)
+	if helpers.HasFlaggedIssues(issues) {
+		return "", ErrUntypeableText
+	}
+	return normalized, nil
+}
+
+// findSimilarTexts compares a fingerprint against the fingerprinted texts of
+// the catalog and the texts of the caller that were not rejected, returning the
+// ones above the warn threshold most similar first. Submissions of other users
+// still in draft or waiting for review are neither shown nor held against anyone
+func (t *TextsService) findSimilarTexts(ctx context.Context, fingerprint []uint32, excludeId int, callerId int) ([]*structures.SimilarText, error) {
+	similar := []*structures.SimilarText{}
+	if len(fingerprint) == 0 {
+		return similar, nil
+	}
+
+	fingerprints, err := t.TextsProvider.GetTextFingerprints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, other := range fingerprints {
+		if other.Id == excludeId || other.Status == structures.TEXT_STATUS_REJECTED {
+			continue
+		}
+		// texts of other users that are not in the catalog stay out of the comparison
+		ownText := callerId != 0 && other.OwnerId != nil && *other.OwnerId == callerId
+		inCatalog := other.Status == structures.TEXT_STATUS_APPROVED && other.Visibility == structures.TEXT_VISIBILITY_PUBLIC
+		if !inCatalog && !ownText {
+			continue
+		}
+		similarity := helpers.FingerprintSimilarity(fingerprint, other.Fingerprint)
+		if similarity < helpers.DUPLICATE_WARN_SIMILARITY {
+			continue
+		}
+		similar = append(similar, &structures.SimilarText{
+			TextId:     other.Id,
+			Title:      other.Title,
+			Status:     other.Status,
+			Similarity: similarity,
+		})
+	}
+
+	sort.SliceStable(similar, func(i, j int) bool {
+		return similar[i].Similarity > similar[j].Similarity
+	})
+	return similar, nil
 }
 
-func (t *TextsService) GetTexts(ctx context.Context) ([]*structures.Text, error) {
+// isDuplicate reports whether the closest match is similar enough to be refused
+func isDuplicate(similar []*structures.SimilarText) bool {
+	return len(similar) != 0 && similar[0].Similarity >= helpers.DUPLICATE_REJECT_SIMILARITY
+}
+
+func isValidTextVisibility(visibility string) bool {
+	switch visibility {
+	case structures.TEXT_VISIBILITY_PUBLIC, structures.TEXT_VISIBILITY_UNLISTED, structures.TEXT_VISIBILITY_PRIVATE:
+		return true
+	}
+	return false
+}
+
+// ownsText reports whether the caller manages the text and can see it whatever
+// its status and visibility, admins manage every text
+func ownsText(text *structures.Text, callerId int, callerType string) bool {
+	if callerType == structures.USER_TYPE_ADMIN {
+		return true
+	}
+	return callerId != 0 && text.OwnerId != nil && *text.OwnerId == callerId
+}
+
+func isCatalogText(text *structures.Text) bool {
+	return text.Status == structures.TEXT_STATUS_APPROVED && text.Visibility == structures.TEXT_VISIBILITY_PUBLIC
+}
+
+// hideShareSlug keeps the share slug of a text for the ones allowed to hand it out
+func hideShareSlug(text *structures.Text, viewerId int, viewerType string) {
+	if !ownsText(text, viewerId, viewerType) {
+		text.ShareSlug = nil
+	}
+}
+
+// ensureShareSlug gives unlisted texts the slug they are shared by, a text
+// keeps its slug when it changes visibility so old links work if it is unlisted again
+func ensureShareSlug(text *structures.Text) error {
+	if text.Visibility != structures.TEXT_VISIBILITY_UNLISTED || text.ShareSlug != nil {
+		return nil
+	}
+	slug, err := helpers.RandomSlug(helpers.SHARE_SLUG_BYTES)
+	if err != nil {
+		return err
+	}
+	text.ShareSlug = &slug
+	return nil
+}
+
+// resolveAttribution validates the source metadata of a text, attribution is
+// required whenever the license asks for it and can be requested on top of that
+func resolveAttribution(text *structures.Text, requested *bool) error {
+	if text.License != "" {
+		license, ok := helpers.NormalizeLicense(text.License)
+		if !ok {
+			return ErrInvalidLicense
+		}
+		text.License = license
+	}
+	if text.SourceUrl != "" && !helpers.IsValidSourceUrl(text.SourceUrl) {
+		return ErrInvalidSourceUrl
+	}
+
+	if requested != nil {
+		text.AttributionRequired = *requested
+	}
+	if license, ok := helpers.LicenseById(text.License); ok && license.AttributionRequired {
+		text.AttributionRequired = true
+	}
+	return nil
+}
+
+// withAttribution fills the credit line of texts whose license requires one
+func withAttribution(text *structures.Text) *structures.Text {
+	if text.AttributionRequired {
+		text.Attribution = helpers.AttributionLine(text)
+	}
+	return text
+}
+
+// SaveDrillText stores a drill made for its owner, a user keeps a single
+// drill text per title that is rewritten each time a new drill is made
+func SaveDrillText(ctx context.Context, textsProvider texts_provider.TextsProviderInterface, drill structures.Text) (*structures.Text, error) {
+	existing, err := textsProvider.GetDrillText(ctx, *drill.OwnerId, drill.Title)
+	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
+		return textsProvider.CreateText(ctx, drill)
+	} else if err != nil {
+		return nil, err
+	}
+
+	drill.Id = existing.Id
+	drill.CreatedAt = existing.CreatedAt
+	return textsProvider.UpdateText(ctx, drill)
+}
+
+// AttachAttributions credits the text each score was typed on when its license
+// requires it, every text is only looked up once
+func AttachAttributions(ctx context.Context, textsProvider texts_provider.TextsProviderInterface, scores ...*structures.Score) error {
+	attributions := map[int]string{}
+
+	for _, score := range scores {
+		attribution, found := attributions[score.TextId]
+		if !found {
+			text, err := textsProvider.GetTextByIdOrTitle(ctx, &score.TextId, nil)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if err == nil && text.AttributionRequired {
+				attribution = helpers.AttributionLine(text)
+			}
+			attributions[score.TextId] = attribution
+		}
+		score.Attribution = attribution
+	}
+	return nil
+}
+
+func isValidTextSort(sort string) bool {
+	switch sort {
+	case "", structures.TEXT_SORT_TOP_RATED, structures.TEXT_SORT_MOST_PLAYED:
+		return true
+	}
+	return false
+}
+
+func isValidTextStatus(status string) bool {
+	switch status {
+	case structures.TEXT_STATUS_DRAFT, structures.TEXT_STATUS_PENDING, structures.TEXT_STATUS_APPROVED, structures.TEXT_STATUS_REJECTED:
+		return true
+	}
+	return false
+}
+
+// submissionStatus resolves the status a text lands with, admins curate the
+// catalog directly while everyone else goes through the moderation queue and
+// can only keep a text as draft or submit it for review, texts that are not
+// public never reach the catalog so they need no review, although a text a
+// moderator rejected or has yet to look at keeps that status when it is hidden
+func submissionStatus(requested, current, submitterType, visibility string) (string, error) {
+	if submitterType == structures.USER_TYPE_ADMIN {
+		if requested == "" {
+			return structures.TEXT_STATUS_APPROVED, nil
+		}
+		if !isValidTextStatus(requested) {
+			return "", ErrInvalidTextStatus
+		}
+		return requested, nil
+	}
+
+	switch requested {
+	case "", structures.TEXT_STATUS_PENDING:
+		if visibility != structures.TEXT_VISIBILITY_PUBLIC {
+			if current == structures.TEXT_STATUS_REJECTED || current == structures.TEXT_STATUS_PENDING {
+				return current, nil
+			}
+			return structures.TEXT_STATUS_APPROVED, nil
+		}
+		return structures.TEXT_STATUS_PENDING, nil
+	case structures.TEXT_STATUS_DRAFT:
+		return structures.TEXT_STATUS_DRAFT, nil
+	}
+	return "", ErrInvalidTextStatus
+}
+
+// GetTexts lists the public catalog, only approved public texts are ever
+// returned no matter the status asked for in the filter, along with every text
+// owned by the viewer of the filter
+func (t *TextsService) GetTexts(ctx context.Context, filter structures.TextFilter) ([]*structures.Text, error) {
 	var result []*structures.Text
 
-	texts, err := t.TextsProvider.GetTexts(ctx)
+	filter.Status = structures.TEXT_STATUS_APPROVED
+	filter.Visibility = structures.TEXT_VISIBILITY_PUBLIC
+	if filter.Language != "" {
+		language, ok := helpers.NormalizeLanguage(filter.Language)
+		if !ok {
+			return nil, ErrInvalidLanguage
+		}
+		filter.Language = language
+	}
+	if filter.License != "" {
+		license, ok := helpers.NormalizeLicense(filter.License)
+		if !ok {
+			return nil, ErrInvalidLicense
+		}
+		filter.License = license
+	}
+	if !isValidTextSort(filter.Sort) {
+		return nil, ErrInvalidSort
+	}
+
+	texts, err := t.TextsProvider.GetTexts(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, text := range texts {
-		result = append(result, text)
+		hideShareSlug(text, filter.ViewerId, "")
+		result = append(result, withAttribution(text))
 	}
 
 	return result, nil
 }
 
-func (t *TextsService) GetTextByIdOrTitle(ctx context.Context, textId *int, title *string) (*structures.Text, error) {
+func (t *TextsService) GetTextByIdOrTitle(ctx context.Context, textId *int, title *string, viewerId int, viewerType string) (*structures.Text, error) {
 	text, err := t.TextsProvider.GetTextByIdOrTitle(ctx, textId, title)
 	if err != nil {
 		return nil, err
 	}
 
-	result := text
+	// texts that did not make it through moderation are not part of the catalog,
+	// and unlisted or private texts are only found by id by the ones managing them
+	if !isCatalogText(text) && !ownsText(text, viewerId, viewerType) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	hideShareSlug(text, viewerId, viewerType)
+
+	result := withAttribution(text)
+	return result, nil
+}
+
+// GetTextByShareSlug resolves a share link, any approved text that is not
+// private can be reached through its slug
+func (t *TextsService) GetTextByShareSlug(ctx context.Context, shareSlug string, viewerId int, viewerType string) (*structures.Text, error) {
+	text, err := t.TextsProvider.GetTextByShareSlug(ctx, shareSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := text.Status == structures.TEXT_STATUS_APPROVED && text.Visibility != structures.TEXT_VISIBILITY_PRIVATE
+	if !shared && !ownsText(text, viewerId, viewerType) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	hideShareSlug(text, viewerId, viewerType)
+
+	result := withAttribution(text)
 	return result, nil
 }
 
-func (t *TextsService) CreateText(ctx context.Context, textInfo structures.TextReq) (*structures.Text, error) {
+func (t *TextsService) CreateText(ctx context.Context, textInfo structures.TextReq, submitterId int, submitterType string) (*structures.Text, error) {
 	textToCreate := structures.ConvertRequestToText(&textInfo)
+	textToCreate.Tags = helpers.NormalizeTags(textToCreate.Tags)
+	err := helpers.ValidateTags(textToCreate.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := t.normalizeBody(textToCreate.TextBody)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create text", "error", err)
+		return nil, err
+	}
+	textToCreate.TextBody = body
+	textToCreate.TextLength = len(body)
+
+	if err := resolveAttribution(textToCreate, textInfo.AttributionRequired); err != nil {
+		slog.ErrorContext(ctx, "failed to create text", "error", err)
+		return nil, err
+	}
+
+	textToCreate.Language = helpers.DEFAULT_LANGUAGE
+	if textInfo.Language != "" {
+		language, ok := helpers.NormalizeLanguage(textInfo.Language)
+		if !ok {
+			slog.ErrorContext(ctx, "failed to create text", "error", ErrInvalidLanguage)
+			return nil, ErrInvalidLanguage
+		}
+		textToCreate.Language = language
+	}
+
+	textToCreate.Visibility = structures.TEXT_VISIBILITY_PUBLIC
+	if textInfo.Visibility != "" {
+		if !isValidTextVisibility(textInfo.Visibility) {
+			slog.ErrorContext(ctx, "failed to create text", "error", ErrInvalidVisibility)
+			return nil, ErrInvalidVisibility
+		}
+		textToCreate.Visibility = textInfo.Visibility
+	}
+
+	status, err := submissionStatus(textInfo.Status, "", submitterType, textToCreate.Visibility)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create text", "error", err)
+		return nil, err
+	}
+	textToCreate.Status = status
+	if submitterId != 0 {
+		textToCreate.SubmitterId = &submitterId
+		textToCreate.OwnerId = &submitterId
+	}
+	if err := ensureShareSlug(textToCreate); err != nil {
+		slog.ErrorContext(ctx, "failed to create text", "error", err)
+		return nil, err
+	}
+
+	// near duplicates are kept out of the catalog unless an admin explicitly lets
+	// them through, anything less similar is created and reported back as a warning
+	textToCreate.Fingerprint = helpers.TextFingerprint(body)
+	similar, err := t.findSimilarTexts(ctx, textToCreate.Fingerprint, 0, submitterId)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create text", "error", err)
+		return nil, err
+	}
+	allowDuplicate := textToCreate.Visibility != structures.TEXT_VISIBILITY_PUBLIC ||
+		(textInfo.AllowDuplicate && submitterType == structures.USER_TYPE_ADMIN)
+	if isDuplicate(similar) && !allowDuplicate {
+		slog.ErrorContext(ctx, "failed to create text", "error", ErrDuplicateText, "similar_text_id", similar[0].TextId)
+		return nil, ErrDuplicateText
+	}
 
 	createdText, err := t.TextsProvider.CreateText(ctx, *textToCreate)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to create text", "error", err)
 		return nil, err
 	}
+	if len(similar) != 0 {
+		createdText.SimilarTexts = similar
+	}
 
-	result := createdText
+	result := withAttribution(createdText)
 	return result, nil
 }
 
-func (t *TextsService) UpdateText(ctx context.Context, textInfo structures.TextReq, textId int) (*structures.Text, error) {
+// checkTextOwner fetches a text for the caller to manage, texts the caller
+// cannot even see are reported as missing
+func (t *TextsService) checkTextOwner(ctx context.Context, textId int, callerId int, callerType string) (*structures.Text, error) {
 	existingText, err := t.TextsProvider.GetTextByIdOrTitle(ctx, &textId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ownsText(existingText, callerId, callerType) {
+		if isCatalogText(existingText) {
+			return nil, ErrTextNotOwned
+		}
+		return nil, gorm.ErrRecordNotFound
+	}
+	return existingText, nil
+}
 
+// UpdateText changes a text on behalf of its owner or an admin, edits by
+// anyone else than an admin send public texts back through moderation
+func (t *TextsService) UpdateText(ctx context.Context, textInfo structures.TextReq, textId int, callerId int, callerType string) (*structures.Text, error) {
+	existingText, err := t.checkTextOwner(ctx, textId, callerId, callerType)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to update text", "error", err)
 		return nil, err
@@ -74,12 +459,98 @@ func (t *TextsService) UpdateText(ctx context.Context, textInfo structures.TextR
 	if textInfo.Difficulty != "" {
 		existingText.Difficulty = textInfo.Difficulty
 	}
+	if textInfo.Language != "" {
+		language, ok := helpers.NormalizeLanguage(textInfo.Language)
+		if !ok {
+			slog.ErrorContext(ctx, "failed to update text", "error", ErrInvalidLanguage)
+			return nil, ErrInvalidLanguage
+		}
+		existingText.Language = language
+	}
 	if len(textInfo.Tags) != 0 {
-		existingText.Tags = textInfo.Tags
+		existingText.Tags = helpers.NormalizeTags(textInfo.Tags)
+		err = helpers.ValidateTags(existingText.Tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if textInfo.Author != "" {
+		existingText.Author = textInfo.Author
+	}
+	if textInfo.Source != "" {
+		existingText.Source = textInfo.Source
+	}
+	if textInfo.SourceUrl != "" {
+		existingText.SourceUrl = textInfo.SourceUrl
+	}
+	if textInfo.License != "" {
+		existingText.License = textInfo.License
+		// a new license brings its own attribution terms unless asked otherwise
+		if textInfo.AttributionRequired == nil {
+			existingText.AttributionRequired = false
+		}
+	}
+	if err := resolveAttribution(existingText, textInfo.AttributionRequired); err != nil {
+		slog.ErrorContext(ctx, "failed to update text", "error", err)
+		return nil, err
+	}
+	if textInfo.Visibility != "" {
+		if !isValidTextVisibility(textInfo.Visibility) {
+			slog.ErrorContext(ctx, "failed to update text", "error", ErrInvalidVisibility)
+			return nil, ErrInvalidVisibility
+		}
+		existingText.Visibility = textInfo.Visibility
 	}
 	if textInfo.TextBody != "" {
-		existingText.TextBody = textInfo.TextBody
-		existingText.TextLength = len(textInfo.TextBody)
+		body, err := t.normalizeBody(textInfo.TextBody)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to update text", "error", err)
+			return nil, err
+		}
+		existingText.TextBody = body
+		existingText.TextLength = len(body)
+		existingText.Fingerprint = helpers.TextFingerprint(body)
+	}
+	// a private or unlisted text made public has to clear the duplicate check
+	// as much as a new public body does
+	allowDuplicate := textInfo.AllowDuplicate && callerType == structures.USER_TYPE_ADMIN
+	if existingText.Visibility == structures.TEXT_VISIBILITY_PUBLIC && !allowDuplicate {
+		if len(existingText.Fingerprint) == 0 {
+			existingText.Fingerprint = helpers.TextFingerprint(existingText.TextBody)
+		}
+		similar, err := t.findSimilarTexts(ctx, existingText.Fingerprint, existingText.Id, callerId)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to update text", "error", err)
+			return nil, err
+		}
+		if isDuplicate(similar) {
+			slog.ErrorContext(ctx, "failed to update text", "error", ErrDuplicateText, "similar_text_id", similar[0].TextId)
+			return nil, ErrDuplicateText
+		}
+	}
+	if callerType == structures.USER_TYPE_ADMIN {
+		if textInfo.Status != "" {
+			if !isValidTextStatus(textInfo.Status) {
+				slog.ErrorContext(ctx, "failed to update text", "error", ErrInvalidTextStatus)
+				return nil, ErrInvalidTextStatus
+			}
+			existingText.Status = textInfo.Status
+		}
+	} else {
+		requested := textInfo.Status
+		if requested == "" && existingText.Status == structures.TEXT_STATUS_DRAFT {
+			requested = structures.TEXT_STATUS_DRAFT
+		}
+		status, err := submissionStatus(requested, existingText.Status, callerType, existingText.Visibility)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to update text", "error", err)
+			return nil, err
+		}
+		existingText.Status = status
+	}
+	if err := ensureShareSlug(existingText); err != nil {
+		slog.ErrorContext(ctx, "failed to update text", "error", err)
+		return nil, err
 	}
 
 	updatedText, err := t.TextsProvider.UpdateText(ctx, *existingText)
@@ -88,11 +559,17 @@ func (t *TextsService) UpdateText(ctx context.Context, textInfo structures.TextR
 		return nil, err
 	}
 
-	result := updatedText
+	result := withAttribution(updatedText)
 	return result, nil
 }
 
-func (t *TextsService) DeleteText(ctx context.Context, textId int) (bool, error) {
+func (t *TextsService) DeleteText(ctx context.Context, textId int, callerId int, callerType string) (bool, error) {
+	if callerType != structures.USER_TYPE_ADMIN {
+		if _, err := t.checkTextOwner(ctx, textId, callerId, callerType); err != nil {
+			return false, err
+		}
+	}
+
 	deleted, err := t.TextsProvider.DeleteText(ctx, textId)
 	if err != nil {
 		return false, err
@@ -101,8 +578,216 @@ func (t *TextsService) DeleteText(ctx context.Context, textId int) (bool, error)
 	return deleted, nil
 }
 
-func NewTextsService(textsProvider texts_provider.TextsProviderInterface) *TextsService {
+// GetModerationTexts lists texts in the given moderation status, pending texts
+// when no status is given
+func (t *TextsService) GetModerationTexts(ctx context.Context, status string) ([]*structures.Text, error) {
+	var result []*structures.Text
+
+	if status == "" {
+		status = structures.TEXT_STATUS_PENDING
+	}
+	if !isValidTextStatus(status) {
+		return nil, ErrInvalidTextStatus
+	}
+
+	// only public texts go through moderation, nobody reviews private material
+	texts, err := t.TextsProvider.GetTexts(ctx, structures.TextFilter{Status: status, Visibility: structures.TEXT_VISIBILITY_PUBLIC})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, text := range texts {
+		result = append(result, text)
+	}
+
+	return result, nil
+}
+
+// ReviewText approves or rejects a pending text, approved texts become part of
+// the public catalog
+func (t *TextsService) ReviewText(ctx context.Context, reviewInfo structures.TextReviewReq, textId int, approved bool) (*structures.Text, error) {
+	existingText, err := t.TextsProvider.GetTextByIdOrTitle(ctx, &textId, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to review text", "error", err)
+		return nil, err
+	}
+
+	if existingText.Status != structures.TEXT_STATUS_PENDING {
+		return nil, ErrTextNotPending
+	}
+
+	reviewedAt := time.Now()
+	existingText.Status = structures.TEXT_STATUS_REJECTED
+	if approved {
+		existingText.Status = structures.TEXT_STATUS_APPROVED
+	}
+	existingText.ReviewerNotes = reviewInfo.ReviewerNotes
+	existingText.ReviewedAt = &reviewedAt
+
+	reviewedText, err := t.TextsProvider.UpdateText(ctx, *existingText)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to review text", "error", err)
+		return nil, err
+	}
+
+	result := reviewedText
+	return result, nil
+}
+
+// LintText reports every character the normalization pipeline would replace,
+// remove or refuse, along with the text as it would be stored
+func (t *TextsService) LintText(ctx context.Context, lintInfo structures.TextLintReq) (*structures.TextLintResult, error) {
+	normalized, issues := t.Normalizer.Normalize(lintInfo.TextBody)
+
+	result := &structures.TextLintResult{
+		Typeable:       !helpers.HasFlaggedIssues(issues),
+		NormalizedBody: normalized,
+		Issues:         issues,
+	}
+	return result, nil
+}
+
+// GetSimilarTexts lists the catalog texts that look like the given text, most
+// similar first
+func (t *TextsService) GetSimilarTexts(ctx context.Context, textId int, viewerId int, viewerType string) ([]*structures.SimilarText, error) {
+	text, err := t.GetTextByIdOrTitle(ctx, &textId, nil, viewerId, viewerType)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint := text.Fingerprint
+	if len(fingerprint) == 0 {
+		fingerprint = helpers.TextFingerprint(text.TextBody)
+	}
+
+	similar, err := t.findSimilarTexts(ctx, fingerprint, text.Id, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*structures.SimilarText{}
+	for _, other := range similar {
+		if other.Status == structures.TEXT_STATUS_APPROVED {
+			result = append(result, other)
+		}
+	}
+	return result, nil
+}
+
+// BackfillFingerprints fingerprints the texts stored before fingerprinting
+// existed, returning how many were updated
+func (t *TextsService) BackfillFingerprints(ctx context.Context) (int, error) {
+	texts, err := t.TextsProvider.GetUnfingerprintedTexts(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to backfill text fingerprints", "error", err)
+		return 0, err
+	}
+
+	updated := 0
+	for _, text := range texts {
+		fingerprint := helpers.TextFingerprint(text.TextBody)
+		if len(fingerprint) == 0 {
+			continue
+		}
+		if _, err := t.TextsProvider.UpdateTextFingerprint(ctx, text.Id, fingerprint); err != nil {
+			slog.ErrorContext(ctx, "failed to backfill text fingerprints", "error", err, "text_id", text.Id)
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+// GetLicenses lists the licenses texts can be published under
+func (t *TextsService) GetLicenses(ctx context.Context) ([]*structures.License, error) {
+	result := helpers.Licenses()
+	return result, nil
+}
+
+// RateText stores the caller rating of a text they can see, rating again
+// replaces the earlier rating
+func (t *TextsService) RateText(ctx context.Context, ratingInfo structures.TextRatingReq, textId int, userId int, userType string) (*structures.TextRating, error) {
+	if ratingInfo.Rating < structures.MIN_TEXT_RATING || ratingInfo.Rating > structures.MAX_TEXT_RATING {
+		return nil, ErrInvalidRating
+	}
+
+	if _, err := t.GetTextByIdOrTitle(ctx, &textId, nil, userId, userType); err != nil {
+		slog.ErrorContext(ctx, "failed to rate text", "error", err)
+		return nil, err
+	}
+
+	rating, err := t.TextsProvider.RateText(ctx, structures.TextRating{UserId: userId, TextId: textId, Rating: ratingInfo.Rating})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to rate text", "error", err)
+		return nil, err
+	}
+
+	result := rating
+	return result, nil
+}
+
+func (t *TextsService) DeleteTextRating(ctx context.Context, textId int, userId int) (bool, error) {
+	deleted, err := t.TextsProvider.DeleteTextRating(ctx, userId, textId)
+	if err != nil {
+		return false, err
+	}
+
+	return deleted, nil
+}
+
+// FavoriteText adds a text the caller can see to their favorites, favoriting
+// twice changes nothing
+func (t *TextsService) FavoriteText(ctx context.Context, textId int, userId int, userType string) (bool, error) {
+	if _, err := t.GetTextByIdOrTitle(ctx, &textId, nil, userId, userType); err != nil {
+		slog.ErrorContext(ctx, "failed to favorite text", "error", err)
+		return false, err
+	}
+
+	favorited, err := t.TextsProvider.FavoriteText(ctx, userId, textId)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to favorite text", "error", err)
+		return false, err
+	}
+
+	return favorited, nil
+}
+
+func (t *TextsService) UnfavoriteText(ctx context.Context, textId int, userId int) (bool, error) {
+	unfavorited, err := t.TextsProvider.UnfavoriteText(ctx, userId, textId)
+	if err != nil {
+		return false, err
+	}
+
+	return unfavorited, nil
+}
+
+// GetFavoriteTexts lists the favorites of a user, texts that left the catalog
+// are only listed for the ones still allowed to see them
+func (t *TextsService) GetFavoriteTexts(ctx context.Context, userId int, viewerId int, viewerType string) ([]*structures.Text, error) {
+	var result []*structures.Text
+
+	texts, err := t.TextsProvider.GetTexts(ctx, structures.TextFilter{
+		Status:      structures.TEXT_STATUS_APPROVED,
+		Visibility:  structures.TEXT_VISIBILITY_PUBLIC,
+		ViewerId:    viewerId,
+		FavoritedBy: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, text := range texts {
+		hideShareSlug(text, viewerId, viewerType)
+		result = append(result, withAttribution(text))
+	}
+
+	return result, nil
+}
+
+func NewTextsService(textsProvider texts_provider.TextsProviderInterface, normalizer *helpers.TextNormalizer) *TextsService {
 	return &TextsService{
 		TextsProvider: textsProvider,
+		Normalizer:    normalizer,
 	}
 }
@@ -2,6 +2,7 @@ package texts_service
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,15 +14,20 @@ import (
 	"gorm.io/gorm"
 )
 
+func testNormalizer() *helpers.TextNormalizer {
+	normalizer, _ := helpers.NewTextNormalizer(nil)
+	return normalizer
+}
+
 func TestGetTexts(t *testing.T) {
 	var (
 		mockResult1 = []*structures.Text{
-			{1, "drill", "test text 1", "normal", []string{"test elem"}, "test text body", len("test text body"), time.Now(), time.Now()},
-			{2, "drill", "test text 2", "normal", []string{"test elem"}, "test text body", len("test text body"), time.Now(), time.Now()},
+			{Id: 1, TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			{Id: 2, TextType: "drill", Title: "test text 2", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 		}
 		expectedResult1 = []*structures.Text{
-			{1, "drill", "test text 1", "normal", []string{"test elem"}, "test text body", len("test text body"), time.Now(), time.Now()},
-			{2, "drill", "test text 2", "normal", []string{"test elem"}, "test text body", len("test text body"), time.Now(), time.Now()},
+			{Id: 1, TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			{Id: 2, TextType: "drill", Title: "test text 2", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 		}
 		mockResult2 = []*structures.Text{}
 		expectedResult2 = []*structures.Text{}
@@ -53,13 +59,13 @@ func TestGetTexts(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
-	textsService := NewTextsService(mockTextsProvider)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
-			mockTextsProvider.EXPECT().GetTexts(context.Background()).Return(testCase.mockResult, testCase.mockErr).Times(1)
+			mockTextsProvider.EXPECT().GetTexts(context.Background(), structures.TextFilter{Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC}).Return(testCase.mockResult, testCase.mockErr).Times(1)
 
-			result, err := textsService.GetTexts(context.Background())
+			result, err := textsService.GetTexts(context.Background(), structures.TextFilter{Status: structures.TEXT_STATUS_PENDING})
 
 			if testCase.expectedErr != nil {
 				if err.Error() != testCase.expectedErr.Error() {
@@ -80,6 +86,29 @@ func TestGetTexts(t *testing.T) {
 	}
 }
 
+func TestGetTextsByLanguage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	mockTextsProvider.EXPECT().
+		GetTexts(context.Background(), structures.TextFilter{Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, Language: "de-DE"}).
+		Return([]*structures.Text{}, nil).
+		Times(1)
+
+	_, err := textsService.GetTexts(context.Background(), structures.TextFilter{Language: "de_de"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	_, err = textsService.GetTexts(context.Background(), structures.TextFilter{Language: "d"})
+	if err != ErrInvalidLanguage {
+		t.Fatalf("expected error: %v but got %v instead", ErrInvalidLanguage, err)
+	}
+}
+
 func TestGetTextByIdOrName(t *testing.T) {
 	data := []struct{
 		testName string
@@ -94,18 +123,18 @@ func TestGetTextByIdOrName(t *testing.T) {
 			"valid id",
 			1,
 			"",
-			&structures.Text{1, "drill", "test text 1", "normal", []string{"test elem"}, "test text body", len("test text body"), time.Now(), time.Now()},
+			&structures.Text{Id: 1, TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.Text{1, "drill", "test text 1", "normal", []string{"test elem"}, "test text body", len("test text body"), time.Now(), time.Now()},
+			&structures.Text{Id: 1, TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
 		{
 			"valid title",
 			0,
 			"test text 1",
-			&structures.Text{1, "drill", "test text 1", "normal", []string{"test elem"}, "test text body", len("test text body"), time.Now(), time.Now()},
+			&structures.Text{Id: 1, TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.Text{1, "drill", "test text 1", "normal", []string{"test elem"}, "test text body", len("test text body"), time.Now(), time.Now()},
+			&structures.Text{Id: 1, TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
 		{
@@ -117,19 +146,28 @@ func TestGetTextByIdOrName(t *testing.T) {
 			nil,
 			gorm.ErrRecordNotFound,
 		},
+		{
+			"pending text is hidden",
+			2,
+			"",
+			&structures.Text{Id: 2, TextType: "drill", Title: "test text 2", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Status: "pending", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			nil,
+			nil,
+			gorm.ErrRecordNotFound,
+		},
 	}
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
-	textsService := NewTextsService(mockTextsProvider)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
 			mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &testCase.inputId, &testCase.inputName).Return(testCase.mockResult, testCase.mockErr).Times(1)
 
-			result, err := textsService.GetTextByIdOrTitle(context.Background(), &testCase.inputId, &testCase.inputName)
+			result, err := textsService.GetTextByIdOrTitle(context.Background(), &testCase.inputId, &testCase.inputName, 0, "")
 
 			if testCase.expectedErr != nil {
 				if err.Error() != testCase.expectedErr.Error() {
@@ -145,38 +183,128 @@ func TestGetTextByIdOrName(t *testing.T) {
 }
 
 func TestCreateText(t *testing.T) {
+	adminId := 1
+	submitterId := 2
 	data := []struct{
 		testName string
 		inputText structures.TextReq
+		inputSubmitterId int
+		inputSubmitterType string
+		expectedInsert *structures.Text
 		mockResult *structures.Text
 		mockErr error
 		expectedResult *structures.Text
 		expectedErr error
 	}{
 		{
-			"valid input text request",
-			structures.TextReq{"drill", "test text 1", "normal", []string{"test elem"}, "test text body"},
-			&structures.Text{1, "drill", "test text 1", "normal", []string{"test elem"}, "test text body", len("test text body"), time.Now(), time.Now()},
+			"admin text goes straight to the catalog",
+			structures.TextReq{TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{" Test  Elem"}, TextBody: "test text body"},
+			adminId,
+			"admin",
+			&structures.Text{TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Language: "en", Status: "approved", Visibility: "public", SubmitterId: &adminId, OwnerId: &adminId},
+			&structures.Text{Id: 1, TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.Text{1, "drill", "test text 1", "normal", []string{"test elem"}, "test text body", len("test text body"), time.Now(), time.Now()},
+			&structures.Text{Id: 1, TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
+		{
+			"regular user cannot self approve",
+			structures.TextReq{TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", Status: "approved"},
+			submitterId,
+			"regular",
+			nil,
+			nil,
+			nil,
+			nil,
+			ErrInvalidTextStatus,
+		},
+		{
+			"tag longer than the tags column",
+			structures.TextReq{TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{strings.Repeat("a", structures.MAX_TAG_NAME_LENGTH+1)}, TextBody: "test text body"},
+			submitterId,
+			"regular",
+			nil,
+			nil,
+			nil,
+			nil,
+			helpers.ErrTagTooLong,
+		},
+		{
+			"regular user submission defaults to pending",
+			structures.TextReq{TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body"},
+			submitterId,
+			"regular",
+			&structures.Text{TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Language: "en", Status: "pending", Visibility: "public", SubmitterId: &submitterId, OwnerId: &submitterId},
+			&structures.Text{Id: 2, TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Status: "pending", Visibility: "public", SubmitterId: &submitterId, OwnerId: &submitterId, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			nil,
+			&structures.Text{Id: 2, TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Status: "pending", Visibility: "public", SubmitterId: &submitterId, OwnerId: &submitterId, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			nil,
+		},
+		{
+			"language is normalized",
+			structures.TextReq{TextType: "drill", Title: "texto 1", Difficulty: "normal", Language: "PT_br", TextBody: "texto de teste"},
+			adminId,
+			"admin",
+			&structures.Text{TextType: "drill", Title: "texto 1", Difficulty: "normal", Tags: []string{}, TextBody: "texto de teste", TextLength: len("texto de teste"), Language: "pt-BR", Status: "approved", Visibility: "public", SubmitterId: &adminId, OwnerId: &adminId},
+			&structures.Text{Id: 3, TextType: "drill", Title: "texto 1", Difficulty: "normal", Tags: []string{}, TextBody: "texto de teste", TextLength: len("texto de teste"), Language: "pt-BR", Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			nil,
+			&structures.Text{Id: 3, TextType: "drill", Title: "texto 1", Difficulty: "normal", Tags: []string{}, TextBody: "texto de teste", TextLength: len("texto de teste"), Language: "pt-BR", Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			nil,
+		},
+		{
+			"malformed language",
+			structures.TextReq{TextType: "drill", Title: "test text 1", Difficulty: "normal", Language: "english!", TextBody: "test text body"},
+			adminId,
+			"admin",
+			nil,
+			nil,
+			nil,
+			nil,
+			ErrInvalidLanguage,
+		},
+		{
+			"pasted punctuation is normalized",
+			structures.TextReq{TextType: "drill", Title: "test text 1", Difficulty: "normal", TextBody: "“don’t\u00a0stop” — ok…\u200b"},
+			adminId,
+			"admin",
+			&structures.Text{TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{}, TextBody: "\"don't stop\" - ok...", TextLength: len("\"don't stop\" - ok..."), Language: "en", Status: "approved", Visibility: "public", SubmitterId: &adminId, OwnerId: &adminId},
+			&structures.Text{Id: 4, TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{}, TextBody: "\"don't stop\" - ok...", TextLength: len("\"don't stop\" - ok..."), Language: "en", Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			nil,
+			&structures.Text{Id: 4, TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{}, TextBody: "\"don't stop\" - ok...", TextLength: len("\"don't stop\" - ok..."), Language: "en", Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			nil,
+		},
+		{
+			"untypeable symbols are refused",
+			structures.TextReq{TextType: "drill", Title: "test text 1", Difficulty: "normal", TextBody: "good job ✅"},
+			adminId,
+			"admin",
+			nil,
+			nil,
+			nil,
+			nil,
+			ErrUntypeableText,
+		},
 	}
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
-	textsService := NewTextsService(mockTextsProvider)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
-			mockTextsProvider.EXPECT().CreateText(context.Background(), *structures.ConvertRequestToText(&testCase.inputText)).Return(testCase.mockResult, testCase.mockErr).Times(1)
+			if testCase.expectedInsert != nil {
+				expectedInsert := *testCase.expectedInsert
+				expectedInsert.Fingerprint = helpers.TextFingerprint(expectedInsert.TextBody)
+				mockTextsProvider.EXPECT().GetTextFingerprints(context.Background()).Return([]*structures.TextFingerprint{}, nil).Times(1)
+				mockTextsProvider.EXPECT().CreateText(context.Background(), expectedInsert).Return(testCase.mockResult, testCase.mockErr).Times(1)
+			}
 
-			result, err := textsService.CreateText(context.Background(), testCase.inputText)
+			result, err := textsService.CreateText(context.Background(), testCase.inputText, testCase.inputSubmitterId, testCase.inputSubmitterType)
 
 			if testCase.expectedErr != nil {
-				if err.Error() != testCase.expectedErr.Error() {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
 					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
 				}
 			} else {
@@ -201,12 +329,12 @@ func TestUpdateText(t *testing.T) {
 	}{
 		{
 			"valid input text request",
-			structures.TextReq{"drill", "test text 1", "normal", []string{"test elem"}, "test text body after update"},
+			structures.TextReq{TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body after update"},
 			1,
-			&structures.Text{1, "drill", "test text 1", "normal", []string{"test elem"}, "test text body", len("test text body"), time.Now(), time.Now()},
-			&structures.Text{1, "drill", "test text 1", "normal", []string{"test elem"}, "test text body after update", len("test text body after update"), time.Now(), time.Now()},
+			&structures.Text{Id: 1, TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body", TextLength: len("test text body"), Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			&structures.Text{Id: 1, TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body after update", TextLength: len("test text body after update"), Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.Text{1, "drill", "test text 1", "normal", []string{"test elem"}, "test text body after update", len("test text body after update"), time.Now(), time.Now()},
+			&structures.Text{Id: 1, TextType: "drill", Title: "test text 1", Difficulty: "normal", Tags: []string{"test elem"}, TextBody: "test text body after update", TextLength: len("test text body after update"), Status: "approved", Visibility: "public", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
 	}
@@ -215,17 +343,21 @@ func TestUpdateText(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
-	textsService := NewTextsService(mockTextsProvider)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
+			expectedUpdate := *testCase.mockResult
+			expectedUpdate.Fingerprint = helpers.TextFingerprint(expectedUpdate.TextBody)
+
 			mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &testCase.inputUpdateId, nil).Return(testCase.mockQueryResult, testCase.mockErr).Times(1)
+			mockTextsProvider.EXPECT().GetTextFingerprints(context.Background()).Return([]*structures.TextFingerprint{}, nil).Times(1)
 			mockTextsProvider.EXPECT().UpdateText(
 				context.Background(),
-				gomock.Cond(func(input structures.Text) bool { return helpers.CompareReflectedStructFields(input, *testCase.mockResult) == nil}),
+				gomock.Cond(func(input structures.Text) bool { return helpers.CompareReflectedStructFields(input, expectedUpdate) == nil}),
 			).Return(testCase.mockResult, testCase.mockErr).Times(1)
 
-			result, err := textsService.UpdateText(context.Background(), testCase.inputText, testCase.inputUpdateId)
+			result, err := textsService.UpdateText(context.Background(), testCase.inputText, testCase.inputUpdateId, 1, structures.USER_TYPE_ADMIN)
 
 			if testCase.expectedErr != nil {
 				if err.Error() != testCase.expectedErr.Error() {
@@ -271,13 +403,13 @@ func TestDeleteText(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
-	textsService := NewTextsService(mockTextsProvider)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
 			mockTextsProvider.EXPECT().DeleteText(context.Background(), testCase.inputDeleteId).Return(testCase.mockResult, testCase.mockErr).Times(1)
 
-			result, err := textsService.DeleteText(context.Background(), testCase.inputDeleteId)
+			result, err := textsService.DeleteText(context.Background(), testCase.inputDeleteId, 1, structures.USER_TYPE_ADMIN)
 
 			if testCase.expectedErr != nil {
 				if err.Error() != testCase.expectedErr.Error() {
@@ -291,3 +423,935 @@ func TestDeleteText(t *testing.T) {
 		})
 	}
 }
+
+func TestGetModerationTexts(t *testing.T) {
+	data := []struct{
+		testName string
+		inputStatus string
+		expectedFilter *structures.TextFilter
+		mockResult []*structures.Text
+		expectedLength int
+		expectedErr error
+	}{
+		{
+			"defaults to pending texts",
+			"",
+			&structures.TextFilter{Status: structures.TEXT_STATUS_PENDING, Visibility: structures.TEXT_VISIBILITY_PUBLIC},
+			[]*structures.Text{
+				{Id: 2, TextType: "drill", Title: "test text 2", Difficulty: "normal", Tags: []string{}, TextBody: "test text body", TextLength: len("test text body"), Status: "pending"},
+			},
+			1,
+			nil,
+		},
+		{
+			"rejected texts",
+			"rejected",
+			&structures.TextFilter{Status: structures.TEXT_STATUS_REJECTED, Visibility: structures.TEXT_VISIBILITY_PUBLIC},
+			[]*structures.Text{},
+			0,
+			nil,
+		},
+		{
+			"invalid status",
+			"published",
+			nil,
+			nil,
+			0,
+			ErrInvalidTextStatus,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			if testCase.expectedFilter != nil {
+				mockTextsProvider.EXPECT().GetTexts(context.Background(), *testCase.expectedFilter).Return(testCase.mockResult, nil).Times(1)
+			}
+
+			result, err := textsService.GetModerationTexts(context.Background(), testCase.inputStatus)
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+			}
+			if len(result) != testCase.expectedLength {
+				t.Fatalf("slice length missmatch: got %v, expected %v", len(result), testCase.expectedLength)
+			}
+		})
+	}
+}
+
+func TestReviewText(t *testing.T) {
+	data := []struct{
+		testName string
+		inputReview structures.TextReviewReq
+		inputTextId int
+		inputApproved bool
+		mockQueryResult *structures.Text
+		expectedStatus string
+		expectedErr error
+	}{
+		{
+			"approve pending text",
+			structures.TextReviewReq{ReviewerNotes: "looks good"},
+			2,
+			true,
+			&structures.Text{Id: 2, Title: "test text 2", Tags: []string{}, Status: "pending"},
+			structures.TEXT_STATUS_APPROVED,
+			nil,
+		},
+		{
+			"reject pending text",
+			structures.TextReviewReq{ReviewerNotes: "duplicated text"},
+			2,
+			false,
+			&structures.Text{Id: 2, Title: "test text 2", Tags: []string{}, Status: "pending"},
+			structures.TEXT_STATUS_REJECTED,
+			nil,
+		},
+		{
+			"text already reviewed",
+			structures.TextReviewReq{},
+			1,
+			true,
+			&structures.Text{Id: 1, Title: "test text 1", Tags: []string{}, Status: "approved"},
+			"",
+			ErrTextNotPending,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &testCase.inputTextId, nil).Return(testCase.mockQueryResult, nil).Times(1)
+			if testCase.expectedErr == nil {
+				mockTextsProvider.EXPECT().UpdateText(
+					context.Background(),
+					gomock.Cond(func(input structures.Text) bool {
+						return input.Status == testCase.expectedStatus &&
+							input.ReviewerNotes == testCase.inputReview.ReviewerNotes &&
+							input.ReviewedAt != nil
+					}),
+				).DoAndReturn(func(_ context.Context, input structures.Text) (*structures.Text, error) { return &input, nil }).Times(1)
+			}
+
+			result, err := textsService.ReviewText(context.Background(), testCase.inputReview, testCase.inputTextId, testCase.inputApproved)
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+			} else if result.Status != testCase.expectedStatus {
+				t.Fatalf("expected status %v, got %v", testCase.expectedStatus, result.Status)
+			}
+		})
+	}
+}
+
+func TestLintText(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	result, err := textsService.LintText(context.Background(), structures.TextLintReq{TextBody: "ﬁne\r\nit’s ☃\u200b"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if result.Typeable {
+		t.Fatalf("expected text with a snowman to be untypeable")
+	}
+	if result.NormalizedBody != "fine\nit's ☃" {
+		t.Fatalf("unexpected normalized body %q", result.NormalizedBody)
+	}
+
+	expectedIssues := []*structures.TextLintIssue{
+		{Position: 0, Line: 1, Column: 1, Char: "ﬁ", CodePoint: "U+FB01", Rule: "ligatures", Action: "replace", Replacement: "fi"},
+		{Position: 7, Line: 2, Column: 3, Char: "’", CodePoint: "U+2019", Rule: "single_quotes", Action: "replace", Replacement: "'"},
+		{Position: 10, Line: 2, Column: 6, Char: "☃", CodePoint: "U+2603", Rule: "untypeable_symbols", Action: "flag"},
+		{Position: 11, Line: 2, Column: 7, Char: "\u200b", CodePoint: "U+200B", Rule: "zero_width", Action: "remove"},
+	}
+	if len(result.Issues) != len(expectedIssues) {
+		t.Fatalf("slice length missmatch: got %v, expected %v", len(result.Issues), len(expectedIssues))
+	}
+	for idx, issue := range result.Issues {
+		if err := helpers.CompareReflectedStructFields(*issue, *expectedIssues[idx]); err != nil {
+			t.Fatalf("issue %v failed: %v", idx, err)
+		}
+	}
+}
+
+func TestLintTextOverrides(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	normalizer, err := helpers.NewTextNormalizer(map[string]string{"dashes": "flag", "double_quotes": "keep"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, normalizer)
+
+	result, _ := textsService.LintText(context.Background(), structures.TextLintReq{TextBody: "“a” — b"})
+	if result.Typeable || len(result.Issues) != 1 || result.Issues[0].Rule != "dashes" || result.NormalizedBody != "“a” — b" {
+		t.Fatalf("unexpected lint result %+v", result)
+	}
+
+	_, err = helpers.NewTextNormalizer(map[string]string{"zero_width": "replace"})
+	if err == nil {
+		t.Fatalf("expected replace override without a replacement to fail")
+	}
+}
+
+func TestCreateTextDuplicates(t *testing.T) {
+	body := "the quick brown fox jumps over the lazy dog while the cat sleeps in the warm afternoon sun"
+	nearBody := body + " and later that evening both of them went out to play"
+	otherBody := "pack my box with five dozen liquor jugs and then take the long road home before the rain"
+	adminId := 1
+	submitterId := 2
+
+	storedFingerprints := []*structures.TextFingerprint{
+		{Id: 1, Title: "the fox", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, Fingerprint: helpers.TextFingerprint(body)},
+		{Id: 2, Title: "the box", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, Fingerprint: helpers.TextFingerprint(otherBody)},
+	}
+
+	data := []struct {
+		testName           string
+		inputText          structures.TextReq
+		inputSubmitterId   int
+		inputSubmitterType string
+		expectCreate       bool
+		expectedSimilarIds []int
+		expectedErr        error
+	}{
+		{
+			testName:           "exact copy is refused",
+			inputText:          structures.TextReq{TextType: "drill", Title: "the fox again", Difficulty: "normal", TextBody: body},
+			inputSubmitterId:   submitterId,
+			inputSubmitterType: "regular",
+			expectedErr:        ErrDuplicateText,
+		},
+		{
+			testName:           "reformatted copy is refused",
+			inputText:          structures.TextReq{TextType: "drill", Title: "the fox again", Difficulty: "normal", TextBody: "The quick, brown fox jumps over the LAZY dog; while the cat sleeps in the warm afternoon sun."},
+			inputSubmitterId:   adminId,
+			inputSubmitterType: "admin",
+			expectedErr:        ErrDuplicateText,
+		},
+		{
+			testName:           "regular users cannot force a duplicate",
+			inputText:          structures.TextReq{TextType: "drill", Title: "the fox again", Difficulty: "normal", TextBody: body, AllowDuplicate: true},
+			inputSubmitterId:   submitterId,
+			inputSubmitterType: "regular",
+			expectedErr:        ErrDuplicateText,
+		},
+		{
+			testName:           "admins can force a duplicate",
+			inputText:          structures.TextReq{TextType: "drill", Title: "the fox again", Difficulty: "normal", TextBody: body, AllowDuplicate: true},
+			inputSubmitterId:   adminId,
+			inputSubmitterType: "admin",
+			expectCreate:       true,
+			expectedSimilarIds: []int{1},
+		},
+		{
+			testName:           "extended passage is created with a warning",
+			inputText:          structures.TextReq{TextType: "drill", Title: "the fox at night", Difficulty: "normal", TextBody: nearBody},
+			inputSubmitterId:   submitterId,
+			inputSubmitterType: "regular",
+			expectCreate:       true,
+			expectedSimilarIds: []int{1},
+		},
+		{
+			testName:           "unrelated text has no warnings",
+			inputText:          structures.TextReq{TextType: "drill", Title: "something else", Difficulty: "normal", TextBody: "sphinx of black quartz judge my vow said the old wizard to the young knight"},
+			inputSubmitterId:   adminId,
+			inputSubmitterType: "admin",
+			expectCreate:       true,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			mockTextsProvider.EXPECT().GetTextFingerprints(context.Background()).Return(storedFingerprints, nil).Times(1)
+			if testCase.expectCreate {
+				mockTextsProvider.EXPECT().CreateText(context.Background(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, text structures.Text) (*structures.Text, error) {
+						text.Id = 3
+						return &text, nil
+					},
+				).Times(1)
+			}
+
+			result, err := textsService.CreateText(context.Background(), testCase.inputText, testCase.inputSubmitterId, testCase.inputSubmitterType)
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if len(result.SimilarTexts) != len(testCase.expectedSimilarIds) {
+				t.Fatalf("unexpected similar texts %+v", result.SimilarTexts)
+			}
+			for idx, similar := range result.SimilarTexts {
+				if similar.TextId != testCase.expectedSimilarIds[idx] {
+					t.Fatalf("expected similar text %v at %v, got %v", testCase.expectedSimilarIds[idx], idx, similar.TextId)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateTextIgnoresPendingSubmissions(t *testing.T) {
+	body := "the quick brown fox jumps over the lazy dog while the cat sleeps in the warm afternoon sun"
+	otherId := 3
+	submitterId := 2
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	// submissions of other users still under review are no one's business yet,
+	// the caller's own ones still count
+	mockTextsProvider.EXPECT().GetTextFingerprints(context.Background()).Return([]*structures.TextFingerprint{
+		{Id: 1, Title: "their draft", Status: structures.TEXT_STATUS_DRAFT, Visibility: structures.TEXT_VISIBILITY_PUBLIC, OwnerId: &otherId, Fingerprint: helpers.TextFingerprint(body)},
+		{Id: 2, Title: "their pending fox", Status: structures.TEXT_STATUS_PENDING, Visibility: structures.TEXT_VISIBILITY_PUBLIC, OwnerId: &otherId, Fingerprint: helpers.TextFingerprint(body)},
+		{Id: 4, Title: "my private fox", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PRIVATE, OwnerId: &submitterId, Fingerprint: helpers.TextFingerprint(body)},
+	}, nil).Times(1)
+	mockTextsProvider.EXPECT().CreateText(context.Background(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, text structures.Text) (*structures.Text, error) {
+			text.Id = 5
+			return &text, nil
+		},
+	).Times(1)
+
+	result, err := textsService.CreateText(context.Background(), structures.TextReq{Title: "the fox", TextBody: body, Visibility: "private"}, submitterId, "regular")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(result.SimilarTexts) != 1 || result.SimilarTexts[0].TextId != 4 {
+		t.Fatalf("unexpected similar texts %+v", result.SimilarTexts)
+	}
+
+	// nor do they block a public submission of the same passage
+	mockTextsProvider.EXPECT().GetTextFingerprints(context.Background()).Return([]*structures.TextFingerprint{
+		{Id: 2, Title: "their pending fox", Status: structures.TEXT_STATUS_PENDING, Visibility: structures.TEXT_VISIBILITY_PUBLIC, OwnerId: &otherId, Fingerprint: helpers.TextFingerprint(body)},
+	}, nil).Times(1)
+	mockTextsProvider.EXPECT().CreateText(context.Background(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, text structures.Text) (*structures.Text, error) {
+			text.Id = 6
+			return &text, nil
+		},
+	).Times(1)
+
+	result, err = textsService.CreateText(context.Background(), structures.TextReq{Title: "the fox", TextBody: body}, submitterId, "regular")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(result.SimilarTexts) != 0 {
+		t.Fatalf("unexpected similar texts %+v", result.SimilarTexts)
+	}
+}
+
+func TestGetSimilarTexts(t *testing.T) {
+	body := "the quick brown fox jumps over the lazy dog while the cat sleeps in the warm afternoon sun"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	textId := 1
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &textId, nil).Return(
+		&structures.Text{Id: 1, TextBody: body, Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, Fingerprint: helpers.TextFingerprint(body)}, nil,
+	).Times(1)
+	mockTextsProvider.EXPECT().GetTextFingerprints(context.Background()).Return([]*structures.TextFingerprint{
+		{Id: 1, Title: "itself", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, Fingerprint: helpers.TextFingerprint(body)},
+		{Id: 2, Title: "approved copy", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, Fingerprint: helpers.TextFingerprint(body)},
+		{Id: 3, Title: "pending copy", Status: structures.TEXT_STATUS_PENDING, Visibility: structures.TEXT_VISIBILITY_PUBLIC, Fingerprint: helpers.TextFingerprint(body)},
+		{Id: 4, Title: "rejected copy", Status: structures.TEXT_STATUS_REJECTED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, Fingerprint: helpers.TextFingerprint(body)},
+		{Id: 5, Title: "unrelated", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, Fingerprint: helpers.TextFingerprint("pack my box with five dozen liquor jugs")},
+	}, nil).Times(1)
+
+	result, err := textsService.GetSimilarTexts(context.Background(), textId, 0, "")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if len(result) != 1 || result[0].TextId != 2 || result[0].Similarity != 1 {
+		t.Fatalf("unexpected similar texts %+v", result)
+	}
+}
+
+func TestBackfillFingerprints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	mockTextsProvider.EXPECT().GetUnfingerprintedTexts(context.Background()).Return([]*structures.Text{
+		{Id: 1, TextBody: "test text body"},
+		{Id: 2, TextBody: ""},
+	}, nil).Times(1)
+	mockTextsProvider.EXPECT().UpdateTextFingerprint(context.Background(), 1, helpers.TextFingerprint("test text body")).Return(true, nil).Times(1)
+
+	updated, err := textsService.BackfillFingerprints(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if updated != 1 {
+		t.Fatalf("unexpected updated count: expected %v, got %v", 1, updated)
+	}
+}
+
+func TestTextVisibility(t *testing.T) {
+	ownerId := 2
+	slug := "c2hhcmVkLXNsdWc"
+	privateText := func() *structures.Text {
+		return &structures.Text{Id: 5, Title: "work notes", TextBody: "work notes", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PRIVATE, OwnerId: &ownerId, ShareSlug: &slug}
+	}
+	unlistedText := func() *structures.Text {
+		return &structures.Text{Id: 6, Title: "shared notes", TextBody: "shared notes", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_UNLISTED, OwnerId: &ownerId, ShareSlug: &slug}
+	}
+
+	data := []struct {
+		testName     string
+		mockText     *structures.Text
+		bySlug       bool
+		viewerId     int
+		viewerType   string
+		expectedErr  error
+		expectedSlug bool
+	}{
+		{testName: "owner sees private text", mockText: privateText(), viewerId: ownerId, viewerType: "regular", expectedSlug: true},
+		{testName: "admin sees private text", mockText: privateText(), viewerId: 1, viewerType: "admin", expectedSlug: true},
+		{testName: "other user cannot see private text", mockText: privateText(), viewerId: 3, viewerType: "regular", expectedErr: gorm.ErrRecordNotFound},
+		{testName: "anonymous cannot see private text", mockText: privateText(), expectedErr: gorm.ErrRecordNotFound},
+		{testName: "unlisted text is not reachable by id", mockText: unlistedText(), viewerId: 3, viewerType: "regular", expectedErr: gorm.ErrRecordNotFound},
+		{testName: "unlisted text is reachable by slug", mockText: unlistedText(), bySlug: true},
+		{testName: "private text is not reachable by slug", mockText: privateText(), bySlug: true, expectedErr: gorm.ErrRecordNotFound},
+		{testName: "owner reaches private text by slug", mockText: privateText(), bySlug: true, viewerId: ownerId, viewerType: "regular", expectedSlug: true},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			var (
+				result *structures.Text
+				err    error
+			)
+			if testCase.bySlug {
+				mockTextsProvider.EXPECT().GetTextByShareSlug(context.Background(), slug).Return(testCase.mockText, nil).Times(1)
+				result, err = textsService.GetTextByShareSlug(context.Background(), slug, testCase.viewerId, testCase.viewerType)
+			} else {
+				mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &testCase.mockText.Id, nil).Return(testCase.mockText, nil).Times(1)
+				result, err = textsService.GetTextByIdOrTitle(context.Background(), &testCase.mockText.Id, nil, testCase.viewerId, testCase.viewerType)
+			}
+
+			if testCase.expectedErr != nil {
+				if err != testCase.expectedErr {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if (result.ShareSlug != nil) != testCase.expectedSlug {
+				t.Fatalf("unexpected share slug %v", result.ShareSlug)
+			}
+		})
+	}
+}
+
+func TestCreatePrivateText(t *testing.T) {
+	body := "the quick brown fox jumps over the lazy dog while the cat sleeps in the warm afternoon sun"
+	ownerId := 2
+	otherId := 3
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	// a copy of a catalog text is fine as long as it stays out of the catalog,
+	// and private texts of other users are never compared against
+	mockTextsProvider.EXPECT().GetTextFingerprints(context.Background()).Return([]*structures.TextFingerprint{
+		{Id: 1, Title: "the fox", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, Fingerprint: helpers.TextFingerprint(body)},
+		{Id: 2, Title: "secret fox", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PRIVATE, OwnerId: &otherId, Fingerprint: helpers.TextFingerprint(body)},
+	}, nil).Times(2)
+	mockTextsProvider.EXPECT().CreateText(context.Background(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, text structures.Text) (*structures.Text, error) {
+			text.Id = 3
+			return &text, nil
+		},
+	).Times(2)
+
+	private, err := textsService.CreateText(context.Background(), structures.TextReq{Title: "my fox", TextBody: body, Visibility: "private"}, ownerId, "regular")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if private.Status != structures.TEXT_STATUS_APPROVED || *private.OwnerId != ownerId || private.ShareSlug != nil {
+		t.Fatalf("unexpected private text %+v", private)
+	}
+	if len(private.SimilarTexts) != 1 || private.SimilarTexts[0].TextId != 1 {
+		t.Fatalf("unexpected similar texts %+v", private.SimilarTexts)
+	}
+
+	unlisted, err := textsService.CreateText(context.Background(), structures.TextReq{Title: "my shared fox", TextBody: body, Visibility: "unlisted"}, ownerId, "regular")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if unlisted.ShareSlug == nil || len(*unlisted.ShareSlug) < 20 {
+		t.Fatalf("expected a share slug, got %v", unlisted.ShareSlug)
+	}
+
+	_, err = textsService.CreateText(context.Background(), structures.TextReq{Title: "bad", TextBody: body, Visibility: "secret"}, ownerId, "regular")
+	if err != ErrInvalidVisibility {
+		t.Fatalf("expected error: %v but got %v instead", ErrInvalidVisibility, err)
+	}
+}
+
+func TestUpdateTextOwnership(t *testing.T) {
+	ownerId := 2
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	catalogId := 1
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &catalogId, nil).DoAndReturn(
+		func(context.Context, *int, *string) (*structures.Text, error) {
+			return &structures.Text{Id: 1, Title: "my text", TextBody: "my text", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, OwnerId: &ownerId}, nil
+		},
+	).Times(3)
+
+	_, err := textsService.UpdateText(context.Background(), structures.TextReq{Title: "stolen"}, catalogId, 3, "regular")
+	if err != ErrTextNotOwned {
+		t.Fatalf("expected error: %v but got %v instead", ErrTextNotOwned, err)
+	}
+
+	_, err = textsService.DeleteText(context.Background(), catalogId, 3, "regular")
+	if err != ErrTextNotOwned {
+		t.Fatalf("expected error: %v but got %v instead", ErrTextNotOwned, err)
+	}
+
+	// owners editing a catalog text send it back to the moderation queue
+	mockTextsProvider.EXPECT().GetTextFingerprints(context.Background()).Return([]*structures.TextFingerprint{}, nil).Times(1)
+	mockTextsProvider.EXPECT().UpdateText(context.Background(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, text structures.Text) (*structures.Text, error) {
+			return &text, nil
+		},
+	).Times(1)
+
+	updated, err := textsService.UpdateText(context.Background(), structures.TextReq{Title: "my better text"}, catalogId, ownerId, "regular")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if updated.Status != structures.TEXT_STATUS_PENDING || updated.Title != "my better text" {
+		t.Fatalf("unexpected updated text %+v", updated)
+	}
+
+	privateId := 5
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &privateId, nil).Return(
+		&structures.Text{Id: 5, Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PRIVATE, OwnerId: &ownerId}, nil,
+	).Times(1)
+
+	_, err = textsService.DeleteText(context.Background(), privateId, 3, "regular")
+	if err != gorm.ErrRecordNotFound {
+		t.Fatalf("expected error: %v but got %v instead", gorm.ErrRecordNotFound, err)
+	}
+}
+
+func TestUpdateTextVisibility(t *testing.T) {
+	body := "the quick brown fox jumps over the lazy dog while the cat sleeps in the warm afternoon sun"
+	ownerId := 2
+	storedFingerprints := []*structures.TextFingerprint{
+		{Id: 1, Title: "the fox", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, Fingerprint: helpers.TextFingerprint(body)},
+	}
+
+	data := []struct {
+		testName       string
+		mockText       *structures.Text
+		inputText      structures.TextReq
+		expectedStatus string
+		expectedErr    error
+	}{
+		{
+			testName:    "private copy cannot be made public",
+			mockText:    &structures.Text{Id: 5, Title: "my fox", TextBody: body, Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PRIVATE, OwnerId: &ownerId},
+			inputText:   structures.TextReq{Visibility: structures.TEXT_VISIBILITY_PUBLIC},
+			expectedErr: ErrDuplicateText,
+		},
+		{
+			testName:       "rejected text stays rejected once unlisted",
+			mockText:       &structures.Text{Id: 6, Title: "my notes", TextBody: "my notes", Status: structures.TEXT_STATUS_REJECTED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, OwnerId: &ownerId},
+			inputText:      structures.TextReq{Visibility: structures.TEXT_VISIBILITY_UNLISTED},
+			expectedStatus: structures.TEXT_STATUS_REJECTED,
+		},
+		{
+			testName:       "pending text stays pending once private",
+			mockText:       &structures.Text{Id: 7, Title: "my notes", TextBody: "my notes", Status: structures.TEXT_STATUS_PENDING, Visibility: structures.TEXT_VISIBILITY_PUBLIC, OwnerId: &ownerId},
+			inputText:      structures.TextReq{Visibility: structures.TEXT_VISIBILITY_PRIVATE},
+			expectedStatus: structures.TEXT_STATUS_PENDING,
+		},
+		{
+			testName:       "draft made private needs no review",
+			mockText:       &structures.Text{Id: 8, Title: "my notes", TextBody: "my notes", Status: structures.TEXT_STATUS_DRAFT, Visibility: structures.TEXT_VISIBILITY_PUBLIC, OwnerId: &ownerId},
+			inputText:      structures.TextReq{Visibility: structures.TEXT_VISIBILITY_PRIVATE, Status: structures.TEXT_STATUS_PENDING},
+			expectedStatus: structures.TEXT_STATUS_APPROVED,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &testCase.mockText.Id, nil).Return(testCase.mockText, nil).Times(1)
+			if testCase.inputText.Visibility == structures.TEXT_VISIBILITY_PUBLIC {
+				mockTextsProvider.EXPECT().GetTextFingerprints(context.Background()).Return(storedFingerprints, nil).Times(1)
+			}
+			if testCase.expectedErr == nil {
+				mockTextsProvider.EXPECT().UpdateText(context.Background(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, text structures.Text) (*structures.Text, error) {
+						return &text, nil
+					},
+				).Times(1)
+			}
+
+			result, err := textsService.UpdateText(context.Background(), testCase.inputText, testCase.mockText.Id, ownerId, "regular")
+
+			if testCase.expectedErr != nil {
+				if err != testCase.expectedErr {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if result.Status != testCase.expectedStatus || result.Visibility != testCase.inputText.Visibility {
+				t.Fatalf("unexpected updated text %+v", result)
+			}
+		})
+	}
+}
+
+func TestTextAttribution(t *testing.T) {
+	adminId := 1
+	requireAttribution := true
+
+	data := []struct {
+		testName            string
+		inputText           structures.TextReq
+		expectedLicense     string
+		expectedRequired    bool
+		expectedAttribution string
+		expectedErr         error
+	}{
+		{
+			testName:            "gutenberg texts are credited",
+			inputText:           structures.TextReq{Title: "chapter one", TextBody: "it is a truth universally acknowledged", Author: "Jane Austen", Source: "Pride and Prejudice", SourceUrl: "https://www.gutenberg.org/ebooks/1342", License: "Gutenberg"},
+			expectedLicense:     "gutenberg",
+			expectedRequired:    true,
+			expectedAttribution: "Pride and Prejudice by Jane Austen (https://www.gutenberg.org/ebooks/1342), Project Gutenberg License",
+		},
+		{
+			testName:         "public domain texts need no credit",
+			inputText:        structures.TextReq{Title: "old poem", TextBody: "shall i compare thee to a summer day", Author: "William Shakespeare", License: "public domain"},
+			expectedLicense:  "public-domain",
+			expectedRequired: false,
+		},
+		{
+			testName:            "credit can be asked for on any text",
+			inputText:           structures.TextReq{Title: "old poem", TextBody: "shall i compare thee to a summer day", Author: "William Shakespeare", License: "public-domain", AttributionRequired: &requireAttribution},
+			expectedLicense:     "public-domain",
+			expectedRequired:    true,
+			expectedAttribution: "William Shakespeare, Public Domain",
+		},
+		{
+			testName:    "unknown license",
+			inputText:   structures.TextReq{Title: "mystery", TextBody: "mystery text", License: "wtfpl-9"},
+			expectedErr: ErrInvalidLicense,
+		},
+		{
+			testName:    "source url must be a web link",
+			inputText:   structures.TextReq{Title: "mystery", TextBody: "mystery text", SourceUrl: "javascript:alert(1)"},
+			expectedErr: ErrInvalidSourceUrl,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			if testCase.expectedErr == nil {
+				mockTextsProvider.EXPECT().GetTextFingerprints(context.Background()).Return([]*structures.TextFingerprint{}, nil).Times(1)
+				mockTextsProvider.EXPECT().CreateText(context.Background(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, text structures.Text) (*structures.Text, error) {
+						text.Id = 1
+						return &text, nil
+					},
+				).Times(1)
+			}
+
+			result, err := textsService.CreateText(context.Background(), testCase.inputText, adminId, structures.USER_TYPE_ADMIN)
+
+			if testCase.expectedErr != nil {
+				if err != testCase.expectedErr {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if result.License != testCase.expectedLicense || result.AttributionRequired != testCase.expectedRequired || result.Attribution != testCase.expectedAttribution {
+				t.Fatalf("unexpected attribution license=%v required=%v attribution=%q", result.License, result.AttributionRequired, result.Attribution)
+			}
+		})
+	}
+}
+
+func TestGetTextsByLicense(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	mockTextsProvider.EXPECT().
+		GetTexts(context.Background(), structures.TextFilter{Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, License: "cc-by-sa-4.0"}).
+		Return([]*structures.Text{
+			{Id: 1, Title: "wiki article", Source: "Wikipedia", License: "cc-by-sa-4.0", AttributionRequired: true},
+		}, nil).
+		Times(1)
+
+	result, err := textsService.GetTexts(context.Background(), structures.TextFilter{License: "CC BY-SA 4.0"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(result) != 1 || result[0].Attribution != "Wikipedia, CC BY-SA 4.0" {
+		t.Fatalf("unexpected texts %+v", result)
+	}
+
+	_, err = textsService.GetTexts(context.Background(), structures.TextFilter{License: "mine"})
+	if err != ErrInvalidLicense {
+		t.Fatalf("expected error: %v but got %v instead", ErrInvalidLicense, err)
+	}
+}
+
+func TestRateText(t *testing.T) {
+	ownerId := 2
+	publicText := &structures.Text{Id: 1, Title: "test", TextBody: "test", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC}
+	privateText := &structures.Text{Id: 5, Title: "work notes", TextBody: "work notes", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PRIVATE, OwnerId: &ownerId}
+
+	data := []struct {
+		testName    string
+		mockText    *structures.Text
+		rating      int
+		userId      int
+		expectedErr error
+	}{
+		{testName: "rate public text", mockText: publicText, rating: 4, userId: 3},
+		{testName: "owner rates private text", mockText: privateText, rating: 5, userId: ownerId},
+		{testName: "rating below range", mockText: publicText, rating: 0, userId: 3, expectedErr: ErrInvalidRating},
+		{testName: "rating above range", mockText: publicText, rating: 6, userId: 3, expectedErr: ErrInvalidRating},
+		{testName: "cannot rate hidden text", mockText: privateText, rating: 3, userId: 3, expectedErr: gorm.ErrRecordNotFound},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			if testCase.expectedErr != ErrInvalidRating {
+				mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &testCase.mockText.Id, nil).Return(testCase.mockText, nil).Times(1)
+			}
+			if testCase.expectedErr == nil {
+				mockTextsProvider.EXPECT().
+					RateText(context.Background(), structures.TextRating{UserId: testCase.userId, TextId: testCase.mockText.Id, Rating: testCase.rating}).
+					DoAndReturn(func(_ context.Context, rating structures.TextRating) (*structures.TextRating, error) {
+						return &rating, nil
+					}).
+					Times(1)
+			}
+
+			result, err := textsService.RateText(context.Background(), structures.TextRatingReq{Rating: testCase.rating}, testCase.mockText.Id, testCase.userId, "regular")
+
+			if testCase.expectedErr != nil {
+				if err != testCase.expectedErr {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if result.Rating != testCase.rating || result.UserId != testCase.userId {
+				t.Fatalf("unexpected rating %+v", result)
+			}
+		})
+	}
+}
+
+func TestFavoriteText(t *testing.T) {
+	ownerId := 2
+	privateText := &structures.Text{Id: 5, Title: "work notes", TextBody: "work notes", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PRIVATE, OwnerId: &ownerId}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	mockTextsProvider.EXPECT().GetTextByIdOrTitle(context.Background(), &privateText.Id, nil).Return(privateText, nil).Times(2)
+	mockTextsProvider.EXPECT().FavoriteText(context.Background(), ownerId, privateText.Id).Return(true, nil).Times(1)
+
+	favorited, err := textsService.FavoriteText(context.Background(), privateText.Id, ownerId, "regular")
+	if err != nil || !favorited {
+		t.Fatalf("expected text to be favorited but got %v %v", favorited, err)
+	}
+
+	_, err = textsService.FavoriteText(context.Background(), privateText.Id, 3, "regular")
+	if err != gorm.ErrRecordNotFound {
+		t.Fatalf("expected error: %v but got %v instead", gorm.ErrRecordNotFound, err)
+	}
+}
+
+func TestGetFavoriteTexts(t *testing.T) {
+	slug := "c2hhcmVkLXNsdWc"
+	ownerId := 2
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	mockTextsProvider.EXPECT().
+		GetTexts(context.Background(), structures.TextFilter{Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, ViewerId: 3, FavoritedBy: ownerId}).
+		Return([]*structures.Text{
+			{Id: 1, Title: "test", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, FavoriteCount: 2},
+			{Id: 6, Title: "shared notes", Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_UNLISTED, OwnerId: &ownerId, ShareSlug: &slug},
+		}, nil).
+		Times(1)
+
+	result, err := textsService.GetFavoriteTexts(context.Background(), ownerId, 3, "regular")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(result) != 2 || result[0].FavoriteCount != 2 || result[1].ShareSlug != nil {
+		t.Fatalf("unexpected favorite texts %+v", result)
+	}
+}
+
+func TestGetTextsSort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+	textsService := NewTextsService(mockTextsProvider, testNormalizer())
+
+	mockTextsProvider.EXPECT().
+		GetTexts(context.Background(), structures.TextFilter{Status: structures.TEXT_STATUS_APPROVED, Visibility: structures.TEXT_VISIBILITY_PUBLIC, Sort: structures.TEXT_SORT_TOP_RATED}).
+		Return([]*structures.Text{{Id: 1, Title: "test", AverageRating: 4.5, RatingCount: 2}}, nil).
+		Times(1)
+
+	result, err := textsService.GetTexts(context.Background(), structures.TextFilter{Sort: structures.TEXT_SORT_TOP_RATED})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(result) != 1 || result[0].AverageRating != 4.5 {
+		t.Fatalf("unexpected texts %+v", result)
+	}
+
+	_, err = textsService.GetTexts(context.Background(), structures.TextFilter{Sort: "newest"})
+	if err != ErrInvalidSort {
+		t.Fatalf("expected error: %v but got %v instead", ErrInvalidSort, err)
+	}
+}
+
+func TestSaveDrillText(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTextsProvider := mockProviders.NewMockTextsProviderInterface(ctrl)
+
+	ownerId := 2
+	drill := structures.Text{TextType: structures.TEXT_TYPE_DRILL, Title: structures.REVIEW_DRILL_TITLE, TextBody: "their there", OwnerId: &ownerId}
+	createdAt := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	mockTextsProvider.EXPECT().GetDrillText(context.Background(), ownerId, structures.REVIEW_DRILL_TITLE).Return(nil, gorm.ErrRecordNotFound).Times(1)
+	mockTextsProvider.EXPECT().CreateText(context.Background(), drill).Return(&structures.Text{Id: 4, Title: drill.Title, TextBody: drill.TextBody}, nil).Times(1)
+
+	result, err := SaveDrillText(context.Background(), mockTextsProvider, drill)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result.Id != 4 {
+		t.Fatalf("expected a new drill text but got %+v", result)
+	}
+
+	mockTextsProvider.EXPECT().GetDrillText(context.Background(), ownerId, structures.REVIEW_DRILL_TITLE).Return(&structures.Text{Id: 4, CreatedAt: createdAt}, nil).Times(1)
+	mockTextsProvider.EXPECT().UpdateText(context.Background(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, text structures.Text) (*structures.Text, error) {
+			return &text, nil
+		},
+	).Times(1)
+
+	drill.TextBody = "weird receive"
+	result, err = SaveDrillText(context.Background(), mockTextsProvider, drill)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result.Id != 4 || result.TextBody != "weird receive" || !result.CreatedAt.Equal(createdAt) {
+		t.Fatalf("expected the drill text to be rewritten but got %+v", result)
+	}
+}
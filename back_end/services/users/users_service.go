@@ -4,9 +4,19 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"strings"
+	"time"
 	"type_writer_api/helpers"
+	"type_writer_api/providers/keyboard_layouts"
 	"type_writer_api/providers/users"
 	"type_writer_api/structures"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidKeyboardLayout = errors.New("unknown keyboard layout")
+	ErrInvalidTimeZone       = errors.New("unknown time zone")
 )
 
 type UsersServiceInterface interface {
@@ -19,7 +29,39 @@ type UsersServiceInterface interface {
 }
 
 type UsersService struct {
-	UsersProvider users_provider.UsersProviderInterface
+	UsersProvider           users_provider.UsersProviderInterface
+	KeyboardLayoutsProvider keyboard_layouts_provider.KeyboardLayoutsProviderInterface
+}
+
+// resolveKeyboardLayout checks the preferred layout is either builtin or one of
+// the stored custom layouts, returning the name it is known by
+func (u *UsersService) resolveKeyboardLayout(ctx context.Context, name string) (string, error) {
+	if builtin, ok := helpers.BuiltinKeyboardLayout(name); ok {
+		return builtin.Name, nil
+	}
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	layout, err := u.KeyboardLayoutsProvider.GetKeyboardLayoutByIdOrName(ctx, nil, &name)
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return "", ErrInvalidKeyboardLayout
+	} else if err != nil {
+		return "", err
+	}
+	return layout.Name, nil
+}
+
+// resolveTimeZone checks the time zone is a known IANA name, returning the
+// name it is known by
+func resolveTimeZone(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "Local" {
+		return "", ErrInvalidTimeZone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return "", ErrInvalidTimeZone
+	}
+	return loc.String(), nil
 }
 
 func (u *UsersService) GetUsers(ctx context.Context) ([]*structures.UserResp, error) {
@@ -57,6 +99,24 @@ func (u *UsersService) CreateUser(ctx context.Context, userInfo structures.UserR
 	}
 	userToCreate.PasswdHash = hashedPassword
 
+	userToCreate.KeyboardLayout = structures.DEFAULT_KEYBOARD_LAYOUT
+	if userInfo.KeyboardLayout != "" {
+		userToCreate.KeyboardLayout, err = u.resolveKeyboardLayout(ctx, userInfo.KeyboardLayout)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to create user", "error", err)
+			return nil, err
+		}
+	}
+
+	userToCreate.TimeZone = structures.DEFAULT_TIME_ZONE
+	if userInfo.TimeZone != "" {
+		userToCreate.TimeZone, err = resolveTimeZone(userInfo.TimeZone)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to create user", "error", err)
+			return nil, err
+		}
+	}
+
 	createdUser, err := u.UsersProvider.CreateUser(ctx, *userToCreate)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to create user", "error", err)
@@ -87,6 +147,20 @@ func (u *UsersService) UpdateUser(ctx context.Context, userInfo structures.UserR
 	if userInfo.Email != "" {
 		existingUser.Email = userInfo.Email
 	}
+	if userInfo.KeyboardLayout != "" {
+		existingUser.KeyboardLayout, err = u.resolveKeyboardLayout(ctx, userInfo.KeyboardLayout)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to update user", "error", err)
+			return nil, err
+		}
+	}
+	if userInfo.TimeZone != "" {
+		existingUser.TimeZone, err = resolveTimeZone(userInfo.TimeZone)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to update user", "error", err)
+			return nil, err
+		}
+	}
 	if userInfo.Password != "" {
 		hashedPassword, err := helpers.HashPassword(userInfo.Password)
 		if err != nil {
@@ -132,8 +206,9 @@ func (u *UsersService) ValidateLoginUser(ctx context.Context, username, password
 	return result, nil
 }
 
-func NewUsersService(usersProvider users_provider.UsersProviderInterface) *UsersService {
+func NewUsersService(usersProvider users_provider.UsersProviderInterface, keyboardLayoutsProvider keyboard_layouts_provider.KeyboardLayoutsProviderInterface) *UsersService {
 	return &UsersService{
-		UsersProvider: usersProvider,
+		UsersProvider:           usersProvider,
+		KeyboardLayoutsProvider: keyboardLayoutsProvider,
 	}
 }
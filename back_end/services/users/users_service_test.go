@@ -19,12 +19,12 @@ func TestGetUsers(t *testing.T) {
 	hashedPassword, _ := helpers.HashPassword(password)
 	var (
 		mockResult1 = []*structures.User{
-			{1, "regular", "testuser1", hashedPassword, "test user1", "tu1@regular", time.Now(), time.Now()},
-			{2, "regular", "testuser2", hashedPassword, "test user2", "tu2@regular", time.Now(), time.Now()},
+			{Id: 1, UserType: "regular", Username: "testuser1", PasswdHash: hashedPassword, Name: "test user1", Email: "tu1@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			{Id: 2, UserType: "regular", Username: "testuser2", PasswdHash: hashedPassword, Name: "test user2", Email: "tu2@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 		}
 		expectedResult1 = []*structures.UserResp{
-			{1, "regular", "testuser1", "test user1", "tu1@regular", time.Now(), time.Now()},
-			{2, "regular", "testuser2", "test user2", "tu2@regular", time.Now(), time.Now()},
+			{Id: 1, UserType: "regular", Username: "testuser1", Name: "test user1", Email: "tu1@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			{Id: 2, UserType: "regular", Username: "testuser2", Name: "test user2", Email: "tu2@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 		}
 		mockResult2 = []*structures.User{}
 		expectedResult2 = []*structures.UserResp{}
@@ -56,7 +56,8 @@ func TestGetUsers(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
-	usersService := NewUsersService(mockUsersProvider)
+	mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+	usersService := NewUsersService(mockUsersProvider, mockKeyboardLayoutsProvider)
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
@@ -99,18 +100,18 @@ func TestGetUserByIdOrName(t *testing.T) {
 			"valid id",
 			1,
 			"",
-			&structures.User{1, "regular", "testuser1", hashedPassword, "test user1", "tu1@regular", time.Now(), time.Now()},
+			&structures.User{Id: 1, UserType: "regular", Username: "testuser1", PasswdHash: hashedPassword, Name: "test user1", Email: "tu1@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.UserResp{1, "regular", "testuser1", "test user1", "tu1@regular", time.Now(), time.Now()},
+			&structures.UserResp{Id: 1, UserType: "regular", Username: "testuser1", Name: "test user1", Email: "tu1@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
 		{
 			"valid username",
 			0,
 			"test user 1",
-			&structures.User{1, "regular", "testuser1", hashedPassword, "test user1", "tu1@regular", time.Now(), time.Now()},
+			&structures.User{Id: 1, UserType: "regular", Username: "testuser1", PasswdHash: hashedPassword, Name: "test user1", Email: "tu1@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.UserResp{1, "regular", "testuser1", "test user1", "tu1@regular", time.Now(), time.Now()},
+			&structures.UserResp{Id: 1, UserType: "regular", Username: "testuser1", Name: "test user1", Email: "tu1@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
 		{
@@ -128,7 +129,8 @@ func TestGetUserByIdOrName(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
-	usersService := NewUsersService(mockUsersProvider)
+	mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+	usersService := NewUsersService(mockUsersProvider, mockKeyboardLayoutsProvider)
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
@@ -162,10 +164,10 @@ func TestCreateUser(t *testing.T) {
 	}{
 		{
 			"valid input user request",
-			structures.UserReq{"regular", "testuser1", password, "test user1", "tu1@regular"},
-			&structures.User{1, "regular", "testuser1", hashedPassword, "test user1", "tu1@regular", time.Now(), time.Now()},
+			structures.UserReq{UserType: "regular", Username: "testuser1", Password: password, Name: "test user1", Email: "tu1@regular"},
+			&structures.User{Id: 1, UserType: "regular", Username: "testuser1", PasswdHash: hashedPassword, Name: "test user1", Email: "tu1@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.UserResp{1, "regular", "testuser1", "test user1", "tu1@regular", time.Now(), time.Now()},
+			&structures.UserResp{Id: 1, UserType: "regular", Username: "testuser1", Name: "test user1", Email: "tu1@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
 	}
@@ -174,7 +176,8 @@ func TestCreateUser(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
-	usersService := NewUsersService(mockUsersProvider)
+	mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+	usersService := NewUsersService(mockUsersProvider, mockKeyboardLayoutsProvider)
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
@@ -210,12 +213,12 @@ func TestUpdateUser(t *testing.T) {
 	}{
 		{
 			"valid input user request",
-			structures.UserReq{"regular", "testuser2", "", "test user2", "tu2@regular"},
+			structures.UserReq{UserType: "regular", Username: "testuser2", Password: "", Name: "test user2", Email: "tu2@regular"},
 			1,
-			&structures.User{1, "regular", "testuser1", hashedPassword, "test user1", "tu1@regular", time.Now(), time.Now()},
-			&structures.User{1, "regular", "testuser2", hashedPassword, "test user2", "tu2@regular", time.Now(), time.Now()},
+			&structures.User{Id: 1, UserType: "regular", Username: "testuser1", PasswdHash: hashedPassword, Name: "test user1", Email: "tu1@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			&structures.User{Id: 1, UserType: "regular", Username: "testuser2", PasswdHash: hashedPassword, Name: "test user2", Email: "tu2@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.UserResp{1, "regular", "testuser2", "test user2", "tu2@regular", time.Now(), time.Now()},
+			&structures.UserResp{Id: 1, UserType: "regular", Username: "testuser2", Name: "test user2", Email: "tu2@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
 	}
@@ -224,7 +227,8 @@ func TestUpdateUser(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
-	usersService := NewUsersService(mockUsersProvider)
+	mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+	usersService := NewUsersService(mockUsersProvider, mockKeyboardLayoutsProvider)
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
@@ -280,7 +284,8 @@ func TestDeleteUser(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
-	usersService := NewUsersService(mockUsersProvider)
+	mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+	usersService := NewUsersService(mockUsersProvider, mockKeyboardLayoutsProvider)
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
@@ -317,16 +322,16 @@ func TestValidateLoginUser(t *testing.T) {
 			"valid username and password",
 			"testuser1",
 			"testPassword",
-			&structures.User{1, "regular", "testuser1", hashedPassword, "test user1", "tu1@regular", time.Now(), time.Now()},
+			&structures.User{Id: 1, UserType: "regular", Username: "testuser1", PasswdHash: hashedPassword, Name: "test user1", Email: "tu1@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
-			&structures.UserResp{1, "regular", "testuser1", "test user1", "tu1@regular", time.Now(), time.Now()},
+			&structures.UserResp{Id: 1, UserType: "regular", Username: "testuser1", Name: "test user1", Email: "tu1@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 		},
 		{
 			"invalid username or password error",
 			"testuser1",
 			"nottestPassword",
-			&structures.User{1, "regular", "testuser1", hashedPassword, "test user1", "tu1@regular", time.Now(), time.Now()},
+			&structures.User{Id: 1, UserType: "regular", Username: "testuser1", PasswdHash: hashedPassword, Name: "test user1", Email: "tu1@regular", CreatedAt: time.Now(), UpdatedAt: time.Now()},
 			nil,
 			nil,
 			errors.New("failed validation"),
@@ -337,7 +342,8 @@ func TestValidateLoginUser(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
-	usersService := NewUsersService(mockUsersProvider)
+	mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+	usersService := NewUsersService(mockUsersProvider, mockKeyboardLayoutsProvider)
 
 	for _, testCase := range data {
 		t.Run(testCase.testName, func(t *testing.T) {
@@ -357,3 +363,126 @@ func TestValidateLoginUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUserKeyboardLayout(t *testing.T) {
+	customLayout := "my-layout"
+	unknownLayout := "nope"
+	data := []struct{
+		testName string
+		inputLayout string
+		mockLayoutName *string
+		mockLayout *structures.KeyboardLayout
+		mockLayoutErr error
+		expectedInsertLayout string
+		expectedErr error
+	}{
+		{
+			"defaults to qwerty",
+			"",
+			nil,
+			nil,
+			nil,
+			"qwerty",
+			nil,
+		},
+		{
+			"builtin layout",
+			" Dvorak",
+			nil,
+			nil,
+			nil,
+			"dvorak",
+			nil,
+		},
+		{
+			"custom layout",
+			"My-Layout",
+			&customLayout,
+			&structures.KeyboardLayout{Id: 1, Name: customLayout},
+			nil,
+			customLayout,
+			nil,
+		},
+		{
+			"unknown layout",
+			"nope",
+			&unknownLayout,
+			nil,
+			gorm.ErrRecordNotFound,
+			"",
+			ErrInvalidKeyboardLayout,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+	mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+	usersService := NewUsersService(mockUsersProvider, mockKeyboardLayoutsProvider)
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			if testCase.mockLayoutName != nil {
+				mockKeyboardLayoutsProvider.EXPECT().GetKeyboardLayoutByIdOrName(context.Background(), nil, testCase.mockLayoutName).Return(testCase.mockLayout, testCase.mockLayoutErr).Times(1)
+			}
+			if testCase.expectedErr == nil {
+				mockUsersProvider.EXPECT().CreateUser(
+					context.Background(),
+					gomock.Cond(func(input structures.User) bool { return input.KeyboardLayout == testCase.expectedInsertLayout }),
+				).Return(&structures.User{Id: 1, Username: "testuser1", KeyboardLayout: testCase.expectedInsertLayout}, nil).Times(1)
+			}
+
+			result, err := usersService.CreateUser(context.Background(), structures.UserReq{Username: "testuser1", Password: "testPassword", KeyboardLayout: testCase.inputLayout})
+
+			if testCase.expectedErr != nil {
+				if err == nil || err.Error() != testCase.expectedErr.Error() {
+					t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+				}
+			} else if result.KeyboardLayout != testCase.expectedInsertLayout {
+				t.Fatalf("expected layout %v, got %v", testCase.expectedInsertLayout, result.KeyboardLayout)
+			}
+		})
+	}
+}
+
+func TestUserTimeZone(t *testing.T) {
+	data := []struct {
+		testName         string
+		inputTimeZone    string
+		expectedTimeZone string
+		expectedErr      error
+	}{
+		{testName: "defaults to utc", inputTimeZone: "", expectedTimeZone: "UTC"},
+		{testName: "iana name", inputTimeZone: " America/Sao_Paulo", expectedTimeZone: "America/Sao_Paulo"},
+		{testName: "unknown time zone", inputTimeZone: "Mars/Olympus_Mons", expectedErr: ErrInvalidTimeZone},
+		{testName: "server local time", inputTimeZone: "Local", expectedErr: ErrInvalidTimeZone},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsersProvider := mockProviders.NewMockUsersProviderInterface(ctrl)
+	mockKeyboardLayoutsProvider := mockProviders.NewMockKeyboardLayoutsProviderInterface(ctrl)
+	usersService := NewUsersService(mockUsersProvider, mockKeyboardLayoutsProvider)
+
+	for _, testCase := range data {
+		t.Run(testCase.testName, func(t *testing.T) {
+			if testCase.expectedErr == nil {
+				mockUsersProvider.EXPECT().CreateUser(
+					context.Background(),
+					gomock.Cond(func(input structures.User) bool { return input.TimeZone == testCase.expectedTimeZone }),
+				).Return(&structures.User{Id: 1, Username: "testuser1", TimeZone: testCase.expectedTimeZone}, nil).Times(1)
+			}
+
+			result, err := usersService.CreateUser(context.Background(), structures.UserReq{Username: "testuser1", Password: "testPassword", TimeZone: testCase.inputTimeZone})
+
+			if err != testCase.expectedErr {
+				t.Fatalf("expected error: %v but got %v instead", testCase.expectedErr, err)
+			}
+			if err == nil && result.TimeZone != testCase.expectedTimeZone {
+				t.Fatalf("expected time zone %v, got %v", testCase.expectedTimeZone, result.TimeZone)
+			}
+		})
+	}
+}
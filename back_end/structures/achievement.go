@@ -0,0 +1,63 @@
+package structures
+
+import "time"
+
+const USER_ACHIEVEMENT_TABLE_NAME = "user_achievements"
+
+// BADGES_CONFIG_PATH holds the badge definitions achievements are awarded by
+const BADGES_CONFIG_PATH = "config/achievements/badges.json"
+
+const (
+	MAX_BADGE_CONDITION_LENGTH = 200
+	// days looked back when counting how long a streak of practice runs
+	MAX_STREAK_DAYS = 366
+)
+
+// ACHIEVEMENT_METRICS are what badge conditions read on top of the scoring
+// metrics of the score being checked
+var ACHIEVEMENT_METRICS = []string{"total_scores", "streak_days", "difficulty"}
+
+// TEXT_DIFFICULTY_LEVELS are the values badge conditions see for the
+// difficulty of the text of a score
+var TEXT_DIFFICULTY_LEVELS = map[string]float64{
+	"easy":   1,
+	"normal": 2,
+	"hard":   3,
+}
+
+// Badge is a badge definition, it is awarded to a user the first time one of
+// their scores meets its condition
+type Badge struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Condition   string `json:"condition"`
+}
+
+type UserAchievement struct {
+	UserId    int       `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	BadgeId   string    `json:"badge_id" gorm:"primaryKey"`
+	ScoreId   *int      `json:"score_id,omitempty"`
+	AwardedAt time.Time `json:"awarded_at"`
+}
+
+// Achievement is a badge along with whether the user earned it and when
+type Achievement struct {
+	Badge
+	Earned    bool       `json:"earned"`
+	ScoreId   *int       `json:"score_id,omitempty"`
+	AwardedAt *time.Time `json:"awarded_at,omitempty"`
+}
+
+type UserAchievements struct {
+	UserId       int            `json:"user_id"`
+	Earned       int            `json:"earned"`
+	Achievements []*Achievement `json:"achievements"`
+}
+
+// ScoreStanding is where a user stood when one of their scores came in
+type ScoreStanding struct {
+	TotalScores int
+	StreakDays  int
+	Difficulty  string
+}
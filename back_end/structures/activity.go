@@ -4,22 +4,116 @@ import "time"
 
 const ACTIVITY_TABLE_NAME = "activities"
 
+// ACTIVITY_RULES_SCHEMA_VERSION is bumped whenever the shape of ActivityRules
+// changes so rules stored by older versions can be told apart
+const ACTIVITY_RULES_SCHEMA_VERSION = 1
+
+// activity kinds pick the engine that runs and ranks an activity
+const (
+	ACTIVITY_KIND_TIMED_TEST   = "timed_test"
+	ACTIVITY_KIND_WORD_RACE    = "word_race"
+	ACTIVITY_KIND_SUDDEN_DEATH = "sudden_death"
+	ACTIVITY_KIND_ZEN          = "zen"
+)
+
+const (
+	MAX_ACTIVITY_TIME_LIMIT  = 3600
+	MAX_ACTIVITY_WORD_TARGET = 1000
+)
+
+// activities without a scoring formula score by wpm
+const (
+	DEFAULT_SCORING_FORMULA    = "wpm"
+	MAX_SCORING_FORMULA_LENGTH = 500
+)
+
+// SCORING_METRICS are the result metrics a scoring formula can read
+var SCORING_METRICS = []string{"wpm", "raw_wpm", "cpm", "accuracy", "errors", "corrected", "consistency", "words", "letters", "duration"}
+
 type Activity struct {
-	Id          int       `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Id             int           `json:"id"`
+	Name           string        `json:"name"`
+	Description    string        `json:"description"`
+	Kind           string        `json:"kind"`
+	ScoringFormula string        `json:"scoring_formula"`
+	Rules          ActivityRules `json:"rules" gorm:"serializer:json"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// ActivityRules describes how an activity plays out, a test ends when the
+// time limit (seconds) or the word target is reached, or on the first error
+// when stop on error is set. Neither limit means the whole text is typed
+type ActivityRules struct {
+	SchemaVersion     int      `json:"schema_version"`
+	TimeLimit         int      `json:"time_limit,omitempty"`
+	WordTarget        int      `json:"word_target,omitempty"`
+	StopOnError       bool     `json:"stop_on_error"`
+	MustCorrectErrors bool     `json:"must_correct_errors"`
+	BlindMode         bool     `json:"blind_mode"`
+	AccuracyThreshold float64  `json:"accuracy_threshold"`
+	AllowedTextTypes  []string `json:"allowed_text_types,omitempty"`
+}
+
+// ActivityInput is what an activity engine plays back, the keystrokes a user
+// sent while typing the text
+type ActivityInput struct {
+	TextType   string       `json:"text_type"`
+	TextBody   string       `json:"text_body"`
+	Keystrokes []*Keystroke `json:"keystrokes"`
+}
+
+// ActivityResult is what an activity engine computed from an input, duration
+// is in milliseconds
+type ActivityResult struct {
+	Wpm         float64 `json:"wpm"`
+	RawWpm      float64 `json:"raw_wpm"`
+	Cpm         float64 `json:"cpm"`
+	Accuracy    float64 `json:"accuracy"`
+	Errors      int     `json:"errors"`
+	Corrected   int     `json:"corrected"`
+	Consistency float64 `json:"consistency"`
+	Words       int     `json:"words"`
+	Letters     int     `json:"letters"`
+	Duration    int     `json:"duration"`
+	Completed   bool    `json:"completed"`
+	Passed      bool    `json:"passed"`
+}
+
+// ScoringFormulaTestReq runs a formula over sample metrics before it is saved
+type ScoringFormulaTestReq struct {
+	Formula string               `json:"formula"`
+	Samples []map[string]float64 `json:"samples"`
+}
+
+type ScoringFormulaSample struct {
+	Metrics    map[string]float64 `json:"metrics"`
+	FinalScore float64            `json:"final_score"`
 }
 
 type ActivityReq struct {
-	Name        string `json:"name,omitempty"`
-	Description string `json:"description,omitempty"`
+	Name           string         `json:"name,omitempty"`
+	Description    string         `json:"description,omitempty"`
+	Kind           string         `json:"kind,omitempty"`
+	ScoringFormula *string        `json:"scoring_formula,omitempty"`
+	Rules          *ActivityRules `json:"rules,omitempty"`
 }
 
 func ConvertRequestToActivity(req *ActivityReq) *Activity {
-	return &Activity{
+	activity := &Activity{
 		Name:        req.Name,
 		Description: req.Description,
+		Kind:        req.Kind,
+		Rules:       ActivityRules{SchemaVersion: ACTIVITY_RULES_SCHEMA_VERSION},
+	}
+	if req.ScoringFormula != nil {
+		activity.ScoringFormula = *req.ScoringFormula
+	}
+	if activity.Kind == "" {
+		activity.Kind = ACTIVITY_KIND_ZEN
+	}
+	if req.Rules != nil {
+		activity.Rules = *req.Rules
 	}
+	return activity
 }
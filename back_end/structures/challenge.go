@@ -0,0 +1,74 @@
+package structures
+
+import "time"
+
+const DAILY_CHALLENGE_TABLE_NAME = "daily_challenges"
+const CHALLENGE_QUEUE_TABLE_NAME = "challenge_queue"
+const CHALLENGE_ATTEMPT_TABLE_NAME = "challenge_attempts"
+
+// CHALLENGE_DATE_FORMAT is how challenge days are written, a calendar day
+// with no time zone attached
+const CHALLENGE_DATE_FORMAT = "2006-01-02"
+
+// a challenge day starts first at UTC+14 and ends last at UTC-12
+const (
+	CHALLENGE_EARLIEST_OFFSET = 14 * time.Hour
+	CHALLENGE_LATEST_OFFSET   = 12 * time.Hour
+)
+
+// DailyChallenge is the activity and text everyone competes on for a day,
+// curated challenges came from the admin queue. The day bounds are only set
+// when the challenge was asked for in a time zone
+type DailyChallenge struct {
+	Id            int        `json:"id"`
+	ChallengeDate string     `json:"date"`
+	ActivityId    int        `json:"activity_id"`
+	TextId        int        `json:"text_id"`
+	Curated       bool       `json:"curated"`
+	StartsAt      *time.Time `json:"starts_at,omitempty" gorm:"-"`
+	EndsAt        *time.Time `json:"ends_at,omitempty" gorm:"-"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type ChallengeQueueEntry struct {
+	Id         int       `json:"id"`
+	ActivityId int       `json:"activity_id"`
+	TextId     int       `json:"text_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type ChallengeQueueEntryReq struct {
+	ActivityId int `json:"activity_id"`
+	TextId     int `json:"text_id"`
+}
+
+type ChallengeAttempt struct {
+	ChallengeId int       `json:"challenge_id"`
+	UserId      int       `json:"user_id"`
+	ScoreId     int       `json:"score_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ChallengeAttemptReq enters a score into a challenge, the score has to be
+// the first one of the user on the challenge day in their own time zone
+type ChallengeAttemptReq struct {
+	ScoreId int `json:"score_id"`
+}
+
+type ChallengeLeaderboardEntry struct {
+	Rank       int       `json:"rank" gorm:"-"`
+	UserId     int       `json:"user_id"`
+	Username   string    `json:"username"`
+	ScoreId    int       `json:"score_id"`
+	FinalScore float64   `json:"final_score"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func ConvertRequestToChallengeQueueEntry(req *ChallengeQueueEntryReq) *ChallengeQueueEntry {
+	return &ChallengeQueueEntry{
+		ActivityId: req.ActivityId,
+		TextId:     req.TextId,
+	}
+}
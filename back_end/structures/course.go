@@ -0,0 +1,86 @@
+package structures
+
+import "time"
+
+const COURSE_TABLE_NAME = "courses"
+const COURSE_LESSON_TABLE_NAME = "course_lessons"
+
+type Course struct {
+	Id          int             `json:"id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Lessons     []*CourseLesson `json:"lessons" gorm:"-"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+type CourseLesson struct {
+	Id          int       `json:"id"`
+	CourseId    int       `json:"course_id"`
+	Position    int       `json:"position"`
+	TextId      int       `json:"text_id"`
+	ActivityId  int       `json:"activity_id"`
+	MinWpm      float64   `json:"min_wpm"`
+	MinAccuracy float64   `json:"min_accuracy"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CourseReq struct {
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Lessons     []*CourseLessonReq `json:"lessons"`
+}
+
+type CourseLessonReq struct {
+	TextId      int     `json:"text_id"`
+	ActivityId  int     `json:"activity_id"`
+	MinWpm      float64 `json:"min_wpm"`
+	MinAccuracy float64 `json:"min_accuracy"`
+}
+
+// CourseProgress is where a user stands in a course, lessons unlock in order
+// as the previous lesson's pass criteria are met by one of the user's scores
+type CourseProgress struct {
+	CourseId         int               `json:"course_id"`
+	UserId           int               `json:"user_id"`
+	TotalLessons     int               `json:"total_lessons"`
+	CompletedLessons int               `json:"completed_lessons"`
+	CurrentLesson    *int              `json:"current_lesson"`
+	Completed        bool              `json:"completed"`
+	Lessons          []*LessonProgress `json:"lessons"`
+}
+
+type LessonProgress struct {
+	LessonId     int     `json:"lesson_id"`
+	Position     int     `json:"position"`
+	TextId       int     `json:"text_id"`
+	ActivityId   int     `json:"activity_id"`
+	MinWpm       float64 `json:"min_wpm"`
+	MinAccuracy  float64 `json:"min_accuracy"`
+	Unlocked     bool    `json:"unlocked"`
+	Passed       bool    `json:"passed"`
+	Attempts     int     `json:"attempts"`
+	BestWpm      float64 `json:"best_wpm"`
+	BestAccuracy float64 `json:"best_accuracy"`
+}
+
+func ConvertRequestToCourse(req *CourseReq) *Course {
+	course := &Course{
+		Title:       req.Title,
+		Description: req.Description,
+		Lessons:     []*CourseLesson{},
+	}
+
+	for idx, lessonReq := range req.Lessons {
+		course.Lessons = append(course.Lessons, &CourseLesson{
+			Position:    idx + 1,
+			TextId:      lessonReq.TextId,
+			ActivityId:  lessonReq.ActivityId,
+			MinWpm:      lessonReq.MinWpm,
+			MinAccuracy: lessonReq.MinAccuracy,
+		})
+	}
+
+	return course
+}
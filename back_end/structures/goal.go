@@ -0,0 +1,78 @@
+package structures
+
+import "time"
+
+const USER_GOAL_TABLE_NAME = "user_goals"
+
+// GOAL_METRICS are the result metrics a goal can target, all of them better
+// the higher they are
+var GOAL_METRICS = []string{"wpm", "raw_wpm", "cpm", "accuracy", "consistency"}
+
+// GOAL_PERCENT_METRICS cannot target more than 100
+var GOAL_PERCENT_METRICS = []string{"accuracy", "consistency"}
+
+// Goals are projected from a line fitted through the GOAL_REGRESSION_SCORES
+// latest scores of a user, fewer than MIN_GOAL_SAMPLES are not enough to
+// project anything. Projections further out than MAX_GOAL_PROJECTION_DAYS
+// are not made
+const (
+	MAX_USER_GOALS           = 20
+	GOAL_REGRESSION_SCORES   = 50
+	MIN_GOAL_SAMPLES         = 5
+	MAX_GOAL_PROJECTION_DAYS = 3650
+)
+
+// UserGoal is a target a user set on a metric, the target date is a calendar
+// day in the time zone of the user
+type UserGoal struct {
+	Id         int       `json:"id"`
+	UserId     int       `json:"user_id"`
+	Metric     string    `json:"metric"`
+	Target     float64   `json:"target"`
+	TargetDate string    `json:"target_date"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type UserGoalReq struct {
+	Metric     string  `json:"metric"`
+	Target     float64 `json:"target"`
+	TargetDate string  `json:"target_date"`
+}
+
+// GoalSample is the value a goal metric had on one score
+type GoalSample struct {
+	Value     float64   `json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GoalProgress is where a user stands on a goal. Current is the fitted value
+// as of the latest score and Trend how much it moves a day, the projected
+// date is left out when the trend never reaches the target
+type GoalProgress struct {
+	UserGoal
+	Samples       int     `json:"samples"`
+	Current       float64 `json:"current"`
+	Trend         float64 `json:"trend"`
+	Achieved      bool    `json:"achieved"`
+	ProjectedDate string  `json:"projected_date,omitempty"`
+	OnTrack       bool    `json:"on_track"`
+}
+
+type UserGoals struct {
+	UserId   int             `json:"user_id"`
+	TimeZone string          `json:"time_zone"`
+	Goals    []*GoalProgress `json:"goals"`
+}
+
+// UserStreak counts the days in a row a user practiced on in their time
+// zone, the current streak is still alive when the user has not practiced
+// yet today but did yesterday
+type UserStreak struct {
+	UserId         int    `json:"user_id"`
+	TimeZone       string `json:"time_zone"`
+	Current        int    `json:"current"`
+	Longest        int    `json:"longest"`
+	PracticedToday bool   `json:"practiced_today"`
+	LastPracticed  string `json:"last_practiced,omitempty"`
+}
@@ -3,8 +3,7 @@ package structures
 import "github.com/golang-jwt/jwt/v5"
 
 type JwtCustomClaims struct {
+	UserId   int    `json:"user_id"`
 	UserType string `json:"user_type"`
 	jwt.RegisteredClaims
 }
-
-
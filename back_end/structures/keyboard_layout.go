@@ -0,0 +1,83 @@
+package structures
+
+import "time"
+
+const KEYBOARD_LAYOUT_TABLE_NAME = "keyboard_layouts"
+
+const DEFAULT_KEYBOARD_LAYOUT = "qwerty"
+
+const (
+	KEY_ROW_NUMBER = "number"
+	KEY_ROW_TOP    = "top"
+	KEY_ROW_HOME   = "home"
+	KEY_ROW_BOTTOM = "bottom"
+	KEY_ROW_SPACE  = "space"
+)
+
+const (
+	HAND_LEFT  = "left"
+	HAND_RIGHT = "right"
+)
+
+const (
+	FINGER_LEFT_PINKY   = "left_pinky"
+	FINGER_LEFT_RING    = "left_ring"
+	FINGER_LEFT_MIDDLE  = "left_middle"
+	FINGER_LEFT_INDEX   = "left_index"
+	FINGER_THUMB        = "thumb"
+	FINGER_RIGHT_INDEX  = "right_index"
+	FINGER_RIGHT_MIDDLE = "right_middle"
+	FINGER_RIGHT_RING   = "right_ring"
+	FINGER_RIGHT_PINKY  = "right_pinky"
+)
+
+// KeyPosition places the characters produced by a physical key, Char is the
+// plain output and ShiftChar the one produced while holding shift
+type KeyPosition struct {
+	Char      string `json:"char"`
+	ShiftChar string `json:"shift_char,omitempty"`
+	Row       string `json:"row"`
+	Column    int    `json:"column"`
+	Finger    string `json:"finger"`
+}
+
+type KeyboardLayout struct {
+	Id          int            `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Builtin     bool           `json:"builtin" gorm:"-"`
+	Keys        []*KeyPosition `json:"keys" gorm:"serializer:json"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+type KeyboardLayoutReq struct {
+	Name        string         `json:"name,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Keys        []*KeyPosition `json:"keys"`
+}
+
+// KeyProfile describes how typing a text spreads over the physical keys of a
+// layout, characters the layout cannot produce are counted as unmapped
+type KeyProfile struct {
+	Layout            string         `json:"layout"`
+	TextId            int            `json:"text_id"`
+	Keystrokes        int            `json:"keystrokes"`
+	ShiftedKeystrokes int            `json:"shifted_keystrokes"`
+	Unmapped          int            `json:"unmapped"`
+	UnmappedChars     []string       `json:"unmapped_chars"`
+	Fingers           map[string]int `json:"fingers"`
+	Rows              map[string]int `json:"rows"`
+	Hands             map[string]int `json:"hands"`
+	HomeRowRatio      float64        `json:"home_row_ratio"`
+	SameFingerBigrams int            `json:"same_finger_bigrams"`
+	HandAlternations  int            `json:"hand_alternations"`
+}
+
+func ConvertRequestToKeyboardLayout(req *KeyboardLayoutReq) *KeyboardLayout {
+	return &KeyboardLayout{
+		Name:        req.Name,
+		Description: req.Description,
+		Keys:        req.Keys,
+	}
+}
@@ -0,0 +1,50 @@
+package structures
+
+import "time"
+
+// keys that do not produce their own name, every other key is the character
+// it produced
+const (
+	KEY_BACKSPACE = "Backspace"
+	KEY_ENTER     = "Enter"
+)
+
+// Keystroke is a single key press, offset is the milliseconds elapsed since
+// the test started
+type Keystroke struct {
+	Key    string `json:"key"`
+	Offset int    `json:"offset"`
+}
+
+const SCORE_KEYSTROKES_TABLE_NAME = "score_keystrokes"
+
+// keystroke logs are stored gzipped as [key, offset, expected, flags] tuples,
+// logs over either limit are refused. Logs older than the retention setting
+// are purged, DEFAULT_KEYSTROKE_LOG_RETENTION_DAYS applies when it is not set
+const (
+	KEYSTROKE_LOG_ENCODING               = "gzip-json-v1"
+	MAX_KEYSTROKE_LOG_EVENTS             = 20000
+	MAX_KEYSTROKE_LOG_BYTES              = 256 << 10
+	DEFAULT_KEYSTROKE_LOG_RETENTION_DAYS = 90
+)
+
+// KeystrokeEvent is a keystroke as it landed on the text, expected is the
+// character of the text at the position the key filled
+type KeystrokeEvent struct {
+	Key       string `json:"key"`
+	Offset    int    `json:"offset"`
+	Expected  string `json:"expected,omitempty"`
+	Correct   bool   `json:"correct"`
+	Backspace bool   `json:"backspace,omitempty"`
+}
+
+// ScoreKeystrokeLog is the compressed keystroke log of a score, the events
+// are only decoded when the log is read back
+type ScoreKeystrokeLog struct {
+	ScoreId    int               `json:"score_id" gorm:"primaryKey;autoIncrement:false"`
+	Encoding   string            `json:"encoding"`
+	EventCount int               `json:"event_count"`
+	Data       []byte            `json:"-"`
+	Events     []*KeystrokeEvent `json:"events" gorm:"-"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
@@ -0,0 +1,65 @@
+package structures
+
+import "time"
+
+const (
+	LEADERBOARD_WINDOW_DAY   = "day"
+	LEADERBOARD_WINDOW_WEEK  = "week"
+	LEADERBOARD_WINDOW_MONTH = "month"
+	LEADERBOARD_WINDOW_ALL   = "all"
+)
+
+// LEADERBOARD_WINDOWS are rolling, a day is the last 24 hours and a month the
+// last 30 days, the all time window has no start
+var LEADERBOARD_WINDOWS = map[string]time.Duration{
+	LEADERBOARD_WINDOW_DAY:   24 * time.Hour,
+	LEADERBOARD_WINDOW_WEEK:  7 * 24 * time.Hour,
+	LEADERBOARD_WINDOW_MONTH: 30 * 24 * time.Hour,
+	LEADERBOARD_WINDOW_ALL:   0,
+}
+
+// LEADERBOARD_METRICS are the metrics a leaderboard can rank by, higher is
+// better for every one of them
+var LEADERBOARD_METRICS = []string{"wpm", "raw_wpm", "cpm", "accuracy", "consistency", "final_score"}
+
+const (
+	DEFAULT_LEADERBOARD_METRIC = "wpm"
+	DEFAULT_LEADERBOARD_LIMIT  = 50
+	MAX_LEADERBOARD_LIMIT      = 100
+	DEFAULT_LEADERBOARD_RADIUS = 5
+	MAX_LEADERBOARD_RADIUS     = 25
+)
+
+// LeaderboardQuery picks the scores a leaderboard ranks, zero ids match every
+// activity or text. Since is worked out from the window, the zero time ranks
+// every score
+type LeaderboardQuery struct {
+	ActivityId int
+	TextId     int
+	Window     string
+	Metric     string
+	Since      time.Time
+	Limit      int
+	Offset     int
+}
+
+// LeaderboardEntry is the best ranked score of a user, value is the metric the
+// leaderboard ranks by and equal values go to the more accurate, then the
+// earlier score
+type LeaderboardEntry struct {
+	Rank      int       `json:"rank"`
+	UserId    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	ScoreId   int       `json:"score_id"`
+	Value     float64   `json:"value"`
+	Accuracy  float64   `json:"accuracy"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Leaderboard struct {
+	ActivityId int                 `json:"activity_id,omitempty"`
+	TextId     int                 `json:"text_id,omitempty"`
+	Window     string              `json:"window"`
+	Metric     string              `json:"metric"`
+	Entries    []*LeaderboardEntry `json:"entries"`
+}
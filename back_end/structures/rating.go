@@ -0,0 +1,29 @@
+package structures
+
+import "time"
+
+const TEXT_RATING_TABLE_NAME = "text_ratings"
+const TEXT_FAVORITE_TABLE_NAME = "text_favorites"
+
+const (
+	MIN_TEXT_RATING = 1
+	MAX_TEXT_RATING = 5
+)
+
+type TextRating struct {
+	UserId    int       `json:"user_id"`
+	TextId    int       `json:"text_id"`
+	Rating    int       `json:"rating"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TextRatingReq struct {
+	Rating int `json:"rating"`
+}
+
+type TextFavorite struct {
+	UserId    int       `json:"user_id"`
+	TextId    int       `json:"text_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,68 @@
+package structures
+
+import "time"
+
+const (
+	MAX_RECOMMENDATIONS = 10
+	MAX_WEAK_KEYS       = 5
+	// days after which a text typed before counts as new again
+	RECOMMENDATION_NOVELTY_DAYS = 14
+	// wpm away from the pace of a difficulty at which a text stops fitting
+	RECOMMENDATION_WPM_SPREAD = 50
+	// rating factor of texts nobody rated yet
+	RECOMMENDATION_UNRATED = 0.5
+)
+
+// RECOMMENDATION_DIFFICULTY_WPM is the pace each text difficulty suits best
+var RECOMMENDATION_DIFFICULTY_WPM = map[string]float64{
+	"easy":   30,
+	"normal": 55,
+	"hard":   80,
+}
+
+const (
+	RECOMMENDATION_WEIGHT_WEAK_KEYS   = "weak_keys"
+	RECOMMENDATION_WEIGHT_WEAK_NGRAMS = "weak_ngrams"
+	RECOMMENDATION_WEIGHT_DIFFICULTY  = "difficulty"
+	RECOMMENDATION_WEIGHT_NOVELTY     = "novelty"
+	RECOMMENDATION_WEIGHT_RATING      = "rating"
+)
+
+// RecommendationFactors are how well a text does on every criteria between 0
+// and 1, the same shape holds how much each criteria weighs
+type RecommendationFactors struct {
+	WeakKeys   float64 `json:"weak_keys"`
+	WeakNgrams float64 `json:"weak_ngrams"`
+	Difficulty float64 `json:"difficulty"`
+	Novelty    float64 `json:"novelty"`
+	Rating     float64 `json:"rating"`
+}
+
+var DEFAULT_RECOMMENDATION_WEIGHTS = RecommendationFactors{
+	WeakKeys:   3,
+	WeakNgrams: 3,
+	Difficulty: 2,
+	Novelty:    2,
+	Rating:     1,
+}
+
+// TextPlay is when a user last typed a text and how many times they did
+type TextPlay struct {
+	TextId       int       `json:"text_id"`
+	Plays        int       `json:"plays"`
+	LastPlayedAt time.Time `json:"last_played_at"`
+}
+
+type Recommendation struct {
+	Text    *Text                 `json:"text"`
+	Score   float64               `json:"score"`
+	Factors RecommendationFactors `json:"factors"`
+}
+
+type Recommendations struct {
+	UserId     int               `json:"user_id"`
+	Wpm        float64           `json:"wpm"`
+	WeakKeys   []string          `json:"weak_keys"`
+	WeakNgrams []string          `json:"weak_ngrams"`
+	Texts      []*Recommendation `json:"texts"`
+}
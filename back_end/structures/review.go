@@ -0,0 +1,46 @@
+package structures
+
+import "time"
+
+const REVIEW_WORD_TABLE_NAME = "review_words"
+
+const (
+	// shorter words are too common to be worth reviewing
+	MIN_REVIEW_WORD_LENGTH = 3
+	MAX_REVIEW_WORD_LENGTH = 60
+	// misses it takes for a word to be reviewed
+	MIN_REVIEW_MISSES = 2
+	// reviews in a row typed clean after which a word is mastered
+	REVIEW_MASTERED_REPETITIONS = 5
+	REVIEW_DEFAULT_EASE         = 2.5
+	REVIEW_MIN_EASE             = 1.3
+	// SM-2 grades from 0 to 5, a keystroke log only tells whether a word
+	// was missed or typed clean
+	REVIEW_QUALITY_MISSED  = 2
+	REVIEW_QUALITY_CLEAN   = 4
+	REVIEW_PASSING_QUALITY = 3
+	MAX_REVIEW_DRILL_WORDS = 20
+	REVIEW_DRILL_WORDS     = 60
+	REVIEW_DRILL_TITLE     = "Review drill"
+)
+
+// ReviewWord is a word in the review deck of a user along with where it is
+// at in its SM-2 schedule
+type ReviewWord struct {
+	UserId         int        `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	Word           string     `json:"word" gorm:"primaryKey"`
+	Misses         int        `json:"misses"`
+	Repetitions    int        `json:"repetitions"`
+	EaseFactor     float64    `json:"ease_factor"`
+	IntervalDays   int        `json:"interval_days"`
+	DueAt          time.Time  `json:"due_at"`
+	Mastered       bool       `json:"mastered"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type ReviewDrill struct {
+	Words []string `json:"words"`
+	Text  *Text    `json:"text"`
+}
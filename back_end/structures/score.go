@@ -4,13 +4,86 @@ import "time"
 
 const SCORE_TABLE_NAME = "scores"
 
+// scores that fail a plausibility check wait for an admin before they are
+// ranked, rejected ones never are
+const (
+	SCORE_REVIEW_CLEAN    = "clean"
+	SCORE_REVIEW_FLAGGED  = "flagged"
+	SCORE_REVIEW_APPROVED = "approved"
+	SCORE_REVIEW_REJECTED = "rejected"
+)
+
+var RANKED_SCORE_REVIEWS = []string{SCORE_REVIEW_CLEAN, SCORE_REVIEW_APPROVED}
+
+// reasons a score gets flagged for, unverified scores came without a
+// keystroke log to check them against
+const (
+	SCORE_FLAG_UNVERIFIED    = "unverified"
+	SCORE_FLAG_SUSTAINED_WPM = "sustained_wpm"
+	SCORE_FLAG_KEY_INTERVALS = "key_intervals"
+	SCORE_FLAG_PASTE_BURST   = "paste_burst"
+)
+
+// plausibility limits, times are in milliseconds. Faster than
+// MAX_SUSTAINED_WPM over a whole window, more than MAX_FAST_KEY_SHARE of the
+// intervals under MIN_KEY_INTERVAL or PASTE_BURST_KEYS characters within
+// PASTE_BURST_SPAN get a score flagged
+const (
+	MAX_SUSTAINED_WPM        = 250
+	SUSTAINED_WPM_WINDOW     = 5000
+	MIN_KEY_INTERVAL         = 10
+	MIN_KEY_INTERVAL_SAMPLES = 10
+	MAX_FAST_KEY_SHARE       = 0.2
+	PASTE_BURST_KEYS         = 10
+	PASTE_BURST_SPAN         = 50
+)
+
+// how far a claimed result may be from the one recomputed from its
+// keystrokes, the wpm tolerance is relative and errors have to match
+const (
+	RESULT_WPM_TOLERANCE      = 0.05
+	RESULT_ACCURACY_TOLERANCE = 1
+)
+
+// SCORE_RESULT_VERSION is the version of the result schema new scores are
+// stored with, results carrying another version are refused
+const SCORE_RESULT_VERSION = 1
+
+// legacy names some clients still send for a result field
+var SCORE_RESULT_ALIASES = map[string]string{"lpm": "cpm"}
+
+const MAX_SCORE_RESULT_EXTRA_FIELDS = 20
+
+// ScoreResult is the outcome stored with a score, speeds are per minute and
+// percentages go from 0 to 100. Fields a client sent that are not part of
+// the schema are kept in Extra
+type ScoreResult struct {
+	Version     int            `json:"version"`
+	Wpm         float64        `json:"wpm"`
+	RawWpm      float64        `json:"raw_wpm"`
+	Cpm         float64        `json:"cpm"`
+	Accuracy    float64        `json:"accuracy"`
+	Errors      int            `json:"errors"`
+	Corrected   int            `json:"corrected"`
+	Consistency float64        `json:"consistency"`
+	Words       int            `json:"words"`
+	Letters     int            `json:"letters"`
+	Extra       map[string]any `json:"extra,omitempty"`
+}
+
 type Score struct {
 	Id         int       `json:"id"`
 	UserId     int       		 `json:"user_id"`
 	ActivityId int       		 `json:"activity_id"`
 	TextId     int       		 `json:"text_id"`
 	Duration   int       		 `json:"duration"`
-	Result     map[string]any    `json:"result" gorm:"serializer:json"`
+	Result     ScoreResult       `json:"result" gorm:"serializer:json"`
+	FinalScore float64           `json:"final_score"`
+	Attribution string           `json:"attribution,omitempty" gorm:"-"`
+	ReviewStatus string          `json:"review_status"`
+	FlagReasons  []string        `json:"flag_reasons" gorm:"serializer:json"`
+	ReviewedAt   *time.Time      `json:"reviewed_at"`
+	KeystrokeLog *ScoreKeystrokeLog `json:"-" gorm:"-"`
 	CreatedAt  time.Time 		 `json:"created_at"`
 	UpdatedAt  time.Time 		 `json:"updated_at"`
 }
@@ -21,14 +94,26 @@ type ScoreReq struct {
 	TextId     int			 	 `json:"text_id,omitempty"`
 	Duration   int			 	 `json:"duration,omitempty"`
 	Result     map[string]any    `json:"result"`
+	Keystrokes []*KeystrokeEvent `json:"keystrokes,omitempty"`
+}
+
+// ScoreFilter narrows down the scores returned by a listing, zero values match
+// every score
+type ScoreFilter struct {
+	UserId     int
+	ActivityId int
+	TextId     int
+	// scores in any of the review statuses, every status when empty
+	ReviewStatuses []string
 }
 
+// ConvertRequestToScore leaves the result out, the raw result of the request
+// has to be parsed and validated first
 func ConvertRequestToScore(req *ScoreReq) *Score {
 	return &Score{
 		UserId:     req.UserId,
 		ActivityId: req.ActivityId,
 		TextId:     req.TextId,
 		Duration:   req.Duration,
-		Result:     req.Result,
 	}
 }
@@ -0,0 +1,57 @@
+package structures
+
+import "time"
+
+const TYPING_SESSION_TABLE_NAME = "typing_sessions"
+
+const (
+	SESSION_STATUS_ACTIVE   = "active"
+	SESSION_STATUS_FINISHED = "finished"
+	SESSION_STATUS_EXPIRED  = "expired"
+)
+
+// sessions of activities without a time limit expire after SESSION_TTL, timed
+// ones get SESSION_GRACE_PERIOD past their limit to send their last events.
+// Keystroke offsets may run SESSION_CLOCK_TOLERANCE ahead of the server clock
+const (
+	SESSION_TTL             = 30 * time.Minute
+	SESSION_GRACE_PERIOD    = time.Minute
+	SESSION_CLOCK_TOLERANCE = 2 * time.Second
+	SESSION_NONCE_BYTES     = 16
+	MAX_SESSION_KEYSTROKES  = 20000
+)
+
+// TypingSession is a run started on the server, the client proves it is
+// typing on this session by sending back its nonce with every request
+type TypingSession struct {
+	Id             int          `json:"id"`
+	UserId         int          `json:"user_id"`
+	ActivityId     int          `json:"activity_id"`
+	TextId         int          `json:"text_id"`
+	Nonce          string       `json:"nonce"`
+	Status         string       `json:"status"`
+	Keystrokes     []*Keystroke `json:"-" gorm:"serializer:json"`
+	KeystrokeCount int          `json:"keystroke_count" gorm:"-"`
+	ScoreId        *int         `json:"score_id"`
+	StartedAt      time.Time    `json:"started_at"`
+	ExpiresAt      time.Time    `json:"expires_at"`
+	FinishedAt     *time.Time   `json:"finished_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+type TypingSessionReq struct {
+	ActivityId int `json:"activity_id"`
+	TextId     int `json:"text_id"`
+}
+
+// SessionEventsReq is a batch of keystrokes, offsets are milliseconds since
+// the client started typing and carry on from the previous batch
+type SessionEventsReq struct {
+	Nonce      string       `json:"nonce"`
+	Keystrokes []*Keystroke `json:"keystrokes"`
+}
+
+type SessionFinishReq struct {
+	Nonce string `json:"nonce"`
+}
@@ -0,0 +1,167 @@
+package structures
+
+import "time"
+
+const USER_STATS_TABLE_NAME = "user_stats"
+const USER_DAILY_STATS_TABLE_NAME = "user_daily_stats"
+
+// Trend buckets and how many of each a stats response goes back
+const STATS_BUCKET_DAY = "day"
+const STATS_BUCKET_WEEK = "week"
+
+var STATS_TREND_BUCKETS = map[string]int{
+	STATS_BUCKET_DAY:  30,
+	STATS_BUCKET_WEEK: 26,
+}
+
+// STATS_ROLLING_WINDOWS are the numbers of latest scores averaged together
+var STATS_ROLLING_WINDOWS = []int{10, 100}
+
+// UserStatsTotals is the running total of a user's scores, averages are the
+// sums divided by the number of scores
+type UserStatsTotals struct {
+	UserId      int     `json:"user_id"`
+	Scores      int     `json:"scores"`
+	Duration    int     `json:"duration"`
+	WpmSum      float64 `json:"wpm_sum"`
+	AccuracySum float64 `json:"accuracy_sum"`
+}
+
+type PersonalBest struct {
+	ActivityId int       `json:"activity_id"`
+	TextId     int       `json:"text_id"`
+	ScoreId    int       `json:"score_id"`
+	Wpm        float64   `json:"wpm"`
+	Accuracy   float64   `json:"accuracy"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// RollingAverage averages the latest scores of a user, Scores is lower than
+// Window when the user has not typed that many yet
+type RollingAverage struct {
+	Window   int     `json:"window"`
+	Scores   int     `json:"scores"`
+	Wpm      float64 `json:"wpm"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// TrendBucket sums up the scores of a day or of a week starting on Monday,
+// Start is the first day of the bucket
+type TrendBucket struct {
+	Start    string  `json:"start"`
+	Scores   int     `json:"scores"`
+	Duration int     `json:"duration"`
+	Wpm      float64 `json:"wpm"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// UserStats is how a user has been doing, durations are in seconds and the
+// percentile is the share of users with a lower average wpm
+type UserStats struct {
+	UserId        int               `json:"user_id"`
+	Scores        int               `json:"scores"`
+	TotalDuration int               `json:"total_duration"`
+	Wpm           float64           `json:"wpm"`
+	Accuracy      float64           `json:"accuracy"`
+	Percentile    float64           `json:"percentile"`
+	Rolling       []*RollingAverage `json:"rolling"`
+	Bests         []*PersonalBest   `json:"bests"`
+	Bucket        string            `json:"bucket"`
+	Trend         []*TrendBucket    `json:"trend"`
+}
+
+// Keymap stats read the keystroke logs of at most MAX_KEYMAP_STATS_LOGS of
+// the latest scores and list the MAX_KEYMAP_CONFUSIONS most common confusions
+const (
+	MAX_KEYMAP_STATS_LOGS = 500
+	MAX_KEYMAP_CONFUSIONS = 20
+)
+
+// KeyStats is how often the characters of a physical key were expected and
+// how often something else was typed instead, characters the layout cannot
+// produce are keys of their own without a finger
+type KeyStats struct {
+	Key      string  `json:"key"`
+	Row      string  `json:"row,omitempty"`
+	Finger   string  `json:"finger,omitempty"`
+	Attempts int     `json:"attempts"`
+	Errors   int     `json:"errors"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+type FingerStats struct {
+	Finger    string  `json:"finger"`
+	Attempts  int     `json:"attempts"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"error_rate"`
+}
+
+// ConfusionPair counts how often a character was typed where another one
+// was expected
+type ConfusionPair struct {
+	Expected string `json:"expected"`
+	Typed    string `json:"typed"`
+	Count    int    `json:"count"`
+}
+
+type KeymapStats struct {
+	UserId     int              `json:"user_id"`
+	Layout     string           `json:"layout"`
+	Window     string           `json:"window"`
+	Scores     int              `json:"scores"`
+	Keys       []*KeyStats      `json:"keys"`
+	Fingers    []*FingerStats   `json:"fingers"`
+	Confusions []*ConfusionPair `json:"confusions"`
+}
+
+const NGRAM_BASELINE_TABLE_NAME = "ngram_baselines"
+
+// Sequence latencies are the milliseconds between the first and last key of
+// a run of correctly typed letters. Pauses longer than MAX_NGRAM_LATENCY are
+// hesitations rather than slow sequences and are not sampled, sequences with
+// fewer samples than the minimums are not ranked
+const (
+	MAX_NGRAM_LATENCY           = 2000
+	MIN_NGRAM_SAMPLES           = 3
+	MIN_NGRAM_BASELINE_SAMPLES  = 20
+	MAX_NGRAM_BASELINE_LOGS     = 2000
+	MAX_WEAK_NGRAMS             = 10
+	NGRAM_DRILL_WORDS           = 60
+	NGRAM_DRILL_TITLE           = "Weak sequences drill"
+	NGRAM_DRILL_CANDIDATE_WORDS = 30
+)
+
+// NgramBaseline is the median latency of a sequence over the latest logs of
+// every user, it is rebuilt daily
+type NgramBaseline struct {
+	Ngram         string    `json:"ngram" gorm:"primaryKey"`
+	Samples       int       `json:"samples"`
+	MedianLatency float64   `json:"median_latency"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// NgramLatency compares how long a user takes on a sequence to everyone
+// else, a ratio over 1 means the user is slower than usual on it. Sequences
+// without a baseline are compared to the user's own median
+type NgramLatency struct {
+	Ngram           string  `json:"ngram"`
+	Samples         int     `json:"samples"`
+	MedianLatency   float64 `json:"median_latency"`
+	BaselineLatency float64 `json:"baseline_latency"`
+	Ratio           float64 `json:"ratio"`
+}
+
+type NgramStats struct {
+	UserId   int             `json:"user_id"`
+	Window   string          `json:"window"`
+	Scores   int             `json:"scores"`
+	Bigrams  []*NgramLatency `json:"bigrams"`
+	Trigrams []*NgramLatency `json:"trigrams"`
+}
+
+// NgramDrill is a private drill text made for a user out of the sequences
+// slowing them down the most
+type NgramDrill struct {
+	Ngrams []string `json:"ngrams"`
+	Text   *Text    `json:"text"`
+}
@@ -0,0 +1,29 @@
+package structures
+
+import "time"
+
+const TAG_TABLE_NAME = "tags"
+const TEXT_TAG_TABLE_NAME = "text_tags"
+const MAX_TAG_NAME_LENGTH = 60
+
+type Tag struct {
+	Id         int       `json:"id"`
+	Name       string    `json:"name"`
+	UsageCount int       `json:"usage_count" gorm:"->"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type TagReq struct {
+	Name string `json:"name,omitempty"`
+}
+
+type TagMergeReq struct {
+	TargetId int `json:"target_id"`
+}
+
+func ConvertRequestToTag(req *TagReq) *Tag {
+	return &Tag{
+		Name: req.Name,
+	}
+}
@@ -4,24 +4,147 @@ import "time"
 
 const TEXT_TABLE_NAME = "texts"
 
+const (
+	TEXT_TYPE_FULL_TEXT = "full-text"
+	TEXT_TYPE_DRILL     = "drill"
+)
+
+const (
+	TEXT_STATUS_DRAFT    = "draft"
+	TEXT_STATUS_PENDING  = "pending"
+	TEXT_STATUS_APPROVED = "approved"
+	TEXT_STATUS_REJECTED = "rejected"
+)
+
+// listings are ordered by id unless one of these is asked for
+const (
+	TEXT_SORT_TOP_RATED   = "top_rated"
+	TEXT_SORT_MOST_PLAYED = "most_played"
+)
+
+// public texts are listed in the catalog, unlisted ones can only be reached
+// through their share slug and private ones only by their owner
+const (
+	TEXT_VISIBILITY_PUBLIC   = "public"
+	TEXT_VISIBILITY_UNLISTED = "unlisted"
+	TEXT_VISIBILITY_PRIVATE  = "private"
+)
+
 type Text struct {
-	Id         int       	`json:"id"`
-	TextType   string    	`json:"text_type"`
-	Title      string    	`json:"title"`
-	Difficulty string    	`json:"difficulty"`
-	Tags       []string  	`json:"tags" gorm:"serializer:json"`
-	TextBody   string    	`json:"text_body"`
-	TextLength int       	`json:"text_length"`
-	CreatedAt  time.Time 	`json:"created_at"`
-	UpdatedAt  time.Time 	`json:"updated_at"`
+	Id            int        	`json:"id"`
+	TextType      string     	`json:"text_type"`
+	Title         string     	`json:"title"`
+	Difficulty    string     	`json:"difficulty"`
+	Language      string     	`json:"language"`
+	Author        string     	`json:"author"`
+	Source        string     	`json:"source"`
+	SourceUrl     string     	`json:"source_url"`
+	License       string     	`json:"license"`
+	AttributionRequired bool 	`json:"attribution_required"`
+	Attribution   string     	`json:"attribution,omitempty" gorm:"-"`
+	Tags          []string   	`json:"tags" gorm:"serializer:json;->"`
+	TextBody      string     	`json:"text_body"`
+	TextLength    int        	`json:"text_length"`
+	Status        string     	`json:"status"`
+	SubmitterId   *int       	`json:"submitter_id"`
+	ReviewerNotes string     	`json:"reviewer_notes"`
+	ReviewedAt    *time.Time 	`json:"reviewed_at"`
+	OwnerId       *int       	`json:"owner_id"`
+	Visibility    string     	`json:"visibility"`
+	ShareSlug     *string    	`json:"share_slug,omitempty"`
+	AverageRating float64    	`json:"average_rating" gorm:"->"`
+	RatingCount   int        	`json:"rating_count" gorm:"->"`
+	FavoriteCount int        	`json:"favorite_count" gorm:"->"`
+	PlayCount     int        	`json:"play_count" gorm:"->"`
+	Fingerprint   []uint32   	`json:"-" gorm:"serializer:json"`
+	SimilarTexts  []*SimilarText `json:"similar_texts,omitempty" gorm:"-"`
+	CreatedAt     time.Time  	`json:"created_at"`
+	UpdatedAt     time.Time  	`json:"updated_at"`
 }
 
 type TextReq struct {
 	TextType   string 		`json:"text_type,omitempty"`
 	Title      string 		`json:"title,omitempty"`
 	Difficulty string 		`json:"difficulty,omitempty"`
+	Language   string 		`json:"language,omitempty"`
+	Author     string 		`json:"author,omitempty"`
+	Source     string 		`json:"source,omitempty"`
+	SourceUrl  string 		`json:"source_url,omitempty"`
+	License    string 		`json:"license,omitempty"`
+	AttributionRequired *bool `json:"attribution_required,omitempty"`
 	Tags       []string 	`json:"tags"`
 	TextBody   string 		`json:"text_body,omitempty"`
+	Status     string 		`json:"status,omitempty"`
+	Visibility string 		`json:"visibility,omitempty"`
+	AllowDuplicate bool 	`json:"allow_duplicate,omitempty"`
+}
+
+type TextReviewReq struct {
+	ReviewerNotes string `json:"reviewer_notes,omitempty"`
+}
+
+// TextFilter narrows down the texts returned by a listing, zero values match
+// every text, texts owned by ViewerId are listed whatever their status and
+// visibility
+type TextFilter struct {
+	Status      string
+	Language    string
+	License     string
+	Visibility  string
+	ViewerId    int
+	FavoritedBy int
+	Sort        string
+}
+
+// License is one of the licenses texts are published under, texts under a
+// license requiring attribution credit their source wherever they are shown
+type License struct {
+	Id                  string `json:"id"`
+	Name                string `json:"name"`
+	Url                 string `json:"url,omitempty"`
+	AttributionRequired bool   `json:"attribution_required"`
+}
+
+// SimilarText points at a text whose content overlaps another one, Similarity
+// is the estimated share of common passages between 0 and 1
+type SimilarText struct {
+	TextId     int     `json:"text_id"`
+	Title      string  `json:"title"`
+	Status     string  `json:"status"`
+	Similarity float64 `json:"similarity"`
+}
+
+// TextFingerprint is the slice of a text needed to compare it against others
+type TextFingerprint struct {
+	Id          int      `json:"id"`
+	Title       string   `json:"title"`
+	Status      string   `json:"status"`
+	OwnerId     *int     `json:"owner_id"`
+	Visibility  string   `json:"visibility"`
+	Fingerprint []uint32 `json:"fingerprint" gorm:"serializer:json"`
+}
+
+type TextLintReq struct {
+	TextBody string `json:"text_body"`
+}
+
+// TextLintIssue is a character the normalization pipeline acted on, Position
+// counts characters from the start of the text while Line and Column start at 1
+type TextLintIssue struct {
+	Position    int    `json:"position"`
+	Line        int    `json:"line"`
+	Column      int    `json:"column"`
+	Char        string `json:"char"`
+	CodePoint   string `json:"code_point"`
+	Rule        string `json:"rule"`
+	Action      string `json:"action"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+type TextLintResult struct {
+	Typeable       bool             `json:"typeable"`
+	NormalizedBody string           `json:"normalized_body"`
+	Issues         []*TextLintIssue `json:"issues"`
 }
 
 func ConvertRequestToText(req *TextReq) *Text {
@@ -29,8 +152,15 @@ func ConvertRequestToText(req *TextReq) *Text {
 		TextType:   req.TextType,
 		Title:      req.Title,
 		Difficulty: req.Difficulty,
+		Language:   req.Language,
+		Author:     req.Author,
+		Source:     req.Source,
+		SourceUrl:  req.SourceUrl,
+		License:    req.License,
 		Tags:		req.Tags,
 		TextBody:   req.TextBody,
 		TextLength: len(req.TextBody),
+		Status:     req.Status,
+		Visibility: req.Visibility,
 	}
 }
@@ -4,33 +4,49 @@ import "time"
 
 const USER_TABLE_NAME = "users"
 
+const (
+	USER_TYPE_ADMIN   = "admin"
+	USER_TYPE_REGULAR = "regular"
+	USER_TYPE_GENERIC = "generic"
+)
+
+// DEFAULT_TIME_ZONE is the time zone days are counted in for users who did
+// not pick one, streaks and goals follow the user's own days
+const DEFAULT_TIME_ZONE = "UTC"
+
 type User struct {
-	Id         int       `json:"id"`
-	UserType   string    `json:"user_type"`
-	Username   string    `json:"username"`
-	PasswdHash string    `json:"passwd_hash"`
-	Name       string    `json:"name"`
-	Email      string    `json:"email"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	Id             int       `json:"id"`
+	UserType       string    `json:"user_type"`
+	Username       string    `json:"username"`
+	PasswdHash     string    `json:"passwd_hash"`
+	Name           string    `json:"name"`
+	Email          string    `json:"email"`
+	KeyboardLayout string    `json:"keyboard_layout"`
+	TimeZone       string    `json:"time_zone"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 type UserReq struct {
-	UserType string `json:"user_type,omitempty"`
-	Username string `json:"username,omitempty"`
-	Password string `json:"password,omitempty"`
-	Name     string `json:"name,omitempty"`
-	Email    string `json:"email,omitempty"`
+	UserType       string `json:"user_type,omitempty"`
+	Username       string `json:"username,omitempty"`
+	Password       string `json:"password,omitempty"`
+	Name           string `json:"name,omitempty"`
+	Email          string `json:"email,omitempty"`
+	KeyboardLayout string `json:"keyboard_layout,omitempty"`
+	TimeZone       string `json:"time_zone,omitempty"`
 }
 
 type UserResp struct {
-	Id        int       `json:"id"`
-	UserType  string    `json:"user_type"`
-	Username  string    `json:"username"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Id             int       `json:"id"`
+	UserType       string    `json:"user_type"`
+	Username       string    `json:"username"`
+	Name           string    `json:"name"`
+	Email          string    `json:"email"`
+	KeyboardLayout string    `json:"keyboard_layout"`
+	TimeZone       string    `json:"time_zone"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 type UserLoginReq struct {
@@ -40,22 +56,25 @@ type UserLoginReq struct {
 
 func ConvertRequestToUser(req *UserReq) *User {
 	return &User{
-		UserType: req.UserType,
-		Username: req.Username,
-		Name:     req.Name,
-		Email:    req.Email,
+		UserType:       req.UserType,
+		Username:       req.Username,
+		Name:           req.Name,
+		Email:          req.Email,
+		KeyboardLayout: req.KeyboardLayout,
 	}
 }
 
 func ConvertUserToResponse(user *User) *UserResp {
 	return &UserResp{
-		Id:        user.Id,
-		UserType:  user.UserType,
-		Username:  user.Username,
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		Id:             user.Id,
+		UserType:       user.UserType,
+		Username:       user.Username,
+		Name:           user.Name,
+		Email:          user.Email,
+		KeyboardLayout: user.KeyboardLayout,
+		TimeZone:       user.TimeZone,
+		CreatedAt:      user.CreatedAt,
+		UpdatedAt:      user.UpdatedAt,
 	}
 }
 
@@ -1,7 +1,7 @@
 INSERT INTO scores (user_id, activity_id, text_id, duration, result)
 VALUES (
-    1, 1, 1, 60, '{ "wpm": 300, "errors": 300 }'
+    1, 1, 1, 60, '{ "version": 1, "wpm": 300, "errors": 300 }'
 ),
 (
-    2, 2, 2, 60, '{ "wpm": 300, "errors": 300 }'
+    2, 2, 2, 60, '{ "version": 1, "wpm": 300, "errors": 300 }'
 )
@@ -0,0 +1,118 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./providers/achievements/achievements_provider.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./providers/achievements/achievements_provider.go -destination=./testing/mocks/providers/achievements_provider_mock.go -package=mock_providers
+//
+
+// Package mock_providers is a generated GoMock package.
+package mock_providers
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	structures "type_writer_api/structures"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAchievementsProviderInterface is a mock of AchievementsProviderInterface interface.
+type MockAchievementsProviderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAchievementsProviderInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockAchievementsProviderInterfaceMockRecorder is the mock recorder for MockAchievementsProviderInterface.
+type MockAchievementsProviderInterfaceMockRecorder struct {
+	mock *MockAchievementsProviderInterface
+}
+
+// NewMockAchievementsProviderInterface creates a new mock instance.
+func NewMockAchievementsProviderInterface(ctrl *gomock.Controller) *MockAchievementsProviderInterface {
+	mock := &MockAchievementsProviderInterface{ctrl: ctrl}
+	mock.recorder = &MockAchievementsProviderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAchievementsProviderInterface) EXPECT() *MockAchievementsProviderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AwardAchievements mocks base method.
+func (m *MockAchievementsProviderInterface) AwardAchievements(ctx context.Context, achievements []*structures.UserAchievement) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AwardAchievements", ctx, achievements)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AwardAchievements indicates an expected call of AwardAchievements.
+func (mr *MockAchievementsProviderInterfaceMockRecorder) AwardAchievements(ctx, achievements any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AwardAchievements", reflect.TypeOf((*MockAchievementsProviderInterface)(nil).AwardAchievements), ctx, achievements)
+}
+
+// CountRankedScores mocks base method.
+func (m *MockAchievementsProviderInterface) CountRankedScores(ctx context.Context, userId int, until time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRankedScores", ctx, userId, until)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRankedScores indicates an expected call of CountRankedScores.
+func (mr *MockAchievementsProviderInterfaceMockRecorder) CountRankedScores(ctx, userId, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRankedScores", reflect.TypeOf((*MockAchievementsProviderInterface)(nil).CountRankedScores), ctx, userId, until)
+}
+
+// GetRankedScores mocks base method.
+func (m *MockAchievementsProviderInterface) GetRankedScores(ctx context.Context, afterId, limit int) ([]*structures.Score, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRankedScores", ctx, afterId, limit)
+	ret0, _ := ret[0].([]*structures.Score)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRankedScores indicates an expected call of GetRankedScores.
+func (mr *MockAchievementsProviderInterfaceMockRecorder) GetRankedScores(ctx, afterId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRankedScores", reflect.TypeOf((*MockAchievementsProviderInterface)(nil).GetRankedScores), ctx, afterId, limit)
+}
+
+// GetScoreDays mocks base method.
+func (m *MockAchievementsProviderInterface) GetScoreDays(ctx context.Context, userId int, until time.Time, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScoreDays", ctx, userId, until, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScoreDays indicates an expected call of GetScoreDays.
+func (mr *MockAchievementsProviderInterfaceMockRecorder) GetScoreDays(ctx, userId, until, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScoreDays", reflect.TypeOf((*MockAchievementsProviderInterface)(nil).GetScoreDays), ctx, userId, until, limit)
+}
+
+// GetUserAchievements mocks base method.
+func (m *MockAchievementsProviderInterface) GetUserAchievements(ctx context.Context, userId int) ([]*structures.UserAchievement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserAchievements", ctx, userId)
+	ret0, _ := ret[0].([]*structures.UserAchievement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserAchievements indicates an expected call of GetUserAchievements.
+func (mr *MockAchievementsProviderInterfaceMockRecorder) GetUserAchievements(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAchievements", reflect.TypeOf((*MockAchievementsProviderInterface)(nil).GetUserAchievements), ctx, userId)
+}
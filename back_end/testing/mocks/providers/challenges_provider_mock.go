@@ -0,0 +1,163 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./providers/challenges/challenges_provider.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./providers/challenges/challenges_provider.go -destination=./testing/mocks/providers/challenges_provider_mock.go -package=mock_providers
+//
+
+// Package mock_providers is a generated GoMock package.
+package mock_providers
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	structures "type_writer_api/structures"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockChallengesProviderInterface is a mock of ChallengesProviderInterface interface.
+type MockChallengesProviderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockChallengesProviderInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockChallengesProviderInterfaceMockRecorder is the mock recorder for MockChallengesProviderInterface.
+type MockChallengesProviderInterfaceMockRecorder struct {
+	mock *MockChallengesProviderInterface
+}
+
+// NewMockChallengesProviderInterface creates a new mock instance.
+func NewMockChallengesProviderInterface(ctrl *gomock.Controller) *MockChallengesProviderInterface {
+	mock := &MockChallengesProviderInterface{ctrl: ctrl}
+	mock.recorder = &MockChallengesProviderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChallengesProviderInterface) EXPECT() *MockChallengesProviderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateChallengeAttempt mocks base method.
+func (m *MockChallengesProviderInterface) CreateChallengeAttempt(ctx context.Context, attemptInfo structures.ChallengeAttempt) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChallengeAttempt", ctx, attemptInfo)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChallengeAttempt indicates an expected call of CreateChallengeAttempt.
+func (mr *MockChallengesProviderInterfaceMockRecorder) CreateChallengeAttempt(ctx, attemptInfo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChallengeAttempt", reflect.TypeOf((*MockChallengesProviderInterface)(nil).CreateChallengeAttempt), ctx, attemptInfo)
+}
+
+// CreateChallengeQueueEntry mocks base method.
+func (m *MockChallengesProviderInterface) CreateChallengeQueueEntry(ctx context.Context, entryInfo structures.ChallengeQueueEntry) (*structures.ChallengeQueueEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChallengeQueueEntry", ctx, entryInfo)
+	ret0, _ := ret[0].(*structures.ChallengeQueueEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChallengeQueueEntry indicates an expected call of CreateChallengeQueueEntry.
+func (mr *MockChallengesProviderInterfaceMockRecorder) CreateChallengeQueueEntry(ctx, entryInfo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChallengeQueueEntry", reflect.TypeOf((*MockChallengesProviderInterface)(nil).CreateChallengeQueueEntry), ctx, entryInfo)
+}
+
+// DeleteChallengeQueueEntry mocks base method.
+func (m *MockChallengesProviderInterface) DeleteChallengeQueueEntry(ctx context.Context, entryId int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChallengeQueueEntry", ctx, entryId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteChallengeQueueEntry indicates an expected call of DeleteChallengeQueueEntry.
+func (mr *MockChallengesProviderInterfaceMockRecorder) DeleteChallengeQueueEntry(ctx, entryId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChallengeQueueEntry", reflect.TypeOf((*MockChallengesProviderInterface)(nil).DeleteChallengeQueueEntry), ctx, entryId)
+}
+
+// GetChallengeByDate mocks base method.
+func (m *MockChallengesProviderInterface) GetChallengeByDate(ctx context.Context, date string) (*structures.DailyChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChallengeByDate", ctx, date)
+	ret0, _ := ret[0].(*structures.DailyChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChallengeByDate indicates an expected call of GetChallengeByDate.
+func (mr *MockChallengesProviderInterfaceMockRecorder) GetChallengeByDate(ctx, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChallengeByDate", reflect.TypeOf((*MockChallengesProviderInterface)(nil).GetChallengeByDate), ctx, date)
+}
+
+// GetChallengeLeaderboard mocks base method.
+func (m *MockChallengesProviderInterface) GetChallengeLeaderboard(ctx context.Context, challengeId int) ([]*structures.ChallengeLeaderboardEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChallengeLeaderboard", ctx, challengeId)
+	ret0, _ := ret[0].([]*structures.ChallengeLeaderboardEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChallengeLeaderboard indicates an expected call of GetChallengeLeaderboard.
+func (mr *MockChallengesProviderInterfaceMockRecorder) GetChallengeLeaderboard(ctx, challengeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChallengeLeaderboard", reflect.TypeOf((*MockChallengesProviderInterface)(nil).GetChallengeLeaderboard), ctx, challengeId)
+}
+
+// GetChallengeQueue mocks base method.
+func (m *MockChallengesProviderInterface) GetChallengeQueue(ctx context.Context) ([]*structures.ChallengeQueueEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChallengeQueue", ctx)
+	ret0, _ := ret[0].([]*structures.ChallengeQueueEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChallengeQueue indicates an expected call of GetChallengeQueue.
+func (mr *MockChallengesProviderInterfaceMockRecorder) GetChallengeQueue(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChallengeQueue", reflect.TypeOf((*MockChallengesProviderInterface)(nil).GetChallengeQueue), ctx)
+}
+
+// GetFirstChallengeScore mocks base method.
+func (m *MockChallengesProviderInterface) GetFirstChallengeScore(ctx context.Context, userId, activityId, textId int, startsAt, endsAt time.Time) (*structures.Score, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFirstChallengeScore", ctx, userId, activityId, textId, startsAt, endsAt)
+	ret0, _ := ret[0].(*structures.Score)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFirstChallengeScore indicates an expected call of GetFirstChallengeScore.
+func (mr *MockChallengesProviderInterfaceMockRecorder) GetFirstChallengeScore(ctx, userId, activityId, textId, startsAt, endsAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFirstChallengeScore", reflect.TypeOf((*MockChallengesProviderInterface)(nil).GetFirstChallengeScore), ctx, userId, activityId, textId, startsAt, endsAt)
+}
+
+// ScheduleChallenge mocks base method.
+func (m *MockChallengesProviderInterface) ScheduleChallenge(ctx context.Context, challengeInfo structures.DailyChallenge, queueEntryId int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleChallenge", ctx, challengeInfo, queueEntryId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleChallenge indicates an expected call of ScheduleChallenge.
+func (mr *MockChallengesProviderInterfaceMockRecorder) ScheduleChallenge(ctx, challengeInfo, queueEntryId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleChallenge", reflect.TypeOf((*MockChallengesProviderInterface)(nil).ScheduleChallenge), ctx, challengeInfo, queueEntryId)
+}
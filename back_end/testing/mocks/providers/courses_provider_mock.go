@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./providers/courses/courses_provider.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./providers/courses/courses_provider.go -destination=./testing/mocks/providers/courses_provider_mock.go -package=mock_providers
+//
+
+// Package mock_providers is a generated GoMock package.
+package mock_providers
+
+import (
+	context "context"
+	reflect "reflect"
+	structures "type_writer_api/structures"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCoursesProviderInterface is a mock of CoursesProviderInterface interface.
+type MockCoursesProviderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCoursesProviderInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockCoursesProviderInterfaceMockRecorder is the mock recorder for MockCoursesProviderInterface.
+type MockCoursesProviderInterfaceMockRecorder struct {
+	mock *MockCoursesProviderInterface
+}
+
+// NewMockCoursesProviderInterface creates a new mock instance.
+func NewMockCoursesProviderInterface(ctrl *gomock.Controller) *MockCoursesProviderInterface {
+	mock := &MockCoursesProviderInterface{ctrl: ctrl}
+	mock.recorder = &MockCoursesProviderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCoursesProviderInterface) EXPECT() *MockCoursesProviderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateCourse mocks base method.
+func (m *MockCoursesProviderInterface) CreateCourse(ctx context.Context, courseInfo structures.Course) (*structures.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCourse", ctx, courseInfo)
+	ret0, _ := ret[0].(*structures.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCourse indicates an expected call of CreateCourse.
+func (mr *MockCoursesProviderInterfaceMockRecorder) CreateCourse(ctx, courseInfo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCourse", reflect.TypeOf((*MockCoursesProviderInterface)(nil).CreateCourse), ctx, courseInfo)
+}
+
+// DeleteCourse mocks base method.
+func (m *MockCoursesProviderInterface) DeleteCourse(ctx context.Context, courseId int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCourse", ctx, courseId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCourse indicates an expected call of DeleteCourse.
+func (mr *MockCoursesProviderInterfaceMockRecorder) DeleteCourse(ctx, courseId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCourse", reflect.TypeOf((*MockCoursesProviderInterface)(nil).DeleteCourse), ctx, courseId)
+}
+
+// GetCourseById mocks base method.
+func (m *MockCoursesProviderInterface) GetCourseById(ctx context.Context, courseId int) (*structures.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCourseById", ctx, courseId)
+	ret0, _ := ret[0].(*structures.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCourseById indicates an expected call of GetCourseById.
+func (mr *MockCoursesProviderInterfaceMockRecorder) GetCourseById(ctx, courseId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourseById", reflect.TypeOf((*MockCoursesProviderInterface)(nil).GetCourseById), ctx, courseId)
+}
+
+// GetCourses mocks base method.
+func (m *MockCoursesProviderInterface) GetCourses(ctx context.Context) ([]*structures.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCourses", ctx)
+	ret0, _ := ret[0].([]*structures.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCourses indicates an expected call of GetCourses.
+func (mr *MockCoursesProviderInterfaceMockRecorder) GetCourses(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourses", reflect.TypeOf((*MockCoursesProviderInterface)(nil).GetCourses), ctx)
+}
+
+// UpdateCourse mocks base method.
+func (m *MockCoursesProviderInterface) UpdateCourse(ctx context.Context, updatedCourseInfo structures.Course) (*structures.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCourse", ctx, updatedCourseInfo)
+	ret0, _ := ret[0].(*structures.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCourse indicates an expected call of UpdateCourse.
+func (mr *MockCoursesProviderInterfaceMockRecorder) UpdateCourse(ctx, updatedCourseInfo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCourse", reflect.TypeOf((*MockCoursesProviderInterface)(nil).UpdateCourse), ctx, updatedCourseInfo)
+}
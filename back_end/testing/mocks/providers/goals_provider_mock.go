@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./providers/goals/goals_provider.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./providers/goals/goals_provider.go -destination=./testing/mocks/providers/goals_provider_mock.go -package=mock_providers
+//
+
+// Package mock_providers is a generated GoMock package.
+package mock_providers
+
+import (
+	context "context"
+	reflect "reflect"
+	structures "type_writer_api/structures"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockGoalsProviderInterface is a mock of GoalsProviderInterface interface.
+type MockGoalsProviderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockGoalsProviderInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockGoalsProviderInterfaceMockRecorder is the mock recorder for MockGoalsProviderInterface.
+type MockGoalsProviderInterfaceMockRecorder struct {
+	mock *MockGoalsProviderInterface
+}
+
+// NewMockGoalsProviderInterface creates a new mock instance.
+func NewMockGoalsProviderInterface(ctrl *gomock.Controller) *MockGoalsProviderInterface {
+	mock := &MockGoalsProviderInterface{ctrl: ctrl}
+	mock.recorder = &MockGoalsProviderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGoalsProviderInterface) EXPECT() *MockGoalsProviderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CountUserGoals mocks base method.
+func (m *MockGoalsProviderInterface) CountUserGoals(ctx context.Context, userId int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUserGoals", ctx, userId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserGoals indicates an expected call of CountUserGoals.
+func (mr *MockGoalsProviderInterfaceMockRecorder) CountUserGoals(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserGoals", reflect.TypeOf((*MockGoalsProviderInterface)(nil).CountUserGoals), ctx, userId)
+}
+
+// CreateUserGoal mocks base method.
+func (m *MockGoalsProviderInterface) CreateUserGoal(ctx context.Context, goalInfo structures.UserGoal) (*structures.UserGoal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserGoal", ctx, goalInfo)
+	ret0, _ := ret[0].(*structures.UserGoal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserGoal indicates an expected call of CreateUserGoal.
+func (mr *MockGoalsProviderInterfaceMockRecorder) CreateUserGoal(ctx, goalInfo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserGoal", reflect.TypeOf((*MockGoalsProviderInterface)(nil).CreateUserGoal), ctx, goalInfo)
+}
+
+// DeleteUserGoal mocks base method.
+func (m *MockGoalsProviderInterface) DeleteUserGoal(ctx context.Context, userId, goalId int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserGoal", ctx, userId, goalId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUserGoal indicates an expected call of DeleteUserGoal.
+func (mr *MockGoalsProviderInterfaceMockRecorder) DeleteUserGoal(ctx, userId, goalId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserGoal", reflect.TypeOf((*MockGoalsProviderInterface)(nil).DeleteUserGoal), ctx, userId, goalId)
+}
+
+// GetGoalSamples mocks base method.
+func (m *MockGoalsProviderInterface) GetGoalSamples(ctx context.Context, userId int, metric string, limit int) ([]*structures.GoalSample, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGoalSamples", ctx, userId, metric, limit)
+	ret0, _ := ret[0].([]*structures.GoalSample)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGoalSamples indicates an expected call of GetGoalSamples.
+func (mr *MockGoalsProviderInterfaceMockRecorder) GetGoalSamples(ctx, userId, metric, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGoalSamples", reflect.TypeOf((*MockGoalsProviderInterface)(nil).GetGoalSamples), ctx, userId, metric, limit)
+}
+
+// GetPracticeDays mocks base method.
+func (m *MockGoalsProviderInterface) GetPracticeDays(ctx context.Context, userId int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPracticeDays", ctx, userId)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPracticeDays indicates an expected call of GetPracticeDays.
+func (mr *MockGoalsProviderInterfaceMockRecorder) GetPracticeDays(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPracticeDays", reflect.TypeOf((*MockGoalsProviderInterface)(nil).GetPracticeDays), ctx, userId)
+}
+
+// GetUserGoals mocks base method.
+func (m *MockGoalsProviderInterface) GetUserGoals(ctx context.Context, userId int) ([]*structures.UserGoal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserGoals", ctx, userId)
+	ret0, _ := ret[0].([]*structures.UserGoal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserGoals indicates an expected call of GetUserGoals.
+func (mr *MockGoalsProviderInterfaceMockRecorder) GetUserGoals(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserGoals", reflect.TypeOf((*MockGoalsProviderInterface)(nil).GetUserGoals), ctx, userId)
+}
@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./providers/keyboard_layouts/keyboard_layouts_provider.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./providers/keyboard_layouts/keyboard_layouts_provider.go -destination=./testing/mocks/providers/keyboard_layouts_provider_mock.go -package=mock_providers
+//
+
+// Package mock_providers is a generated GoMock package.
+package mock_providers
+
+import (
+	context "context"
+	reflect "reflect"
+	structures "type_writer_api/structures"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockKeyboardLayoutsProviderInterface is a mock of KeyboardLayoutsProviderInterface interface.
+type MockKeyboardLayoutsProviderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyboardLayoutsProviderInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockKeyboardLayoutsProviderInterfaceMockRecorder is the mock recorder for MockKeyboardLayoutsProviderInterface.
+type MockKeyboardLayoutsProviderInterfaceMockRecorder struct {
+	mock *MockKeyboardLayoutsProviderInterface
+}
+
+// NewMockKeyboardLayoutsProviderInterface creates a new mock instance.
+func NewMockKeyboardLayoutsProviderInterface(ctrl *gomock.Controller) *MockKeyboardLayoutsProviderInterface {
+	mock := &MockKeyboardLayoutsProviderInterface{ctrl: ctrl}
+	mock.recorder = &MockKeyboardLayoutsProviderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyboardLayoutsProviderInterface) EXPECT() *MockKeyboardLayoutsProviderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CountLayoutUsers mocks base method.
+func (m *MockKeyboardLayoutsProviderInterface) CountLayoutUsers(ctx context.Context, name string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLayoutUsers", ctx, name)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountLayoutUsers indicates an expected call of CountLayoutUsers.
+func (mr *MockKeyboardLayoutsProviderInterfaceMockRecorder) CountLayoutUsers(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLayoutUsers", reflect.TypeOf((*MockKeyboardLayoutsProviderInterface)(nil).CountLayoutUsers), ctx, name)
+}
+
+// CreateKeyboardLayout mocks base method.
+func (m *MockKeyboardLayoutsProviderInterface) CreateKeyboardLayout(ctx context.Context, layoutInfo structures.KeyboardLayout) (*structures.KeyboardLayout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKeyboardLayout", ctx, layoutInfo)
+	ret0, _ := ret[0].(*structures.KeyboardLayout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateKeyboardLayout indicates an expected call of CreateKeyboardLayout.
+func (mr *MockKeyboardLayoutsProviderInterfaceMockRecorder) CreateKeyboardLayout(ctx, layoutInfo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKeyboardLayout", reflect.TypeOf((*MockKeyboardLayoutsProviderInterface)(nil).CreateKeyboardLayout), ctx, layoutInfo)
+}
+
+// DeleteKeyboardLayout mocks base method.
+func (m *MockKeyboardLayoutsProviderInterface) DeleteKeyboardLayout(ctx context.Context, layoutId int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteKeyboardLayout", ctx, layoutId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteKeyboardLayout indicates an expected call of DeleteKeyboardLayout.
+func (mr *MockKeyboardLayoutsProviderInterfaceMockRecorder) DeleteKeyboardLayout(ctx, layoutId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKeyboardLayout", reflect.TypeOf((*MockKeyboardLayoutsProviderInterface)(nil).DeleteKeyboardLayout), ctx, layoutId)
+}
+
+// GetKeyboardLayoutByIdOrName mocks base method.
+func (m *MockKeyboardLayoutsProviderInterface) GetKeyboardLayoutByIdOrName(ctx context.Context, layoutId *int, name *string) (*structures.KeyboardLayout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyboardLayoutByIdOrName", ctx, layoutId, name)
+	ret0, _ := ret[0].(*structures.KeyboardLayout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyboardLayoutByIdOrName indicates an expected call of GetKeyboardLayoutByIdOrName.
+func (mr *MockKeyboardLayoutsProviderInterfaceMockRecorder) GetKeyboardLayoutByIdOrName(ctx, layoutId, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyboardLayoutByIdOrName", reflect.TypeOf((*MockKeyboardLayoutsProviderInterface)(nil).GetKeyboardLayoutByIdOrName), ctx, layoutId, name)
+}
+
+// GetKeyboardLayouts mocks base method.
+func (m *MockKeyboardLayoutsProviderInterface) GetKeyboardLayouts(ctx context.Context) ([]*structures.KeyboardLayout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyboardLayouts", ctx)
+	ret0, _ := ret[0].([]*structures.KeyboardLayout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyboardLayouts indicates an expected call of GetKeyboardLayouts.
+func (mr *MockKeyboardLayoutsProviderInterfaceMockRecorder) GetKeyboardLayouts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyboardLayouts", reflect.TypeOf((*MockKeyboardLayoutsProviderInterface)(nil).GetKeyboardLayouts), ctx)
+}
+
+// UpdateKeyboardLayout mocks base method.
+func (m *MockKeyboardLayoutsProviderInterface) UpdateKeyboardLayout(ctx context.Context, updatedLayoutInfo structures.KeyboardLayout) (*structures.KeyboardLayout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateKeyboardLayout", ctx, updatedLayoutInfo)
+	ret0, _ := ret[0].(*structures.KeyboardLayout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateKeyboardLayout indicates an expected call of UpdateKeyboardLayout.
+func (mr *MockKeyboardLayoutsProviderInterfaceMockRecorder) UpdateKeyboardLayout(ctx, updatedLayoutInfo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateKeyboardLayout", reflect.TypeOf((*MockKeyboardLayoutsProviderInterface)(nil).UpdateKeyboardLayout), ctx, updatedLayoutInfo)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./providers/leaderboards/leaderboards_provider.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./providers/leaderboards/leaderboards_provider.go -destination=./testing/mocks/providers/leaderboards_provider_mock.go -package=mock_providers
+//
+
+// Package mock_providers is a generated GoMock package.
+package mock_providers
+
+import (
+	context "context"
+	reflect "reflect"
+	structures "type_writer_api/structures"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLeaderboardsProviderInterface is a mock of LeaderboardsProviderInterface interface.
+type MockLeaderboardsProviderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeaderboardsProviderInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockLeaderboardsProviderInterfaceMockRecorder is the mock recorder for MockLeaderboardsProviderInterface.
+type MockLeaderboardsProviderInterfaceMockRecorder struct {
+	mock *MockLeaderboardsProviderInterface
+}
+
+// NewMockLeaderboardsProviderInterface creates a new mock instance.
+func NewMockLeaderboardsProviderInterface(ctrl *gomock.Controller) *MockLeaderboardsProviderInterface {
+	mock := &MockLeaderboardsProviderInterface{ctrl: ctrl}
+	mock.recorder = &MockLeaderboardsProviderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeaderboardsProviderInterface) EXPECT() *MockLeaderboardsProviderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetLeaderboard mocks base method.
+func (m *MockLeaderboardsProviderInterface) GetLeaderboard(ctx context.Context, query structures.LeaderboardQuery) ([]*structures.LeaderboardEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderboard", ctx, query)
+	ret0, _ := ret[0].([]*structures.LeaderboardEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeaderboard indicates an expected call of GetLeaderboard.
+func (mr *MockLeaderboardsProviderInterfaceMockRecorder) GetLeaderboard(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboard", reflect.TypeOf((*MockLeaderboardsProviderInterface)(nil).GetLeaderboard), ctx, query)
+}
+
+// GetLeaderboardEntry mocks base method.
+func (m *MockLeaderboardsProviderInterface) GetLeaderboardEntry(ctx context.Context, query structures.LeaderboardQuery, userId int) (*structures.LeaderboardEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderboardEntry", ctx, query, userId)
+	ret0, _ := ret[0].(*structures.LeaderboardEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeaderboardEntry indicates an expected call of GetLeaderboardEntry.
+func (mr *MockLeaderboardsProviderInterfaceMockRecorder) GetLeaderboardEntry(ctx, query, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboardEntry", reflect.TypeOf((*MockLeaderboardsProviderInterface)(nil).GetLeaderboardEntry), ctx, query, userId)
+}
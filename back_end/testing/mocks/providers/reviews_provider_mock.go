@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./providers/reviews/reviews_provider.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./providers/reviews/reviews_provider.go -destination=./testing/mocks/providers/reviews_provider_mock.go -package=mock_providers
+//
+
+// Package mock_providers is a generated GoMock package.
+package mock_providers
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	structures "type_writer_api/structures"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReviewsProviderInterface is a mock of ReviewsProviderInterface interface.
+type MockReviewsProviderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewsProviderInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockReviewsProviderInterfaceMockRecorder is the mock recorder for MockReviewsProviderInterface.
+type MockReviewsProviderInterfaceMockRecorder struct {
+	mock *MockReviewsProviderInterface
+}
+
+// NewMockReviewsProviderInterface creates a new mock instance.
+func NewMockReviewsProviderInterface(ctrl *gomock.Controller) *MockReviewsProviderInterface {
+	mock := &MockReviewsProviderInterface{ctrl: ctrl}
+	mock.recorder = &MockReviewsProviderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviewsProviderInterface) EXPECT() *MockReviewsProviderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetDueReviewWords mocks base method.
+func (m *MockReviewsProviderInterface) GetDueReviewWords(ctx context.Context, userId int, now time.Time, limit int) ([]*structures.ReviewWord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueReviewWords", ctx, userId, now, limit)
+	ret0, _ := ret[0].([]*structures.ReviewWord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueReviewWords indicates an expected call of GetDueReviewWords.
+func (mr *MockReviewsProviderInterfaceMockRecorder) GetDueReviewWords(ctx, userId, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueReviewWords", reflect.TypeOf((*MockReviewsProviderInterface)(nil).GetDueReviewWords), ctx, userId, now, limit)
+}
+
+// GetReviewWords mocks base method.
+func (m *MockReviewsProviderInterface) GetReviewWords(ctx context.Context, userId int, words []string) ([]*structures.ReviewWord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewWords", ctx, userId, words)
+	ret0, _ := ret[0].([]*structures.ReviewWord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewWords indicates an expected call of GetReviewWords.
+func (mr *MockReviewsProviderInterfaceMockRecorder) GetReviewWords(ctx, userId, words any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewWords", reflect.TypeOf((*MockReviewsProviderInterface)(nil).GetReviewWords), ctx, userId, words)
+}
+
+// SaveReviewWords mocks base method.
+func (m *MockReviewsProviderInterface) SaveReviewWords(ctx context.Context, words []*structures.ReviewWord) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveReviewWords", ctx, words)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveReviewWords indicates an expected call of SaveReviewWords.
+func (mr *MockReviewsProviderInterfaceMockRecorder) SaveReviewWords(ctx, words any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveReviewWords", reflect.TypeOf((*MockReviewsProviderInterface)(nil).SaveReviewWords), ctx, words)
+}
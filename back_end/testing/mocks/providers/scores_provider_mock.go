@@ -3,15 +3,16 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=./providers/scores/scores_provider.go -destination=./testing/mocks/providers/scores_provider_mock.go
+//	mockgen -source=./providers/scores/scores_provider.go -destination=./testing/mocks/providers/scores_provider_mock.go -package=mock_providers
 //
 
-// Package mock_scores_provider is a generated GoMock package.
+// Package mock_providers is a generated GoMock package.
 package mock_providers
 
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 	structures "type_writer_api/structures"
 
 	gomock "go.uber.org/mock/gomock"
@@ -71,6 +72,21 @@ func (mr *MockScoresProviderInterfaceMockRecorder) DeleteScore(ctx, scoreId any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScore", reflect.TypeOf((*MockScoresProviderInterface)(nil).DeleteScore), ctx, scoreId)
 }
 
+// DeleteScoreKeystrokesBefore mocks base method.
+func (m *MockScoresProviderInterface) DeleteScoreKeystrokesBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScoreKeystrokesBefore", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteScoreKeystrokesBefore indicates an expected call of DeleteScoreKeystrokesBefore.
+func (mr *MockScoresProviderInterfaceMockRecorder) DeleteScoreKeystrokesBefore(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScoreKeystrokesBefore", reflect.TypeOf((*MockScoresProviderInterface)(nil).DeleteScoreKeystrokesBefore), ctx, before)
+}
+
 // GetScoreById mocks base method.
 func (m *MockScoresProviderInterface) GetScoreById(ctx context.Context, scoreId int) (*structures.Score, error) {
 	m.ctrl.T.Helper()
@@ -86,19 +102,34 @@ func (mr *MockScoresProviderInterfaceMockRecorder) GetScoreById(ctx, scoreId any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScoreById", reflect.TypeOf((*MockScoresProviderInterface)(nil).GetScoreById), ctx, scoreId)
 }
 
+// GetScoreKeystrokes mocks base method.
+func (m *MockScoresProviderInterface) GetScoreKeystrokes(ctx context.Context, scoreId int) (*structures.ScoreKeystrokeLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScoreKeystrokes", ctx, scoreId)
+	ret0, _ := ret[0].(*structures.ScoreKeystrokeLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScoreKeystrokes indicates an expected call of GetScoreKeystrokes.
+func (mr *MockScoresProviderInterfaceMockRecorder) GetScoreKeystrokes(ctx, scoreId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScoreKeystrokes", reflect.TypeOf((*MockScoresProviderInterface)(nil).GetScoreKeystrokes), ctx, scoreId)
+}
+
 // GetScores mocks base method.
-func (m *MockScoresProviderInterface) GetScores(ctx context.Context) ([]*structures.Score, error) {
+func (m *MockScoresProviderInterface) GetScores(ctx context.Context, filter structures.ScoreFilter) ([]*structures.Score, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetScores", ctx)
+	ret := m.ctrl.Call(m, "GetScores", ctx, filter)
 	ret0, _ := ret[0].([]*structures.Score)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetScores indicates an expected call of GetScores.
-func (mr *MockScoresProviderInterfaceMockRecorder) GetScores(ctx any) *gomock.Call {
+func (mr *MockScoresProviderInterfaceMockRecorder) GetScores(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScores", reflect.TypeOf((*MockScoresProviderInterface)(nil).GetScores), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScores", reflect.TypeOf((*MockScoresProviderInterface)(nil).GetScores), ctx, filter)
 }
 
 // UpdateScore mocks base method.
@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./providers/sessions/sessions_provider.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./providers/sessions/sessions_provider.go -destination=./testing/mocks/providers/sessions_provider_mock.go -package=mock_providers
+//
+
+// Package mock_providers is a generated GoMock package.
+package mock_providers
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	structures "type_writer_api/structures"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSessionsProviderInterface is a mock of SessionsProviderInterface interface.
+type MockSessionsProviderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionsProviderInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockSessionsProviderInterfaceMockRecorder is the mock recorder for MockSessionsProviderInterface.
+type MockSessionsProviderInterfaceMockRecorder struct {
+	mock *MockSessionsProviderInterface
+}
+
+// NewMockSessionsProviderInterface creates a new mock instance.
+func NewMockSessionsProviderInterface(ctrl *gomock.Controller) *MockSessionsProviderInterface {
+	mock := &MockSessionsProviderInterface{ctrl: ctrl}
+	mock.recorder = &MockSessionsProviderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionsProviderInterface) EXPECT() *MockSessionsProviderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AppendSessionKeystrokes mocks base method.
+func (m *MockSessionsProviderInterface) AppendSessionKeystrokes(ctx context.Context, sessionId, stored int, keystrokes []*structures.Keystroke) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendSessionKeystrokes", ctx, sessionId, stored, keystrokes)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AppendSessionKeystrokes indicates an expected call of AppendSessionKeystrokes.
+func (mr *MockSessionsProviderInterfaceMockRecorder) AppendSessionKeystrokes(ctx, sessionId, stored, keystrokes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendSessionKeystrokes", reflect.TypeOf((*MockSessionsProviderInterface)(nil).AppendSessionKeystrokes), ctx, sessionId, stored, keystrokes)
+}
+
+// CreateSession mocks base method.
+func (m *MockSessionsProviderInterface) CreateSession(ctx context.Context, sessionInfo structures.TypingSession) (*structures.TypingSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, sessionInfo)
+	ret0, _ := ret[0].(*structures.TypingSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockSessionsProviderInterfaceMockRecorder) CreateSession(ctx, sessionInfo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockSessionsProviderInterface)(nil).CreateSession), ctx, sessionInfo)
+}
+
+// ExpireSession mocks base method.
+func (m *MockSessionsProviderInterface) ExpireSession(ctx context.Context, sessionId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireSession", ctx, sessionId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireSession indicates an expected call of ExpireSession.
+func (mr *MockSessionsProviderInterfaceMockRecorder) ExpireSession(ctx, sessionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireSession", reflect.TypeOf((*MockSessionsProviderInterface)(nil).ExpireSession), ctx, sessionId)
+}
+
+// FinishSession mocks base method.
+func (m *MockSessionsProviderInterface) FinishSession(ctx context.Context, sessionId int, finishedAt time.Time, scoreInfo structures.Score) (*structures.Score, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishSession", ctx, sessionId, finishedAt, scoreInfo)
+	ret0, _ := ret[0].(*structures.Score)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishSession indicates an expected call of FinishSession.
+func (mr *MockSessionsProviderInterfaceMockRecorder) FinishSession(ctx, sessionId, finishedAt, scoreInfo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishSession", reflect.TypeOf((*MockSessionsProviderInterface)(nil).FinishSession), ctx, sessionId, finishedAt, scoreInfo)
+}
+
+// GetSessionById mocks base method.
+func (m *MockSessionsProviderInterface) GetSessionById(ctx context.Context, sessionId int) (*structures.TypingSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionById", ctx, sessionId)
+	ret0, _ := ret[0].(*structures.TypingSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionById indicates an expected call of GetSessionById.
+func (mr *MockSessionsProviderInterfaceMockRecorder) GetSessionById(ctx, sessionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionById", reflect.TypeOf((*MockSessionsProviderInterface)(nil).GetSessionById), ctx, sessionId)
+}
@@ -0,0 +1,193 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./providers/stats/stats_provider.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./providers/stats/stats_provider.go -destination=./testing/mocks/providers/stats_provider_mock.go -package=mock_providers
+//
+
+// Package mock_providers is a generated GoMock package.
+package mock_providers
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	structures "type_writer_api/structures"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStatsProviderInterface is a mock of StatsProviderInterface interface.
+type MockStatsProviderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsProviderInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockStatsProviderInterfaceMockRecorder is the mock recorder for MockStatsProviderInterface.
+type MockStatsProviderInterfaceMockRecorder struct {
+	mock *MockStatsProviderInterface
+}
+
+// NewMockStatsProviderInterface creates a new mock instance.
+func NewMockStatsProviderInterface(ctrl *gomock.Controller) *MockStatsProviderInterface {
+	mock := &MockStatsProviderInterface{ctrl: ctrl}
+	mock.recorder = &MockStatsProviderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatsProviderInterface) EXPECT() *MockStatsProviderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetLatestKeystrokeLogs mocks base method.
+func (m *MockStatsProviderInterface) GetLatestKeystrokeLogs(ctx context.Context, limit int) ([]*structures.ScoreKeystrokeLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestKeystrokeLogs", ctx, limit)
+	ret0, _ := ret[0].([]*structures.ScoreKeystrokeLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestKeystrokeLogs indicates an expected call of GetLatestKeystrokeLogs.
+func (mr *MockStatsProviderInterfaceMockRecorder) GetLatestKeystrokeLogs(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestKeystrokeLogs", reflect.TypeOf((*MockStatsProviderInterface)(nil).GetLatestKeystrokeLogs), ctx, limit)
+}
+
+// GetNgramBaselines mocks base method.
+func (m *MockStatsProviderInterface) GetNgramBaselines(ctx context.Context) ([]*structures.NgramBaseline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNgramBaselines", ctx)
+	ret0, _ := ret[0].([]*structures.NgramBaseline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNgramBaselines indicates an expected call of GetNgramBaselines.
+func (mr *MockStatsProviderInterfaceMockRecorder) GetNgramBaselines(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNgramBaselines", reflect.TypeOf((*MockStatsProviderInterface)(nil).GetNgramBaselines), ctx)
+}
+
+// GetPersonalBests mocks base method.
+func (m *MockStatsProviderInterface) GetPersonalBests(ctx context.Context, userId int) ([]*structures.PersonalBest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonalBests", ctx, userId)
+	ret0, _ := ret[0].([]*structures.PersonalBest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonalBests indicates an expected call of GetPersonalBests.
+func (mr *MockStatsProviderInterfaceMockRecorder) GetPersonalBests(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonalBests", reflect.TypeOf((*MockStatsProviderInterface)(nil).GetPersonalBests), ctx, userId)
+}
+
+// GetRollingAverage mocks base method.
+func (m *MockStatsProviderInterface) GetRollingAverage(ctx context.Context, userId, window int) (*structures.RollingAverage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRollingAverage", ctx, userId, window)
+	ret0, _ := ret[0].(*structures.RollingAverage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRollingAverage indicates an expected call of GetRollingAverage.
+func (mr *MockStatsProviderInterfaceMockRecorder) GetRollingAverage(ctx, userId, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRollingAverage", reflect.TypeOf((*MockStatsProviderInterface)(nil).GetRollingAverage), ctx, userId, window)
+}
+
+// GetTrend mocks base method.
+func (m *MockStatsProviderInterface) GetTrend(ctx context.Context, userId int, bucket, since string) ([]*structures.TrendBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrend", ctx, userId, bucket, since)
+	ret0, _ := ret[0].([]*structures.TrendBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrend indicates an expected call of GetTrend.
+func (mr *MockStatsProviderInterfaceMockRecorder) GetTrend(ctx, userId, bucket, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrend", reflect.TypeOf((*MockStatsProviderInterface)(nil).GetTrend), ctx, userId, bucket, since)
+}
+
+// GetUserKeystrokeLogs mocks base method.
+func (m *MockStatsProviderInterface) GetUserKeystrokeLogs(ctx context.Context, userId int, since time.Time, limit int) ([]*structures.ScoreKeystrokeLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserKeystrokeLogs", ctx, userId, since, limit)
+	ret0, _ := ret[0].([]*structures.ScoreKeystrokeLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserKeystrokeLogs indicates an expected call of GetUserKeystrokeLogs.
+func (mr *MockStatsProviderInterfaceMockRecorder) GetUserKeystrokeLogs(ctx, userId, since, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserKeystrokeLogs", reflect.TypeOf((*MockStatsProviderInterface)(nil).GetUserKeystrokeLogs), ctx, userId, since, limit)
+}
+
+// GetUserTextPlays mocks base method.
+func (m *MockStatsProviderInterface) GetUserTextPlays(ctx context.Context, userId int) ([]*structures.TextPlay, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTextPlays", ctx, userId)
+	ret0, _ := ret[0].([]*structures.TextPlay)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTextPlays indicates an expected call of GetUserTextPlays.
+func (mr *MockStatsProviderInterfaceMockRecorder) GetUserTextPlays(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTextPlays", reflect.TypeOf((*MockStatsProviderInterface)(nil).GetUserTextPlays), ctx, userId)
+}
+
+// GetUserTotals mocks base method.
+func (m *MockStatsProviderInterface) GetUserTotals(ctx context.Context, userId int) (*structures.UserStatsTotals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTotals", ctx, userId)
+	ret0, _ := ret[0].(*structures.UserStatsTotals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTotals indicates an expected call of GetUserTotals.
+func (mr *MockStatsProviderInterfaceMockRecorder) GetUserTotals(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTotals", reflect.TypeOf((*MockStatsProviderInterface)(nil).GetUserTotals), ctx, userId)
+}
+
+// GetWpmPercentile mocks base method.
+func (m *MockStatsProviderInterface) GetWpmPercentile(ctx context.Context, wpm float64) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWpmPercentile", ctx, wpm)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWpmPercentile indicates an expected call of GetWpmPercentile.
+func (mr *MockStatsProviderInterfaceMockRecorder) GetWpmPercentile(ctx, wpm any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWpmPercentile", reflect.TypeOf((*MockStatsProviderInterface)(nil).GetWpmPercentile), ctx, wpm)
+}
+
+// ReplaceNgramBaselines mocks base method.
+func (m *MockStatsProviderInterface) ReplaceNgramBaselines(ctx context.Context, baselines []*structures.NgramBaseline) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceNgramBaselines", ctx, baselines)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceNgramBaselines indicates an expected call of ReplaceNgramBaselines.
+func (mr *MockStatsProviderInterfaceMockRecorder) ReplaceNgramBaselines(ctx, baselines any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceNgramBaselines", reflect.TypeOf((*MockStatsProviderInterface)(nil).ReplaceNgramBaselines), ctx, baselines)
+}
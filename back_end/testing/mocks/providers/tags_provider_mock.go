@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./providers/tags/tags_provider.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./providers/tags/tags_provider.go -destination=./testing/mocks/providers/tags_provider_mock.go -package=mock_providers
+//
+
+// Package mock_providers is a generated GoMock package.
+package mock_providers
+
+import (
+	context "context"
+	reflect "reflect"
+	structures "type_writer_api/structures"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTagsProviderInterface is a mock of TagsProviderInterface interface.
+type MockTagsProviderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagsProviderInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockTagsProviderInterfaceMockRecorder is the mock recorder for MockTagsProviderInterface.
+type MockTagsProviderInterfaceMockRecorder struct {
+	mock *MockTagsProviderInterface
+}
+
+// NewMockTagsProviderInterface creates a new mock instance.
+func NewMockTagsProviderInterface(ctrl *gomock.Controller) *MockTagsProviderInterface {
+	mock := &MockTagsProviderInterface{ctrl: ctrl}
+	mock.recorder = &MockTagsProviderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagsProviderInterface) EXPECT() *MockTagsProviderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateTag mocks base method.
+func (m *MockTagsProviderInterface) CreateTag(ctx context.Context, tagInfo structures.Tag) (*structures.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTag", ctx, tagInfo)
+	ret0, _ := ret[0].(*structures.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTag indicates an expected call of CreateTag.
+func (mr *MockTagsProviderInterfaceMockRecorder) CreateTag(ctx, tagInfo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockTagsProviderInterface)(nil).CreateTag), ctx, tagInfo)
+}
+
+// DeleteTag mocks base method.
+func (m *MockTagsProviderInterface) DeleteTag(ctx context.Context, tagId int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, tagId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockTagsProviderInterfaceMockRecorder) DeleteTag(ctx, tagId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockTagsProviderInterface)(nil).DeleteTag), ctx, tagId)
+}
+
+// GetTagByIdOrName mocks base method.
+func (m *MockTagsProviderInterface) GetTagByIdOrName(ctx context.Context, tagId *int, name *string) (*structures.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagByIdOrName", ctx, tagId, name)
+	ret0, _ := ret[0].(*structures.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagByIdOrName indicates an expected call of GetTagByIdOrName.
+func (mr *MockTagsProviderInterfaceMockRecorder) GetTagByIdOrName(ctx, tagId, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagByIdOrName", reflect.TypeOf((*MockTagsProviderInterface)(nil).GetTagByIdOrName), ctx, tagId, name)
+}
+
+// GetTags mocks base method.
+func (m *MockTagsProviderInterface) GetTags(ctx context.Context) ([]*structures.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", ctx)
+	ret0, _ := ret[0].([]*structures.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockTagsProviderInterfaceMockRecorder) GetTags(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockTagsProviderInterface)(nil).GetTags), ctx)
+}
+
+// MergeTags mocks base method.
+func (m *MockTagsProviderInterface) MergeTags(ctx context.Context, sourceId, targetId int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeTags", ctx, sourceId, targetId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeTags indicates an expected call of MergeTags.
+func (mr *MockTagsProviderInterfaceMockRecorder) MergeTags(ctx, sourceId, targetId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeTags", reflect.TypeOf((*MockTagsProviderInterface)(nil).MergeTags), ctx, sourceId, targetId)
+}
+
+// UpdateTag mocks base method.
+func (m *MockTagsProviderInterface) UpdateTag(ctx context.Context, updatedTagInfo structures.Tag) (*structures.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTag", ctx, updatedTagInfo)
+	ret0, _ := ret[0].(*structures.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTag indicates an expected call of UpdateTag.
+func (mr *MockTagsProviderInterfaceMockRecorder) UpdateTag(ctx, updatedTagInfo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockTagsProviderInterface)(nil).UpdateTag), ctx, updatedTagInfo)
+}
@@ -71,6 +71,51 @@ func (mr *MockTextsProviderInterfaceMockRecorder) DeleteText(ctx, textId any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteText", reflect.TypeOf((*MockTextsProviderInterface)(nil).DeleteText), ctx, textId)
 }
 
+// DeleteTextRating mocks base method.
+func (m *MockTextsProviderInterface) DeleteTextRating(ctx context.Context, userId, textId int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTextRating", ctx, userId, textId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTextRating indicates an expected call of DeleteTextRating.
+func (mr *MockTextsProviderInterfaceMockRecorder) DeleteTextRating(ctx, userId, textId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTextRating", reflect.TypeOf((*MockTextsProviderInterface)(nil).DeleteTextRating), ctx, userId, textId)
+}
+
+// FavoriteText mocks base method.
+func (m *MockTextsProviderInterface) FavoriteText(ctx context.Context, userId, textId int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FavoriteText", ctx, userId, textId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FavoriteText indicates an expected call of FavoriteText.
+func (mr *MockTextsProviderInterfaceMockRecorder) FavoriteText(ctx, userId, textId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteText", reflect.TypeOf((*MockTextsProviderInterface)(nil).FavoriteText), ctx, userId, textId)
+}
+
+// GetDrillText mocks base method.
+func (m *MockTextsProviderInterface) GetDrillText(ctx context.Context, ownerId int, title string) (*structures.Text, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDrillText", ctx, ownerId, title)
+	ret0, _ := ret[0].(*structures.Text)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDrillText indicates an expected call of GetDrillText.
+func (mr *MockTextsProviderInterfaceMockRecorder) GetDrillText(ctx, ownerId, title any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDrillText", reflect.TypeOf((*MockTextsProviderInterface)(nil).GetDrillText), ctx, ownerId, title)
+}
+
 // GetTextByIdOrTitle mocks base method.
 func (m *MockTextsProviderInterface) GetTextByIdOrTitle(ctx context.Context, textId *int, title *string) (*structures.Text, error) {
 	m.ctrl.T.Helper()
@@ -86,19 +131,94 @@ func (mr *MockTextsProviderInterfaceMockRecorder) GetTextByIdOrTitle(ctx, textId
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTextByIdOrTitle", reflect.TypeOf((*MockTextsProviderInterface)(nil).GetTextByIdOrTitle), ctx, textId, title)
 }
 
+// GetTextByShareSlug mocks base method.
+func (m *MockTextsProviderInterface) GetTextByShareSlug(ctx context.Context, shareSlug string) (*structures.Text, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTextByShareSlug", ctx, shareSlug)
+	ret0, _ := ret[0].(*structures.Text)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTextByShareSlug indicates an expected call of GetTextByShareSlug.
+func (mr *MockTextsProviderInterfaceMockRecorder) GetTextByShareSlug(ctx, shareSlug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTextByShareSlug", reflect.TypeOf((*MockTextsProviderInterface)(nil).GetTextByShareSlug), ctx, shareSlug)
+}
+
+// GetTextFingerprints mocks base method.
+func (m *MockTextsProviderInterface) GetTextFingerprints(ctx context.Context) ([]*structures.TextFingerprint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTextFingerprints", ctx)
+	ret0, _ := ret[0].([]*structures.TextFingerprint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTextFingerprints indicates an expected call of GetTextFingerprints.
+func (mr *MockTextsProviderInterfaceMockRecorder) GetTextFingerprints(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTextFingerprints", reflect.TypeOf((*MockTextsProviderInterface)(nil).GetTextFingerprints), ctx)
+}
+
 // GetTexts mocks base method.
-func (m *MockTextsProviderInterface) GetTexts(ctx context.Context) ([]*structures.Text, error) {
+func (m *MockTextsProviderInterface) GetTexts(ctx context.Context, filter structures.TextFilter) ([]*structures.Text, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTexts", ctx)
+	ret := m.ctrl.Call(m, "GetTexts", ctx, filter)
 	ret0, _ := ret[0].([]*structures.Text)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTexts indicates an expected call of GetTexts.
-func (mr *MockTextsProviderInterfaceMockRecorder) GetTexts(ctx any) *gomock.Call {
+func (mr *MockTextsProviderInterfaceMockRecorder) GetTexts(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTexts", reflect.TypeOf((*MockTextsProviderInterface)(nil).GetTexts), ctx, filter)
+}
+
+// GetUnfingerprintedTexts mocks base method.
+func (m *MockTextsProviderInterface) GetUnfingerprintedTexts(ctx context.Context) ([]*structures.Text, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnfingerprintedTexts", ctx)
+	ret0, _ := ret[0].([]*structures.Text)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnfingerprintedTexts indicates an expected call of GetUnfingerprintedTexts.
+func (mr *MockTextsProviderInterfaceMockRecorder) GetUnfingerprintedTexts(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTexts", reflect.TypeOf((*MockTextsProviderInterface)(nil).GetTexts), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnfingerprintedTexts", reflect.TypeOf((*MockTextsProviderInterface)(nil).GetUnfingerprintedTexts), ctx)
+}
+
+// RateText mocks base method.
+func (m *MockTextsProviderInterface) RateText(ctx context.Context, rating structures.TextRating) (*structures.TextRating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RateText", ctx, rating)
+	ret0, _ := ret[0].(*structures.TextRating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RateText indicates an expected call of RateText.
+func (mr *MockTextsProviderInterfaceMockRecorder) RateText(ctx, rating any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RateText", reflect.TypeOf((*MockTextsProviderInterface)(nil).RateText), ctx, rating)
+}
+
+// UnfavoriteText mocks base method.
+func (m *MockTextsProviderInterface) UnfavoriteText(ctx context.Context, userId, textId int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfavoriteText", ctx, userId, textId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnfavoriteText indicates an expected call of UnfavoriteText.
+func (mr *MockTextsProviderInterfaceMockRecorder) UnfavoriteText(ctx, userId, textId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfavoriteText", reflect.TypeOf((*MockTextsProviderInterface)(nil).UnfavoriteText), ctx, userId, textId)
 }
 
 // UpdateText mocks base method.
@@ -115,3 +235,18 @@ func (mr *MockTextsProviderInterfaceMockRecorder) UpdateText(ctx, updatedtextInf
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateText", reflect.TypeOf((*MockTextsProviderInterface)(nil).UpdateText), ctx, updatedtextInfo)
 }
+
+// UpdateTextFingerprint mocks base method.
+func (m *MockTextsProviderInterface) UpdateTextFingerprint(ctx context.Context, textId int, fingerprint []uint32) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTextFingerprint", ctx, textId, fingerprint)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTextFingerprint indicates an expected call of UpdateTextFingerprint.
+func (mr *MockTextsProviderInterfaceMockRecorder) UpdateTextFingerprint(ctx, textId, fingerprint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTextFingerprint", reflect.TypeOf((*MockTextsProviderInterface)(nil).UpdateTextFingerprint), ctx, textId, fingerprint)
+}
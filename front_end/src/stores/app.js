@@ -74,8 +74,9 @@ export const useAppStore = defineStore('app', () => {
   //     text_id: 1,
   //     duration: 60,
   //     result: {
+  //       version: 1,
   //       wpm: 300,
-  //       lpm: 300,
+  //       cpm: 300,
   //       letters: 100,
   //       words: 100,
   //       errors: 100,
@@ -198,7 +199,7 @@ export const useAppStore = defineStore('app', () => {
   function computeStats() {
     const actualTime = timerSeconds.value == 0 ? testTime.value :  testTime.value - timerSeconds.value
     const MODIFIER =  actualTime / 60
-    let letters = 0, words = 0, errors = 0, wpm = 0, lpm = 0
+    let letters = 0, words = 0, errors = 0, wpm = 0, cpm = 0
     for (const [idx, val] of processedGameText.value.entries()) {
       if (idx < cursor.value) {
         letters++
@@ -209,8 +210,8 @@ export const useAppStore = defineStore('app', () => {
       }
     }
     wpm = Number((words / MODIFIER).toString().match(/\d+(.\d{1,2})?/).at(0))
-    lpm = Number((letters / MODIFIER).toString().match(/\d+(.\d{1,2})?/).at(0))
-    const computedStats = [actualTime, {wpm, lpm, letters, words, errors, corrected: 0}]
+    cpm = Number((letters / MODIFIER).toString().match(/\d+(.\d{1,2})?/).at(0))
+    const computedStats = [actualTime, {wpm, cpm, letters, words, errors}]
     // console.log(computedStats)
     return computedStats
   }